@@ -0,0 +1,206 @@
+package iris
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultAccessLogBufferSize the lines which each sink of the access log buffers before the next ones are dropped
+	DefaultAccessLogBufferSize = 1024
+	// accessLogTimeFormat the time format of the Combined Log Format
+	accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+)
+
+// AccessLogOptions the options of the EnableAccessLog
+type AccessLogOptions struct {
+	// Sinks the writers of the access log, i.e a RotatingFile, the os.Stdout, a syslog writer,
+	// each one is written concurrently, by its own goroutine
+	// Defaults to the os.Stdout
+	Sinks []io.Writer
+	// BufferSize the lines which each sink buffers, while it's slow, before the next ones are dropped, so the requests never wait for a sink
+	// Defaults to the DefaultAccessLogBufferSize
+	BufferSize int
+}
+
+// AccessLog writes a line for each request, in the Combined Log Format with the duration in milliseconds appended, to its sinks
+//
+// 127.0.0.1 - - [25/Dec/2016:15:04:05 +0200] "GET /users/42 HTTP/1.1" 200 1024 "https://example.com/" "Mozilla/5.0" 12.5
+type AccessLog struct {
+	bufferSize int
+
+	mu    sync.RWMutex
+	sinks []*AccessLogSink
+}
+
+// AccessLogSink a writer of the access log
+type AccessLogSink struct {
+	w       io.Writer
+	lines   chan []byte
+	done    chan struct{}
+	written uint64
+	dropped uint64
+	errors  uint64
+}
+
+// EnableAccessLog enables the access log of the default iris instance, see the Framework's EnableAccessLog
+func EnableAccessLog(options ...AccessLogOptions) *AccessLog {
+	return Default.EnableAccessLog(options...)
+}
+
+// EnableAccessLog enables the access log and returns it, more sinks can be added by its AddSink.
+// The pending lines are written and the sinks which are io.Closer, except the os.Stdout and os.Stderr, are closed by the Shutdown.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// file, err := iris.NewRotatingFile("./logs/access.log", iris.RotatingFileOptions{MaxSize: 100 << 20, MaxBackups: 7})
+// app.EnableAccessLog(iris.AccessLogOptions{Sinks: []io.Writer{file, os.Stdout}})
+func (s *Framework) EnableAccessLog(options ...AccessLogOptions) *AccessLog {
+	if s.accessLog != nil {
+		return s.accessLog
+	}
+	var o AccessLogOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.BufferSize <= 0 {
+		o.BufferSize = DefaultAccessLogBufferSize
+	}
+	if len(o.Sinks) == 0 {
+		o.Sinks = []io.Writer{os.Stdout}
+	}
+
+	l := &AccessLog{bufferSize: o.BufferSize}
+	for _, w := range o.Sinks {
+		l.AddSink(w)
+	}
+	s.accessLog = l
+	s.OnShutdown(func(context.Context) {
+		if err := l.Close(); err != nil {
+			s.log(LogLevelError, "closing the access log", "err", err)
+		}
+	})
+	return l
+}
+
+// AddSink adds a writer to the access log and returns its sink, which counts the written and the dropped lines
+func (l *AccessLog) AddSink(w io.Writer) *AccessLogSink {
+	sink := &AccessLogSink{w: w, lines: make(chan []byte, l.bufferSize), done: make(chan struct{})}
+	go sink.loop()
+	l.mu.Lock()
+	l.sinks = append(l.sinks, sink)
+	l.mu.Unlock()
+	return sink
+}
+
+// Sinks returns the sinks of the access log
+func (l *AccessLog) Sinks() []*AccessLogSink {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]*AccessLogSink(nil), l.sinks...)
+}
+
+// Close writes the pending lines and closes the sinks which are io.Closer, except the os.Stdout and os.Stderr,
+// it returns the first error of their Close
+func (l *AccessLog) Close() error {
+	l.mu.Lock()
+	sinks := l.sinks
+	l.sinks = nil
+	l.mu.Unlock()
+
+	var firstErr error
+	for _, sink := range sinks {
+		close(sink.lines)
+		<-sink.done
+		if c, ok := sink.w.(io.Closer); ok && sink.w != os.Stdout && sink.w != os.Stderr {
+			if err := c.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// startRequest keeps the time which the request is started
+func (l *AccessLog) startRequest(ctx *Context) {
+	ctx.start = time.Now()
+}
+
+// observeRequest formats the line of a served request, before its response is flushed, and passes it to the sinks
+func (l *AccessLog) observeRequest(ctx *Context) {
+	r := ctx.Request
+	status := ctx.ResponseWriter.StatusCode()
+	if status == 0 {
+		status = StatusOK
+	}
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		user = u
+	}
+
+	line := make([]byte, 0, 256)
+	line = append(line, ctx.RemoteAddr()...)
+	line = append(line, " - "...)
+	line = append(line, user...)
+	line = append(line, " ["...)
+	line = ctx.start.AppendFormat(line, accessLogTimeFormat)
+	line = append(line, "] "...)
+	line = strconv.AppendQuote(line, r.Method+" "+r.RequestURI+" "+r.Proto)
+	line = append(line, ' ')
+	line = strconv.AppendInt(line, int64(status), 10)
+	line = append(line, ' ')
+	line = strconv.AppendInt(line, int64(len(ctx.ResponseWriter.Body())), 10)
+	line = append(line, ' ')
+	line = strconv.AppendQuote(line, r.Referer())
+	line = append(line, ' ')
+	line = strconv.AppendQuote(line, r.UserAgent())
+	line = append(line, ' ')
+	line = strconv.AppendFloat(line, float64(time.Since(ctx.start))/float64(time.Millisecond), 'f', 3, 64)
+	line = append(line, '\n')
+
+	l.mu.RLock()
+	for _, sink := range l.sinks {
+		sink.send(line)
+	}
+	l.mu.RUnlock()
+}
+
+// send buffers the line, it's dropped if the buffer is full
+func (sink *AccessLogSink) send(line []byte) {
+	select {
+	case sink.lines <- line:
+	default:
+		atomic.AddUint64(&sink.dropped, 1)
+	}
+}
+
+func (sink *AccessLogSink) loop() {
+	defer close(sink.done)
+	for line := range sink.lines {
+		if _, err := sink.w.Write(line); err != nil {
+			atomic.AddUint64(&sink.errors, 1)
+			continue
+		}
+		atomic.AddUint64(&sink.written, 1)
+	}
+}
+
+// Written returns the number of the lines which are written to the sink
+func (sink *AccessLogSink) Written() uint64 {
+	return atomic.LoadUint64(&sink.written)
+}
+
+// Dropped returns the number of the lines which are dropped because the sink's buffer was full
+func (sink *AccessLogSink) Dropped() uint64 {
+	return atomic.LoadUint64(&sink.dropped)
+}
+
+// Errors returns the number of the lines which the sink failed to write
+func (sink *AccessLogSink) Errors() uint64 {
+	return atomic.LoadUint64(&sink.errors)
+}
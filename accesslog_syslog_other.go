@@ -0,0 +1,16 @@
+//go:build windows || plan9 || js || wasip1
+
+package iris
+
+import (
+	"io"
+
+	"github.com/kataras/go-errors"
+)
+
+var errSyslogNotSupported = errors.New("Syslog is not supported on this platform")
+
+// SyslogSink returns an error, the syslog is not supported on this platform
+func SyslogSink(tag string) (io.Writer, error) {
+	return nil, errSyslogNotSupported
+}
@@ -0,0 +1,14 @@
+//go:build !(windows || plan9 || js || wasip1)
+
+package iris
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// SyslogSink returns a sink of the access log which writes the lines to the local syslog daemon, with the 'tag',
+// as info messages of the local0 facility
+func SyslogSink(tag string) (io.Writer, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+}
@@ -0,0 +1,71 @@
+package iris
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// allowedHostsHandler rejects the requests of the 'h' whose Host header doesn't match the Configuration's AllowedHosts
+func (s *Framework) allowedHostsHandler(h http.Handler) http.Handler {
+	allowed := make([]string, len(s.Config.AllowedHosts))
+	for i, host := range s.Config.AllowedHosts {
+		allowed[i] = strings.ToLower(host)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusOK
+		if hostname, ok := requestHostname(r.Host); !ok {
+			status = StatusBadRequest
+		} else if !matchHost(allowed, hostname) {
+			status = StatusMisdirectedRequest
+		}
+		if status != StatusOK {
+			ctx := s.AcquireCtx(w, r)
+			s.mux.fireError(status, ctx)
+			s.ReleaseCtx(ctx)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requestHostname returns the lowercase hostname of the Host header, without the port,
+// false if it's empty or it contains characters which are not allowed in a hostname
+func requestHostname(host string) (string, bool) {
+	if host == "" {
+		return "", false
+	}
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	} else if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		hostname = host[1 : len(host)-1]
+	}
+	if hostname == "" {
+		return "", false
+	}
+	for i := 0; i < len(hostname); i++ {
+		c := hostname[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_' || c == ':') {
+			return "", false
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(hostname, ".")), true
+}
+
+// matchHost reports whether the 'hostname' is one of the 'allowed', the "*.example.com" matches the subdomains of the example.com
+func matchHost(allowed []string, hostname string) bool {
+	for _, pattern := range allowed {
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(hostname, pattern[1:]) {
+				return true
+			}
+			continue
+		}
+		if hostname == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,337 @@
+package iris
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kataras/go-errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultAPIKeyHeader the default header of the api keys of the APIKeyAuth
+	DefaultAPIKeyHeader = "X-API-Key"
+	// DefaultSQLAPIKeysTable the default table name of the SQLAPIKeyStore
+	DefaultSQLAPIKeysTable = "iris_api_keys"
+	// DefaultRedisAPIKeyStorePrefix the prefix of the keys of the RedisAPIKeyStore
+	DefaultRedisAPIKeyStorePrefix = "iris-apikey:"
+	// apiKeyContextKey the context's value of the resolved api key, see the ctx.APIKey
+	apiKeyContextKey = "iris.apikey"
+)
+
+var (
+	errAPIKeyLookup  = errors.New("APIKeyAuth: looking up a key failed. Trace: %s")
+	errAPIKeyMigrate = errors.New("SQL API key store: unable to migrate the '%s' table. Trace: %s")
+)
+
+// APIKey the owner and the scopes of an api key
+type APIKey struct {
+	// ID the identifier of the key, i.e for the logs and the audits, never the key itself
+	ID string `json:"id"`
+	// Owner the user or the service which owns the key
+	Owner string `json:"owner"`
+	// Scopes the permissions of the key, i.e "orders:read", see the RequireScopes
+	Scopes []string `json:"scopes"`
+	// ExpiresAt the time after which the key is rejected
+	// Defaults to zero, it never expires
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// HasScope returns true if the key has the 'scope', or the "*" scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// expired returns true if the key has expired at the 'now'
+func (k *APIKey) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && now.After(k.ExpiresAt)
+}
+
+// APIKeyStore resolves the api keys of the APIKeyAuth, the StaticAPIKeyStore, the SQLAPIKeyStore and the RedisAPIKeyStore are APIKeyStores.
+// The stores keep the HashAPIKey of the keys, not the keys.
+type APIKeyStore interface {
+	// Lookup returns the APIKey of the 'hash' of a key, nil if it doesn't exist
+	Lookup(ctx context.Context, hash string) (*APIKey, error)
+}
+
+// HashAPIKey returns the hex sha256 of the 'key', which the APIKeyStores keep
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// StaticAPIKeyStore is the in-memory APIKeyStore of a fixed set of keys, i.e the keys of the internal services of the configuration
+type StaticAPIKeyStore struct {
+	keys map[string]*APIKey
+}
+
+var _ APIKeyStore = &StaticAPIKeyStore{}
+
+// NewStaticAPIKeyStore returns a new StaticAPIKeyStore of the 'keys', by key
+//
+// Usage:
+// store := iris.NewStaticAPIKeyStore(map[string]iris.APIKey{
+//	os.Getenv("BILLING_API_KEY"): {ID: "billing", Owner: "billing-service", Scopes: []string{"invoices:write"}},
+// })
+func NewStaticAPIKeyStore(keys map[string]APIKey) *StaticAPIKeyStore {
+	s := &StaticAPIKeyStore{keys: make(map[string]*APIKey, len(keys))}
+	for key, k := range keys {
+		k := k
+		s.keys[HashAPIKey(key)] = &k
+	}
+	return s
+}
+
+// Lookup returns the APIKey of the 'hash', nil if it doesn't exist
+func (s *StaticAPIKeyStore) Lookup(_ context.Context, hash string) (*APIKey, error) {
+	return s.keys[hash], nil
+}
+
+// SQLAPIKeyStore is the database/sql APIKeyStore, its table keeps the hash, the id, the owner,
+// the space-separated scopes and the unix expiration, zero if the key never expires, of each key.
+//
+// Call its Migrate once, before the server starts, to create the keys table.
+type SQLAPIKeyStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+	table   string
+}
+
+var _ APIKeyStore = &SQLAPIKeyStore{}
+
+// NewSQLAPIKeyStore returns a new APIKeyStore of the 'table' of the 'db',
+// if the table is empty then the DefaultSQLAPIKeysTable is used
+func NewSQLAPIKeyStore(db *sql.DB, dialect SQLDialect, table string) *SQLAPIKeyStore {
+	if table == "" {
+		table = DefaultSQLAPIKeysTable
+	}
+	return &SQLAPIKeyStore{db: db, dialect: dialect, table: table}
+}
+
+// Migrate creates the keys table if not exists
+func (s *SQLAPIKeyStore) Migrate() error {
+	create := "CREATE TABLE IF NOT EXISTS " + s.table + " (" +
+		"hash " + s.dialect.sidType() + " NOT NULL PRIMARY KEY, " +
+		"id " + s.dialect.sidType() + " NOT NULL, " +
+		"owner " + s.dialect.sidType() + " NOT NULL, " +
+		"scopes " + s.dialect.dataType() + " NOT NULL, " +
+		"expires_at BIGINT NOT NULL DEFAULT 0)"
+	if _, err := s.db.Exec(create); err != nil {
+		return errAPIKeyMigrate.Format(s.table, err)
+	}
+	return nil
+}
+
+// Add saves the 'k' of the 'key'
+func (s *SQLAPIKeyStore) Add(key string, k APIKey) error {
+	d := s.dialect
+	var expiresAt int64
+	if !k.ExpiresAt.IsZero() {
+		expiresAt = k.ExpiresAt.Unix()
+	}
+	_, err := s.db.Exec("INSERT INTO "+s.table+" (hash, id, owner, scopes, expires_at) VALUES ("+
+		d.param(1)+", "+d.param(2)+", "+d.param(3)+", "+d.param(4)+", "+d.param(5)+")",
+		HashAPIKey(key), k.ID, k.Owner, strings.Join(k.Scopes, " "), expiresAt)
+	return err
+}
+
+// Revoke removes the keys of the 'id'
+func (s *SQLAPIKeyStore) Revoke(id string) error {
+	_, err := s.db.Exec("DELETE FROM "+s.table+" WHERE id = "+s.dialect.param(1), id)
+	return err
+}
+
+// Lookup returns the APIKey of the 'hash', nil if it doesn't exist
+func (s *SQLAPIKeyStore) Lookup(ctx context.Context, hash string) (*APIKey, error) {
+	var (
+		k         APIKey
+		scopes    string
+		expiresAt int64
+	)
+	err := s.db.QueryRowContext(ctx, "SELECT id, owner, scopes, expires_at FROM "+s.table+" WHERE hash = "+s.dialect.param(1), hash).
+		Scan(&k.ID, &k.Owner, &scopes, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = strings.Fields(scopes)
+	if expiresAt > 0 {
+		k.ExpiresAt = time.Unix(expiresAt, 0)
+	}
+	return &k, nil
+}
+
+// RedisAPIKeyStore is the Redis APIKeyStore, each key is saved as json, with the expiration of the key, if any,
+// and the hashes of each id are kept to a set, so the keys are revoked by their id
+//
+// Usage:
+// client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+// store := iris.NewRedisAPIKeyStore(client, "")
+type RedisAPIKeyStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ APIKeyStore = &RedisAPIKeyStore{}
+
+// NewRedisAPIKeyStore returns a new RedisAPIKeyStore, an empty 'prefix' means the DefaultRedisAPIKeyStorePrefix
+func NewRedisAPIKeyStore(client redis.UniversalClient, prefix string) *RedisAPIKeyStore {
+	if prefix == "" {
+		prefix = DefaultRedisAPIKeyStorePrefix
+	}
+	return &RedisAPIKeyStore{client: client, prefix: prefix}
+}
+
+// Add saves the 'k' of the 'key', it's removed by the redis when it expires
+func (s *RedisAPIKeyStore) Add(key string, k APIKey) error {
+	c := context.Background()
+	b, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	var ttl time.Duration
+	if !k.ExpiresAt.IsZero() {
+		if ttl = time.Until(k.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+	hash := HashAPIKey(key)
+	_, err = s.client.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, s.prefix+hash, b, ttl)
+		// the set of the id doesn't expire, a revoke must find the keys which never expire too
+		pipe.SAdd(c, s.idKey(k.ID), hash)
+		return nil
+	})
+	return err
+}
+
+// Revoke removes the keys of the 'id'
+func (s *RedisAPIKeyStore) Revoke(id string) error {
+	c := context.Background()
+	hashes, err := s.client.SMembers(c, s.idKey(id)).Result()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(hashes)+1)
+	for _, hash := range hashes {
+		keys = append(keys, s.prefix+hash)
+	}
+	return s.client.Del(c, append(keys, s.idKey(id))...).Err()
+}
+
+// idKey returns the key of the set of the hashes of the 'id', the hashes are hex so they never start with "id:"
+func (s *RedisAPIKeyStore) idKey(id string) string {
+	return s.prefix + "id:" + id
+}
+
+// Lookup returns the APIKey of the 'hash', nil if it doesn't exist
+func (s *RedisAPIKeyStore) Lookup(ctx context.Context, hash string) (*APIKey, error) {
+	b, err := s.client.Get(ctx, s.prefix+hash).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k := new(APIKey)
+	if err := json.Unmarshal(b, k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// APIKeyOptions the options of the APIKeyAuth
+type APIKeyOptions struct {
+	// Header the request header of the key
+	// Defaults to the DefaultAPIKeyHeader, "X-API-Key"
+	Header string
+	// QueryParameter the url parameter of the key, i.e "api_key", the urls are kept by the logs and the proxies,
+	// so it should be used only by the clients which can't send headers
+	// Defaults to "", disabled
+	QueryParameter string
+	// Optional continues without a key, the ctx.APIKey returns nil, the requests with an unknown or expired key are still rejected
+	// Defaults to false, the requests without a key are rejected with the 401 status code
+	Optional bool
+}
+
+// APIKeyAuth returns a middleware which resolves the api key of the request, from its header or its url parameter, with the 'store'.
+// The requests without a key, or with an unknown or expired one, are rejected with the 401 status code,
+// the errors of the store with the 500. The resolved key is returned by the ctx.APIKey, see the RequireScopes.
+//
+// Usage:
+// api := app.Party("/api", iris.APIKeyAuth(iris.NewSQLAPIKeyStore(db, iris.SQLDialectPostgres, "")))
+// api.Get("/orders", iris.RequireScopes("orders:read"), listOrders)
+func APIKeyAuth(store APIKeyStore, options ...APIKeyOptions) HandlerFunc {
+	var o APIKeyOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Header == "" {
+		o.Header = DefaultAPIKeyHeader
+	}
+
+	return func(ctx *Context) {
+		key := ctx.RequestHeader(o.Header)
+		if key == "" && o.QueryParameter != "" {
+			key = ctx.URLParam(o.QueryParameter)
+		}
+		if key == "" {
+			if o.Optional {
+				ctx.Next()
+				return
+			}
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+
+		k, err := store.Lookup(ctx.Request.Context(), HashAPIKey(key))
+		if err != nil {
+			ctx.framework.log(LogLevelError, errAPIKeyLookup.Format(err).Error())
+			ctx.EmitError(StatusInternalServerError)
+			return
+		}
+		if k == nil || k.expired(ctx.framework.now()) {
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+		ctx.Set(apiKeyContextKey, k)
+		ctx.Next()
+	}
+}
+
+// APIKey returns the api key of the request which is resolved by the APIKeyAuth, nil if there is not any
+func (ctx *Context) APIKey() *APIKey {
+	k, _ := ctx.Get(apiKeyContextKey).(*APIKey)
+	return k
+}
+
+// RequireScopes returns a middleware which rejects the requests whose api key, see the APIKeyAuth, doesn't have all the 'scopes',
+// with the 403 status code, or with the 401 if there is not any key
+func RequireScopes(scopes ...string) HandlerFunc {
+	return func(ctx *Context) {
+		k := ctx.APIKey()
+		if k == nil {
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+		for _, scope := range scopes {
+			if !k.HasScope(scope) {
+				ctx.EmitError(StatusForbidden)
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,189 @@
+package iris
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// assetResolver resolves the cache-busting paths of the files which are served by the static handlers,
+// it's used by the "asset" template func.
+type assetResolver struct {
+	roots []assetRoot
+	// hashes the content's hash of each asset, by its request path, it's re-computed when the file is modified
+	hashes map[string]assetHash
+	mu     sync.RWMutex
+}
+
+type assetRoot struct {
+	prefix     string
+	filesystem http.FileSystem
+	// fsys is not nil for the HandleDir's file systems
+	fsys fs.FS
+	// fingerprint if true then the content's hash is part of the file's name, i.e "/assets/app.3f9ab2c1.js"
+	fingerprint bool
+}
+
+type assetHash struct {
+	modTime time.Time
+	hash    string
+}
+
+func newAssetResolver() *assetResolver {
+	return &assetResolver{hashes: make(map[string]assetHash)}
+}
+
+// add registers a static file system which is served under the 'prefix' request path
+func (a *assetResolver) add(prefix string, filesystem http.FileSystem) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	a.mu.Lock()
+	a.roots = append(a.roots, assetRoot{prefix: prefix, filesystem: filesystem})
+	a.mu.Unlock()
+}
+
+// addFS registers a file system of the HandleDir which is served under the 'prefix' request path,
+// if 'fingerprint' is true then its files are resolved to their fingerprinted names
+func (a *assetResolver) addFS(prefix string, fsys fs.FS, fingerprint bool) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	a.mu.Lock()
+	a.roots = append(a.roots, assetRoot{prefix: prefix, filesystem: http.FS(fsys), fsys: fsys, fingerprint: fingerprint})
+	a.mu.Unlock()
+}
+
+// root returns the root which serves the 'reqPath', the longest prefix wins
+func (a *assetResolver) root(reqPath string) *assetRoot {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var root *assetRoot
+	for i := range a.roots {
+		r := &a.roots[i]
+		if (reqPath == r.prefix || strings.HasPrefix(reqPath, strings.TrimSuffix(r.prefix, "/")+"/")) &&
+			(root == nil || len(r.prefix) > len(root.prefix)) {
+			root = r
+		}
+	}
+	return root
+}
+
+// open finds and opens the file which is served under the 'reqPath', the longest prefix wins
+func (a *assetResolver) open(reqPath string) (http.File, error) {
+	root := a.root(reqPath)
+	if root == nil {
+		return nil, errDirectoryFileNotFound.Format(reqPath, "no static handler serves this path")
+	}
+	name := strings.TrimPrefix(reqPath, strings.TrimSuffix(root.prefix, "/"))
+	return root.filesystem.Open(name)
+}
+
+// hash returns the short content's hash of the asset which is served under the 'reqPath'
+func (a *assetResolver) hash(reqPath string) (string, error) {
+	f, err := a.open(reqPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.RLock()
+	h, ok := a.hashes[reqPath]
+	a.mu.RUnlock()
+	if ok && h.modTime.Equal(info.ModTime()) {
+		return h.hash, nil
+	}
+
+	hasher := sha1.New()
+	if _, err = io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	h = assetHash{modTime: info.ModTime(), hash: hex.EncodeToString(hasher.Sum(nil))[:8]}
+	a.mu.Lock()
+	a.hashes[reqPath] = h
+	a.mu.Unlock()
+	return h.hash, nil
+}
+
+// resolve returns the 'reqPath' with its content's hash as query, i.e "/static/app.js?v=3f9ab2c1",
+// or as part of its name, i.e "/assets/app.3f9ab2c1.js", when it's served by a HandleDir with the Fingerprint option.
+// If the file is not served by any static handler then the 'reqPath' is returned as it's.
+func (a *assetResolver) resolve(reqPath string) string {
+	h, err := a.hash(reqPath)
+	if err != nil {
+		return reqPath
+	}
+	if root := a.root(reqPath); root != nil && root.fingerprint {
+		return fingerprint(reqPath, h)
+	}
+	return reqPath + "?v=" + h
+}
+
+// manifest returns the fingerprinted request path of each file of the fingerprinted file systems, by its request path
+func (a *assetResolver) manifest() map[string]string {
+	a.mu.RLock()
+	roots := make([]assetRoot, len(a.roots))
+	copy(roots, a.roots)
+	a.mu.RUnlock()
+
+	m := make(map[string]string)
+	for _, root := range roots {
+		if !root.fingerprint {
+			continue
+		}
+		fs.WalkDir(root.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			reqPath := path.Join(root.prefix, name)
+			if h, err := a.hash(reqPath); err == nil {
+				m[reqPath] = fingerprint(reqPath, h)
+			}
+			return nil
+		})
+	}
+	return m
+}
+
+// fingerprint returns the 'reqPath' with the 'hash' before its extension, i.e "/assets/app.3f9ab2c1.js"
+func fingerprint(reqPath string, hash string) string {
+	ext := path.Ext(reqPath)
+	if ext == path.Base(reqPath) { // a dot file, i.e "/.htaccess"
+		ext = ""
+	}
+	return strings.TrimSuffix(reqPath, ext) + "." + hash + ext
+}
+
+// unfingerprint returns the original name and the hash of a fingerprinted 'name', the opposite of fingerprint
+func unfingerprint(name string) (original string, hash string, ok bool) {
+	ext := path.Ext(name)
+	withoutExt := strings.TrimSuffix(name, ext)
+	if isAssetHash(strings.TrimPrefix(ext, ".")) { // the file has no extension, i.e "LICENSE.3f9ab2c1"
+		return withoutExt, ext[1:], withoutExt != "" && !strings.HasSuffix(withoutExt, "/")
+	}
+	idx := strings.LastIndexByte(withoutExt, '.')
+	if idx < 0 || !isAssetHash(withoutExt[idx+1:]) {
+		return "", "", false
+	}
+	return withoutExt[:idx] + ext, withoutExt[idx+1:], true
+}
+
+// isAssetHash reports whether the 's' is a content's hash of the assetResolver, 8 lowercase hex characters
+func isAssetHash(s string) bool {
+	if len(s) != 8 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
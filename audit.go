@@ -0,0 +1,184 @@
+package iris
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AuditOutcomeSuccess the outcome of an action which responded with a non-error status code
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeFailure the outcome of an action which responded with a 4xx or 5xx status code or panicked
+	AuditOutcomeFailure = "failure"
+	// AuditOutcomeRolledBack the outcome of an action which one of its transactions failed, so its changes are rolled back
+	AuditOutcomeRolledBack = "rolledback"
+
+	// transactionFailedContextKey is set by the failed transactions, see TransactionFailed
+	transactionFailedContextKey = "__IRIS_TRANSACTION_FAILED__"
+)
+
+// AuditEntry the record of an audited action
+type AuditEntry struct {
+	Time       time.Time         `json:"time"`
+	Action     string            `json:"action"`
+	Actor      string            `json:"actor"`
+	Method     string            `json:"method"`
+	Route      string            `json:"route"`
+	Path       string            `json:"path"`
+	Params     map[string]string `json:"params,omitempty"`
+	RemoteAddr string            `json:"remoteAddr"`
+	StatusCode int               `json:"statusCode"`
+	Outcome    string            `json:"outcome"`
+	Duration   time.Duration     `json:"duration"`
+}
+
+// AuditSink records the audit entries, i.e to a database table or to an append-only file
+type AuditSink interface {
+	Record(entry AuditEntry) error
+}
+
+// AuditSinkFunc is an AuditSink func
+type AuditSinkFunc func(entry AuditEntry) error
+
+// Record calls the func
+func (fn AuditSinkFunc) Record(entry AuditEntry) error {
+	return fn(entry)
+}
+
+// JSONAuditSink returns an AuditSink which writes the entries as json lines to the 'w', i.e a RotatingFile
+func JSONAuditSink(w io.Writer) AuditSink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return AuditSinkFunc(func(entry AuditEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return enc.Encode(entry)
+	})
+}
+
+// AuditOptions the options of the EnableAudit
+type AuditOptions struct {
+	// Sink records the entries, it's required
+	Sink AuditSink
+	// Actor returns the user who performs the action, i.e from the session
+	// Defaults to the basic authentication's username, "anonymous" if there is not any
+	Actor func(ctx *Context) string
+	// RedactParams the route and the url query parameters whose values are replaced by [REDACTED]
+	// Defaults to the DefaultDumpRedactFields
+	RedactParams []string
+}
+
+type frameworkAudit struct {
+	options AuditOptions
+	redact  map[string]bool
+}
+
+// EnableAudit enables the audit of the default iris instance, see the Framework's EnableAudit
+func EnableAudit(options AuditOptions) {
+	Default.EnableAudit(options)
+}
+
+// EnableAudit sets the sink of the audited actions, see the Audit
+func (s *Framework) EnableAudit(options AuditOptions) {
+	if options.Actor == nil {
+		options.Actor = func(ctx *Context) string {
+			if u, _, ok := ctx.Request.BasicAuth(); ok && u != "" {
+				return u
+			}
+			return "anonymous"
+		}
+	}
+	if options.RedactParams == nil {
+		options.RedactParams = DefaultDumpRedactFields
+	}
+	redact := make(map[string]bool, len(options.RedactParams))
+	for _, p := range options.RedactParams {
+		redact[strings.ToLower(p)] = true
+	}
+	s.audit = &frameworkAudit{options: options, redact: redact}
+}
+
+// Audit returns a middleware which records the 'action', i.e "user.update", of the sensitive routes to the sink of the EnableAudit,
+// with the actor, the route, the parameters, the outcome and the latency.
+// An action is recorded as rolled back when one of its transactions fails.
+//
+// Usage:
+// app.EnableAudit(iris.AuditOptions{Sink: iris.JSONAuditSink(auditFile)})
+// app.Put("/users/:id", iris.Audit("user.update"), updateUser)
+func Audit(action string) HandlerFunc {
+	return func(ctx *Context) {
+		a := ctx.framework.audit
+		if a == nil {
+			ctx.Next()
+			return
+		}
+
+		start := time.Now()
+		// the route parameters are the only values before the handlers
+		params := a.params(ctx)
+		completed := false
+		defer func() {
+			entry := AuditEntry{
+				Time:       start,
+				Action:     action,
+				Actor:      a.options.Actor(ctx),
+				Method:     ctx.Method(),
+				Route:      ctx.framework.mux.routeOf(ctx),
+				Path:       ctx.Path(),
+				Params:     params,
+				RemoteAddr: ctx.RemoteAddr(),
+				StatusCode: ctx.ResponseWriter.StatusCode(),
+				Outcome:    AuditOutcomeSuccess,
+				Duration:   time.Since(start),
+			}
+			if entry.StatusCode == 0 {
+				entry.StatusCode = StatusOK
+			}
+			switch {
+			case !completed:
+				// the handler panicked
+				entry.StatusCode = StatusInternalServerError
+				entry.Outcome = AuditOutcomeFailure
+			case ctx.TransactionFailed():
+				entry.Outcome = AuditOutcomeRolledBack
+			case entry.StatusCode >= StatusBadRequest:
+				entry.Outcome = AuditOutcomeFailure
+			}
+			if err := a.options.Sink.Record(entry); err != nil {
+				ctx.framework.log(LogLevelError, "audit", "action", action, "err", err)
+			}
+		}()
+		ctx.Next()
+		completed = true
+	}
+}
+
+// params returns the route and the url query parameters, with the secrets redacted
+func (a *frameworkAudit) params(ctx *Context) map[string]string {
+	params := make(map[string]string)
+	ctx.VisitValues(func(k []byte, v interface{}) {
+		if s, ok := v.(string); ok {
+			params[string(k)] = s
+		}
+	})
+	for k, v := range ctx.Request.URL.Query() {
+		if _, ok := params[k]; !ok && len(v) > 0 {
+			params[k] = v[0]
+		}
+	}
+	for k := range params {
+		if a.redact[strings.ToLower(k)] {
+			params[k] = dumpRedacted
+		}
+	}
+	return params
+}
+
+// TransactionFailed returns true if one of the request's transactions failed, so its changes are rolled back
+func (ctx *Context) TransactionFailed() bool {
+	v, _ := ctx.Get(transactionFailedContextKey).(bool)
+	return v
+}
@@ -0,0 +1,135 @@
+package iris
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"github.com/kataras/go-errors"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var errAutoTLSNoDomains = errors.New("AutoTLS: at least one domain is required")
+
+// RunAutoTLS starts the https server with automatic certificates from Let's Encrypt, for the 'domains' only,
+// the certificates are obtained on the first request of each domain, by the TLS-ALPN-01 or the HTTP-01 challenge,
+// they are cached and they are renewed before they expire, look the Configuration's AutoTLS.
+//
+// A secondary http server, on the AutoTLS.HTTPAddr(:80), answers the HTTP-01 challenges and redirects the rest of the requests to https,
+// it's shut down with the main server.
+//
+// It blocks like the Serve, an error is returned if the servers can't start
+func RunAutoTLS(domains ...string) error {
+	return Default.RunAutoTLS(domains...)
+}
+
+// RunAutoTLS starts the https server with automatic certificates from Let's Encrypt, for the 'domains' only,
+// the certificates are obtained on the first request of each domain, by the TLS-ALPN-01 or the HTTP-01 challenge,
+// they are cached and they are renewed before they expire, look the Configuration's AutoTLS.
+//
+// A secondary http server, on the AutoTLS.HTTPAddr(:80), answers the HTTP-01 challenges and redirects the rest of the requests to https,
+// it's shut down with the main server.
+//
+// It blocks like the Serve, an error is returned if the servers can't start
+func (s *Framework) RunAutoTLS(domains ...string) error {
+	if len(domains) == 0 {
+		return errAutoTLSNoDomains
+	}
+	c := s.Config.AutoTLS
+	m := newAutoTLSManager(c, domains)
+
+	addr := ParseHost(c.Addr)
+	if s.Config.VHost == "" {
+		s.Config.VHost = domains[0] + ":443"
+		// this will be set as the front-end listening addr
+	}
+	ln, err := TCP4(addr)
+	if err != nil {
+		return err
+	}
+
+	if !c.DisableHTTP {
+		httpLn, err := TCP4(c.HTTPAddr)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		// a nil fallback redirects to https
+		httpSrv := &http.Server{Handler: m.HTTPHandler(nil), ReadTimeout: s.Config.ReadTimeout, WriteTimeout: s.Config.WriteTimeout}
+		go httpSrv.Serve(httpLn)
+		s.OnShutdown(func(ctx context.Context) {
+			httpSrv.Shutdown(ctx)
+		})
+		// the OnShutdown hooks are not fired if the main server can't start
+		defer func() {
+			if !s.IsRunning() {
+				httpSrv.Close()
+			}
+		}()
+	}
+
+	// the TLSConfig adds the acme-tls/1 protocol, for the TLS-ALPN-01 challenges
+	tlsConfig := s.tlsConfig(m.TLSConfig())
+	return s.serveOwned(tls.NewListener(ln, tlsConfig))
+}
+
+// newAutoTLSManager returns the certificates' manager of the 'domains'
+func newAutoTLSManager(c AutoTLSConfiguration, domains []string) *autocert.Manager {
+	m := &autocert.Manager{
+		Prompt:      autocert.AcceptTOS,
+		HostPolicy:  autocert.HostWhitelist(domains...),
+		Email:       c.Email,
+		Cache:       c.Cache,
+		RenewBefore: c.RenewBefore,
+	}
+	if m.Cache == nil && c.CacheDir != "" {
+		m.Cache = autocert.DirCache(c.CacheDir)
+	}
+	if c.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: c.DirectoryURL}
+	}
+	return m
+}
+
+// DefaultRedisAutoTLSCachePrefix the default prefix of the keys of the RedisAutoTLSCache
+const DefaultRedisAutoTLSCachePrefix = "iris-autotls:"
+
+// RedisAutoTLSCache is the Redis autocert.Cache of the automatic certificates,
+// the instances of a horizontally scaled application share their certificates through it.
+//
+// Usage: app.Set(iris.OptionAutoTLSCache(iris.NewRedisAutoTLSCache(client, "")))
+type RedisAutoTLSCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ autocert.Cache = &RedisAutoTLSCache{}
+
+// NewRedisAutoTLSCache returns a new Redis certificates' cache, an empty 'prefix' means the DefaultRedisAutoTLSCachePrefix
+func NewRedisAutoTLSCache(client redis.UniversalClient, prefix string) *RedisAutoTLSCache {
+	if prefix == "" {
+		prefix = DefaultRedisAutoTLSCachePrefix
+	}
+	return &RedisAutoTLSCache{client: client, prefix: prefix}
+}
+
+// Get returns the data of the 'key', or the autocert.ErrCacheMiss
+func (c *RedisAutoTLSCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, autocert.ErrCacheMiss
+	}
+	return data, err
+}
+
+// Put stores the data of the 'key'
+func (c *RedisAutoTLSCache) Put(ctx context.Context, key string, data []byte) error {
+	return c.client.Set(ctx, c.prefix+key, data, 0).Err()
+}
+
+// Delete removes the 'key'
+func (c *RedisAutoTLSCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
@@ -0,0 +1,120 @@
+package iris
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+// BenchmarkRequest a request of the Benchmark
+type BenchmarkRequest struct {
+	// Name the name of the sub-benchmark
+	// Defaults to the method and the path, i.e "GET /users/42"
+	Name string
+	// Method the method of the request
+	// Defaults to "GET"
+	Method string
+	// Path the path, with the query, of the request
+	Path string
+	// Header the headers of the request
+	Header http.Header
+	// Body the body of the request, it's sent on each iteration
+	Body []byte
+	// Status if not zero then the benchmark fails when the response has another status code, i.e a route which doesn't exist
+	// Defaults to 0, any status code
+	Status int
+}
+
+// benchmarkBody the re-readable body of a benchmark's request
+type benchmarkBody struct {
+	bytes.Reader
+}
+
+func (*benchmarkBody) Close() error { return nil }
+
+// benchmarkResponseWriter discards the responses of a benchmark, its headers are reused
+type benchmarkResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *benchmarkResponseWriter) Header() http.Header         { return w.header }
+func (w *benchmarkResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *benchmarkResponseWriter) WriteHeader(status int)      { w.status = status }
+
+// reset clears the response of the previous iteration
+func (w *benchmarkResponseWriter) reset() {
+	for k := range w.header {
+		delete(w.header, k)
+	}
+	w.status = StatusOK
+}
+
+// Benchmark runs a sub-benchmark of each of the 'requests' against the router of the 'app', in-process, with its pooled contexts,
+// it reports the allocations and the p50, p95 and p99 latencies of each one, so the regressions of the routes are caught by the benchmarks of the CI.
+// The app is built if it's not built already.
+//
+// Usage:
+// func BenchmarkAPI(b *testing.B) {
+//	iris.Benchmark(b, app, []iris.BenchmarkRequest{
+//		{Path: "/users/42", Status: iris.StatusOK},
+//		{Method: iris.MethodPost, Path: "/users", Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(`{"name":"kataras"}`)},
+//	})
+// }
+func Benchmark(b *testing.B, app *Framework, requests []BenchmarkRequest) {
+	app.Set(OptionDisableBanner(true))
+	if !app.Plugins.PreBuildFired() {
+		app.Build()
+	}
+	for _, r := range requests {
+		r := r
+		if r.Method == "" {
+			r.Method = MethodGet
+		}
+		if r.Name == "" {
+			r.Name = r.Method + " " + r.Path
+		}
+		b.Run(r.Name, func(b *testing.B) {
+			benchmarkRequest(b, app.Router, r)
+		})
+	}
+}
+
+// benchmarkRequest serves the 'r' b.N times and reports its latency percentiles
+func benchmarkRequest(b *testing.B, h http.Handler, r BenchmarkRequest) {
+	req := httptest.NewRequest(r.Method, r.Path, nil)
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+	body := &benchmarkBody{}
+	req.ContentLength = int64(len(r.Body))
+	w := &benchmarkResponseWriter{header: make(http.Header)}
+	latencies := make([]time.Duration, b.N)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// the router replaces the body, i.e by the MaxRequestBodySize reader
+		body.Reset(r.Body)
+		req.Body = body
+		w.reset()
+		started := time.Now()
+		h.ServeHTTP(w, req)
+		latencies[i] = time.Since(started)
+		if r.Status != 0 && w.status != r.Status {
+			b.Fatalf("%s: expected the status code %d but got %d", r.Name, r.Status, w.status)
+		}
+	}
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	for _, p := range []struct {
+		unit       string
+		percentile float64
+	}{{"p50-ns", .50}, {"p95-ns", .95}, {"p99-ns", .99}} {
+		b.ReportMetric(float64(latencies[int(float64(len(latencies)-1)*p.percentile)]), p.unit)
+	}
+}
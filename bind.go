@@ -0,0 +1,347 @@
+package iris
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/kataras/go-errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	errBindTarget = errors.New("Bind: expected a pointer to a struct but got %T")
+	errBindField  = errors.New("Bind: the %s:%q of the %s field: %s")
+)
+
+// BinderFunc returns the value of the 'name' from a source of the request, i.e a session or the claims of a jwt,
+// and false if it doesn't exist, see the RegisterBinder
+type BinderFunc func(ctx *Context, name string) (interface{}, bool)
+
+// defaultBinders the built'n binding sources, by struct tag
+var defaultBinders = map[string]BinderFunc{
+	// the named path parameters
+	"param": func(ctx *Context, name string) (interface{}, bool) {
+		v := ctx.Param(name)
+		return v, v != ""
+	},
+	// the url query parameters, the fields of slices get all their values
+	"query": func(ctx *Context, name string) (interface{}, bool) {
+		v, ok := ctx.Request.URL.Query()[name]
+		return v, ok
+	},
+	"header": func(ctx *Context, name string) (interface{}, bool) {
+		v, ok := ctx.Request.Header[http.CanonicalHeaderKey(name)]
+		return v, ok
+	},
+	"cookie": func(ctx *Context, name string) (interface{}, bool) {
+		c, err := ctx.Request.Cookie(name)
+		if err != nil {
+			return nil, false
+		}
+		return c.Value, true
+	},
+}
+
+// RegisterBinder registers a binding source to the default iris instance, see the Framework's RegisterBinder
+func RegisterBinder(tag string, fn BinderFunc) {
+	Default.RegisterBinder(tag, fn)
+}
+
+// RegisterBinder registers the 'fn' as the source of the struct fields of the 'tag', which are bound by the ctx.Bind,
+// i.e the values which a middleware provides, it replaces the previous one of the tag and the built'n ones,
+// the "param", "query", "header" and "cookie".
+// The values are assigned to the fields directly, converted, or parsed if they're strings or slices of strings.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.RegisterBinder("session", func(ctx *iris.Context, name string) (interface{}, bool) {
+//	v := ctx.Session().Get(name)
+//	return v, v != nil
+// })
+//
+// type Profile struct {
+//	UserID int    `session:"user_id" validate:"required"`
+//	Bio    string `json:"bio"`
+// }
+func (s *Framework) RegisterBinder(tag string, fn BinderFunc) {
+	if s.binders == nil {
+		s.binders = make(map[string]BinderFunc)
+	}
+	s.binders[tag] = fn
+}
+
+// binder returns the binding source of the 'tag', nil if there is not any
+func (s *Framework) binder(tag string) BinderFunc {
+	if fn, ok := s.binders[tag]; ok {
+		return fn
+	}
+	return defaultBinders[tag]
+}
+
+// Bind reads the request's body to the 'ptr', a pointer to a struct, by its Content-Type, the json, xml, form, msgpack or cbor, if any,
+// then sets its fields which are tagged by a binding source, i.e `param:"id"`, `query:"page"`, `header:"X-Request-Id"`, `cookie:"theme"`
+// or the ones of the RegisterBinder, and validates it with the Validator, whose errors are rendered with the 422 status code.
+// The files of a multipart form are bound to the fields of the *multipart.FileHeader, []*multipart.FileHeader
+// and []byte types, by their form tag or name, with the size and the media type constraints of their `file` tag,
+// i.e `file:"max=2MB,accept=image/png image/jpeg"`, the media type is the one of the file's part.
+//
+// Usage:
+// type UpdateArticle struct {
+//	ID      int    `param:"id"`
+//	Title   string `json:"title" validate:"required"`
+//	Version int    `header:"If-Match"`
+// }
+//
+// type UploadAvatar struct {
+//	Caption string                `form:"caption"`
+//	Avatar  *multipart.FileHeader `form:"avatar" file:"max=2MB,accept=image/*" validate:"required"`
+// }
+// var req UpdateArticle
+// if err := ctx.Bind(&req); err != nil {
+//	return
+// }
+func (ctx *Context) Bind(ptr interface{}) error {
+	val := reflect.ValueOf(ptr)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return errBindTarget.Format(ptr)
+	}
+	if err := ctx.bindBody(ptr); err != nil {
+		return err
+	}
+	if err := ctx.bindSources(val.Elem()); err != nil {
+		return err
+	}
+	errs, err := ctx.bindFiles(val.Elem())
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		ctx.renderValidationError(errs)
+		return errs
+	}
+	return ctx.bound(ptr)
+}
+
+// bindBody reads the request's body to the 'ptr' by its Content-Type, without validating it
+func (ctx *Context) bindBody(ptr interface{}) error {
+	ct := ctx.RequestHeader(contentType)
+	if ctx.Request.Body == nil || ctx.Request.ContentLength == 0 || ct == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return err
+	}
+	var unmarshaler Unmarshaler
+	switch mediaType {
+	case contentJSON:
+		unmarshaler = UnmarshalerFunc(json.Unmarshal)
+		if e := ctx.jsonEngine(); e != nil {
+			unmarshaler = UnmarshalerFunc(e.codec.Unmarshal)
+		}
+	case contentXML, "application/xml":
+		unmarshaler = UnmarshalerFunc(xml.Unmarshal)
+	case contentMsgpack, "application/x-msgpack":
+		unmarshaler = UnmarshalerFunc(msgpack.Unmarshal)
+	case contentCBOR:
+		unmarshaler = UnmarshalerFunc(cbor.Unmarshal)
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		if mediaType == "multipart/form-data" {
+			// the fields and the files, see the bindFiles
+			if err := ctx.Request.ParseMultipartForm(DefaultMultipartMemory); err != nil {
+				return errReadBody.With(err)
+			}
+		}
+		if values := ctx.FormValues(); values != nil {
+			if err := ctx.decodeForm(values, ptr); err != nil {
+				return errReadBody.With(err)
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+	return ctx.unmarshalBody(ptr, unmarshaler)
+}
+
+// bindSources sets the fields of the 'val' which are tagged by a binding source, the embedded and the nested structs too
+func (ctx *Context) bindSources(val reflect.Value) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		fv := val.Field(i)
+		bound, err := ctx.bindField(f, fv)
+		if err != nil {
+			return err
+		}
+		if !bound && fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Time{}) {
+			if err := ctx.bindSources(fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindField sets the 'fv' to the value of the first of its binding sources which has it, it returns true if it's set
+func (ctx *Context) bindField(f reflect.StructField, fv reflect.Value) (bool, error) {
+	for _, tag := range tagKeys(f.Tag) {
+		fn := ctx.framework.binder(tag)
+		if fn == nil {
+			continue
+		}
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		v, ok := fn(ctx, name)
+		if !ok || v == nil {
+			continue
+		}
+		if err := setField(fv, v); err != nil {
+			return false, errBindField.Format(tag, name, f.Name, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// tagKeys returns the keys of a struct tag, in their order
+func tagKeys(tag reflect.StructTag) []string {
+	var keys []string
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		i := strings.Index(s, `:"`)
+		if i <= 0 {
+			return keys
+		}
+		keys = append(keys, s[:i])
+		s = s[i+2:]
+		// skip the quoted value
+		for j := 0; j < len(s); j++ {
+			if s[j] == '\\' {
+				j++
+			} else if s[j] == '"' {
+				s = s[j+1:]
+				break
+			}
+		}
+	}
+}
+
+// setField sets the 'fv' to the 'v', which is assigned, converted or parsed from a string or a slice of strings
+func setField(fv reflect.Value, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	ft := fv.Type()
+	if rv.Type().AssignableTo(ft) {
+		fv.Set(rv)
+		return nil
+	}
+	if ft.Kind() == reflect.Ptr {
+		ptr := reflect.New(ft.Elem())
+		if err := setField(ptr.Elem(), v); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	switch values := v.(type) {
+	case []string:
+		if ft.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(ft, len(values), len(values))
+			for i, s := range values {
+				if err := setString(slice.Index(i), s); err != nil {
+					return err
+				}
+			}
+			fv.Set(slice)
+			return nil
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return setString(fv, values[0])
+	case string:
+		return setString(fv, values)
+	}
+
+	// the numbers of the other kinds, i.e the float64 of the json claims to an int field
+	if isNumberKind(rv.Kind()) && isNumberKind(ft.Kind()) {
+		fv.Set(rv.Convert(ft))
+		return nil
+	}
+	if ft.Kind() == reflect.String {
+		fv.SetString(fmt.Sprint(v))
+		return nil
+	}
+	return fmt.Errorf("%T is not assignable to %s", v, ft)
+}
+
+// setString parses the 's' to the 'fv' by its kind
+func setString(fv reflect.Value, s string) error {
+	if fv.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err == nil {
+			fv.SetInt(int64(d))
+		}
+		return err
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Ptr:
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setString(ptr.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+	default:
+		if u, ok := fv.Addr().Interface().(interface{ UnmarshalText([]byte) error }); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+		return fmt.Errorf("the %s can't be parsed from a string", fv.Type())
+	}
+	return nil
+}
+
+// isNumberKind returns true if the 'k' is an integer or a float
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
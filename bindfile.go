@@ -0,0 +1,199 @@
+package iris
+
+import (
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/go-errors"
+)
+
+// DefaultMultipartMemory the bytes of the multipart forms which the ctx.Bind keeps in memory, the rest are stored to temporary files
+const DefaultMultipartMemory = 32 << 20
+
+var (
+	errFileTag  = errors.New("Bind: the file:%q of the %s field: %s")
+	errFileRead = errors.New("Bind: reading the %q file: %s")
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	bytesType       = reflect.TypeOf([]byte(nil))
+)
+
+// fileConstraints the constraints of the `file` struct tag of a field, i.e `file:"max=2MB,accept=image/png image/jpeg"`
+type fileConstraints struct {
+	// max the size of each file, in bytes
+	max int64
+	// accept the media types of the files, the wildcards of the subtypes too, i.e "image/*"
+	accept []string
+}
+
+// parseFileTag parses the `file` struct tag
+func parseFileTag(tag string) (fileConstraints, error) {
+	var c fileConstraints
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, param := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+		switch name {
+		case "max":
+			n, err := parseByteSize(param)
+			if err != nil {
+				return c, err
+			}
+			c.max = n
+		case "accept":
+			c.accept = strings.Fields(param)
+		default:
+			return c, errors.New("unknown rule " + name)
+		}
+	}
+	return c, nil
+}
+
+// parseByteSize parses the sizes like the "512", "64KB", "2MB" and "1GB", the units are powers of 1024
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * multiplier, nil
+}
+
+// check returns the errors of the 'fh' which violates the constraints
+func (c fileConstraints) check(field string, fh *multipart.FileHeader) ValidationErrors {
+	var errs ValidationErrors
+	if c.max > 0 && fh.Size > c.max {
+		errs = append(errs, FieldError{Field: field, Rule: "max", Code: "max", Param: strconv.FormatInt(c.max, 10),
+			Message: "must be at most " + strconv.FormatInt(c.max, 10) + " bytes"})
+	}
+	if len(c.accept) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(fh.Header.Get(contentType))
+		accepted := false
+		for _, pattern := range c.accept {
+			if ok, _ := path.Match(pattern, mediaType); ok {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			errs = append(errs, FieldError{Field: field, Rule: "accept", Code: "accept", Param: strings.Join(c.accept, " "),
+				Message: "must be one of " + strings.Join(c.accept, ", ")})
+		}
+	}
+	return errs
+}
+
+// bindFiles sets the fields of the 'val' of the *multipart.FileHeader, []*multipart.FileHeader and []byte types
+// to the files of the multipart form, by their form tag or name, it returns the errors of the files which violate their constraints
+func (ctx *Context) bindFiles(val reflect.Value) (ValidationErrors, error) {
+	form := ctx.Request.MultipartForm
+	if form == nil || len(form.File) == 0 {
+		return nil, nil
+	}
+	var errs ValidationErrors
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		fv := val.Field(i)
+		if f.Anonymous && fv.Kind() == reflect.Struct {
+			embeddedErrs, err := ctx.bindFiles(fv)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, embeddedErrs...)
+			continue
+		}
+		if f.Type != fileHeaderType && f.Type != fileHeadersType && f.Type != bytesType {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("form"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		files := form.File[name]
+		if len(files) == 0 {
+			continue
+		}
+		c, err := parseFileTag(f.Tag.Get("file"))
+		if err != nil {
+			return nil, errFileTag.Format(f.Tag.Get("file"), f.Name, err)
+		}
+		if f.Type != fileHeadersType {
+			files = files[:1]
+		}
+		var fieldErrs ValidationErrors
+		for _, fh := range files {
+			fieldErrs = append(fieldErrs, c.check(name, fh)...)
+		}
+		if len(fieldErrs) > 0 {
+			errs = append(errs, fieldErrs...)
+			continue
+		}
+
+		switch f.Type {
+		case fileHeaderType:
+			fv.Set(reflect.ValueOf(files[0]))
+		case fileHeadersType:
+			fv.Set(reflect.ValueOf(files))
+		case bytesType:
+			// the contents are limited to the max of the tag, or to the Config.MaxRequestBodySize
+			limit := c.max
+			if limit <= 0 {
+				if limit = ctx.framework.Config.MaxRequestBodySize; limit <= 0 {
+					limit = DefaultMaxRequestBodySize
+				}
+			}
+			if files[0].Size > limit {
+				errs = append(errs, FieldError{Field: name, Rule: "max", Code: "max", Param: strconv.FormatInt(limit, 10),
+					Message: "must be at most " + strconv.FormatInt(limit, 10) + " bytes"})
+				continue
+			}
+			b, err := readFileHeader(files[0], limit)
+			if err != nil {
+				return nil, errFileRead.Format(files[0].Filename, err)
+			}
+			fv.SetBytes(b)
+		}
+	}
+	return errs, nil
+}
+
+// readFileHeader reads the contents of an uploaded file, up to the 'limit' bytes
+func readFileHeader(fh *multipart.FileHeader, limit int64) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(io.LimitReader(f, limit))
+}
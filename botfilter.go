@@ -0,0 +1,161 @@
+package iris
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultBotUserAgents the parts of the User-Agents of the vulnerability scanners which the UseBotFilter blocks by default, case-insensitive
+	DefaultBotUserAgents = []string{"sqlmap", "nikto", "nmap", "masscan", "zgrab", "nuclei", "wpscan", "dirbuster", "gobuster", "acunetix", "nessus", "openvas"}
+	// DefaultBotPaths the paths which are probed by the scanners and the UseBotFilter blocks by default,
+	// the ones of the php applications and the leaked secrets, case-insensitive prefixes
+	DefaultBotPaths = []string{"/wp-login.php", "/wp-admin", "/xmlrpc.php", "/.env", "/.git/", "/.aws/", "/phpmyadmin", "/cgi-bin/", "/vendor/phpunit", "/admin.php"}
+)
+
+// BotVerdict the result of the BotFilterOptions' Classify
+type BotVerdict int
+
+const (
+	// BotUnknown the request is classified by the User-Agents and the paths of the BotFilterOptions
+	BotUnknown BotVerdict = iota
+	// BotAllow the request is allowed, i.e a known crawler or a monitoring service
+	BotAllow
+	// BotBlock the request is blocked
+	BotBlock
+)
+
+// the reasons of the blocked requests, the label of the iris_blocked_bots_total metric
+const (
+	botReasonUserAgent      = "user_agent"
+	botReasonEmptyUserAgent = "empty_user_agent"
+	botReasonPath           = "path"
+	botReasonCustom         = "custom"
+)
+
+// BotFilterOptions the heuristics of the UseBotFilter
+type BotFilterOptions struct {
+	// UserAgents the case-insensitive parts of the User-Agents which are blocked
+	// Defaults to the DefaultBotUserAgents
+	UserAgents []string
+	// Paths the case-insensitive prefixes of the paths which are blocked
+	// Defaults to the DefaultBotPaths
+	Paths []string
+	// BlockEmptyUserAgent if true then the requests without a User-Agent are blocked, the browsers always send one
+	// Defaults to false
+	BlockEmptyUserAgent bool
+	// Tarpit delays the responses of the blocked requests, so the scanners slow down, the delay ends when the client disconnects
+	// Defaults to 0, no delay
+	Tarpit time.Duration
+	// StatusCode the status code of the blocked requests
+	// Defaults to 403, StatusForbidden
+	StatusCode int
+	// Classify the custom classification of a request, i.e by its ip's reputation,
+	// it runs before the heuristics, which decide the requests of the BotUnknown verdict
+	// Defaults to nil
+	Classify func(ctx *Context) BotVerdict
+}
+
+// botFilter the lowered heuristics of the UseBotFilter
+type botFilter struct {
+	options    BotFilterOptions
+	userAgents []string
+	paths      []string
+}
+
+// UseBotFilter blocks the requests of the scanners and the bots to the default iris instance, see the Framework's UseBotFilter
+func UseBotFilter(options ...BotFilterOptions) {
+	Default.UseBotFilter(options...)
+}
+
+// UseBotFilter blocks the requests of the scanners and the bots by their User-Agents, their paths, i.e the /wp-login.php and the /.env probes,
+// and the custom Classify, before the routing, so the probes of the paths which don't exist are blocked too.
+// The blocked requests are counted by the iris_blocked_bots_total metric, see the EnableMetrics.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.UseBotFilter(iris.BotFilterOptions{Tarpit: 10 * time.Second, Classify: func(ctx *iris.Context) iris.BotVerdict {
+//	if reputation.IsBad(ctx.RemoteAddr()) {
+//		return iris.BotBlock
+//	}
+//	return iris.BotUnknown
+// }})
+func (s *Framework) UseBotFilter(options ...BotFilterOptions) {
+	var o BotFilterOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.UserAgents == nil {
+		o.UserAgents = DefaultBotUserAgents
+	}
+	if o.Paths == nil {
+		o.Paths = DefaultBotPaths
+	}
+	if o.StatusCode <= 0 {
+		o.StatusCode = StatusForbidden
+	}
+	f := &botFilter{options: o}
+	for _, ua := range o.UserAgents {
+		f.userAgents = append(f.userAgents, strings.ToLower(ua))
+	}
+	for _, p := range o.Paths {
+		f.paths = append(f.paths, strings.ToLower(p))
+	}
+	s.botFilter = f
+}
+
+// classify returns the reason of a blocked request, empty if it's allowed
+func (f *botFilter) classify(ctx *Context) string {
+	if f.options.Classify != nil {
+		switch f.options.Classify(ctx) {
+		case BotAllow:
+			return ""
+		case BotBlock:
+			return botReasonCustom
+		}
+	}
+	ua := strings.ToLower(ctx.Request.UserAgent())
+	if ua == "" && f.options.BlockEmptyUserAgent {
+		return botReasonEmptyUserAgent
+	}
+	for _, part := range f.userAgents {
+		if strings.Contains(ua, part) {
+			return botReasonUserAgent
+		}
+	}
+	path := strings.ToLower(ctx.Request.URL.Path)
+	for _, prefix := range f.paths {
+		if strings.HasPrefix(path, prefix) {
+			return botReasonPath
+		}
+	}
+	return ""
+}
+
+// botFilterHandler blocks the requests of the 'h' which the UseBotFilter classifies as bots
+func (s *Framework) botFilterHandler(h http.Handler) http.Handler {
+	f := s.botFilter
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := s.AcquireCtx(w, r)
+		reason := f.classify(ctx)
+		if reason == "" {
+			s.ReleaseCtx(ctx)
+			h.ServeHTTP(w, r)
+			return
+		}
+		if m := s.metrics; m != nil {
+			m.blockedBots.Inc(reason)
+		}
+		if f.options.Tarpit > 0 {
+			t := time.NewTimer(f.options.Tarpit)
+			select {
+			case <-t.C:
+			case <-r.Context().Done():
+				t.Stop()
+			}
+		}
+		s.mux.fireError(f.options.StatusCode, ctx)
+		s.ReleaseCtx(ctx)
+	})
+}
@@ -0,0 +1,182 @@
+package iris
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl builds the Cache-Control header of a response, see the ctx.CacheControl
+type CacheControl struct {
+	ctx *Context
+
+	maxAge               time.Duration
+	sMaxAge              time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	// the max-age, s-maxage and stale-* directives which are set, a zero duration is valid
+	durations map[string]bool
+
+	public          bool
+	private         bool
+	noCache         bool
+	noStore         bool
+	mustRevalidate  bool
+	proxyRevalidate bool
+	noTransform     bool
+	immutable       bool
+}
+
+// CacheControl returns a builder of the response's Cache-Control header, which is set by its Apply
+//
+// Usage:
+// ctx.CacheControl().Public().MaxAge(time.Hour).Immutable().Apply()
+// ctx.CacheControl().NoStore().Apply()
+func (ctx *Context) CacheControl() *CacheControl {
+	return &CacheControl{ctx: ctx, durations: make(map[string]bool)}
+}
+
+// MaxAge sets the max-age directive, the seconds which the response is fresh
+func (c *CacheControl) MaxAge(d time.Duration) *CacheControl {
+	c.maxAge = d
+	c.durations["max-age"] = true
+	return c
+}
+
+// SMaxAge sets the s-maxage directive, the max-age of the shared caches, i.e the CDNs
+func (c *CacheControl) SMaxAge(d time.Duration) *CacheControl {
+	c.sMaxAge = d
+	c.durations["s-maxage"] = true
+	return c
+}
+
+// StaleWhileRevalidate sets the stale-while-revalidate directive
+func (c *CacheControl) StaleWhileRevalidate(d time.Duration) *CacheControl {
+	c.staleWhileRevalidate = d
+	c.durations["stale-while-revalidate"] = true
+	return c
+}
+
+// StaleIfError sets the stale-if-error directive
+func (c *CacheControl) StaleIfError(d time.Duration) *CacheControl {
+	c.staleIfError = d
+	c.durations["stale-if-error"] = true
+	return c
+}
+
+// Public sets the public directive, it removes the private
+func (c *CacheControl) Public() *CacheControl {
+	c.public, c.private = true, false
+	return c
+}
+
+// Private sets the private directive, the shared caches don't store the response, it removes the public
+func (c *CacheControl) Private() *CacheControl {
+	c.private, c.public = true, false
+	return c
+}
+
+// NoCache sets the no-cache directive, the response is revalidated before each use
+func (c *CacheControl) NoCache() *CacheControl {
+	c.noCache = true
+	return c
+}
+
+// NoStore sets the no-store directive, the response is not stored by any cache,
+// the Apply writes only the no-store, the other directives are meaningless with it
+func (c *CacheControl) NoStore() *CacheControl {
+	c.noStore = true
+	return c
+}
+
+// MustRevalidate sets the must-revalidate directive
+func (c *CacheControl) MustRevalidate() *CacheControl {
+	c.mustRevalidate = true
+	return c
+}
+
+// ProxyRevalidate sets the proxy-revalidate directive
+func (c *CacheControl) ProxyRevalidate() *CacheControl {
+	c.proxyRevalidate = true
+	return c
+}
+
+// NoTransform sets the no-transform directive
+func (c *CacheControl) NoTransform() *CacheControl {
+	c.noTransform = true
+	return c
+}
+
+// Immutable sets the immutable directive, the response never changes while it's fresh, i.e the fingerprinted files
+func (c *CacheControl) Immutable() *CacheControl {
+	c.immutable = true
+	return c
+}
+
+// String returns the value of the header, i.e "public, max-age=3600, immutable"
+func (c *CacheControl) String() string {
+	if c.noStore {
+		return "no-store"
+	}
+	var directives []string
+	add := func(ok bool, directive string) {
+		if ok {
+			directives = append(directives, directive)
+		}
+	}
+	seconds := func(directive string, d time.Duration) {
+		if c.durations[directive] {
+			if d < 0 {
+				d = 0
+			}
+			directives = append(directives, directive+"="+strconv.FormatInt(int64(d/time.Second), 10))
+		}
+	}
+	add(c.public, "public")
+	add(c.private, "private")
+	add(c.noCache, "no-cache")
+	seconds("max-age", c.maxAge)
+	seconds("s-maxage", c.sMaxAge)
+	add(c.mustRevalidate, "must-revalidate")
+	add(c.proxyRevalidate, "proxy-revalidate")
+	add(c.noTransform, "no-transform")
+	add(c.immutable, "immutable")
+	seconds("stale-while-revalidate", c.staleWhileRevalidate)
+	seconds("stale-if-error", c.staleIfError)
+	return strings.Join(directives, ", ")
+}
+
+// Apply sets the response's Cache-Control header, it replaces the previous one
+func (c *CacheControl) Apply() {
+	if v := c.String(); v != "" {
+		c.ctx.ResponseWriter.Header().Set(cacheControl, v)
+	}
+}
+
+// ExpiresIn sets the response's Expires header to the time after the 'd',
+// the HTTP/1.0 caches use it, the max-age of the Cache-Control overrides it
+func (ctx *Context) ExpiresIn(d time.Duration) {
+	ctx.ResponseWriter.Header().Set("Expires", ctx.framework.now().Add(d).UTC().Format(http.TimeFormat))
+}
+
+// Vary adds the request 'headers' to the response's Vary header, if they're not already there,
+// so the caches keep a response for each of their values, i.e ctx.Vary("Accept-Language")
+func (ctx *Context) Vary(headers ...string) {
+	h := ctx.ResponseWriter.Header()
+	existing := make(map[string]bool)
+	for _, v := range h[varyHeader] {
+		for _, name := range strings.Split(v, ",") {
+			existing[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	if existing["*"] {
+		return
+	}
+	for _, name := range headers {
+		if name = http.CanonicalHeaderKey(strings.TrimSpace(name)); name != "" && !existing[name] {
+			existing[name] = true
+			h.Add(varyHeader, name)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package iris
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errCacheWarm      = errors.New("Cache warming: %s responded with %d")
+	errCacheWarmPanic = errors.New("Cache warming: %s panicked: %v")
+)
+
+// CacheWarm primes the response caches of the default iris instance, see the Framework's CacheWarm
+func CacheWarm(urls []string, concurrency int) error {
+	return Default.CacheWarm(urls, concurrency)
+}
+
+// CacheWarm serves GET requests of the 'urls' internally, without the network, by the router,
+// so their responses are stored to the response caches of their routes, the cached ones are refreshed.
+// The paths, i.e "/products?page=1", are requested at the VScheme and VHost of the Config,
+// the full urls, i.e "https://example.com/products", should be used when the caches are keyed by another host.
+// Up to 'concurrency' requests are served at the same time, at least one.
+// It returns the first error, a url which responded with a 4xx or 5xx status code or panicked,
+// after all the urls are served.
+//
+// It builds the server, so at the startup it should be called from an OnServe callback,
+// it can be called periodically to keep the caches warm.
+//
+// Usage:
+// app.OnServe(func(net.Addr) error {
+//	go app.CacheWarm([]string{"/", "/products"}, 4)
+//	return nil
+// })
+func (s *Framework) CacheWarm(urls []string, concurrency int) error {
+	s.Build()
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, u := range urls {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(u string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := s.warm(u); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(u)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// warm serves a GET request of the 'u' which is not served from the response caches, so its response is saved
+func (s *Framework) warm(u string) (err error) {
+	if !strings.Contains(u, "://") {
+		u = s.Config.VScheme + s.Config.VHost + u
+	}
+	ctx := context.WithValue(context.Background(), responseCacheRevalidateKey{}, true)
+	req, err := http.NewRequestWithContext(ctx, MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.RemoteAddr = "127.0.0.1:0"
+
+	defer func() {
+		if v := recover(); v != nil {
+			err = errCacheWarmPanic.Format(u, v)
+		}
+	}()
+	w := &discardResponseWriter{header: make(http.Header)}
+	s.Router.ServeHTTP(w, req)
+	if w.statusCode >= StatusBadRequest {
+		return errCacheWarm.Format(u, w.statusCode)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package iris
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// contentCBOR the content type of the CBOR data
+const contentCBOR = "application/cbor"
+
+var (
+	cborEncMode, _              = cbor.EncOptions{}.EncMode()
+	cborDeterministicEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+)
+
+// cborSerializer the serializer of the "application/cbor", it's registered by default, see the RegisterSerializer
+type cborSerializer struct {
+	s *Framework
+}
+
+// Serialize marshals the 'v' to CBOR, deterministically if the Config.CBORDeterministic
+// or the "deterministic" render option is true
+func (c cborSerializer) Serialize(v interface{}, options ...map[string]interface{}) ([]byte, error) {
+	deterministic := c.s.Config.CBORDeterministic
+	if len(options) > 0 {
+		if b, ok := options[0]["deterministic"].(bool); ok {
+			deterministic = b
+		}
+	}
+	if deterministic {
+		return cborDeterministicEncMode.Marshal(v)
+	}
+	return cborEncMode.Marshal(v)
+}
+
+// CBOR marshals the 'v' to CBOR and writes it with the "application/cbor" content type,
+// the cbor struct tags, or the json ones, name the fields
+func (ctx *Context) CBOR(status int, v interface{}) error {
+	return ctx.RenderWithStatus(status, contentCBOR, v)
+}
+
+// ReadCBOR reads the CBOR request's body to the 'v'
+func (ctx *Context) ReadCBOR(v interface{}) error {
+	return ctx.UnmarshalBody(v, UnmarshalerFunc(cbor.Unmarshal))
+}
@@ -0,0 +1,182 @@
+package iris
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCertReloadInterval the interval which the ListenTLS checks the certificate and key files for changes
+const DefaultCertReloadInterval = 1 * time.Minute
+
+// CertReloader keeps the certificate of a certFile and keyFile pair
+// and reloads it when the files are changed, i.e renewed, without restarting the server.
+//
+// Usage:
+// reloader, err := iris.NewCertReloader("mycert.crt", "mykey.key")
+// stop := reloader.Watch(time.Minute, nil)
+// ln, err := iris.GETCERT(":443", reloader.GetCertificate)
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	certTime time.Time
+	keyTime  time.Time
+}
+
+// NewCertReloader loads the certificate of the certFile and keyFile and returns a new CertReloader
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errCertKeyMissing
+	}
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// modTimes returns the modification times of the certificate and key files
+func (r *CertReloader) modTimes() (certTime time.Time, keyTime time.Time, err error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// load (re)loads the certificate, on error the previous one is kept
+func (r *CertReloader) load() error {
+	certTime, keyTime, err := r.modTimes()
+	if err != nil {
+		return errParseTLS.Format(r.certFile, r.keyFile, err)
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errParseTLS.Format(r.certFile, r.keyFile, err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.certTime = certTime
+	r.keyTime = keyTime
+	r.mu.Unlock()
+	return nil
+}
+
+// Reload reloads the certificate if the certificate or the key file has been changed since the last load,
+// on error the current certificate is kept and served
+func (r *CertReloader) Reload() error {
+	certTime, keyTime, err := r.modTimes()
+	if err != nil {
+		return errParseTLS.Format(r.certFile, r.keyFile, err)
+	}
+	r.mu.RLock()
+	changed := !certTime.Equal(r.certTime) || !keyTime.Equal(r.keyTime)
+	r.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return r.load()
+}
+
+// GetCertificate returns the current certificate, it's the tls.Config's GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	cert := r.cert
+	r.mu.RUnlock()
+	return cert, nil
+}
+
+// Watch checks the files for changes every 'interval', on its own goroutine, until the returned stop func is called.
+// The reload errors, i.e a half-written key, are passed to the 'onError', which can be nil
+func (r *CertReloader) Watch(interval time.Duration, onError func(error)) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultCertReloadInterval
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := r.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// GETCERT returns a new TLS Listener which takes the certificate of each connection from the 'getCertificate',
+// i.e a CertReloader's GetCertificate or a certificate store
+func GETCERT(addr string, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (net.Listener, error) {
+	ln, err := TCP4(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return tls.NewListener(ln, getCertTLSConfig(getCertificate)), nil
+}
+
+// getCertTLSConfig returns the tls.Config of the GETCERT listeners
+func getCertTLSConfig(getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) *tls.Config {
+	return &tls.Config{
+		GetCertificate:           getCertificate,
+		PreferServerCipherSuites: true,
+		NextProtos:               []string{"h2", "http/1.1"},
+	}
+}
+
+// ListenTLSGetCertificate starts a https server which takes the certificates from the 'getCertificate', see the Framework's ListenTLSGetCertificate
+func ListenTLSGetCertificate(addr string, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) {
+	Default.ListenTLSGetCertificate(addr, getCertificate)
+}
+
+// ListenTLSGetCertificate starts a https server which takes the certificate of each connection from the 'getCertificate',
+// so the certificates can be changed, i.e renewed, at runtime without restarting the server
+//
+// It panics on error if you need a func to return an error, use the Serve
+// ex: iris.ListenTLSGetCertificate(":443", store.GetCertificate)
+func (s *Framework) ListenTLSGetCertificate(addr string, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) {
+	addr = ParseHost(addr)
+	if s.Config.VHost == "" {
+		s.Config.VHost = addr
+		// this will be set as the front-end listening addr
+	}
+
+	ln, err := s.listenGetCert(addr, getCertificate)
+	if err != nil {
+		s.logPanic(err)
+	}
+	s.Must(s.serveOwned(ln))
+}
+
+// watchCert reloads the certificate of the certFile and keyFile when they're changed, until the server is shut down
+func (s *Framework) watchCert(certFile, keyFile string) (*CertReloader, error) {
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	stop := reloader.Watch(DefaultCertReloadInterval, func(err error) {
+		s.log(LogLevelError, "reloading the certificate", "err", err)
+	})
+	s.OnShutdown(func(context.Context) { stop() })
+	return reloader, nil
+}
@@ -0,0 +1,154 @@
+package iris
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+var errRandomID = errors.New("RandomIDGenerator: the random source failed. Trace: %s")
+
+// requestIDContextKey the context's value of the id of the request, see the ctx.RequestID
+const requestIDContextKey = "iris.requestid"
+
+// Clock tells the time to the framework, the expiration of the stateless sessions and of the cached responses,
+// the rate and the login limits, the error reports, see the Framework's Clock field.
+// The tests replace it with a FakeClock to move the time forward without waiting.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// ClockFunc the func which implements the Clock
+type ClockFunc func() time.Time
+
+// Now returns the time of the func
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock the Clock of the time.Now, which is used by default
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FakeClock is the Clock of the tests, its time changes only by its Set and Advance
+//
+// Usage:
+// clock := iris.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+// app.Clock = clock
+// e.GET("/").Expect().Status(iris.StatusOK)
+// clock.Advance(time.Hour)
+// e.GET("/").Expect().Status(iris.StatusTooManyRequests)
+type FakeClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+var _ Clock = &FakeClock{}
+
+// NewFakeClock returns a new FakeClock whose time is the 'now'
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set sets the time of the clock to the 'now'
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	c.now = now
+	c.mu.Unlock()
+}
+
+// Advance moves the time of the clock forward by the 'd'
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// IDGenerator generates the ids of the framework, the ids of the stateless sessions, of the requests, see the ctx.RequestID,
+// and of the websocket servers and connections, see the Framework's IDGenerator field.
+// The tests replace it with a SequentialIDGenerator to predict the ids.
+type IDGenerator interface {
+	// NewID returns a new, unique, id
+	NewID() string
+}
+
+// IDGeneratorFunc the func which implements the IDGenerator
+type IDGeneratorFunc func() string
+
+// NewID returns the id of the func
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// RandomIDGenerator the IDGenerator of the hex-encoded 16 crypto-random bytes, which is used by default
+var RandomIDGenerator IDGenerator = IDGeneratorFunc(newRandomID)
+
+// newRandomID returns the hex of 16 crypto-random bytes, it panics if the random source fails,
+// a predictable id, i.e of a session, can't be used instead
+func newRandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(errRandomID.Format(err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// SequentialIDGenerator is the IDGenerator of the tests, its ids are its prefix followed by 1, 2, 3 and so on
+type SequentialIDGenerator struct {
+	prefix string
+	n      uint64
+}
+
+var _ IDGenerator = &SequentialIDGenerator{}
+
+// NewSequentialIDGenerator returns a new SequentialIDGenerator of the 'prefix', i.e "id-"
+func NewSequentialIDGenerator(prefix string) *SequentialIDGenerator {
+	return &SequentialIDGenerator{prefix: prefix}
+}
+
+// NewID returns the next id
+func (g *SequentialIDGenerator) NewID() string {
+	return g.prefix + strconv.FormatUint(atomic.AddUint64(&g.n, 1), 10)
+}
+
+// now returns the time of the Clock, the SystemClock's if it's nil
+func (s *Framework) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+// newID returns a new id of the IDGenerator, the RandomIDGenerator's if it's nil
+func (s *Framework) newID() string {
+	if s.IDGenerator == nil {
+		return newRandomID()
+	}
+	return s.IDGenerator.NewID()
+}
+
+// RequestID returns the id of the request, its X-Request-Id header if it's sent, otherwise a new id of the Framework's IDGenerator,
+// the same one on each call of the request
+func (ctx *Context) RequestID() string {
+	if id, ok := ctx.Get(requestIDContextKey).(string); ok {
+		return id
+	}
+	id := ctx.RequestHeader("X-Request-Id")
+	if id == "" {
+		id = ctx.framework.newID()
+	}
+	ctx.Set(requestIDContextKey, id)
+	return id
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/imdario/mergo"
 	"github.com/kataras/go-options"
 	"github.com/kataras/go-sessions"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 type (
@@ -71,6 +72,21 @@ type Configuration struct {
 
 	ReadTimeout  time.Duration // maximum duration before timing out read of the request
 	WriteTimeout time.Duration // maximum duration before timing out write of the response
+	// ReadHeaderTimeout is the amount of time allowed to read the request headers,
+	// if zero the ReadTimeout is used
+	ReadHeaderTimeout time.Duration
+	// IdleTimeout is the maximum amount of time to wait for the next request when keep-alives are enabled,
+	// if zero the ReadTimeout is used,
+	// if both are zero and the connections are limited, by the MaxConnections or the MaxConnectionsPerIP, the DefaultLimitedIdleTimeout is used
+	IdleTimeout time.Duration
+	// MaxConnections limits the number of the concurrent connections of the main server,
+	// the new connections wait until one of the open connections is closed.
+	// If zero, no limit
+	MaxConnections int
+	// MaxConnectionsPerIP limits the number of the concurrent connections of a client's IP to the main server,
+	// the connections over the limit are closed on their first read.
+	// If zero, no limit
+	MaxConnectionsPerIP int
 
 	// MaxHeaderBytes controls the maximum number of bytes the
 	// server will read parsing the request header's keys and
@@ -81,6 +97,13 @@ type Configuration struct {
 	// MaxRequestBodySize limit the maximum size of request body the server will read
 	// If zero, DefaultMaxRequestBodySize is used.
 	MaxRequestBodySize int64
+	// MaxFormKeys limit the number of the form and query keys which the ctx.ReadForm, ReadQuery and Bind decode
+	// If zero, DefaultMaxFormKeys is used.
+	MaxFormKeys int
+	// MaxFormSliceIndex limit the highest index of the slices of the form and query keys, i.e the items[999],
+	// so a request can't allocate a huge slice
+	// If zero, DefaultMaxFormSliceIndex is used.
+	MaxFormSliceIndex int
 	// TLSNextProto optionally specifies a function to take over
 	// ownership of the provided TLS connection when an NPN/ALPN
 	// protocol upgrade has occurred. The map key is the protocol
@@ -160,6 +183,11 @@ type Configuration struct {
 	//
 	// Default is [IRIS]
 	LoggerPreffix string
+	// LogLevel the minimum level of the framework's entries which are written to the Logger,
+	// it's ignored when a StructuredLogger is set by the SetLogger
+	//
+	// Default is LogLevelInfo
+	LogLevel LogLevel
 
 	// DisableTemplateEngines set to true to disable loading the default template engine (html/template) and disallow the use of iris.UseEngine
 	// Defaults to false
@@ -170,6 +198,19 @@ type Configuration struct {
 	// Defaults to false
 	IsDevelopment bool
 
+	// DumpRequests if true the DumpHandler logs the dumps of all the requests, not only of the ones with its header
+	// Defaults to false
+	DumpRequests bool
+
+	// AllowedHosts the hostnames which the server answers to, the requests with a different Host header
+	// are rejected with 421 Misdirected Request, and the requests without a valid one with 400 Bad Request, before the routing.
+	// The requests without a Host header, i.e the HTTP/1.0 health checks of some load balancers, are rejected with the 400 too,
+	// their checks should send one of the AllowedHosts.
+	// It prevents the Host header injection, i.e to the absolute urls of the password reset emails, and the DNS rebinding.
+	// The "*.example.com" matches the subdomains of the example.com
+	// Defaults to empty, all hosts are allowed
+	AllowedHosts []string
+
 	// TimeFormat time format for any kind of datetime parsing
 	TimeFormat string
 
@@ -183,12 +224,49 @@ type Configuration struct {
 	// Defaults to false
 	Gzip bool
 
+	// CBORDeterministic encodes the ctx.CBOR responses with the Core Deterministic Encoding of the RFC 8949,
+	// the map keys are sorted and the integers, floats and lengths are the shortest, so the same values are encoded to the same bytes,
+	// i.e for the signatures and the hashes of the constrained devices
+	// Defaults to false
+	CBORDeterministic bool
+
+	// JSON the options of the ctx.JSON of all the routes, the UseJSONCodec's and the ctx.JSON's options override them.
+	// In development, see the IsDevelopment, the json is indented and the encoding errors are written to the client by default,
+	// otherwise it's compact, with the <, > and & escaped
+	// Defaults to empty JSONOptions
+	JSON JSONOptions
+
+	// XML the options of the ctx.XML of all the routes, i.e the root element, the namespaces and the declaration
+	// of a legacy integration, the ctx.XML's options override them
+	// Defaults to empty XMLOptions
+	XML XMLOptions
+
+	// ParamEncryptionKey the AES key(16, 24 or 32 bytes) of the EncryptParam, the DecryptParam and the EncryptedParams,
+	// the opaque tokens of the path parameters
+	// Defaults to empty, the params are not encrypted
+	ParamEncryptionKey []byte
+
 	// Sessions contains the configs for sessions
 	Sessions SessionsConfiguration
 
+	// JWTSessions contains the configs for the stateless sessions,
+	// when its Secret is not empty the Context.Session() keeps the whole session inside a signed (and optionally encrypted) JWT cookie,
+	// named after the Sessions.Cookie, and nothing is stored server-side.
+	JWTSessions JWTSessionsConfiguration
+
 	// Websocket contains the configs for Websocket's server integration
 	Websocket WebsocketConfiguration
 
+	// AutoTLS contains the configs for the automatic, Let's Encrypt, certificates of the RunAutoTLS
+	AutoTLS AutoTLSConfiguration
+
+	// HTTP2 contains the configs for the HTTP/2 of the main server and the additional hosts
+	HTTP2 HTTP2Configuration
+
+	// TLS contains the versions, the curves and the cipher suites of the ListenTLS, ListenTLSGetCertificate and RunAutoTLS servers,
+	// see the TLSModern, TLSIntermediate and TLSOld presets
+	TLS TLSConfiguration
+
 	// Other are the custom, dynamic options, can be empty
 	// this fill used only by you to set any app's options you want
 	// for each of an Iris instance
@@ -246,6 +324,36 @@ var (
 			c.WriteTimeout = val
 		}
 	}
+	// ReadHeaderTimeout is the amount of time allowed to read the request headers,
+	// if zero the ReadTimeout is used
+	OptionReadHeaderTimeout = func(val time.Duration) OptionSet {
+		return func(c *Configuration) {
+			c.ReadHeaderTimeout = val
+		}
+	}
+	// IdleTimeout is the maximum amount of time to wait for the next request when keep-alives are enabled,
+	// if zero the ReadTimeout is used, if both are zero and the connections are limited the DefaultLimitedIdleTimeout is used
+	OptionIdleTimeout = func(val time.Duration) OptionSet {
+		return func(c *Configuration) {
+			c.IdleTimeout = val
+		}
+	}
+	// MaxConnections limits the number of the concurrent connections of the main server,
+	// the new connections wait until one of the open connections is closed.
+	// If zero, no limit
+	OptionMaxConnections = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.MaxConnections = val
+		}
+	}
+	// MaxConnectionsPerIP limits the number of the concurrent connections of a client's IP to the main server,
+	// the connections over the limit are closed on their first read.
+	// If zero, no limit
+	OptionMaxConnectionsPerIP = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.MaxConnectionsPerIP = val
+		}
+	}
 
 	// MaxHeaderBytes controls the maximum number of bytes the
 	// server will read parsing the request header's keys and
@@ -264,6 +372,20 @@ var (
 			c.MaxRequestBodySize = val
 		}
 	}
+	// OptionMaxFormKeys limit the number of the form and query keys which are decoded
+	// If zero, DefaultMaxFormKeys(1000) is used.
+	OptionMaxFormKeys = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.MaxFormKeys = val
+		}
+	}
+	// OptionMaxFormSliceIndex limit the highest index of the slices of the form and query keys
+	// If zero, DefaultMaxFormSliceIndex(1000) is used.
+	OptionMaxFormSliceIndex = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.MaxFormSliceIndex = val
+		}
+	}
 	// TLSNextProto optionally specifies a function to take over
 	// ownership of the provided TLS connection when an NPN/ALPN
 	// protocol upgrade has occurred. The map key is the protocol
@@ -374,6 +496,16 @@ var (
 		}
 	}
 
+	// OptionLogLevel the minimum level of the framework's entries which are written to the Logger,
+	// it's ignored when a StructuredLogger is set by the SetLogger
+	//
+	// Default is LogLevelInfo
+	OptionLogLevel = func(val LogLevel) OptionSet {
+		return func(c *Configuration) {
+			c.LogLevel = val
+		}
+	}
+
 	// OptionDisableTemplateEngines set to true to disable loading the default template engine (html/template) and disallow the use of iris.UseEngine
 	// Default is false
 	OptionDisableTemplateEngines = func(val bool) OptionSet {
@@ -391,6 +523,23 @@ var (
 		}
 	}
 
+	// OptionDumpRequests if true the DumpHandler logs the dumps of all the requests, not only of the ones with its header
+	// Default is false
+	OptionDumpRequests = func(val bool) OptionSet {
+		return func(c *Configuration) {
+			c.DumpRequests = val
+		}
+	}
+
+	// OptionAllowedHosts the hostnames which the server answers to, the "*.example.com" matches the subdomains of the example.com,
+	// the requests with a different Host header are rejected before the routing
+	// Defaults to empty, all hosts are allowed
+	OptionAllowedHosts = func(val ...string) OptionSet {
+		return func(c *Configuration) {
+			c.AllowedHosts = val
+		}
+	}
+
 	// OptionTimeFormat time format for any kind of datetime parsing
 	OptionTimeFormat = func(val string) OptionSet {
 		return func(c *Configuration) {
@@ -416,6 +565,38 @@ var (
 		}
 	}
 
+	// OptionCBORDeterministic encodes the ctx.CBOR responses with the Core Deterministic Encoding of the RFC 8949
+	// Default is false
+	OptionCBORDeterministic = func(val bool) OptionSet {
+		return func(c *Configuration) {
+			c.CBORDeterministic = val
+		}
+	}
+
+	// OptionJSON the options of the ctx.JSON of all the routes
+	// Default is empty JSONOptions
+	OptionJSON = func(val JSONOptions) OptionSet {
+		return func(c *Configuration) {
+			c.JSON = val
+		}
+	}
+
+	// OptionXML the options of the ctx.XML of all the routes
+	// Default is empty XMLOptions
+	OptionXML = func(val XMLOptions) OptionSet {
+		return func(c *Configuration) {
+			c.XML = val
+		}
+	}
+
+	// OptionParamEncryptionKey the AES key(16, 24 or 32 bytes) of the encrypted path parameters, see the EncryptParam
+	// Default is empty
+	OptionParamEncryptionKey = func(val []byte) OptionSet {
+		return func(c *Configuration) {
+			c.ParamEncryptionKey = val
+		}
+	}
+
 	// OptionOther are the custom, dynamic options, can be empty
 	// this fill used only by you to set any app's options you want
 	// for each of an Iris instance
@@ -455,6 +636,10 @@ const (
 	DefaultMaxHeaderBytes = 8096
 	//This is the default value (10MB) that limits the size of request body
 	DefaultMaxRequestBodySize int64 = 10 << 20
+	// DefaultMaxFormKeys the default limit of the number of the form and query keys which are decoded
+	DefaultMaxFormKeys = 1000
+	// DefaultMaxFormSliceIndex the default limit of the highest index of the slices of the form and query keys
+	DefaultMaxFormSliceIndex = 1000
 	// DefaultReadTimeout no read client timeout
 	DefaultReadTimeout = 0
 	// DefaultWriteTimeout no serve client timeout
@@ -475,6 +660,8 @@ func DefaultConfiguration() Configuration {
 		WriteTimeout:           DefaultWriteTimeout,
 		MaxHeaderBytes:         DefaultMaxHeaderBytes,
 		MaxRequestBodySize:     DefaultMaxRequestBodySize,
+		MaxFormKeys:            DefaultMaxFormKeys,
+		MaxFormSliceIndex:      DefaultMaxFormSliceIndex,
 		CheckForUpdates:        false,
 		CheckForUpdatesSync:    false,
 		DisablePathCorrection:  DefaultDisablePathCorrection,
@@ -483,13 +670,18 @@ func DefaultConfiguration() Configuration {
 		DisableBanner:          false,
 		LoggerOut:              DefaultLoggerOut,
 		LoggerPreffix:          DefaultLoggerPreffix,
+		LogLevel:               LogLevelInfo,
 		DisableTemplateEngines: false,
 		IsDevelopment:          false,
 		TimeFormat:             DefaultTimeFormat,
 		Charset:                DefaultCharset,
 		Gzip:                   false,
 		Sessions:               DefaultSessionsConfiguration(),
+		JWTSessions:            DefaultJWTSessionsConfiguration(),
 		Websocket:              DefaultWebsocketConfiguration(),
+		AutoTLS:                DefaultAutoTLSConfiguration(),
+		HTTP2:                  DefaultHTTP2Configuration(),
+		TLS:                    DefaultTLSConfiguration(),
 		Other:                  options.Options{},
 	}
 }
@@ -590,6 +782,291 @@ func DefaultSessionsConfiguration() SessionsConfiguration {
 	}
 }
 
+// JWTSessionsConfiguration the configuration for the stateless, JWT cookie-backed, sessions
+type JWTSessionsConfiguration struct {
+	// Secret the key which signs the session's token (HMAC-SHA256)
+	// the stateless mode is enabled only when this field is not empty
+	// Defaults to empty
+	Secret []byte
+	// EncryptionKey if not empty the session's values are encrypted with AES-GCM before signed,
+	// the key should be 16, 24 or 32 bytes long in order to select AES-128, AES-192, or AES-256
+	// Defaults to empty, values are only signed
+	EncryptionKey []byte
+	// Expires the duration of which the token and its cookie expires
+	// Defaults to 0, the cookie is removed when the browser closes
+	Expires time.Duration
+	// MaxCookieSize the maximum size of the session's cookie in bytes,
+	// if the encoded session is larger than that then its changes are not saved and an error is logged
+	// Defaults to 4096
+	MaxCookieSize int
+	// MaxRevoked the maximum number of the remotely destroyed sessions which are remembered until their tokens expire,
+	// the oldest ones are forgotten first and their tokens are accepted again,
+	// it's the only bound of the revoked sessions when the Expires is 0
+	// Defaults to 10000
+	MaxRevoked int
+}
+
+var (
+	// OptionJWTSessionsSecret enables the stateless sessions, the key which signs the session's token
+	OptionJWTSessionsSecret = func(val []byte) OptionSet {
+		return func(c *Configuration) {
+			c.JWTSessions.Secret = val
+		}
+	}
+
+	// OptionJWTSessionsEncryptionKey the AES key(16, 24 or 32 bytes) which encrypts the session's values
+	// Defaults to empty, values are only signed
+	OptionJWTSessionsEncryptionKey = func(val []byte) OptionSet {
+		return func(c *Configuration) {
+			c.JWTSessions.EncryptionKey = val
+		}
+	}
+
+	// OptionJWTSessionsExpires the duration of which the token and its cookie expires
+	// Defaults to 0, the cookie is removed when the browser closes
+	OptionJWTSessionsExpires = func(val time.Duration) OptionSet {
+		return func(c *Configuration) {
+			c.JWTSessions.Expires = val
+		}
+	}
+
+	// OptionJWTSessionsMaxCookieSize the maximum size of the session's cookie in bytes
+	// Defaults to 4096
+	OptionJWTSessionsMaxCookieSize = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.JWTSessions.MaxCookieSize = val
+		}
+	}
+
+	// OptionJWTSessionsMaxRevoked the maximum number of the remotely destroyed sessions which are remembered
+	// Defaults to 10000
+	OptionJWTSessionsMaxRevoked = func(val int) OptionSet {
+		return func(c *Configuration) {
+			c.JWTSessions.MaxRevoked = val
+		}
+	}
+)
+
+// DefaultJWTSessionsConfiguration the default configs for the stateless sessions, disabled by default
+func DefaultJWTSessionsConfiguration() JWTSessionsConfiguration {
+	return JWTSessionsConfiguration{
+		MaxCookieSize: DefaultJWTSessionsMaxCookieSize,
+		MaxRevoked:    DefaultJWTSessionsMaxRevoked,
+	}
+}
+
+// AutoTLSConfiguration the configuration for the automatic certificates of the RunAutoTLS
+type AutoTLSConfiguration struct {
+	// Email the contact email of the ACME account, the certificate authority sends the expiration notices there
+	// Defaults to empty
+	Email string
+	// CacheDir the directory which keeps the certificates and the account key, it's used when the Cache is nil
+	// Defaults to "./certcache"
+	CacheDir string
+	// Cache keeps the certificates and the account key, i.e the iris.RedisAutoTLSCache,
+	// the certificates should be cached, otherwise the rate limits of the certificate authority are reached quickly
+	// Defaults to nil, the CacheDir is used
+	Cache autocert.Cache
+	// RenewBefore the certificates are renewed this long before they expire
+	// Default value is 30 days
+	RenewBefore time.Duration
+	// DirectoryURL the ACME directory of the certificate authority, i.e the Let's Encrypt's staging one for testing
+	// Defaults to the Let's Encrypt's production directory
+	DirectoryURL string
+	// Addr the address of the https server
+	// Defaults to ":443"
+	Addr string
+	// HTTPAddr the address of the secondary http server, which answers the HTTP-01 challenges and redirects the rest of the requests to https
+	// Defaults to ":80"
+	HTTPAddr string
+	// DisableHTTP if true then the secondary http server is not started, only the TLS-ALPN-01 challenges are answered
+	// Defaults to false
+	DisableHTTP bool
+}
+
+var (
+	// OptionAutoTLSEmail the contact email of the ACME account
+	OptionAutoTLSEmail = func(val string) OptionSet {
+		return func(c *Configuration) {
+			c.AutoTLS.Email = val
+		}
+	}
+
+	// OptionAutoTLSCache keeps the certificates and the account key, i.e the iris.RedisAutoTLSCache
+	// Defaults to nil, the CacheDir is used
+	OptionAutoTLSCache = func(val autocert.Cache) OptionSet {
+		return func(c *Configuration) {
+			c.AutoTLS.Cache = val
+		}
+	}
+
+	// OptionAutoTLSDisableHTTP if true then the secondary, :80, http server is not started
+	// Defaults to false
+	OptionAutoTLSDisableHTTP = func(val bool) OptionSet {
+		return func(c *Configuration) {
+			c.AutoTLS.DisableHTTP = val
+		}
+	}
+)
+
+const (
+	// DefaultAutoTLSCacheDir "./certcache"
+	DefaultAutoTLSCacheDir = "./certcache"
+	// DefaultAutoTLSRenewBefore 30 days
+	DefaultAutoTLSRenewBefore = 30 * 24 * time.Hour
+)
+
+// DefaultAutoTLSConfiguration the default configs for the automatic certificates
+func DefaultAutoTLSConfiguration() AutoTLSConfiguration {
+	return AutoTLSConfiguration{
+		CacheDir:    DefaultAutoTLSCacheDir,
+		RenewBefore: DefaultAutoTLSRenewBefore,
+		Addr:        ":443",
+		HTTPAddr:    ":80",
+	}
+}
+
+// HTTP2Configuration the configuration for the HTTP/2 of the servers, the zero values are the golang.org/x/net/http2 defaults.
+// The HTTP/2 is negotiated on the TLS listeners, it's disabled when the TLSNextProto is set
+type HTTP2Configuration struct {
+	// MaxConcurrentStreams the number of the concurrent streams that each client may have open at a time
+	// Defaults to 0, 250 streams
+	MaxConcurrentStreams uint32
+	// MaxReadFrameSize the largest frame this server is willing to read, between 16KB and 16MB
+	// Defaults to 0, 1MB
+	MaxReadFrameSize uint32
+	// MaxUploadBufferPerConnection the size of the initial flow control window for each connection
+	// Defaults to 0, 1MB
+	MaxUploadBufferPerConnection int32
+	// MaxUploadBufferPerStream the size of the initial flow control window for each stream
+	// Defaults to 0, 1MB
+	MaxUploadBufferPerStream int32
+	// IdleTimeout how long until idle clients are closed with a GOAWAY frame
+	// Defaults to 0, the server's IdleTimeout
+	IdleTimeout time.Duration
+	// H2C if true then the cleartext HTTP/2 (h2c), the prior knowledge and the Upgrade: h2c, is served on the non TLS listeners,
+	// i.e for the gRPC clients behind a trusted proxy. It should not be enabled on public listeners
+	// Defaults to false
+	H2C bool
+}
+
+var (
+	// OptionHTTP2MaxConcurrentStreams the number of the concurrent streams that each client may have open at a time
+	// Defaults to 0, 250 streams
+	OptionHTTP2MaxConcurrentStreams = func(val uint32) OptionSet {
+		return func(c *Configuration) {
+			c.HTTP2.MaxConcurrentStreams = val
+		}
+	}
+
+	// OptionHTTP2MaxReadFrameSize the largest frame this server is willing to read, between 16KB and 16MB
+	// Defaults to 0, 1MB
+	OptionHTTP2MaxReadFrameSize = func(val uint32) OptionSet {
+		return func(c *Configuration) {
+			c.HTTP2.MaxReadFrameSize = val
+		}
+	}
+
+	// OptionH2C if true then the cleartext HTTP/2 is served on the non TLS listeners
+	// Defaults to false
+	OptionH2C = func(val bool) OptionSet {
+		return func(c *Configuration) {
+			c.HTTP2.H2C = val
+		}
+	}
+)
+
+// DefaultHTTP2Configuration the default configs for the HTTP/2, the golang.org/x/net/http2 defaults
+func DefaultHTTP2Configuration() HTTP2Configuration {
+	return HTTP2Configuration{}
+}
+
+// TLSConfiguration the configuration for the TLS of the servers, the zero values are the crypto/tls defaults.
+// A warning is logged on start when the MinVersion is older than the TLS 1.2 or an insecure cipher suite is chosen
+type TLSConfiguration struct {
+	// MinVersion the oldest TLS version which is accepted, i.e tls.VersionTLS12
+	// Defaults to 0, the crypto/tls default, TLS 1.2
+	MinVersion uint16
+	// MaxVersion the newest TLS version which is accepted
+	// Defaults to 0, the newest which the crypto/tls supports
+	MaxVersion uint16
+	// CurvePreferences the elliptic curves of the ECDHE handshakes, in preference order
+	// Defaults to nil, the crypto/tls default
+	CurvePreferences []tls.CurveID
+	// CipherSuites the cipher suites of the TLS 1.0 - 1.2 connections, the TLS 1.3 ones are not configurable
+	// Defaults to nil, the crypto/tls secure defaults
+	CipherSuites []uint16
+}
+
+// isZero returns true if nothing is configured
+func (c TLSConfiguration) isZero() bool {
+	return c.MinVersion == 0 && c.MaxVersion == 0 && len(c.CurvePreferences) == 0 && len(c.CipherSuites) == 0
+}
+
+var (
+	// OptionTLS the versions, the curves and the cipher suites of the TLS servers, i.e the iris.TLSModern()
+	// Default is the crypto/tls defaults
+	OptionTLS = func(val TLSConfiguration) OptionSet {
+		return func(c *Configuration) {
+			c.TLS = val
+		}
+	}
+)
+
+// DefaultTLSConfiguration the default configs for the TLS, the crypto/tls defaults
+func DefaultTLSConfiguration() TLSConfiguration {
+	return TLSConfiguration{}
+}
+
+// TLSModern the "modern" TLS configuration of the Mozilla's recommendations, the TLS 1.3 only,
+// for the services whose clients are recent, i.e the internal or the mobile apps' ones
+func TLSModern() TLSConfiguration {
+	return TLSConfiguration{
+		MinVersion:       tls.VersionTLS13,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+	}
+}
+
+// TLSIntermediate the "intermediate" TLS configuration of the Mozilla's recommendations, the TLS 1.2 and 1.3
+// with the forward secrecy and AEAD cipher suites only, for the general-purpose servers
+func TLSIntermediate() TLSConfiguration {
+	return TLSConfiguration{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
+
+// TLSOld the "old" TLS configuration of the Mozilla's recommendations, the TLS 1.0 to 1.3
+// with the CBC and the non forward secrecy cipher suites, only for the legacy clients which can't be upgraded,
+// it's logged as weak on start
+func TLSOld() TLSConfiguration {
+	c := TLSIntermediate()
+	c.MinVersion = tls.VersionTLS10
+	c.CipherSuites = append(c.CipherSuites,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+		tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+		tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+		tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+	)
+	return c
+}
+
 // WebsocketConfiguration the config contains options for the Websocket main config field
 type WebsocketConfiguration struct {
 	// WriteTimeout time allowed to write a message to the connection.
@@ -761,6 +1238,103 @@ func DefaultWebsocketConfiguration() WebsocketConfiguration {
 	}
 }
 
+// RealtimeConfiguration the configs for the websocket servers which are created by the app.NewWebsocketServer
+type RealtimeConfiguration struct {
+	// WriteTimeout time allowed to write a message to the connection.
+	// Default value is 15 * time.Second
+	WriteTimeout time.Duration
+	// PongTimeout time allowed to read the next frame, a pong or a message, from the connection,
+	// the connection is considered dead and it's closed if it's exceeded.
+	// Default value is 60 * time.Second
+	PongTimeout time.Duration
+	// PingPeriod send ping messages to the connection with this period, it must be less than the PongTimeout.
+	// A negative value disables the pings and the PongTimeout, the dead connections are detected by the operating system only.
+	// Default value is (PongTimeout * 9) / 10
+	PingPeriod time.Duration
+	// MaxMessageSize max message size allowed from connection, the connection is closed on larger messages
+	// Default value is 65536
+	MaxMessageSize int64
+	// Error specifies the function for generating HTTP error responses on a failed handshake.
+	//
+	// The default behavior is to store the reason in the context (ctx.Set(reason)) and fire any custom error (ctx.EmitError(status))
+	Error func(ctx *Context, status int, reason error)
+	// CheckOrigin returns true if the request Origin header is acceptable.
+	//
+	// The default behavior is to allow all origins
+	// you can change this behavior by setting the CheckOrigin = iris.WebsocketCheckSameOrigin
+	CheckOrigin func(r *http.Request) bool
+	// Compression enables the permessage-deflate extension, the messages are compressed when the client supports it
+	// Defaults to false
+	Compression bool
+	// CompressionLevel the compress/flate level of the compressed messages, from flate.HuffmanOnly(-2) to flate.BestCompression(9)
+	// Default value is 1, the flate.BestSpeed
+	CompressionLevel int
+	// CompressionThreshold the messages which are smaller than that, in bytes, are not compressed
+	// Default value is 512
+	CompressionThreshold int
+	// CompressionContextTakeover if true then the compression's context is kept between the messages of a connection,
+	// when the client accepts it, the ratio is better but each connection keeps its compressor and a 32KB window.
+	// Defaults to false, each message is compressed on its own
+	CompressionContextTakeover bool
+	// LongPollHoldTimeout the time which a long-polling request waits for messages before it's answered with an empty list
+	// Default value is 25 * time.Second
+	LongPollHoldTimeout time.Duration
+	// LongPollSessionTimeout the long-polling connections are closed if the client doesn't poll for that time,
+	// it should be greater than the LongPollHoldTimeout
+	// Default value is 60 * time.Second
+	LongPollSessionTimeout time.Duration
+	// MaxConnections the limit of the server's connections, the new ones are rejected with StatusServiceUnavailable
+	// Defaults to 0, unlimited
+	MaxConnections int
+	// MaxConnectionsPerIP the limit of the connections of a client's ip, the new ones are rejected with StatusTooManyRequests
+	// Defaults to 0, unlimited
+	MaxConnectionsPerIP int
+	// SendQueueSize the number of the outgoing messages which can wait to be written to a connection
+	// Default value is 256
+	SendQueueSize int
+	// SlowConsumer decides what happens to a message when the send queue of its connection is full,
+	// RealtimeSlowConsumerClose, RealtimeSlowConsumerDropNewest or RealtimeSlowConsumerDropOldest
+	// Defaults to RealtimeSlowConsumerClose, the connection is closed
+	SlowConsumer RealtimeSlowConsumerPolicy
+	// Codec encodes and decodes the messages and their data, RealtimeJSON, RealtimeMsgpack, RealtimeProtobuf or a custom RealtimeCodec,
+	// the long-polling transport is available only with the RealtimeJSON
+	// Defaults to RealtimeJSON
+	Codec RealtimeCodec
+}
+
+const (
+	// DefaultRealtimeMaxMessageSize 65536
+	DefaultRealtimeMaxMessageSize = 64 << 10
+	// DefaultRealtimeCompressionLevel 1, the flate.BestSpeed
+	DefaultRealtimeCompressionLevel = 1
+	// DefaultRealtimeCompressionThreshold 512
+	DefaultRealtimeCompressionThreshold = 512
+	// DefaultRealtimeLongPollHoldTimeout 25 * time.Second
+	DefaultRealtimeLongPollHoldTimeout = 25 * time.Second
+	// DefaultRealtimeLongPollSessionTimeout 60 * time.Second
+	DefaultRealtimeLongPollSessionTimeout = 60 * time.Second
+	// DefaultRealtimeSendQueueSize 256
+	DefaultRealtimeSendQueueSize = 256
+)
+
+// DefaultRealtimeConfiguration returns the default config for the app.NewWebsocketServer
+func DefaultRealtimeConfiguration() RealtimeConfiguration {
+	return RealtimeConfiguration{
+		WriteTimeout:           DefaultWebsocketWriteTimeout,
+		PongTimeout:            DefaultWebsocketPongTimeout,
+		PingPeriod:             DefaultWebsocketPingPeriod,
+		MaxMessageSize:         DefaultRealtimeMaxMessageSize,
+		Error:                  DefaultWebsocketError,
+		CheckOrigin:            DefaultWebsocketCheckOrigin,
+		CompressionLevel:       DefaultRealtimeCompressionLevel,
+		CompressionThreshold:   DefaultRealtimeCompressionThreshold,
+		LongPollHoldTimeout:    DefaultRealtimeLongPollHoldTimeout,
+		LongPollSessionTimeout: DefaultRealtimeLongPollSessionTimeout,
+		SendQueueSize:          DefaultRealtimeSendQueueSize,
+		Codec:                  RealtimeJSON,
+	}
+}
+
 // Default values for base Server conf
 const (
 	// DefaultServerHostname returns the default hostname which is 0.0.0.0
@@ -0,0 +1,157 @@
+package iris
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// DefaultLimitedIdleTimeout the idle timeout of the keep-alive connections of a server which limits its connections,
+// when neither the IdleTimeout nor the ReadTimeout is set, so the idle connections don't hold their slots forever
+const DefaultLimitedIdleTimeout = time.Minute
+
+var errConnectionsPerIP = errors.New("Too many connections of the IP %s, maximum is %d")
+
+// LimitListener returns a listener which accepts at most 'max' concurrent connections
+// and at most 'maxPerIP' concurrent connections of the same remote IP, zero means no limit.
+// The Accept waits for a free slot when the 'max' is reached,
+// the connections over the 'maxPerIP' are closed on their first Read.
+//
+// The remote IP is resolved by the connection's goroutine, on its first Read, never by the Accept,
+// so a ProxyProtocol listener which waits for the header of a slow client doesn't block the rest of the connections.
+//
+// The main server is limited by the Configuration's MaxConnections and MaxConnectionsPerIP,
+// use it for the listeners of the additional hosts.
+func LimitListener(ln net.Listener, max int, maxPerIP int) net.Listener {
+	if max <= 0 && maxPerIP <= 0 {
+		return ln
+	}
+	l := &limitListener{Listener: ln, maxPerIP: maxPerIP, ips: make(map[string]int), done: make(chan struct{})}
+	if max > 0 {
+		l.sem = make(chan struct{}, max)
+	}
+	return l
+}
+
+type limitListener struct {
+	net.Listener
+	sem      chan struct{}
+	maxPerIP int
+
+	mu  sync.Mutex
+	ips map[string]int
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// acquire waits for a free slot, it returns false if the listener is closed
+func (l *limitListener) acquire() bool {
+	if l.sem == nil {
+		return true
+	}
+	select {
+	case <-l.done:
+		return false
+	case l.sem <- struct{}{}:
+		return true
+	}
+}
+
+func (l *limitListener) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	if !l.acquire() {
+		// the listener is closed, let its Accept return the error
+		return l.Listener.Accept()
+	}
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return &limitConn{Conn: conn, l: l}, nil
+}
+
+func (l *limitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+// remoteIP returns the IP part of the connection's remote address
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr().String()
+	if ip, _, err := net.SplitHostPort(addr); err == nil {
+		return ip
+	}
+	return addr
+}
+
+type limitConn struct {
+	net.Conn
+	l *limitListener
+	// admitOnce counts the connection to its IP, on its first Read, or prevents it when it's closed first
+	admitOnce sync.Once
+	ip        string
+	// err is not nil if the connection is over the limit of its IP
+	err       error
+	closeOnce sync.Once
+}
+
+// admit counts the connection to its IP, the err is set if the IP has too many connections
+func (c *limitConn) admit() {
+	if c.l.maxPerIP <= 0 {
+		return
+	}
+	ip := remoteIP(c.Conn)
+	c.l.mu.Lock()
+	defer c.l.mu.Unlock()
+	if c.l.ips[ip] >= c.l.maxPerIP {
+		c.err = errConnectionsPerIP.Format(ip, c.l.maxPerIP)
+		return
+	}
+	c.l.ips[ip]++
+	c.ip = ip
+}
+
+func (c *limitConn) Read(b []byte) (int, error) {
+	c.admitOnce.Do(c.admit)
+	if c.err != nil {
+		c.Close()
+		return 0, c.err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	// a connection which is closed before its first Read is not counted
+	c.admitOnce.Do(func() {})
+	c.closeOnce.Do(func() {
+		if c.ip != "" {
+			c.l.mu.Lock()
+			if c.l.ips[c.ip]--; c.l.ips[c.ip] <= 0 {
+				delete(c.l.ips, c.ip)
+			}
+			c.l.mu.Unlock()
+		}
+		c.l.release()
+	})
+	return err
+}
+
+// idleTimeout returns the IdleTimeout of the main server,
+// the DefaultLimitedIdleTimeout if its connections are limited and neither the IdleTimeout nor the ReadTimeout is set
+func (s *Framework) idleTimeout() time.Duration {
+	if s.Config.IdleTimeout == 0 && s.Config.ReadTimeout == 0 && (s.Config.MaxConnections > 0 || s.Config.MaxConnectionsPerIP > 0) {
+		return DefaultLimitedIdleTimeout
+	}
+	return s.Config.IdleTimeout
+}
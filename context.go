@@ -15,14 +15,17 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/iris-contrib/formBinder"
 	"github.com/kataras/go-errors"
 	"github.com/kataras/go-fs"
 	"github.com/kataras/go-sessions"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -66,6 +69,8 @@ const (
 
 	// stopExecutionPosition used inside the Context, is the number which shows us that the context's middleware manualy stop the execution
 	stopExecutionPosition = 255
+	// bodyContextKey the context's value of the request's body which is read by the BodyBytes
+	bodyContextKey = "iris.body"
 )
 
 // errors
@@ -145,6 +150,10 @@ type (
 		session    sessions.Session
 		// Pos is the position number of the Context, look .Next to understand
 		Pos int // exported because is useful for debugging
+		// start the time the request is routed, it's set only when the metrics are enabled
+		start time.Time
+		// span the request's server span, it's set only when the tracing is enabled
+		span trace.Span
 	}
 )
 
@@ -284,6 +293,17 @@ func (ctx *Context) RemoteAddr() string {
 	return addr
 }
 
+// connIP returns the ip of the request's connection, the X-Real-Ip and the X-Forwarded-For headers are not used,
+// any client can set them, it's the key of the limits.
+// The connections of a ProxyProtocol listener have the real client's address
+func (ctx *Context) connIP() string {
+	addr := strings.TrimSpace(ctx.Request.RemoteAddr)
+	if ip, _, err := net.SplitHostPort(addr); err == nil {
+		return ip
+	}
+	return addr
+}
+
 // RequestHeader returns the request header's value
 // accepts one parameter, the key of the header (string)
 // returns string
@@ -422,9 +442,38 @@ func (u UnmarshalerFunc) Unmarshal(data []byte, v interface{}) error {
 	return u(data, v)
 }
 
+// BodyBytes reads the request's body once and returns it, the body is replaced by a reader of the same bytes,
+// so it can be read again by the next handlers, i.e the ctx.ReadJSON after a signature verification
+func (ctx *Context) BodyBytes() ([]byte, error) {
+	if b, ok := ctx.Get(bodyContextKey).([]byte); ok {
+		ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(b))
+		return b, nil
+	}
+	if ctx.Request.Body == nil {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Request.Body.Close()
+	ctx.Set(bodyContextKey, b)
+	ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
 // UnmarshalBody reads the request's body and binds it to a value or pointer of any type
 // Examples of usage: context.ReadJSON, context.ReadXML
 func (ctx *Context) UnmarshalBody(v interface{}, unmarshaler Unmarshaler) error {
+	if err := ctx.unmarshalBody(v, unmarshaler); err != nil {
+		return err
+	}
+	// the bound value is sanitized and validated by the Validator, its errors are rendered with the 422 status code
+	return ctx.bound(v)
+}
+
+// unmarshalBody reads the request's body to the 'v' with the 'unmarshaler', without validating it
+func (ctx *Context) unmarshalBody(v interface{}, unmarshaler Unmarshaler) error {
 	if ctx.Request.Body == nil {
 		return errors.New("Empty body, please send request body!")
 	}
@@ -440,20 +489,23 @@ func (ctx *Context) UnmarshalBody(v interface{}, unmarshaler Unmarshaler) error
 	//
 	// See 'BodyDecoder' for more
 	if decoder, isDecoder := v.(BodyDecoder); isDecoder {
-		return decoder.Decode(rawData)
-	}
-
-	// check if v is already a pointer, if yes then pass as it's
-	if reflect.TypeOf(v).Kind() == reflect.Ptr {
-		return unmarshaler.Unmarshal(rawData, v)
+		err = decoder.Decode(rawData)
+	} else if reflect.TypeOf(v).Kind() == reflect.Ptr {
+		// check if v is already a pointer, if yes then pass as it's
+		err = unmarshaler.Unmarshal(rawData, v)
+	} else {
+		// finally, if the v doesn't contains a self-body decoder and it's not a pointer
+		// use the custom unmarshaler to bind the body
+		err = unmarshaler.Unmarshal(rawData, &v)
 	}
-	// finally, if the v doesn't contains a self-body decoder and it's not a pointer
-	// use the custom unmarshaler to bind the body
-	return unmarshaler.Unmarshal(rawData, &v)
+	return err
 }
 
 // ReadJSON reads JSON from request's body and binds it to a value of any json-valid type
 func (ctx *Context) ReadJSON(jsonObject interface{}) error {
+	if e := ctx.jsonEngine(); e != nil {
+		return ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(e.codec.Unmarshal))
+	}
 	return ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(json.Unmarshal))
 }
 
@@ -469,7 +521,19 @@ func (ctx *Context) ReadForm(formObject interface{}) error {
 	if values == nil {
 		return errors.New("An empty form passed on context.ReadForm")
 	}
-	return errReadBody.With(formBinder.Decode(values, formObject))
+	if err := ctx.decodeForm(values, formObject); err != nil {
+		return errReadBody.With(err)
+	}
+	return ctx.bound(formObject)
+}
+
+// ReadQuery binds the 'queryObject' with the url query parameters, like the ReadForm,
+// i.e the "?page=2&filter[status]=open&sort[]=name"
+func (ctx *Context) ReadQuery(queryObject interface{}) error {
+	if err := ctx.decodeForm(ctx.Request.URL.Query(), queryObject); err != nil {
+		return err
+	}
+	return ctx.bound(queryObject)
 }
 
 // ResetBody resets the body of the response
@@ -515,7 +579,7 @@ func (ctx *Context) Redirect(urlToRedirect string, statusHeader ...int) {
 
 	if urlToRedirect == ctx.Path() {
 		if ctx.framework.Config.IsDevelopment {
-			ctx.Log("Trying to redirect to itself. FROM: %s TO: %s", ctx.Path(), urlToRedirect)
+			ctx.framework.log(LogLevelWarn, "trying to redirect to itself", "from", ctx.Path(), "to", urlToRedirect)
 		}
 	}
 	http.Redirect(ctx.ResponseWriter.ResponseWriter, ctx.Request, urlToRedirect, httpStatus)
@@ -551,6 +615,9 @@ func (ctx *Context) Panic() {
 func (ctx *Context) EmitError(statusCode int) {
 	ctx.framework.EmitError(statusCode, ctx)
 	ctx.StopExecution()
+	if statusCode >= StatusInternalServerError && ctx.framework.hasErrorReports() {
+		ctx.framework.reportError(ctx, errEmitError.Format(statusCode, statusText[statusCode]), debug.Stack())
+	}
 }
 
 // -------------------------------------------------------------------------------------
@@ -646,8 +713,7 @@ func (ctx *Context) TryWriteGzip(b []byte) (int, error) {
 
 // renderSerialized renders contents with a serializer with status OK which you can change using RenderWithStatus or ctx.SetStatusCode(iris.StatusCode)
 func (ctx *Context) renderSerialized(contentType string, obj interface{}, options ...map[string]interface{}) error {
-	s := ctx.framework.serializers
-	finalResult, err := s.Serialize(contentType, obj, options...)
+	finalResult, options, err := ctx.framework.serialize(contentType, obj, options)
 	if err != nil {
 		return err
 	}
@@ -663,7 +729,7 @@ func (ctx *Context) renderSerialized(contentType string, obj interface{}, option
 		ctype = contentHTML
 	}
 
-	if ctype != contentBinary { // set the charset only on non-binary data
+	if ctype != contentBinary && charset != "" { // set the charset only on non-binary data
 		ctype += "; charset=" + charset
 	}
 	ctx.SetContentType(ctype)
@@ -690,7 +756,13 @@ func (ctx *Context) RenderTemplateSource(status int, src string, binding interfa
 // Note: the options: "gzip" and "charset" are built'n support by Iris, so you can pass these on any template engine or serialize engines
 func (ctx *Context) RenderWithStatus(status int, name string, binding interface{}, options ...map[string]interface{}) (err error) {
 	if strings.IndexByte(name, '.') > -1 { //we have template
-		err = ctx.framework.templates.renderFile(ctx, name, binding, options...)
+		span := ctx.startSpan("iris.render", attribute.String("iris.template", name))
+		if views, e := ctx.findView(name); e != nil {
+			err = views.render(e, ctx, name, binding, options)
+		} else {
+			err = ctx.framework.templates.renderFile(ctx, name, binding, options...)
+		}
+		endSpan(span, err)
 	} else {
 		err = ctx.renderSerialized(name, binding, options...)
 	}
@@ -702,6 +774,16 @@ func (ctx *Context) RenderWithStatus(status int, name string, binding interface{
 	return
 }
 
+// findView returns the view engine which is responsible for the 'name', the party's engines are first
+func (ctx *Context) findView(name string) (*viewEngines, ViewEngine) {
+	if views, ok := ctx.Get(viewEnginesContextKey).(*viewEngines); ok {
+		if e := views.find(name); e != nil {
+			return views, e
+		}
+	}
+	return ctx.framework.views, ctx.framework.views.find(name)
+}
+
 // Render same as .RenderWithStatus but with status to iris.StatusOK (200) if no previous status exists
 // builds up the response from the specified template or a serialize engine.
 // Note: the options: "gzip" and "charset" are built'n support by Iris, so you can pass these on any template engine or serialize engine
@@ -713,13 +795,33 @@ func (ctx *Context) Render(name string, binding interface{}, options ...map[stri
 	return ctx.RenderWithStatus(errCode, name, binding, options...)
 }
 
+// RenderWithLayout same as .Render but renders the template inside the 'layout' template,
+// it overrides the party's and the view engine's default layout for this call only.
+func (ctx *Context) RenderWithLayout(name string, layout string, binding interface{}, options ...map[string]interface{}) error {
+	opts := map[string]interface{}{"layout": layout}
+	if len(options) > 0 {
+		for k, v := range options[0] {
+			if k != "layout" {
+				opts[k] = v
+			}
+		}
+	}
+	return ctx.Render(name, binding, opts)
+}
+
+// ViewLayout sets the layout template for the rest of this request's renders,
+// it overrides the party's layout, use it on a route's middleware to set a per-route layout.
+func (ctx *Context) ViewLayout(layout string) {
+	ctx.Set(TemplateLayoutContextKey, layout)
+}
+
 // MustRender same as .Render but returns 503 service unavailable http status with a (html) message if render failed
 // Note: the options: "gzip" and "charset" are built'n support by Iris, so you can pass these on any template engine or serialize engine
 func (ctx *Context) MustRender(name string, binding interface{}, options ...map[string]interface{}) {
 	if err := ctx.Render(name, binding, options...); err != nil {
 		ctx.HTML(StatusServiceUnavailable, fmt.Sprintf("<h2>Template: %s</h2><b>%s</b>", name, err.Error()))
 		if ctx.framework.Config.IsDevelopment {
-			ctx.framework.Logger.Printf("MustRender panics on template: %s.Trace: %s\n", name, err)
+			ctx.framework.log(LogLevelError, "MustRender", "template", name, "err", err)
 		}
 	}
 }
@@ -727,6 +829,14 @@ func (ctx *Context) MustRender(name string, binding interface{}, options ...map[
 // TemplateString accepts a template filename, its context data and returns the result of the parsed template (string)
 // if any error returns empty string
 func (ctx *Context) TemplateString(name string, binding interface{}, options ...map[string]interface{}) string {
+	// the party's view engines, if any
+	if views, e := ctx.findView(name); e != nil && views != ctx.framework.views && !ctx.framework.Config.DisableTemplateEngines {
+		res, err := views.executeString(e, name, binding, options)
+		if err != nil {
+			return ""
+		}
+		return res
+	}
 	return ctx.framework.TemplateString(name, binding, options...)
 }
 
@@ -746,8 +856,15 @@ func (ctx *Context) Data(status int, v []byte) error {
 }
 
 // JSON marshals the given interface object and writes the JSON response.
-func (ctx *Context) JSON(status int, v interface{}) error {
-	return ctx.RenderWithStatus(status, contentJSON, v)
+// It's encoded by the JSON engine of the UseJSONCodec, if any, with the Config.JSON options
+// overridden by the ones of the UseJSONCodec and the 'options', i.e ctx.JSON(iris.StatusOK, v, iris.JSONOptions{Compact: true}).
+// Without any of them, in production, it's rendered by the json serializer, see the UseSerializer.
+func (ctx *Context) JSON(status int, v interface{}, options ...JSONOptions) error {
+	e := ctx.jsonEngine()
+	if e == nil && len(options) == 0 && ctx.framework.Config.JSON == (JSONOptions{}) && !ctx.framework.Config.IsDevelopment {
+		return ctx.RenderWithStatus(status, contentJSON, v)
+	}
+	return ctx.writeJSON(e, status, v, options...)
 }
 
 // JSONP marshals the given interface object and writes the JSON response.
@@ -760,11 +877,6 @@ func (ctx *Context) Text(status int, v string) error {
 	return ctx.RenderWithStatus(status, contentText, v)
 }
 
-// XML marshals the given interface object and writes the XML response.
-func (ctx *Context) XML(status int, v interface{}) error {
-	return ctx.RenderWithStatus(status, contentXML, v)
-}
-
 // MarkdownString parses the (dynamic) markdown string and returns the converted html string
 func (ctx *Context) MarkdownString(markdownText string) string {
 	return ctx.framework.SerializeToString(contentMarkdown, markdownText)
@@ -778,6 +890,13 @@ func (ctx *Context) Markdown(status int, markdown string) {
 	ctx.HTML(status, ctx.MarkdownString(markdown))
 }
 
+// MarkdownBytes converts the 'markdown' to html, with the app.Markdown renderer which sanitizes the result, and writes it to the client
+func (ctx *Context) MarkdownBytes(status int, markdown []byte) {
+	ctx.SetContentType(contentHTML + "; charset=" + ctx.framework.Config.Charset)
+	ctx.SetStatusCode(status)
+	ctx.Write(ctx.framework.Markdown.Render(markdown))
+}
+
 // -------------------------------------------------------------------------------------
 // -------------------------------------------------------------------------------------
 // --------------------Static content serve by context implementation-------------------
@@ -788,14 +907,63 @@ func (ctx *Context) Markdown(status int, markdown string) {
 // returns true if (client-side) duration has expired
 func (ctx *Context) staticCachePassed(modtime time.Time) bool {
 	if t, err := time.Parse(ctx.framework.Config.TimeFormat, ctx.RequestHeader(ifModifiedSince)); err == nil && modtime.Before(t.Add(StaticCacheDuration)) {
-		ctx.ResponseWriter.Header().Del(contentType)
-		ctx.ResponseWriter.Header().Del(contentLength)
-		ctx.SetStatusCode(StatusNotModified)
+		ctx.WriteNotModified()
 		return true
 	}
 	return false
 }
 
+// CheckIfModifiedSince returns true if the resource which is modified at 'modtime' should be sent,
+// false if it's not modified since the request's If-Modified-Since header.
+// The header is ignored if it's missing or invalid, if the 'modtime' is zero
+// and if the request has an If-None-Match header, which takes precedence.
+//
+// Usage:
+// if !ctx.CheckIfModifiedSince(post.UpdatedAt) {
+//	ctx.WriteNotModified()
+//	return
+// }
+func (ctx *Context) CheckIfModifiedSince(modtime time.Time) bool {
+	if modtime.IsZero() || modtime.Equal(time.Unix(0, 0)) || ctx.RequestHeader("If-None-Match") != "" {
+		return true
+	}
+	header := ctx.RequestHeader(ifModifiedSince)
+	if header == "" {
+		return true
+	}
+	t, err := time.Parse(ctx.framework.Config.TimeFormat, header)
+	if err != nil {
+		if t, err = http.ParseTime(header); err != nil {
+			return true
+		}
+	}
+	// the header has a precision of seconds
+	return modtime.Truncate(time.Second).After(t)
+}
+
+// WriteNotModified responds with the 304 Not Modified status code, without the body and its Content-Type and Content-Length
+func (ctx *Context) WriteNotModified() {
+	h := ctx.ResponseWriter.Header()
+	h.Del(contentType)
+	h.Del(contentLength)
+	ctx.ResponseWriter.ResetBody()
+	ctx.SetStatusCode(StatusNotModified)
+}
+
+// WriteWithExpiration writes the 'body' with the Last-Modified header of the 'modtime',
+// or responds with the 304 Not Modified if it's not modified since the request's If-Modified-Since, see CheckIfModifiedSince.
+// Use the ctx.ExpiresIn or the ctx.CacheControl for the freshness of the response.
+func (ctx *Context) WriteWithExpiration(body []byte, modtime time.Time) (int, error) {
+	if !ctx.CheckIfModifiedSince(modtime) {
+		ctx.WriteNotModified()
+		return 0, nil
+	}
+	if !modtime.IsZero() {
+		ctx.ResponseWriter.Header().Set(lastModified, modtime.UTC().Format(ctx.framework.Config.TimeFormat))
+	}
+	return ctx.ResponseWriter.Write(body)
+}
+
 // SetClientCachedBody like SetBody but it sends with an expiration datetime
 // which is managed by the client-side (all major browsers supports this feature)
 func (ctx *Context) SetClientCachedBody(status int, bodyContent []byte, cType string, modtime time.Time) {
@@ -818,10 +986,8 @@ func (ctx *Context) SetClientCachedBody(status int, bodyContent []byte, cType st
 // You can define your own "Content-Type" header also, after this function call
 // Doesn't implements resuming (by range), use ctx.SendFile instead
 func (ctx *Context) ServeContent(content io.ReadSeeker, filename string, modtime time.Time, gzipCompression bool) error {
-	if t, err := time.Parse(ctx.framework.Config.TimeFormat, ctx.RequestHeader(ifModifiedSince)); err == nil && modtime.Before(t.Add(1*time.Second)) {
-		ctx.ResponseWriter.Header().Del(contentType)
-		ctx.ResponseWriter.Header().Del(contentLength)
-		ctx.SetStatusCode(StatusNotModified)
+	if !ctx.CheckIfModifiedSince(modtime) {
+		ctx.WriteNotModified()
 		return nil
 	}
 
@@ -1172,22 +1338,55 @@ func (ctx *Context) RemoveCookie(name string) {
 
 // Session returns the current session ( && flash messages )
 func (ctx *Context) Session() sessions.Session {
-	if ctx.framework.sessions == nil { // this should never return nil but FOR ANY CASE, on future changes.
-		return nil
+	if ctx.session != nil {
+		return ctx.session
 	}
 
-	if ctx.session == nil {
+	created := false
+	if ctx.framework.jwtSessions != nil {
+		sess := ctx.framework.jwtSessions.Start(ctx.Request)
+		created = sess.isNew
+		ctx.session = sess
+	} else {
+		if ctx.framework.sessions == nil { // this should never return nil but FOR ANY CASE, on future changes.
+			return nil
+		}
+		created = ctx.GetCookie(ctx.framework.Config.Sessions.Cookie) == ""
 		ctx.session = ctx.framework.sessions.Start(ctx.ResponseWriter, ctx.Request)
 	}
+
+	if !ctx.framework.sessionHooks.empty() {
+		ctx.session = &hookedSession{Session: ctx.session, ctx: ctx}
+		if created {
+			ctx.framework.sessionHooks.fireCreate(ctx, ctx.session)
+		}
+	}
 	return ctx.session
 }
 
 // SessionDestroy destroys the whole session, calls the provider's destroy and remove the cookie
 func (ctx *Context) SessionDestroy() {
-	if sess := ctx.Session(); sess != nil {
-		ctx.framework.sessions.Destroy(ctx.ResponseWriter, ctx.Request)
+	sess := ctx.Session()
+	if sess == nil {
+		return
 	}
 
+	ctx.framework.sessionHooks.fireDestroy(ctx, sess)
+
+	if jwtSess, ok := unwrapSession(sess).(*jwtSession); ok {
+		// stateless, the cookie is removed on release
+		ctx.framework.jwtSessions.revoke(jwtSess.ID())
+		jwtSess.destroy()
+		return
+	}
+
+	ctx.framework.sessions.Destroy(ctx.ResponseWriter, ctx.Request)
+}
+
+// Tr returns the translated message of the 'key' in the request's language, see I18n
+func (ctx *Context) Tr(key string, args ...interface{}) string {
+	i := ctx.framework.I18n
+	return i.Tr(i.Language(ctx), key, args...)
 }
 
 var maxAgeExp = regexp.MustCompile(`maxage=(\d+)`)
@@ -1260,15 +1459,28 @@ func (ctx *Context) BeginTransaction(pipe func(transaction *Transaction)) {
 	}
 	// get a transaction scope from the pool by passing the temp context/
 	t := newTransaction(ctx)
+	span := ctx.startSpan("iris.transaction")
 	defer func() {
 		if err := recover(); err != nil {
 			if ctx.framework.Config.IsDevelopment {
-				ctx.Log(errTransactionInterrupted.Format(err).Error())
+				ctx.framework.log(LogLevelError, errTransactionInterrupted.Format(err).Error())
+			}
+			if ctx.framework.hasErrorReports() {
+				ctx.framework.reportError(ctx, errTransactionInterrupted.Format(err), debug.Stack())
 			}
 			// complete (again or not , doesn't matters) the scope without loud
 			t.Complete(nil)
+			t.hasError = true
 			// we continue as normal, no need to return here*
 		}
+		if m := ctx.framework.metrics; m != nil {
+			m.observeTransaction(t)
+		}
+		if t.hasError {
+			span.SetStatus(codes.Error, "transaction failed")
+			ctx.Set(transactionFailedContextKey, true)
+		}
+		span.End()
 
 		// write the temp contents to the original writer
 		t.Context.ResponseWriter.writeTo(ctx.ResponseWriter)
@@ -1283,7 +1495,12 @@ func (ctx *Context) BeginTransaction(pipe func(transaction *Transaction)) {
 
 // Log logs to the iris defined logger
 func (ctx *Context) Log(format string, a ...interface{}) {
-	ctx.framework.Logger.Printf(format, a...)
+	ctx.framework.log(LogLevelInfo, fmt.Sprintf(format, a...))
+}
+
+// LogFields logs a structured entry to the iris defined logger, the 'fields' are key-value pairs, i.e "user", id
+func (ctx *Context) LogFields(level LogLevel, msg string, fields ...interface{}) {
+	ctx.framework.log(level, msg, fields...)
 }
 
 // Framework returns the Iris instance, containing the configuration and all other fields
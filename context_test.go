@@ -5,16 +5,28 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
 	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/gavv/httpexpect"
+	"github.com/kataras/go-sessions"
 	"github.com/kataras/iris"
 	"github.com/kataras/iris/httptest"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // White-box testing *
@@ -524,6 +536,140 @@ func TestContextSessions(t *testing.T) {
 	e.GET("/clear").Expect().Status(iris.StatusOK).JSON().Object().Empty()
 }
 
+func TestContextJWTSessions(t *testing.T) {
+	t.Parallel()
+	values := map[string]interface{}{
+		"Name":   "iris",
+		"Months": "4",
+	}
+
+	app := iris.New(iris.OptionSessionsCookie("mystatelesssession"),
+		iris.OptionJWTSessionsSecret([]byte("my secret")),
+		iris.OptionJWTSessionsEncryptionKey([]byte("0123456789abcdef")),
+		iris.OptionJWTSessionsMaxCookieSize(512))
+
+	app.Post("/set", func(ctx *iris.Context) {
+		vals := make(map[string]interface{}, 0)
+		if err := ctx.ReadJSON(&vals); err != nil {
+			t.Fatalf("Cannot readjson. Trace %s", err.Error())
+		}
+		for k, v := range vals {
+			ctx.Session().Set(k, v)
+		}
+	})
+
+	app.Get("/get", func(ctx *iris.Context) {
+		ctx.JSON(iris.StatusOK, ctx.Session().GetAll())
+	})
+
+	app.Get("/destroy", func(ctx *iris.Context) {
+		ctx.SessionDestroy()
+		ctx.JSON(iris.StatusOK, ctx.Session().GetAll())
+	})
+
+	app.Get("/large", func(ctx *iris.Context) {
+		ctx.Session().Set("large", strings.Repeat("a", 1024))
+	})
+
+	e := httptest.New(app, t)
+
+	e.POST("/set").WithJSON(values).Expect().Status(iris.StatusOK).Cookie("mystatelesssession").Value().NotEmpty()
+	e.GET("/get").Expect().Status(iris.StatusOK).JSON().Object().Equal(values)
+	// too large, the cookie is not written and the previous values are kept
+	e.GET("/large").Expect().Status(iris.StatusOK).Cookies().Empty()
+	e.GET("/get").Expect().Status(iris.StatusOK).JSON().Object().Equal(values)
+
+	e.GET("/destroy").Expect().Status(iris.StatusOK).JSON().Object().Empty()
+	e.GET("/get").Expect().Status(iris.StatusOK).JSON().Object().Empty()
+	// a tampered token is ignored
+	e.GET("/get").WithCookie("mystatelesssession", "a.b.c").Expect().Status(iris.StatusOK).JSON().Object().Empty()
+}
+
+func TestContextJWTSessionsRevoke(t *testing.T) {
+	t.Parallel()
+	app := iris.New(iris.OptionSessionsCookie("mystatelesssession"),
+		iris.OptionJWTSessionsSecret([]byte("my secret")),
+		iris.OptionJWTSessionsMaxRevoked(1))
+
+	app.Get("/login", func(ctx *iris.Context) {
+		ctx.Session().Set("user", ctx.URLParam("user"))
+		ctx.WriteString(ctx.Session().ID())
+	})
+	app.Get("/get", func(ctx *iris.Context) {
+		ctx.WriteString(ctx.Session().GetString("user"))
+	})
+	app.Get("/revoke", func(ctx *iris.Context) {
+		app.DestroySessionByID(ctx.URLParam("sid"))
+	})
+
+	e := httptest.New(app, t)
+	login := func(user string) (sid string, token string) {
+		res := httptest.New(app, t).GET("/login").WithQuery("user", user).Expect().Status(iris.StatusOK)
+		return res.Body().Raw(), res.Cookie("mystatelesssession").Value().Raw()
+	}
+	get := func(token string) *httpexpect.String {
+		return httptest.New(app, t).GET("/get").WithCookie("mystatelesssession", token).Expect().Status(iris.StatusOK).Body()
+	}
+
+	firstID, first := login("kataras")
+	secondID, second := login("makis")
+	get(first).Equal("kataras")
+
+	e.GET("/revoke").WithQuery("sid", firstID).Expect().Status(iris.StatusOK)
+	get(first).Empty()
+	get(second).Equal("makis")
+
+	// only the latest revoke is remembered
+	e.GET("/revoke").WithQuery("sid", secondID).Expect().Status(iris.StatusOK)
+	get(second).Empty()
+	get(first).Equal("kataras")
+}
+
+func TestContextSessionHooks(t *testing.T) {
+	t.Parallel()
+	app := iris.New()
+	var created, updated, destroyed int32
+
+	app.OnSessionCreate(func(ctx *iris.Context, sess sessions.Session) {
+		atomic.AddInt32(&created, 1)
+	})
+	app.OnSessionUpdate(func(ctx *iris.Context, sess sessions.Session) {
+		atomic.AddInt32(&updated, 1)
+	})
+	app.OnSessionDestroy(func(ctx *iris.Context, sess sessions.Session) {
+		if sess.GetString("user") != "kataras" {
+			t.Fatalf("Expected the session's values to be available on destroy")
+		}
+		atomic.AddInt32(&destroyed, 1)
+	})
+
+	app.Get("/login", func(ctx *iris.Context) {
+		ctx.Session().Set("user", "kataras")
+		ctx.Session().Set("role", "admin")
+	})
+	app.Get("/get", func(ctx *iris.Context) {
+		ctx.WriteString(ctx.Session().GetString("user"))
+	})
+	app.Get("/logout", func(ctx *iris.Context) {
+		ctx.SessionDestroy()
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/login").Expect().Status(iris.StatusOK)
+	e.GET("/get").Expect().Status(iris.StatusOK).Body().Equal("kataras")
+	e.GET("/logout").Expect().Status(iris.StatusOK)
+
+	if c := atomic.LoadInt32(&created); c != 1 {
+		t.Fatalf("Expected OnSessionCreate to be fired once but fired %d times", c)
+	}
+	if u := atomic.LoadInt32(&updated); u != 2 {
+		t.Fatalf("Expected OnSessionUpdate to be fired twice but fired %d times", u)
+	}
+	if d := atomic.LoadInt32(&destroyed); d != 1 {
+		t.Fatalf("Expected OnSessionDestroy to be fired once but fired %d times", d)
+	}
+}
+
 type renderTestInformationType struct {
 	XMLName    xml.Name `xml:"info"`
 	FirstAttr  string   `xml:"first,attr"`
@@ -638,6 +784,425 @@ func TestContextPreRender(t *testing.T) {
 	e.GET("/").Expect().Status(iris.StatusOK).Body().Contains(expected)
 }
 
+// writeTestTemplates writes the "files" to a temp directory and returns its path
+func writeTestTemplates(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "iris-views")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestContextRenderView(t *testing.T) {
+	dir := writeTestTemplates(t, map[string]string{
+		"index.html":         "<h1>Hello {{.Name}}</h1>",
+		"about.html":         `{{ define "title" }}About{{ end }}<p>{{ partial "partials/name.html" .Name }}</p>`,
+		"partials/name.html": "<b>{{.}}</b>",
+		"layouts/main.html":  "<html><body>{{ yield }}</body></html>",
+		"layouts/page.html":  `<title>{{ block "title" . }}Default{{ end }}</title>{{ yield }}`,
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.AdaptView(iris.HTML(dir, ".html").Layout("layouts/main.html"))
+
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", map[string]string{"Name": "iris"})
+	})
+	app.Get("/nolayout", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", map[string]string{"Name": "iris"}, iris.RenderOptions{"layout": iris.NoLayout})
+	})
+	app.Get("/page", func(ctx *iris.Context) {
+		ctx.RenderWithLayout("index.html", "layouts/page.html", map[string]string{"Name": "iris"})
+	})
+	app.Get("/about", func(ctx *iris.Context) {
+		ctx.ViewLayout("layouts/page.html")
+		ctx.Next()
+	}, func(ctx *iris.Context) {
+		ctx.MustRender("about.html", map[string]string{"Name": "iris"})
+	})
+	app.Get("/notfound", func(ctx *iris.Context) {
+		if err := ctx.Render("missing.html", nil); err != nil {
+			ctx.SetStatusCode(iris.StatusInternalServerError)
+		}
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).ContentType("text/html", app.Config.Charset).
+		Body().Equal("<html><body><h1>Hello iris</h1></body></html>")
+	e.GET("/nolayout").Expect().Status(iris.StatusOK).Body().Equal("<h1>Hello iris</h1>")
+	e.GET("/page").Expect().Status(iris.StatusOK).Body().Equal("<title>Default</title><h1>Hello iris</h1>")
+	e.GET("/about").Expect().Status(iris.StatusOK).Body().Equal("<title>About</title><p><b>iris</b></p>")
+	e.GET("/notfound").Expect().Status(iris.StatusInternalServerError)
+}
+
+func TestContextRenderViewReload(t *testing.T) {
+	dir := writeTestTemplates(t, map[string]string{"index.html": "<h1>v1</h1>"})
+	defer os.RemoveAll(dir)
+
+	app := iris.New(iris.OptionIsDevelopment(true))
+	app.AdaptView(iris.HTML(dir, ".html"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v1</h1>")
+
+	filename := filepath.Join(dir, "index.html")
+	if err := ioutil.WriteFile(filename, []byte("<h1>v2</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// make sure that the modification time is changed, even on file systems with low resolution
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(filename, future, future)
+
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v2</h1>")
+}
+
+func TestContextRenderViewFS(t *testing.T) {
+	templates := fstest.MapFS{
+		"index.html":        {Data: []byte("<h1>Hello {{.}}</h1>")},
+		"layouts/main.html": {Data: []byte("<main>{{ yield }}</main>")},
+	}
+
+	app := iris.New()
+	app.RegisterView(iris.HTML(templates, ".html").Layout("layouts/main.html"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", "embedded")
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<main><h1>Hello embedded</h1></main>")
+}
+
+func TestContextTranslate(t *testing.T) {
+	app := iris.New()
+	app.I18n.Add("en-US", map[string]string{"hello": "Hello %s", "bye": "Bye"})
+	app.I18n.Add("el-GR", map[string]string{"hello": "Γειά σου %s"})
+
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.WriteString(ctx.Tr("hello", "iris") + " " + ctx.Tr("bye") + " " + ctx.Tr("missing"))
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("Hello iris Bye missing")
+	e.GET("/").WithHeader("Accept-Language", "fr;q=0.9, el;q=0.8").Expect().Status(iris.StatusOK).
+		Body().Equal("Γειά σου iris Bye missing")
+	e.GET("/").WithQuery("lang", "el-GR").WithHeader("Accept-Language", "en-US").Expect().Status(iris.StatusOK).
+		Body().Equal("Γειά σου iris Bye missing")
+}
+
+func TestContextRenderViewFuncs(t *testing.T) {
+	templates := fstest.MapFS{
+		"index.html": {Data: []byte(`<a href="{{ urlpath "profile" "kataras" }}">{{ t "el-GR" "hello" }}</a>` +
+			`<link href="{{ asset "/static/main.css" }}">{{ safe "<b>safe</b>" }}<script>var data = {{ json . }};</script>`)},
+	}
+
+	app := iris.New()
+	app.I18n.Add("el-GR", map[string]string{"hello": "Γειά"})
+	app.StaticFS("/static", fstest.MapFS{"main.css": {Data: []byte("body{}")}})
+	app.RegisterView(iris.HTML(templates, ".html"))
+	app.Get("/profile/:username", func(ctx *iris.Context) {})("profile")
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", map[string]int{"stars": 5})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().
+		Equal(`<a href="/profile/kataras">Γειά</a><link href="/static/main.css?v=a4c0dac4"><b>safe</b><script>var data = {"stars":5};</script>`)
+}
+
+func TestContextCSPNonce(t *testing.T) {
+	templates := fstest.MapFS{
+		"index.html": {Data: []byte(`<script nonce="{{ .CSPNonce }}">init()</script>`)},
+	}
+
+	app := iris.New()
+	app.RegisterView(iris.HTML(templates, ".html"))
+	app.Use(iris.ContentSecurityPolicy("script-src 'self' 'nonce-{nonce}'"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", iris.Map{})
+	})
+
+	e := httptest.New(app, t)
+	res := e.GET("/").Expect().Status(iris.StatusOK)
+	policy := res.Header("Content-Security-Policy").Raw()
+	if !strings.HasPrefix(policy, "script-src 'self' 'nonce-") || strings.Contains(policy, "{nonce}") {
+		t.Fatalf("unexpected policy %q", policy)
+	}
+	nonce := strings.TrimSuffix(strings.TrimPrefix(policy, "script-src 'self' 'nonce-"), "'")
+	res.Body().Equal(`<script nonce="` + nonce + `">init()</script>`)
+
+	// a new nonce on each request
+	if next := e.GET("/").Expect().Header("Content-Security-Policy").Raw(); next == policy {
+		t.Fatalf("expected a new nonce but got the same %q", next)
+	}
+}
+
+func TestContextMarkdownBytes(t *testing.T) {
+	app := iris.New()
+	app.Markdown.Highlighter = func(code []byte, lang string) []byte {
+		return []byte(`<pre class="` + lang + `">` + string(code) + `</pre>`)
+	}
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MarkdownBytes(iris.StatusOK, []byte("# Hello\n\n<script>alert(1)</script>\n\n```go\nfmt.Println()\n```\n"))
+	})
+
+	e := httptest.New(app, t)
+	body := e.GET("/").Expect().Status(iris.StatusOK).ContentType("text/html", app.Config.Charset).Body()
+	body.Contains("<h1>Hello</h1>")
+	body.NotContains("<script>")
+	body.Contains(`<pre class="go">fmt.Println()` + "\n</pre>")
+}
+
+func TestContextRenderViewMarkdown(t *testing.T) {
+	docs := fstest.MapFS{
+		"index.md": {Data: []byte("# {{ .Title }}\n")},
+	}
+
+	app := iris.New()
+	app.AdaptView(iris.Markdown(docs, ".md"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.md", map[string]string{"Title": "Docs"})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>Docs</h1>\n")
+}
+
+func TestContextRenderViewDjango(t *testing.T) {
+	templates := fstest.MapFS{
+		"index.html":        {Data: []byte("<h1>Hello {{ Name }}</h1>")},
+		"funcs.html":        {Data: []byte(`<a href="{{ urlpath("profile", "kataras") }}">{{ t("el-GR", "hello") }}</a> {{ greet(Name) }}`)},
+		"page.html":         {Data: []byte(`{% extends "layouts/base.html" %}{% block title %}Page{% endblock %}`)},
+		"layouts/main.html": {Data: []byte("<main>{{ yield }}</main>")},
+		"layouts/page.html": {Data: []byte("<section>{{ yield }}</section>")},
+		"layouts/base.html": {Data: []byte("<title>{% block title %}Default{% endblock %}</title>")},
+	}
+
+	engine := iris.Django(templates, ".html").Layout("layouts/main.html")
+	engine.AddFunc("greet", func(name string) string {
+		return "Hi " + name
+	})
+
+	app := iris.New()
+	app.I18n.Add("el-GR", map[string]string{"hello": "Γειά"})
+	app.RegisterView(engine)
+	app.Get("/profile/:username", func(ctx *iris.Context) {})("profile")
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", iris.Map{"Name": "iris"})
+	})
+	app.Get("/nolayout", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", struct{ Name string }{"iris"}, iris.RenderOptions{"layout": iris.NoLayout})
+	})
+	app.Get("/layout", func(ctx *iris.Context) {
+		ctx.RenderWithLayout("index.html", "layouts/page.html", iris.Map{"Name": "iris"})
+	})
+	app.Get("/funcs", func(ctx *iris.Context) {
+		ctx.MustRender("funcs.html", iris.Map{"Name": "makis"}, iris.RenderOptions{"layout": iris.NoLayout})
+	})
+	app.Get("/extends", func(ctx *iris.Context) {
+		ctx.MustRender("page.html", nil, iris.RenderOptions{"layout": iris.NoLayout})
+	})
+	app.Get("/notfound", func(ctx *iris.Context) {
+		if err := ctx.Render("missing.html", nil); err != nil {
+			ctx.SetStatusCode(iris.StatusInternalServerError)
+		}
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).ContentType("text/html", app.Config.Charset).
+		Body().Equal("<main><h1>Hello iris</h1></main>")
+	e.GET("/nolayout").Expect().Status(iris.StatusOK).Body().Equal("<h1>Hello iris</h1>")
+	e.GET("/layout").Expect().Status(iris.StatusOK).Body().Equal("<section><h1>Hello iris</h1></section>")
+	e.GET("/funcs").Expect().Status(iris.StatusOK).Body().Equal(`<a href="/profile/kataras">Γειά</a> Hi makis`)
+	e.GET("/extends").Expect().Status(iris.StatusOK).Body().Equal("<title>Page</title>")
+	e.GET("/notfound").Expect().Status(iris.StatusInternalServerError)
+}
+
+func TestContextRenderViewDjangoReload(t *testing.T) {
+	dir := writeTestTemplates(t, map[string]string{"index.html": "<h1>v1</h1>"})
+	defer os.RemoveAll(dir)
+
+	dev := iris.New(iris.OptionIsDevelopment(true))
+	dev.AdaptView(iris.Django(dir, ".html"))
+	prod := iris.New()
+	prod.AdaptView(iris.Django(dir, ".html"))
+	for _, app := range []*iris.Framework{dev, prod} {
+		app.Get("/", func(ctx *iris.Context) {
+			ctx.MustRender("index.html", nil)
+		})
+	}
+
+	devExpect, prodExpect := httptest.New(dev, t), httptest.New(prod, t)
+	devExpect.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v1</h1>")
+	prodExpect.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v1</h1>")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>v2</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// re-parsed on each execution only in the development mode
+	devExpect.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v2</h1>")
+	prodExpect.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>v1</h1>")
+}
+
+func TestContextRenderViewDjangoLoad(t *testing.T) {
+	templates := fstest.MapFS{
+		"index.html":  {Data: []byte("<h1>{{ Name }}</h1>")},
+		"broken.html": {Data: []byte("<h1>{% if Name %}</h1>")},
+	}
+
+	// not executed before the Load
+	engine := iris.Django(templates, ".html")
+	if err := engine.ExecuteWriter(ioutil.Discard, "index.html", "", nil); err == nil {
+		t.Fatalf("expected an error of the execution before the Load")
+	}
+	if err := engine.Load(); err == nil || !strings.Contains(err.Error(), "broken.html") {
+		t.Fatalf("expected the Load to fail on the syntax error of the broken.html but got %v", err)
+	}
+
+	delete(templates, "broken.html")
+	if err := engine.Load(); err != nil {
+		t.Fatal(err)
+	}
+	buf := new(strings.Builder)
+	if err := engine.ExecuteWriter(buf, "index.html", "", iris.Map{"Name": "iris"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "<h1>iris</h1>" {
+		t.Fatalf("expected the rendered index.html but got %q", got)
+	}
+
+	// the syntax errors are reported at the Build
+	broken := iris.New()
+	broken.AdaptView(iris.Django(fstest.MapFS{"index.html": {Data: []byte("{% if Name %}")}}, ".html"))
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatalf("expected the Build to panic on a template syntax error")
+		}
+	}()
+	broken.Build()
+}
+
+func TestContextRenderViewParty(t *testing.T) {
+	publicDir := writeTestTemplates(t, map[string]string{
+		"index.html": "<h1>public</h1>",
+	})
+	defer os.RemoveAll(publicDir)
+	adminDir := writeTestTemplates(t, map[string]string{
+		"index.html":         "<h1>admin</h1>",
+		"layouts/admin.html": "<main>{{ yield }}</main>",
+	})
+	defer os.RemoveAll(adminDir)
+
+	app := iris.New()
+	app.RegisterView(iris.HTML(publicDir, ".html"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+
+	admin := app.Party("/admin").RegisterView(iris.HTML(adminDir, ".html").Layout("layouts/admin.html"))
+	admin.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+	admin.Party("/users").Get("/", func(ctx *iris.Context) {
+		ctx.WriteString(ctx.TemplateString("index.html", nil))
+	})
+	app.Get("/missing", func(ctx *iris.Context) {
+		// the admin's layout is not visible to the rest of the app
+		if err := ctx.Render("layouts/admin.html", nil); err != nil {
+			ctx.SetStatusCode(iris.StatusNotFound)
+		}
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>public</h1>")
+	e.GET("/admin").Expect().Status(iris.StatusOK).Body().Equal("<main><h1>admin</h1></main>")
+	e.GET("/admin/users").Expect().Status(iris.StatusOK).Body().Equal("<main><h1>admin</h1></main>")
+	e.GET("/missing").Expect().Status(iris.StatusNotFound)
+}
+
+func TestContextRenderViewPartyOrder(t *testing.T) {
+	app := iris.New()
+	app.RegisterView(iris.HTML(fstest.MapFS{"index.html": {Data: []byte("<h1>public</h1>")}}, ".html"))
+
+	// the routes and the child parties which are registered before the party's RegisterView use its engines too
+	admin := app.Party("/admin")
+	admin.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+	users := admin.Party("/users")
+	users.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+	reports := admin.Party("/reports")
+	reports.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", nil)
+	})
+
+	admin.RegisterView(iris.HTML(fstest.MapFS{"index.html": {Data: []byte("<h1>admin</h1>")}}, ".html"))
+	// the nearest party's engines are used
+	reports.RegisterView(iris.HTML(fstest.MapFS{"index.html": {Data: []byte("<h1>reports</h1>")}}, ".html"))
+
+	e := httptest.New(app, t)
+	e.GET("/admin").Expect().Status(iris.StatusOK).Body().Equal("<h1>admin</h1>")
+	e.GET("/admin/users").Expect().Status(iris.StatusOK).Body().Equal("<h1>admin</h1>")
+	e.GET("/admin/reports").Expect().Status(iris.StatusOK).Body().Equal("<h1>reports</h1>")
+}
+
+func TestContextRenderViewMetrics(t *testing.T) {
+	dir := writeTestTemplates(t, map[string]string{
+		"index.html":  "<h1>{{.}}</h1>",
+		"broken.html": "<h1>{{.Missing}}</h1>",
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.AdaptView(iris.HTML(dir, ".html"))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.MustRender("index.html", "iris")
+	})
+	app.Get("/broken", func(ctx *iris.Context) {
+		ctx.MustRender("broken.html", "iris")
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("<h1>iris</h1>")
+	e.GET("/").Expect().Status(iris.StatusOK)
+	// nothing of the failed execution is written to the response
+	e.GET("/broken").Expect().Status(iris.StatusServiceUnavailable).Body().NotContains("<h1>")
+
+	metrics := app.ViewMetrics()
+	if m := metrics["index.html"]; m.Renders != 2 || m.Errors != 0 || m.Max <= 0 || m.Average() > m.Max {
+		t.Fatalf("unexpected metric for index.html: %#v", m)
+	}
+	if m := metrics["broken.html"]; m.Renders != 1 || m.Errors != 1 {
+		t.Fatalf("unexpected metric for broken.html: %#v", m)
+	}
+
+	// the syntax errors are reported at the Build
+	syntaxDir := writeTestTemplates(t, map[string]string{"index.html": "<h1>{{ .Name </h1>"})
+	defer os.RemoveAll(syntaxDir)
+	broken := iris.New()
+	broken.AdaptView(iris.HTML(syntaxDir, ".html"))
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatalf("expected the Build to panic on a template syntax error")
+		}
+	}()
+	broken.Build()
+}
+
 func TestTemplatesDisabled(t *testing.T) {
 	iris.ResetDefault()
 	defer iris.Close()
@@ -765,3 +1330,653 @@ func TestTransactions(t *testing.T) {
 		Body().
 		Equal(customErrorTemplateText)
 }
+
+func TestTransactionsRender(t *testing.T) {
+	dir := writeTestTemplates(t, map[string]string{
+		"item.html":   "<li>{{.}}</li>",
+		"broken.html": "<li>{{.Missing}}</li>",
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.AdaptView(iris.HTML(dir, ".html"))
+
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.BeginTransaction(func(transaction *iris.Transaction) {
+			transaction.Response.Render("item.html", "first")
+			err := iris.NewTransactionErrResult()
+			err.StatusCode = iris.StatusInternalServerError
+			transaction.Complete(err)
+		})
+		ctx.BeginTransaction(func(transaction *iris.Transaction) {
+			transaction.Response.Render("item.html", "second")
+			if err := transaction.Response.Render("broken.html", "second"); err == nil {
+				t.Fatalf("expected an execution error from the broken template")
+			}
+			transaction.Complete(nil)
+		})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).ContentType("text/html", app.Config.Charset).
+		Body().Equal("<li>second</li>")
+}
+
+func TestContextCacheControl(t *testing.T) {
+	app := iris.New()
+	app.Get("/asset", func(ctx *iris.Context) {
+		ctx.CacheControl().Public().MaxAge(365 * 24 * time.Hour).Immutable().Apply()
+		ctx.Vary("accept-encoding", "Accept-Language")
+		ctx.Vary("Accept-Encoding")
+	})
+	app.Get("/feed", func(ctx *iris.Context) {
+		ctx.CacheControl().MaxAge(time.Minute).SMaxAge(0).StaleWhileRevalidate(30 * time.Second).Apply()
+		ctx.ExpiresIn(time.Minute)
+	})
+	app.Get("/account", func(ctx *iris.Context) {
+		ctx.CacheControl().Private().MaxAge(time.Hour).NoStore().Apply()
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/asset").Expect().Status(iris.StatusOK).
+		Header("Cache-Control").Equal("public, max-age=31536000, immutable")
+	e.GET("/asset").Expect().Headers().Value("Vary").Array().Equal([]string{"Accept-Encoding", "Accept-Language"})
+	r := e.GET("/feed").Expect().Status(iris.StatusOK)
+	r.Header("Cache-Control").Equal("max-age=60, s-maxage=0, stale-while-revalidate=30")
+	if _, err := http.ParseTime(r.Raw().Header.Get("Expires")); err != nil {
+		t.Fatalf("expected an http date to the Expires but got %v", err)
+	}
+	e.GET("/account").Expect().Header("Cache-Control").Equal("no-store")
+}
+
+func TestContextWriteWithExpiration(t *testing.T) {
+	modtime := time.Date(2016, 12, 25, 15, 4, 5, 500, time.UTC)
+	app := iris.New()
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.SetContentType("text/plain")
+		ctx.WriteWithExpiration([]byte("content"), modtime)
+	})
+
+	e := httptest.New(app, t)
+	r := e.GET("/").Expect().Status(iris.StatusOK)
+	r.Body().Equal("content")
+	lastModified := r.Header("Last-Modified").Raw()
+	if lastModified != modtime.Format(http.TimeFormat) {
+		t.Fatalf("expected the Last-Modified %s but got %s", modtime.Format(http.TimeFormat), lastModified)
+	}
+
+	e.GET("/").WithHeader("If-Modified-Since", lastModified).Expect().
+		Status(iris.StatusNotModified).Body().Empty()
+	e.GET("/").WithHeader("If-Modified-Since", modtime.Add(-time.Hour).Format(http.TimeFormat)).Expect().
+		Status(iris.StatusOK).Body().Equal("content")
+	e.GET("/").WithHeader("If-Modified-Since", "invalid").Expect().Status(iris.StatusOK)
+}
+
+// testSerializer prefixes the json of the values with its options' "prefix"
+type testSerializer struct{}
+
+func (testSerializer) Serialize(v interface{}, options ...map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if len(options) > 0 {
+		prefix, _ = options[0]["prefix"].(string)
+	}
+	return append([]byte(prefix), b...), nil
+}
+
+type testNegotiated struct {
+	XMLName xml.Name `json:"-" xml:"item"`
+	ID      int      `json:"id" xml:"id"`
+}
+
+func TestContextNegotiate(t *testing.T) {
+	const jsonAPI = "application/vnd.api+json"
+	app := iris.New()
+	app.RegisterSerializer(jsonAPI, testSerializer{}, map[string]interface{}{"prefix": "api:", "charset": "UTF-8"})
+	app.Get("/render", func(ctx *iris.Context) {
+		ctx.Render(jsonAPI, map[string]int{"id": 42}, map[string]interface{}{"prefix": "override:"})
+	})
+	app.Get("/negotiate", func(ctx *iris.Context) {
+		ctx.Negotiate(iris.StatusOK, testNegotiated{ID: 42})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/render").Expect().Status(iris.StatusOK).ContentType(jsonAPI, "UTF-8").
+		Body().Equal(`override:{"id":42}`)
+	e.GET("/negotiate").WithHeader("Accept", "text/html, application/vnd.api+json;q=0.9, */*;q=0.1").Expect().
+		Status(iris.StatusOK).ContentType(jsonAPI).Body().Equal(`api:{"id":42}`)
+	e.GET("/negotiate").WithHeader("Accept", "application/xml").Expect().
+		Status(iris.StatusOK).ContentType("text/xml")
+	e.GET("/negotiate").Expect().Status(iris.StatusOK).ContentType("application/json").JSON().Object().Equal(map[string]int{"id": 42})
+	e.GET("/negotiate").WithHeader("Accept", "image/png").Expect().Status(iris.StatusNotAcceptable)
+
+	// the built'n json serializer can be replaced
+	app.RegisterSerializer("application/json", testSerializer{}, map[string]interface{}{"prefix": "custom:"})
+	e.GET("/negotiate").Expect().Status(iris.StatusOK).Body().Equal(`custom:{"id":42}`)
+}
+
+// testJSONCodec is the encoding/json codec which counts its encoders and its unmarshals
+type testJSONCodec struct {
+	encoders   int32
+	unmarshals int32
+}
+
+func (c *testJSONCodec) NewEncoder(w io.Writer) iris.JSONEncoder {
+	atomic.AddInt32(&c.encoders, 1)
+	return iris.StdJSONCodec.NewEncoder(w)
+}
+
+func (c *testJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddInt32(&c.unmarshals, 1)
+	return iris.StdJSONCodec.Unmarshal(data, v)
+}
+
+func TestContextJSONCodec(t *testing.T) {
+	codec := &testJSONCodec{}
+	app := iris.New()
+	app.UseJSONCodec(nil, iris.JSONOptions{Indent: "\t"})
+	api := app.Party("/api").UseJSONCodec(codec, iris.JSONOptions{DisableHTMLEscape: true})
+	handler := func(ctx *iris.Context) {
+		var v map[string]string
+		if err := ctx.ReadJSON(&v); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.JSON(iris.StatusCreated, v)
+	}
+	app.Post("/", handler)
+	api.Post("/", handler)
+
+	e := httptest.New(app, t)
+	e.POST("/").WithJSON(map[string]string{"html": "<b>"}).Expect().Status(iris.StatusCreated).
+		ContentType("application/json", app.Config.Charset).Body().Equal("{\n\t\"html\": \"\\u003cb\\u003e\"\n}\n")
+	e.POST("/api/").WithJSON(map[string]string{"html": "<b>"}).Expect().Status(iris.StatusCreated).
+		Body().Equal("{\"html\":\"<b>\"}\n")
+	if codec.encoders != 1 || codec.unmarshals != 1 {
+		t.Fatalf("expected the party's codec to be used once but got %d encoders and %d unmarshals", codec.encoders, codec.unmarshals)
+	}
+}
+
+func TestContextProto(t *testing.T) {
+	app := iris.New()
+	app.Post("/echo", func(ctx *iris.Context) {
+		msg := &wrapperspb.StringValue{}
+		if err := ctx.ReadProto(msg); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.Negotiate(iris.StatusOK, msg)
+	})
+
+	b, err := proto.Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := httptest.New(app, t)
+	r := e.POST("/echo").WithHeader("Content-Type", "application/x-protobuf").WithHeader("Accept", "application/x-protobuf").
+		WithBytes(b).Expect().Status(iris.StatusOK).ContentType("application/x-protobuf")
+	msg := &wrapperspb.StringValue{}
+	if err := proto.Unmarshal([]byte(r.Body().Raw()), msg); err != nil || msg.GetValue() != "hello" {
+		t.Fatalf("expected the protobuf message 'hello' but got %q, %v", msg.GetValue(), err)
+	}
+
+	e.POST("/echo").WithHeader("Content-Type", "application/json").WithBytes([]byte(`"hello"`)).Expect().
+		Status(iris.StatusOK).ContentType("application/json").Body().Equal(`"hello"`)
+	e.POST("/echo").WithHeader("Content-Type", "text/csv").WithBytes(b).Expect().Status(iris.StatusBadRequest)
+	e.POST("/echo").WithHeader("Content-Type", "application/x-protobuf").WithHeader("Accept", "text/html").
+		WithBytes(b).Expect().Status(iris.StatusNotAcceptable)
+}
+
+func TestContextMsgPack(t *testing.T) {
+	type item struct {
+		ID   int    `msgpack:"id"`
+		Name string `msgpack:"name"`
+	}
+	app := iris.New()
+	app.Post("/items", func(ctx *iris.Context) {
+		var v item
+		if err := ctx.ReadMsgPack(&v); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.Negotiate(iris.StatusCreated, v)
+	})
+
+	b, err := msgpack.Marshal(item{ID: 42, Name: "iris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := httptest.New(app, t)
+	r := e.POST("/items").WithHeader("Accept", "application/x-msgpack").WithBytes(b).Expect().
+		Status(iris.StatusCreated).ContentType("application/msgpack")
+	var got item
+	if err := msgpack.Unmarshal([]byte(r.Body().Raw()), &got); err != nil || got.ID != 42 || got.Name != "iris" {
+		t.Fatalf("unexpected item %+v, %v", got, err)
+	}
+	if ct := r.Raw().Header.Get("Content-Type"); ct != "application/msgpack" {
+		t.Fatalf("expected the msgpack content type without a charset but got %q", ct)
+	}
+}
+
+func TestContextCBOR(t *testing.T) {
+	app := iris.New()
+	app.Config.CBORDeterministic = true
+	app.Post("/readings", func(ctx *iris.Context) {
+		var v map[string]int
+		if err := ctx.ReadCBOR(&v); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.Negotiate(iris.StatusOK, v)
+	})
+
+	readings := map[string]int{"temperature": 21, "humidity": 40, "co2": 415}
+	b, err := cbor.Marshal(readings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	det, _ := cbor.CoreDetEncOptions().EncMode()
+	expected, err := det.Marshal(readings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := httptest.New(app, t)
+	e.POST("/readings").WithHeader("Accept", "application/cbor").WithBytes(b).Expect().
+		Status(iris.StatusOK).ContentType("application/cbor").Body().Equal(string(expected))
+	e.POST("/readings").WithBytes(b).Expect().Status(iris.StatusOK).JSON().Object().
+		Equal(map[string]int{"temperature": 21, "humidity": 40, "co2": 415})
+}
+
+func TestContextCSV(t *testing.T) {
+	app := iris.New()
+	app.Get("/export", func(ctx *iris.Context) {
+		rows := make(chan []string)
+		go func() {
+			defer close(rows)
+			rows <- []string{"1", "Müller, Hans", "12.50"}
+			rows <- []string{"2", `say "hi"`, "3"}
+		}()
+		ctx.CSV([]string{"id", "customer", "total"}, rows, iris.CSVOptions{Filename: "orders.csv", BOM: true, FlushRows: 1})
+	})
+
+	e := httptest.New(app, t)
+	r := e.GET("/export").Expect().Status(iris.StatusOK)
+	r.ContentType("text/csv", app.Config.Charset)
+	r.Header("Content-Disposition").Equal("attachment; filename=orders.csv")
+	r.Body().Equal("\xEF\xBB\xBFid,customer,total\n1,\"Müller, Hans\",12.50\n2,\"say \"\"hi\"\"\",3\n")
+}
+
+func TestContextNDJSON(t *testing.T) {
+	app := iris.New()
+	app.Get("/events", func(ctx *iris.Context) {
+		events := make(chan interface{})
+		go func() {
+			defer close(events)
+			events <- map[string]int{"id": 1}
+			events <- map[string]int{"id": 2}
+		}()
+		ctx.NDJSONStream(events)
+	})
+	app.Post("/events", func(ctx *iris.Context) {
+		var ids []string
+		err := ctx.ReadNDJSON(func(raw []byte) error {
+			ids = append(ids, string(raw))
+			return nil
+		})
+		if err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.Text(iris.StatusOK, strings.Join(ids, "|"))
+	})
+
+	e := httptest.New(app, t)
+	r := e.GET("/events").Expect().Status(iris.StatusOK)
+	r.ContentType("application/x-ndjson", app.Config.Charset)
+	r.Body().Equal("{\"id\":1}\n{\"id\":2}\n")
+	e.POST("/events").WithBytes([]byte("{\"id\":1}\n\n{\"id\":2}\n")).Expect().
+		Status(iris.StatusOK).Body().Equal(`{"id":1}|{"id":2}`)
+}
+
+func TestContextMultipart(t *testing.T) {
+	app := iris.New()
+	app.Get("/report", func(ctx *iris.Context) {
+		m := ctx.Multipart("mixed")
+		m.SetBoundary("iris-boundary")
+		m.JSON("metadata", map[string]string{"title": "Q3"})
+		m.File("report", "report.txt", "text/plain", strings.NewReader("revenue"))
+		m.Close()
+	})
+	app.Get("/form", func(ctx *iris.Context) {
+		m := ctx.MultipartStream("form-data")
+		m.SetBoundary("iris-boundary")
+		m.File("report", "report.txt", "", strings.NewReader("revenue"))
+		m.Close()
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/report").Expect().Status(iris.StatusOK).
+		ContentType("multipart/mixed").Body().Equal("--iris-boundary\r\n" +
+		"Content-Type: application/json; charset=" + app.Config.Charset + "\r\n\r\n" +
+		"{\"title\":\"Q3\"}\n\r\n--iris-boundary\r\n" +
+		"Content-Disposition: attachment; filename=report.txt\r\nContent-Type: text/plain\r\n\r\n" +
+		"revenue\r\n--iris-boundary--\r\n")
+	e.GET("/form").Expect().Status(iris.StatusOK).
+		Header("Content-Type").Equal("multipart/form-data; boundary=iris-boundary")
+	e.GET("/form").Expect().Body().Equal("--iris-boundary\r\n" +
+		"Content-Disposition: form-data; filename=report.txt; name=report\r\n" +
+		"Content-Type: application/octet-stream\r\n\r\n" +
+		"revenue\r\n--iris-boundary--\r\n")
+}
+
+func TestContextJSONOptions(t *testing.T) {
+	app := iris.New(iris.OptionIsDevelopment(true))
+	app.Get("/", func(ctx *iris.Context) {
+		ctx.JSON(iris.StatusOK, map[string]string{"html": "<b>"})
+	})
+	app.Get("/compact", func(ctx *iris.Context) {
+		ctx.JSON(iris.StatusOK, map[string]string{"html": "<b>"}, iris.JSONOptions{Compact: true, DisableHTMLEscape: true})
+	})
+	app.Get("/error", func(ctx *iris.Context) {
+		ctx.JSON(iris.StatusOK, map[string]interface{}{"ch": make(chan int)})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("{\n  \"html\": \"\\u003cb\\u003e\"\n}\n")
+	e.GET("/compact").Expect().Status(iris.StatusOK).Body().Equal("{\"html\":\"<b>\"}\n")
+	e.GET("/error").Expect().Status(iris.StatusInternalServerError).JSON().Object().
+		Equal(map[string]string{"error": "json: unsupported type: chan int"})
+
+	app.Config.IsDevelopment = false
+	app.Config.JSON = iris.JSONOptions{Indent: "\t"}
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("{\n\t\"html\": \"\\u003cb\\u003e\"\n}\n")
+	e.GET("/compact").Expect().Status(iris.StatusOK).Body().Equal("{\"html\":\"<b>\"}\n")
+	e.GET("/error").Expect().Body().Empty()
+}
+
+type testPriceResponse struct {
+	XMLName xml.Name `xml:"GetPriceResponse"`
+	Price   float64  `xml:"Price"`
+}
+
+func TestContextXMLOptions(t *testing.T) {
+	app := iris.New(iris.OptionXML(iris.XMLOptions{Header: xml.Header}))
+	app.Get("/price", func(ctx *iris.Context) {
+		ctx.XML(iris.StatusOK, testPriceResponse{Price: 1.5}, iris.XMLOptions{
+			Namespaces: map[string]string{"m": "https://example.com/prices"},
+		})
+	})
+	app.Get("/soap", func(ctx *iris.Context) {
+		ctx.XML(iris.StatusOK, testPriceResponse{Price: 1.5}, iris.XMLOptions{
+			Root:       "soap:Body",
+			Namespaces: map[string]string{"soap": "http://schemas.xmlsoap.org/soap/envelope/"},
+			Transform: func(v interface{}) interface{} {
+				return struct {
+					Response interface{}
+				}{v}
+			},
+		})
+	})
+
+	e := httptest.New(app, t)
+	e.GET("/price").Expect().Status(iris.StatusOK).ContentType("text/xml", app.Config.Charset).Body().
+		Equal(xml.Header + `<GetPriceResponse xmlns:m="https://example.com/prices"><Price>1.5</Price></GetPriceResponse>`)
+	e.GET("/soap").Expect().Status(iris.StatusOK).Body().
+		Equal(xml.Header + `<soap:Body xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+			`<GetPriceResponse><Price>1.5</Price></GetPriceResponse></soap:Body>`)
+}
+
+type testSignup struct {
+	Email   string `json:"email" validate:"required,email"`
+	Name    string `json:"name" validate:"required,min=2,max=8"`
+	Plan    string `json:"plan" validate:"oneof=free pro"`
+	Address struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+func TestContextValidator(t *testing.T) {
+	app := iris.New()
+	app.Post("/signup", func(ctx *iris.Context) {
+		var s testSignup
+		if err := ctx.ReadJSON(&s); err != nil {
+			return
+		}
+		ctx.Text(iris.StatusCreated, s.Name)
+	})
+
+	e := httptest.New(app, t)
+	e.POST("/signup").WithJSON(map[string]interface{}{
+		"email": "gerasimos@example.com", "name": "Gerasimos", "plan": "pro", "address": map[string]string{"city": "Athens"},
+	}).Expect().Status(iris.StatusUnprocessableEntity).JSON().Object().Value("errors").Array().Equal([]iris.FieldError{
+		{Field: "name", Rule: "max", Code: "max", Param: "8", Message: "must be at most 8 characters"},
+	})
+	e.POST("/signup").WithJSON(map[string]interface{}{"email": "gerasimos", "name": "Makis", "plan": "gold"}).
+		Expect().Status(iris.StatusUnprocessableEntity).JSON().Object().Value("errors").Array().Equal([]iris.FieldError{
+		{Field: "email", Rule: "email", Code: "email", Message: "must be an email address"},
+		{Field: "plan", Rule: "oneof", Code: "oneof", Param: "free pro", Message: "must be one of free, pro"},
+		{Field: "address.city", Rule: "required", Code: "required", Message: "is required"},
+	})
+	e.POST("/signup").WithJSON(map[string]interface{}{
+		"email": "makis@example.com", "name": "Makis", "plan": "free", "address": map[string]string{"city": "Athens"},
+	}).Expect().Status(iris.StatusCreated).Body().Equal("Makis")
+
+	app.Validator = nil
+	e.POST("/signup").WithJSON(map[string]interface{}{"name": "Gerasimos"}).
+		Expect().Status(iris.StatusCreated).Body().Equal("Gerasimos")
+}
+
+type testUpdateArticle struct {
+	ID      int      `param:"id"`
+	UserID  int      `user:"id" validate:"required"`
+	Title   string   `json:"title" validate:"required"`
+	Tags    []string `query:"tag"`
+	Draft   *bool    `query:"draft"`
+	TraceID string   `header:"X-Trace-Id"`
+}
+
+func TestContextBind(t *testing.T) {
+	app := iris.New()
+	app.RegisterBinder("user", func(ctx *iris.Context, name string) (interface{}, bool) {
+		v := ctx.Get("user." + name)
+		return v, v != nil
+	})
+	app.Put("/articles/:id", func(ctx *iris.Context) {
+		// the claims of a jwt are float64
+		if ctx.RequestHeader("Authorization") != "" {
+			ctx.Set("user.id", float64(7))
+		}
+		ctx.Next()
+	}, func(ctx *iris.Context) {
+		var req testUpdateArticle
+		if err := ctx.Bind(&req); err != nil {
+			// the validation errors are already rendered
+			if _, ok := err.(iris.ValidationErrors); !ok {
+				ctx.EmitError(iris.StatusBadRequest)
+			}
+			return
+		}
+		ctx.JSON(iris.StatusOK, req)
+	})
+
+	e := httptest.New(app, t)
+	e.PUT("/articles/42").WithQuery("tag", "go").WithQuery("tag", "web").WithQuery("draft", "true").
+		WithHeader("Authorization", "Bearer token").WithHeader("X-Trace-Id", "abc").
+		WithJSON(map[string]string{"title": "Iris"}).Expect().Status(iris.StatusOK).JSON().Object().Equal(map[string]interface{}{
+		"ID": 42, "UserID": 7, "title": "Iris", "Tags": []string{"go", "web"}, "Draft": true, "TraceID": "abc",
+	})
+	e.PUT("/articles/42").WithJSON(map[string]string{"title": "Iris"}).Expect().Status(iris.StatusUnprocessableEntity).
+		JSON().Object().Value("errors").Array().Element(0).Object().ValueEqual("field", "UserID")
+	e.PUT("/articles/abc").WithHeader("Authorization", "Bearer token").WithJSON(map[string]string{"title": "Iris"}).
+		Expect().Status(iris.StatusBadRequest)
+}
+
+type testOrderForm struct {
+	Items []struct {
+		Name string `form:"name" json:"name"`
+		Qty  int    `form:"qty" json:"qty"`
+	} `form:"items" json:"items"`
+	Attrs map[string]string `form:"attrs" json:"attrs"`
+	Tags  []string          `form:"tags" json:"tags"`
+}
+
+func TestContextReadFormDeep(t *testing.T) {
+	app := iris.New(iris.OptionMaxFormSliceIndex(10))
+	handler := func(ctx *iris.Context) {
+		var order testOrderForm
+		read := ctx.ReadForm
+		if ctx.Method() == iris.MethodGet {
+			read = ctx.ReadQuery
+		}
+		if err := read(&order); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.JSON(iris.StatusOK, order)
+	}
+	app.Post("/orders", handler)
+	app.Get("/orders", handler)
+
+	expected := map[string]interface{}{
+		"items": []map[string]interface{}{{"name": "pen", "qty": 2}, {"name": "ink", "qty": 0}},
+		"attrs": map[string]string{"color": "red"},
+		"tags":  []string{"gift", "express"},
+	}
+	e := httptest.New(app, t)
+	e.POST("/orders").WithFormField("items[0][name]", "pen").WithFormField("items[0][qty]", "2").
+		WithFormField("items[1].name", "ink").WithFormField("attrs[color]", "red").
+		WithFormField("tags[]", "gift").WithFormField("tags[]", "express").
+		Expect().Status(iris.StatusOK).JSON().Object().Equal(expected)
+	e.GET("/orders").WithQueryString("items[0][name]=pen&items[0][qty]=2&items[1][name]=ink&attrs[color]=red&tags[]=gift&tags[]=express").
+		Expect().Status(iris.StatusOK).JSON().Object().Equal(expected)
+	e.GET("/orders").WithQuery("items[11][name]", "pen").Expect().Status(iris.StatusBadRequest)
+}
+
+type testUploadForm struct {
+	Caption string                `form:"caption"`
+	Avatar  *multipart.FileHeader `form:"avatar" file:"max=1KB"`
+	Doc     []byte                `form:"doc" file:"max=16B"`
+	Photo   *multipart.FileHeader `form:"photo" file:"accept=image/*"`
+}
+
+func TestContextBindMultipart(t *testing.T) {
+	app := iris.New()
+	app.Post("/upload", func(ctx *iris.Context) {
+		var form testUploadForm
+		if err := ctx.Bind(&form); err != nil {
+			return
+		}
+		ctx.JSON(iris.StatusOK, map[string]interface{}{
+			"caption": form.Caption, "avatar": form.Avatar.Filename, "size": form.Avatar.Size, "doc": string(form.Doc),
+		})
+	})
+
+	e := httptest.New(app, t)
+	e.POST("/upload").WithMultipart().WithFormField("caption", "me").
+		WithFileBytes("avatar", "me.png", []byte("0123456789")).WithFileBytes("doc", "doc.txt", []byte("hello")).
+		Expect().Status(iris.StatusOK).JSON().Object().
+		Equal(map[string]interface{}{"caption": "me", "avatar": "me.png", "size": 10, "doc": "hello"})
+	e.POST("/upload").WithMultipart().WithFileBytes("doc", "doc.txt", []byte("more than sixteen bytes")).
+		WithFileBytes("photo", "photo.png", []byte("not an image")).
+		Expect().Status(iris.StatusUnprocessableEntity).JSON().Object().Value("errors").Array().Equal([]iris.FieldError{
+		{Field: "doc", Rule: "max", Code: "max", Param: "16", Message: "must be at most 16 bytes"},
+		{Field: "photo", Rule: "accept", Code: "accept", Param: "image/*", Message: "must be one of image/*"},
+	})
+}
+
+type testProfileForm struct {
+	Email string   `json:"email" sanitize:"trim,lower" validate:"required,email"`
+	Bio   string   `json:"bio" sanitize:"strip,nfc,escape"`
+	Tags  []string `json:"tags" sanitize:"trim"`
+}
+
+func TestContextSanitize(t *testing.T) {
+	app := iris.New()
+	app.RegisterSanitizer("lower", strings.ToLower)
+	app.Post("/profile", func(ctx *iris.Context) {
+		var p testProfileForm
+		if err := ctx.ReadJSON(&p); err != nil {
+			return
+		}
+		ctx.JSON(iris.StatusOK, p)
+	})
+
+	e := httptest.New(app, t)
+	e.POST("/profile").WithJSON(map[string]interface{}{
+		"email": "  Makis@Example.COM \n", "bio": "Café\x00 <b>", "tags": []string{" go ", "web "},
+	}).Expect().Status(iris.StatusOK).JSON().Object().Equal(map[string]interface{}{
+		"email": "makis@example.com", "bio": "Café &lt;b&gt;", "tags": []string{"go", "web"},
+	})
+}
+
+func TestContextValidationI18n(t *testing.T) {
+	app := iris.New()
+	app.I18n.Add("en-US", map[string]string{"validation.required": "{field} is required"})
+	app.I18n.Add("el-GR", map[string]string{
+		"validation.required": "το {field} είναι υποχρεωτικό",
+		"validation.max":      "το {field} πρέπει να έχει έως {param} χαρακτήρες",
+	})
+	app.Post("/signup", func(ctx *iris.Context) {
+		var s testSignup
+		if err := ctx.ReadJSON(&s); err != nil {
+			return
+		}
+		ctx.Text(iris.StatusCreated, s.Name)
+	})
+
+	e := httptest.New(app, t)
+	body := map[string]interface{}{"email": "makis@example.com", "name": "Gerasimos", "plan": "pro"}
+	e.POST("/signup").WithHeader("Accept-Language", "el").WithJSON(body).Expect().Status(iris.StatusUnprocessableEntity).
+		JSON().Object().Value("errors").Array().Equal([]iris.FieldError{
+		{Field: "name", Rule: "max", Code: "max", Param: "8", Message: "το name πρέπει να έχει έως 8 χαρακτήρες"},
+		{Field: "address.city", Rule: "required", Code: "required", Message: "το address.city είναι υποχρεωτικό"},
+	})
+	// the max is not translated to english, the default message is kept
+	e.POST("/signup").WithJSON(body).Expect().Status(iris.StatusUnprocessableEntity).
+		JSON().Object().Value("errors").Array().Equal([]iris.FieldError{
+		{Field: "name", Rule: "max", Code: "max", Param: "8", Message: "must be at most 8 characters"},
+		{Field: "address.city", Rule: "required", Code: "required", Message: "address.city is required"},
+	})
+}
+
+func TestNewTestContext(t *testing.T) {
+	requireAdmin := func(ctx *iris.Context) {
+		if ctx.RequestHeader("X-Role") != "admin" {
+			ctx.EmitError(iris.StatusForbidden)
+			return
+		}
+		ctx.Next()
+	}
+	updateUser := func(ctx *iris.Context) {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := ctx.ReadJSON(&user); err != nil {
+			ctx.EmitError(iris.StatusBadRequest)
+			return
+		}
+		ctx.JSON(iris.StatusOK, iris.Map{"id": ctx.Param("id"), "name": user.Name, "page": ctx.URLParam("page")})
+	}
+
+	ctx := iris.NewTestContext(iris.MethodPut, "/users/42?page=2", iris.WithTestParam("id", "42"),
+		iris.WithTestHeader("X-Role", "admin"), iris.WithTestJSON(iris.Map{"name": "kataras"}))
+	ctx.Middleware = iris.Middleware{iris.HandlerFunc(requireAdmin), iris.HandlerFunc(updateUser)}
+	ctx.Do()
+	if status := ctx.ResponseWriter.StatusCode(); status != iris.StatusOK {
+		t.Fatalf("expected status 200 but got %d", status)
+	}
+	if body := string(ctx.ResponseWriter.Body()); body != `{"id":"42","name":"kataras","page":"2"}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+
+	ctx = iris.NewTestContext(iris.MethodPut, "/users/42")
+	ctx.Middleware = iris.Middleware{iris.HandlerFunc(requireAdmin), iris.HandlerFunc(updateUser)}
+	ctx.Do()
+	if status := ctx.ResponseWriter.StatusCode(); status != iris.StatusForbidden {
+		t.Fatalf("expected status 403 but got %d", status)
+	}
+}
@@ -0,0 +1,70 @@
+package iris
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	// cspNonceContextKey the context's value of the request's nonce, see the ctx.CSPNonce
+	cspNonceContextKey = "iris.cspnonce"
+	// CSPNonceTemplateKey the key of the request's nonce in the map bindings of the templates,
+	// which is set when the ContentSecurityPolicy is used, i.e <script nonce="{{ .CSPNonce }}">
+	CSPNonceTemplateKey = "CSPNonce"
+)
+
+// ContentSecurityPolicy returns a middleware which sets the Content-Security-Policy header of the 'policy',
+// its "{nonce}" placeholders are replaced by a new cryptographic nonce of each request, see the ctx.CSPNonce.
+// The nonce is exposed to the templates of the map bindings as the CSPNonceTemplateKey, "CSPNonce",
+// so the inline scripts and styles of the page are allowed without the 'unsafe-inline'.
+//
+// Usage:
+// app.Use(iris.ContentSecurityPolicy("default-src 'self'; script-src 'self' 'nonce-{nonce}'"))
+// in the template: <script nonce="{{ .CSPNonce }}">init()</script>
+func ContentSecurityPolicy(policy string) HandlerFunc {
+	return func(ctx *Context) {
+		header := policy
+		if strings.Contains(policy, "{nonce}") {
+			header = strings.Replace(policy, "{nonce}", ctx.CSPNonce(), -1)
+		}
+		ctx.SetHeader("Content-Security-Policy", header)
+		ctx.Next()
+	}
+}
+
+// CSPNonce returns the cryptographic nonce of the request, the url-safe base64 of 16 random bytes, which the html templates don't escape,
+// it's generated on the first call, the ContentSecurityPolicy's header contains the same nonce
+func (ctx *Context) CSPNonce() string {
+	if nonce := ctx.GetString(cspNonceContextKey); nonce != "" {
+		return nonce
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// the crypto/rand never fails on the supported platforms
+		panic(err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	ctx.Set(cspNonceContextKey, nonce)
+	return nonce
+}
+
+// exposeCSPNonce sets the request's nonce, if any, to the 'binding' of a template, if it's a map without the CSPNonceTemplateKey
+func (ctx *Context) exposeCSPNonce(binding interface{}) {
+	nonce := ctx.GetString(cspNonceContextKey)
+	if nonce == "" {
+		return
+	}
+	var m map[string]interface{}
+	switch b := binding.(type) {
+	case Map:
+		m = b
+	case map[string]interface{}:
+		m = b
+	default:
+		return
+	}
+	if _, ok := m[CSPNonceTemplateKey]; !ok && m != nil {
+		m[CSPNonceTemplateKey] = nonce
+	}
+}
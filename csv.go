@@ -0,0 +1,122 @@
+package iris
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+)
+
+const (
+	// DefaultCSVFlushRows the rows which the ctx.CSV writes between its flushes to the client
+	DefaultCSVFlushRows = 100
+	// contentCSV the content type of the csv
+	contentCSV = "text/csv"
+	// utf8BOM the byte order mark which lets the Excel detect the UTF-8 of a csv
+	utf8BOM = "\xEF\xBB\xBF"
+)
+
+// CSVOptions the options of the ctx.CSV
+type CSVOptions struct {
+	// Filename the name of the downloaded file, i.e "orders.csv", it's sent by the Content-Disposition header
+	// Defaults to empty, the csv is not sent as an attachment
+	Filename string
+	// BOM writes the UTF-8 byte order mark first, so the Excel opens the non-ASCII text correctly
+	// Defaults to false
+	BOM bool
+	// Comma the field delimiter, i.e ';' for the Excel of the locales with the decimal comma
+	// Defaults to ','
+	Comma rune
+	// UseCRLF ends the lines with \r\n
+	// Defaults to false
+	UseCRLF bool
+	// FlushRows the rows which are written between the flushes to the client
+	// Defaults to the DefaultCSVFlushRows
+	FlushRows int
+}
+
+// CSV streams the 'headers', if any, and the 'rows', until the channel is closed, as csv, with the quoting of the RFC 4180.
+// The rows are written directly to the client, flushed every FlushRows, they are not buffered,
+// so the large exports don't keep the whole response in memory, the status code and the headers should be set before.
+// It stops and returns the request context's error if the client goes away,
+// so the producer of the rows should stop on the ctx.Request.Context().Done() too.
+//
+// Usage:
+// rows := make(chan []string)
+// go func() {
+//	defer close(rows)
+//	for _, o := range orders {
+//		select {
+//		case rows <- []string{o.ID, o.Customer, o.Total}:
+//		case <-ctx.Request.Context().Done():
+//			return
+//		}
+//	}
+// }()
+// ctx.CSV([]string{"id", "customer", "total"}, rows, iris.CSVOptions{Filename: "orders.csv", BOM: true})
+func (ctx *Context) CSV(headers []string, rows <-chan []string, options ...CSVOptions) error {
+	var o CSVOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.FlushRows <= 0 {
+		o.FlushRows = DefaultCSVFlushRows
+	}
+
+	h := ctx.ResponseWriter.Header()
+	h.Set(contentType, contentCSV+"; charset="+ctx.framework.Config.Charset)
+	if o.Filename != "" {
+		h.Set(contentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": o.Filename}))
+	}
+	if ctx.ResponseWriter.StatusCode() == 0 {
+		ctx.SetStatusCode(StatusOK)
+	}
+
+	out := ctx.ResponseWriter.stream()
+	flusher, _ := out.(http.Flusher)
+	if o.BOM {
+		if _, err := out.Write([]byte(utf8BOM)); err != nil {
+			return err
+		}
+	}
+	w := csv.NewWriter(out)
+	if o.Comma != 0 {
+		w.Comma = o.Comma
+	}
+	w.UseCRLF = o.UseCRLF
+
+	flush := func() error {
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	if len(headers) > 0 {
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+	done := ctx.Request.Context().Done()
+	for n := 1; ; n++ {
+		select {
+		case row, ok := <-rows:
+			if !ok {
+				return flush()
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+			if n%o.FlushRows == 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-done:
+			return ctx.Request.Context().Err()
+		}
+	}
+}
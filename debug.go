@@ -0,0 +1,146 @@
+package iris
+
+import (
+	"expvar"
+	"net"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DebugOptions the options of the EnableDebugEndpoints
+type DebugOptions struct {
+	// Username and Password if not empty the endpoints are protected by the basic authentication
+	Username string
+	Password string
+	// AllowedIPs the IPs or the CIDRs, i.e "10.0.0.0/8", of the clients which can access the endpoints,
+	// the connection's address is checked, the X-Real-Ip and X-Forwarded-For headers are ignored
+	// Defaults to empty, all clients are allowed
+	AllowedIPs []string
+}
+
+// DebugStats the framework's runtime stats which are served by the debug endpoints
+type DebugStats struct {
+	Version             string  `json:"version"`
+	GoVersion           string  `json:"goVersion"`
+	Uptime              string  `json:"uptime"`
+	Goroutines          int     `json:"goroutines"`
+	Routes              int     `json:"routes"`
+	Hosts               int     `json:"hosts"`
+	RealtimeServers     int     `json:"realtimeServers"`
+	RealtimeConnections int     `json:"realtimeConnections"`
+	HeapAlloc           uint64  `json:"heapAlloc"`
+	HeapObjects         uint64  `json:"heapObjects"`
+	GCPauseTotal        string  `json:"gcPauseTotal"`
+	NumGC               uint32  `json:"numGC"`
+	GCCPUFraction       float64 `json:"gcCPUFraction"`
+}
+
+// DebugStats returns the framework's runtime stats, the routes, the additional hosts, the websocket connections and the memory
+func (s *Framework) DebugStats() DebugStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	s.shutdownMu.Lock()
+	hosts := len(s.hosts)
+	realtimeServers := s.realtimeServers
+	s.shutdownMu.Unlock()
+	connections := 0
+	for _, ws := range realtimeServers {
+		connections += ws.Metrics().Connections
+	}
+	var uptime time.Duration
+	if !s.startedAt.IsZero() {
+		uptime = time.Since(s.startedAt).Truncate(time.Second)
+	}
+
+	return DebugStats{
+		Version:             Version,
+		GoVersion:           runtime.Version(),
+		Uptime:              uptime.String(),
+		Goroutines:          runtime.NumGoroutine(),
+		Routes:              len(s.mux.lookups),
+		Hosts:               hosts,
+		RealtimeServers:     len(realtimeServers),
+		RealtimeConnections: connections,
+		HeapAlloc:           mem.HeapAlloc,
+		HeapObjects:         mem.HeapObjects,
+		GCPauseTotal:        time.Duration(mem.PauseTotalNs).String(),
+		NumGC:               mem.NumGC,
+		GCCPUFraction:       mem.GCCPUFraction,
+	}
+}
+
+// EnableDebugEndpoints mounts the debug endpoints of the default iris instance, see the Framework's EnableDebugEndpoints
+func EnableDebugEndpoints(prefix string, options ...DebugOptions) {
+	Default.EnableDebugEndpoints(prefix, options...)
+}
+
+// EnableDebugEndpoints mounts the debug endpoints under the 'prefix', i.e "/debug":
+//
+// prefix/pprof/ the pprof index and profiles, i.e go tool pprof http://localhost:8080/debug/pprof/heap
+// prefix/vars the expvar variables
+// prefix/iris the framework's Stats
+//
+// The endpoints expose the internals of the application, they should be protected by the options' credentials and IP restrictions
+func (s *Framework) EnableDebugEndpoints(prefix string, options ...DebugOptions) {
+	var o DebugOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	guard, err := debugGuard(o)
+	if err != nil {
+		s.logPanic(err)
+	}
+
+	debug := s.Party(prefix, guard)
+	profiles := func(ctx *Context) {
+		switch name := strings.TrimPrefix(ctx.Param("name"), slash); name {
+		case "":
+			pprof.Index(ctx.ResponseWriter, ctx.Request)
+		case "cmdline":
+			pprof.Cmdline(ctx.ResponseWriter, ctx.Request)
+		case "profile":
+			pprof.Profile(ctx.ResponseWriter, ctx.Request)
+		case "symbol":
+			pprof.Symbol(ctx.ResponseWriter, ctx.Request)
+		case "trace":
+			pprof.Trace(ctx.ResponseWriter, ctx.Request)
+		default:
+			pprof.Handler(name).ServeHTTP(ctx.ResponseWriter, ctx.Request)
+		}
+	}
+	debug.Get("/pprof/*name", profiles)
+	debug.Post("/pprof/*name", profiles)
+	debug.Get("/vars", ToHandler(expvar.Handler()))
+	debug.Get("/iris", func(ctx *Context) {
+		ctx.JSON(StatusOK, s.Stats())
+	})
+}
+
+// debugGuard returns the middleware which checks the client's IP and credentials of the debug endpoints
+func debugGuard(o DebugOptions) (HandlerFunc, error) {
+	networks, err := parseNetworks(o.AllowedIPs)
+	if err != nil {
+		return nil, err
+	}
+	next := HandlerFunc(func(ctx *Context) { ctx.Next() })
+	if o.Username != "" || o.Password != "" {
+		next = basicAuthHandler(o.Username, o.Password, "debug", next)
+	}
+
+	return func(ctx *Context) {
+		if len(networks) > 0 {
+			host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+			if err != nil {
+				host = ctx.Request.RemoteAddr
+			}
+			if !containsIP(networks, net.ParseIP(host)) {
+				ctx.EmitError(StatusForbidden)
+				return
+			}
+		}
+		next(ctx)
+	}, nil
+}
@@ -0,0 +1,544 @@
+package iris
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dir is a system directory which can be served by the HandleDir, i.e iris.Dir("./public"),
+// it's an fs.FS too, so it can be a layer of the OverlayFS
+type Dir string
+
+var _ fs.FS = Dir("")
+
+// Open opens the file 'name' of the directory, implements the fs.FS
+func (d Dir) Open(name string) (fs.File, error) {
+	return os.DirFS(string(d)).Open(name)
+}
+
+// DefaultDirIndexName is the default index file of the HandleDir's directories
+const DefaultDirIndexName = "index.html"
+
+const (
+	// CacheControlImmutable the Cache-Control of the files which never change, i.e the fingerprinted "app.3f9ab2.js"
+	CacheControlImmutable = "public, max-age=31536000, immutable"
+	// CacheControlNoCache the Cache-Control of the files which should be revalidated on each request, i.e the html pages
+	CacheControlNoCache = "no-cache"
+)
+
+// DirCacheControl the Cache-Control value of the files which match its Pattern, look DirOptions.CacheControl
+type DirCacheControl struct {
+	// Pattern an extension, i.e ".html", or a path.Match pattern which is matched against
+	// the file's path, relative to the served directory, and against the file's name, i.e "js/*.min.js" or "*.woff2"
+	Pattern string
+	// Value the Cache-Control header's value, i.e iris.CacheControlImmutable or "public, max-age=300"
+	Value string
+}
+
+// match reports whether the file 'name' matches the pattern
+func (c DirCacheControl) match(name string) bool {
+	if strings.HasPrefix(c.Pattern, ".") && !strings.ContainsAny(c.Pattern, "*?[") {
+		return strings.EqualFold(path.Ext(name), c.Pattern)
+	}
+	if ok, _ := path.Match(c.Pattern, name); ok {
+		return true
+	}
+	ok, _ := path.Match(c.Pattern, path.Base(name))
+	return ok
+}
+
+// DirOptions the options of the HandleDir
+type DirOptions struct {
+	// IndexName the file which is served on a directory's request, if exists
+	// Defaults to "index.html", set it to "-" to disable the index files
+	IndexName string
+	// ShowList if true then the contents of a directory, without an index file, are listed
+	// Defaults to false
+	ShowList bool
+	// ListTemplate the template of the directories' listing, it's executed with a *DirListing
+	// Defaults to nil, the DefaultDirListTemplate is used.
+	// The listing is written as json instead, when the client accepts json, i.e Accept: application/json
+	ListTemplate *template.Template
+	// ShowHidden if true then the hidden files, the files which their name starts with a dot, are listed
+	// Defaults to false
+	ShowHidden bool
+	// SPA if true then the root's index file is served on the not found files,
+	// so the client-side router of a single page application can handle the request's path
+	// Defaults to false
+	SPA bool
+	// CacheControl the Cache-Control values of the served files, by their extension or a path pattern,
+	// the first matching rule is applied, i.e
+	// []iris.DirCacheControl{{".html", iris.CacheControlNoCache}, {"*.js", "public, max-age=86400"}}
+	// Defaults to nil, no Cache-Control header is sent
+	CacheControl []DirCacheControl
+	// Fingerprint if true then the files are served under their fingerprinted names too, i.e "/assets/app.3f9ab2c1.js" for the "/assets/app.js",
+	// with the CacheControlImmutable, the {{ asset "/assets/app.js" }} template func and the app.Asset resolve to the fingerprinted names.
+	// The hash is the content's hash, a fingerprinted name of an older content is not found
+	// Defaults to false
+	Fingerprint bool
+	// Compress if true then the files are gzip compressed, if the client supports it.
+	// Note that the precompressed files, i.e the "app.js.br" and the "app.js.gz" next to the "app.js",
+	// are served, without any compression, when they exist and the client accepts their encoding, even if Compress is false
+	// Defaults to false
+	Compress bool
+}
+
+// dirHandler serves the files of a file system under a request path
+type dirHandler struct {
+	fs      fs.FS
+	prefix  string
+	options DirOptions
+	assets  *assetResolver
+	// etags the entity tags of the files without a modification time, by their name,
+	// these files are embedded so their contents never change
+	etags sync.Map
+}
+
+func newDirHandler(fileSystem fs.FS, prefix string, options DirOptions, assets *assetResolver) *dirHandler {
+	if options.IndexName == "" {
+		options.IndexName = DefaultDirIndexName
+	}
+	return &dirHandler{fs: fileSystem, prefix: prefix, options: options, assets: assets}
+}
+
+// name returns the file system's name of the request's path, "." for the root
+func (d *dirHandler) name(reqPath string) string {
+	name := strings.TrimPrefix(path.Clean("/"+reqPath), d.prefix)
+	name = strings.Trim(name, slash)
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// fingerprinted returns the original file of the fingerprinted 'name', i.e "app.js" for the "app.3f9ab2c1.js",
+// if the hash is the current content's hash of the original file
+func (d *dirHandler) fingerprinted(name string) (string, fs.FileInfo, bool) {
+	original, hash, ok := unfingerprint(name)
+	if !ok {
+		return "", nil, false
+	}
+	info, err := fs.Stat(d.fs, original)
+	if err != nil || info.IsDir() {
+		return "", nil, false
+	}
+	if h, err := d.assets.hash(d.prefix + slash + original); err != nil || h != hash {
+		return "", nil, false
+	}
+	return original, info, true
+}
+
+// index returns the index file of the 'dir', if any
+func (d *dirHandler) index(dir string) (string, fs.FileInfo, bool) {
+	if d.options.IndexName == "-" {
+		return "", nil, false
+	}
+	name := path.Join(dir, d.options.IndexName)
+	info, err := fs.Stat(d.fs, name)
+	if err != nil || info.IsDir() {
+		return "", nil, false
+	}
+	return name, info, true
+}
+
+func (d *dirHandler) serve(ctx *Context) {
+	reqPath := ctx.Request.URL.Path
+	name := d.name(reqPath)
+
+	info, err := fs.Stat(d.fs, name)
+	if err != nil {
+		if d.options.Fingerprint {
+			if original, originalInfo, ok := d.fingerprinted(name); ok {
+				ctx.SetHeader("Cache-Control", CacheControlImmutable)
+				d.serveFile(ctx, original, originalInfo)
+				return
+			}
+		}
+		if d.options.SPA {
+			if index, indexInfo, ok := d.index("."); ok {
+				d.serveFile(ctx, index, indexInfo)
+				return
+			}
+		}
+		ctx.EmitError(StatusNotFound)
+		return
+	}
+
+	if !info.IsDir() {
+		d.serveFile(ctx, name, info)
+		return
+	}
+
+	// the relative links of the index and the listing are resolved by the trailing slash
+	if !strings.HasSuffix(reqPath, slash) {
+		target := reqPath + slash
+		if q := ctx.Request.URL.RawQuery; q != "" {
+			target += "?" + q
+		}
+		ctx.Redirect(target, StatusMovedPermanently)
+		return
+	}
+
+	if index, indexInfo, ok := d.index(name); ok {
+		d.serveFile(ctx, index, indexInfo)
+		return
+	}
+
+	if d.options.ShowList {
+		d.list(ctx, name)
+		return
+	}
+
+	if d.options.SPA {
+		if index, indexInfo, ok := d.index("."); ok {
+			d.serveFile(ctx, index, indexInfo)
+			return
+		}
+	}
+	ctx.EmitError(StatusNotFound)
+}
+
+// precompressedEncodings the encodings of the precompressed files, by preference,
+// i.e the "app.js.br" or the "app.js.gz" is served instead of the "app.js"
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// acceptsEncoding reports whether the Accept-Encoding 'header' accepts the content 'coding'
+func acceptsEncoding(header string, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		token, params := part, ""
+		if idx := strings.IndexByte(part, ';'); idx > -1 {
+			token, params = strings.TrimSpace(part[:idx]), strings.TrimSpace(part[idx+1:])
+		}
+		if token != coding && token != "*" {
+			continue
+		}
+		if strings.HasPrefix(params, "q=") {
+			if q, err := strconv.ParseFloat(params[2:], 64); err == nil && q <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// precompressed returns the precompressed variant of the file 'name' which the client accepts, if any,
+// 'exists' is true if the file has any precompressed variant, even if the client doesn't accept its encoding.
+func (d *dirHandler) precompressed(ctx *Context, name string) (encoding string, encodedName string, encodedInfo fs.FileInfo, exists bool) {
+	acceptEncoding := ctx.RequestHeader(acceptEncodingHeader)
+	for _, p := range precompressedEncodings {
+		info, err := fs.Stat(d.fs, name+p.ext)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		exists = true
+		if encoding == "" && acceptsEncoding(acceptEncoding, p.encoding) {
+			encoding, encodedName, encodedInfo = p.encoding, name+p.ext, info
+		}
+	}
+	return
+}
+
+// serveFile writes the file 'name' to the client, the http.ServeContent handles the conditional and the range requests.
+//
+// The precompressed variant of the file, i.e the "app.js.br" or the "app.js.gz", is served instead, if the client accepts its encoding.
+func (d *dirHandler) serveFile(ctx *Context, name string, info fs.FileInfo) {
+	encoding, encodedName, encodedInfo, hasEncoded := d.precompressed(ctx, name)
+	if hasEncoded {
+		ctx.ResponseWriter.Header().Add(varyHeader, acceptEncodingHeader)
+	}
+	// the content type is still the type of the original file, by its name
+	servedName, servedInfo := name, info
+	if encoding != "" {
+		servedName, servedInfo = encodedName, encodedInfo
+	}
+
+	f, err := d.fs.Open(servedName)
+	if err != nil {
+		ctx.EmitError(StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			ctx.EmitError(StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+
+	etag, err := d.etag(servedName, servedInfo, content)
+	if err != nil {
+		ctx.EmitError(StatusInternalServerError)
+		return
+	}
+
+	// written through the context's response writer, so the status code, the metrics and the response cache see the file
+	var writer http.ResponseWriter = ctx.ResponseWriter
+	if encoding != "" {
+		ctx.SetHeader(contentEncodingHeader, encoding)
+	} else if d.options.Compress && ctx.clientAllowsGzip() {
+		if !hasEncoded {
+			ctx.ResponseWriter.Header().Add(varyHeader, acceptEncodingHeader)
+		}
+		ctx.SetHeader(contentEncodingHeader, "gzip")
+		// the ranges of the compressed content can't be served
+		ctx.Request.Header.Del("Range")
+		// each encoding is a different representation of the file
+		etag = strings.TrimSuffix(etag, `"`) + `-gzip"`
+		gzipResWriter := acquireGzipResponseWriter(ctx.ResponseWriter)
+		writer = gzipResWriter
+		defer releaseGzipResponseWriter(gzipResWriter)
+	}
+
+	ctx.SetHeader("ETag", etag)
+	// the fingerprinted files are already immutable
+	if cacheControl := d.cacheControl(name); cacheControl != "" && ctx.ResponseWriter.Header().Get("Cache-Control") == "" {
+		ctx.SetHeader("Cache-Control", cacheControl)
+	}
+	// the If-None-Match, If-Modified-Since, If-Range, Range and the HEAD requests are handled by the http.ServeContent
+	http.ServeContent(writer, ctx.Request, info.Name(), servedInfo.ModTime(), content)
+	if ctx.ResponseWriter.StatusCode() == StatusRequestedRangeNotSatisfiable {
+		// the Content-Range header is kept, the body is rendered by the error handler
+		ctx.EmitError(StatusRequestedRangeNotSatisfiable)
+	}
+}
+
+// cacheControl returns the Cache-Control value of the file 'name', empty if no rule matches
+func (d *dirHandler) cacheControl(name string) string {
+	for _, c := range d.options.CacheControl {
+		if c.match(name) {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// etag returns the strong entity tag of a file, by its modification time and size,
+// or by its contents' hash when the file system has no modification times, i.e an embed.FS
+func (d *dirHandler) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()), nil
+	}
+	if etag, ok := d.etags.Load(name); ok {
+		return etag.(string), nil
+	}
+
+	h := sha1.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:8]) + `"`
+	d.etags.Store(name, etag)
+	return etag, nil
+}
+
+// DirListEntry is a file or a directory of a DirListing
+type DirListEntry struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// DirBreadcrumb is a parent directory of a DirListing, from the root to the listed directory
+type DirBreadcrumb struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// DirListing is the data of a directory's listing, which is passed to the DirOptions.ListTemplate or written as json
+type DirListing struct {
+	// Path the request path of the directory, with a trailing slash
+	Path        string          `json:"path"`
+	Breadcrumbs []DirBreadcrumb `json:"breadcrumbs"`
+	// Entries the directories first and then the files, sorted by the Sort field
+	Entries []DirListEntry `json:"entries"`
+	// Sort the field which the entries are sorted by, "name", "size" or "modtime", given by the ?sort= url parameter
+	Sort string `json:"sort"`
+	// Desc true if the entries are sorted in descending order, given by the ?order=desc url parameter
+	Desc bool `json:"desc"`
+}
+
+// DefaultDirListTemplate the template of the directories' listing, used when the DirOptions.ListTemplate is nil
+var DefaultDirListTemplate = template.Must(template.New("dirlist").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{ .Path }}</title></head>
+<body>
+<nav>{{ range $i, $b := .Breadcrumbs }}{{ if $i }} / {{ end }}<a href="{{ $b.Path }}">{{ $b.Name }}</a>{{ end }}</nav>
+<table>
+<tr><th><a href="?sort=name">Name</a></th><th><a href="?sort=size">Size</a></th><th><a href="?sort=modtime">Modified</a></th></tr>
+{{ range .Entries }}<tr><td><a href="{{ .Path }}">{{ .Name }}{{ if .IsDir }}/{{ end }}</a></td><td>{{ if not .IsDir }}{{ .Size }}{{ end }}</td><td>{{ .ModTime.Format "2006-01-02 15:04:05" }}</td></tr>
+{{ end }}</table>
+</body>
+</html>
+`))
+
+// listing returns the listing of the directory 'dir', the 'reqPath' is the request path of the directory
+func (d *dirHandler) listing(dir string, reqPath string, sortBy string, desc bool) (*DirListing, error) {
+	entries, err := fs.ReadDir(d.fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &DirListing{Path: reqPath, Sort: sortBy, Desc: desc}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !d.options.ShowHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		link := url.URL{Path: name}
+		e := DirListEntry{Name: name, Path: reqPath + link.String(), IsDir: entry.IsDir(), ModTime: info.ModTime()}
+		if e.IsDir {
+			e.Path += slash
+		} else {
+			e.Size = info.Size()
+		}
+		listing.Entries = append(listing.Entries, e)
+	}
+
+	sort.SliceStable(listing.Entries, func(i, j int) bool {
+		a, b := listing.Entries[i], listing.Entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		if desc {
+			a, b = b, a
+		}
+		switch sortBy {
+		case "size":
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case "modtime":
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		}
+		return a.Name < b.Name
+	})
+
+	// the root and each parent directory
+	listing.Breadcrumbs = append(listing.Breadcrumbs, DirBreadcrumb{Name: slash, Path: d.prefix + slash})
+	if dir != "." {
+		parent := d.prefix
+		for _, part := range strings.Split(dir, slash) {
+			parent += slash + part
+			listing.Breadcrumbs = append(listing.Breadcrumbs, DirBreadcrumb{Name: part, Path: parent + slash})
+		}
+	}
+	return listing, nil
+}
+
+// list writes the listing of the directory 'dir', as json if the client accepts json, otherwise as html
+func (d *dirHandler) list(ctx *Context, dir string) {
+	sortBy := ctx.URLParam("sort")
+	if sortBy != "size" && sortBy != "modtime" {
+		sortBy = "name"
+	}
+	desc := ctx.URLParam("order") == "desc"
+
+	listing, err := d.listing(dir, ctx.Request.URL.Path, sortBy, desc)
+	if err != nil {
+		ctx.EmitError(StatusInternalServerError)
+		return
+	}
+
+	if strings.Contains(ctx.RequestHeader("Accept"), contentJSON) {
+		b, err := json.Marshal(listing)
+		if err != nil {
+			ctx.EmitError(StatusInternalServerError)
+			return
+		}
+		ctx.SetContentType(contentJSON + "; charset=" + ctx.framework.Config.Charset)
+		ctx.SetStatusCode(StatusOK)
+		ctx.Write(b)
+		return
+	}
+
+	tmpl := d.options.ListTemplate
+	if tmpl == nil {
+		tmpl = DefaultDirListTemplate
+	}
+	buf := new(bytes.Buffer)
+	if err = tmpl.Execute(buf, listing); err != nil {
+		ctx.EmitError(StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType(contentHTML + "; charset=" + ctx.framework.Config.Charset)
+	ctx.SetStatusCode(StatusOK)
+	ctx.Write(buf.Bytes())
+}
+
+// HandleDir serves the files of the 'fileSystem' under the 'reqPath',
+// the 'fileSystem' can be an iris.Dir("./public"), an fs.FS (i.e a go:embed embed.FS) or an http.FileSystem.
+// The optional 'options' configure the index files, the directory listing, the single page applications and the compression.
+//
+//     app.HandleDir("/assets", iris.Dir("./assets"))
+//     app.HandleDir("/", spaFiles, iris.DirOptions{SPA: true, Compress: true})
+func HandleDir(reqPath string, fileSystem interface{}, options ...DirOptions) RouteNameFunc {
+	return Default.HandleDir(reqPath, fileSystem, options...)
+}
+
+// HandleDir serves the files of the 'fileSystem' under the 'reqPath',
+// the 'fileSystem' can be an iris.Dir("./public"), an fs.FS (i.e a go:embed embed.FS) or an http.FileSystem.
+// The optional 'options' configure the index files, the directory listing, the single page applications and the compression.
+//
+//     app.HandleDir("/assets", iris.Dir("./assets"))
+//     app.HandleDir("/", spaFiles, iris.DirOptions{SPA: true, Compress: true})
+func (api *muxAPI) HandleDir(reqPath string, fileSystem interface{}, options ...DirOptions) RouteNameFunc {
+	fsys, err := toFS(fileSystem)
+	if err != nil {
+		api.mux.logPanic(err)
+	}
+
+	var opts DirOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	fullpath := api.relativePath + reqPath
+	if idx := strings.Index(fullpath, subdomainIndicator); idx > 0 {
+		fullpath = fullpath[idx+1:]
+	}
+	prefix := path.Clean(slash + fullpath)
+	api.mux.assets.addFS(prefix, fsys, opts.Fingerprint)
+
+	d := newDirHandler(fsys, strings.TrimSuffix(prefix, slash), opts, api.mux.assets)
+	routePath := validateWildcard(reqPath, "file")
+	return api.registerResourceRoute(routePath, d.serve)
+}
@@ -0,0 +1,184 @@
+package iris
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultDumpHeader the request header which enables the dumps of the DumpHandler for a request
+	DefaultDumpHeader = "X-Iris-Dump"
+	// DefaultDumpMaxBodySize the max bytes of the request and response bodies which are dumped, the rest is truncated
+	DefaultDumpMaxBodySize = 64 * 1024
+	// dumpRedacted replaces the values of the secrets
+	dumpRedacted = "[REDACTED]"
+)
+
+var (
+	// DefaultDumpRedactHeaders the headers whose values are redacted from the dumps
+	DefaultDumpRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Csrf-Token"}
+	// DefaultDumpRedactFields the json and form fields whose values are redacted from the dumps' bodies
+	DefaultDumpRedactFields = []string{"password", "token", "access_token", "refresh_token", "secret", "client_secret"}
+)
+
+// DumpOptions the options of the DumpHandler
+type DumpOptions struct {
+	// Header the request header which enables the dumps of a request, the Config.DumpRequests enables them for all the requests
+	// Defaults to the DefaultDumpHeader
+	Header string
+	// Body if true the request and the response bodies are dumped too
+	// Defaults to false
+	Body bool
+	// MaxBodySize the max bytes of each dumped body, the rest is truncated
+	// Defaults to the DefaultDumpMaxBodySize
+	MaxBodySize int
+	// RedactHeaders the headers whose values are replaced by [REDACTED]
+	// Defaults to the DefaultDumpRedactHeaders
+	RedactHeaders []string
+	// RedactFields the json and form fields, of the bodies, whose values are replaced by [REDACTED]
+	// Defaults to the DefaultDumpRedactFields
+	RedactFields []string
+}
+
+// DumpRequest returns the wire representation of the request, its body too if 'body' is true,
+// the body can be read again by the handlers
+func (ctx *Context) DumpRequest(body bool) ([]byte, error) {
+	return httputil.DumpRequest(ctx.Request, body)
+}
+
+// DumpResponse returns the wire representation of the response which is written so far, its body too if 'body' is true,
+// the response is buffered until the end of the request so the dump doesn't change it
+func (ctx *Context) DumpResponse(body bool) []byte {
+	w := ctx.ResponseWriter
+	status := w.StatusCode()
+	if status == 0 {
+		status = StatusOK
+	}
+
+	var b bytes.Buffer
+	b.WriteString(ctx.Request.Proto + " " + strconv.Itoa(status) + " " + statusText[status] + "\r\n")
+	w.Header().Write(&b)
+	b.WriteString("\r\n")
+	if body {
+		b.Write(w.Body())
+	}
+	return b.Bytes()
+}
+
+// DumpHandler returns a middleware which logs the dumps of the request and of the response, with their secrets redacted,
+// for the requests with the options' Header or for all the requests if the Config.DumpRequests is true.
+// The dumps are logged at the info level, with the "request" and "response" fields.
+//
+// The header can be sent by any client, so the dumps, which increase the size of the logs, should be used for debugging.
+//
+// Usage:
+// app.UseFunc(iris.DumpHandler(iris.DumpOptions{Body: true}))
+// curl -H "X-Iris-Dump: 1" http://localhost:8080/users
+func DumpHandler(options ...DumpOptions) HandlerFunc {
+	var o DumpOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Header == "" {
+		o.Header = DefaultDumpHeader
+	}
+	if o.MaxBodySize <= 0 {
+		o.MaxBodySize = DefaultDumpMaxBodySize
+	}
+	if o.RedactHeaders == nil {
+		o.RedactHeaders = DefaultDumpRedactHeaders
+	}
+	if o.RedactFields == nil {
+		o.RedactFields = DefaultDumpRedactFields
+	}
+	r := newDumpRedactor(o.RedactHeaders, o.RedactFields)
+
+	return func(ctx *Context) {
+		if !ctx.framework.Config.DumpRequests && ctx.Request.Header.Get(o.Header) == "" {
+			ctx.Next()
+			return
+		}
+
+		request, err := ctx.DumpRequest(o.Body)
+		if err != nil {
+			ctx.framework.log(LogLevelError, "request dump", "err", err)
+		}
+		ctx.Next()
+		response := ctx.DumpResponse(o.Body)
+
+		ctx.framework.log(LogLevelInfo, "request dump",
+			"method", ctx.Method(),
+			"path", ctx.Path(),
+			"request", string(r.redact(request, o.MaxBodySize)),
+			"response", string(r.redact(response, o.MaxBodySize)))
+	}
+}
+
+// dumpRedactor redacts the secrets of the dumps
+type dumpRedactor struct {
+	headers map[string]bool
+	// jsonFields matches the "field": "value" of the json bodies
+	jsonFields *regexp.Regexp
+	// formFields matches the field=value of the form bodies and the query strings
+	formFields *regexp.Regexp
+}
+
+func newDumpRedactor(headers []string, fields []string) *dumpRedactor {
+	r := &dumpRedactor{headers: make(map[string]bool, len(headers))}
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	if len(fields) > 0 {
+		quoted := make([]string, len(fields))
+		for i, f := range fields {
+			quoted[i] = regexp.QuoteMeta(f)
+		}
+		names := strings.Join(quoted, "|")
+		r.jsonFields = regexp.MustCompile(`(?i)("(?:` + names + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+		r.formFields = regexp.MustCompile(`(?i)((?:^|[?&\s])(?:` + names + `)=)[^&\s]*`)
+	}
+	return r
+}
+
+// redact replaces the values of the secret headers and fields and truncates the body of the 'dump' to 'maxBodySize' bytes
+func (r *dumpRedactor) redact(dump []byte, maxBodySize int) []byte {
+	head, body := dump, []byte(nil)
+	if i := bytes.Index(dump, []byte("\r\n\r\n")); i >= 0 {
+		head, body = dump[:i], dump[i+4:]
+	}
+
+	lines := bytes.Split(head, []byte("\r\n"))
+	for i, line := range lines {
+		if i == 0 {
+			// the request line, its query can contain secrets too
+			lines[i] = r.redactFields(line)
+			continue
+		}
+		if colon := bytes.IndexByte(line, ':'); colon > 0 && r.headers[http.CanonicalHeaderKey(string(line[:colon]))] {
+			lines[i] = append(line[:colon:colon], ": "+dumpRedacted...)
+		}
+	}
+
+	var b bytes.Buffer
+	b.Write(bytes.Join(lines, []byte("\r\n")))
+	b.WriteString("\r\n\r\n")
+	if len(body) > maxBodySize {
+		b.Write(r.redactFields(body[:maxBodySize]))
+		b.WriteString("... (truncated " + strconv.Itoa(len(body)-maxBodySize) + " bytes)")
+	} else {
+		b.Write(r.redactFields(body))
+	}
+	return b.Bytes()
+}
+
+func (r *dumpRedactor) redactFields(b []byte) []byte {
+	if r.jsonFields == nil || len(b) == 0 {
+		return b
+	}
+	b = r.jsonFields.ReplaceAll(b, []byte(`${1}"`+dumpRedacted+`"`))
+	return r.formFields.ReplaceAll(b, []byte(`${1}`+dumpRedacted))
+}
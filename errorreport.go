@@ -0,0 +1,261 @@
+package iris
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+const (
+	// DefaultErrorReporterBatchSize the max reports of a batch of the ErrorReporter
+	DefaultErrorReporterBatchSize = 10
+	// DefaultErrorReporterFlushInterval the interval which the ErrorReporter sends the pending reports
+	DefaultErrorReporterFlushInterval = 5 * time.Second
+	// DefaultErrorReporterMaxPending the max pending reports of the ErrorReporter, the next ones are dropped until they're sent
+	DefaultErrorReporterMaxPending = 1000
+)
+
+var (
+	errRequestPanic = errors.New("Request panic: %v")
+	errEmitError    = errors.New("EmitError: %d %s")
+)
+
+// ErrorReportFunc receives the errors of the requests, see OnErrorReport
+type ErrorReportFunc func(ctx *Context, err error, stack []byte)
+
+// OnErrorReport registers an error report callback of the default iris instance, see the Framework's OnErrorReport
+func OnErrorReport(cb ErrorReportFunc) {
+	Default.OnErrorReport(cb)
+}
+
+// OnErrorReport registers a callback which receives the errors of the requests, with the stack where they happened,
+// so the crash reporters, i.e Sentry, integrate without wrapping every handler:
+//
+// the panics of the handlers, they're recovered and answered with the 500 Internal Server Error handler, when there is a callback
+// the ctx.EmitError with a 5xx status code
+// the failed transactions, their Complete with a non-nil error and their panics
+//
+// The callbacks are fired, in the order they are registered, on the request's goroutine, the 'ctx' is released after the request,
+// the ErrorReporter's Report samples and batches the reports on its own goroutine.
+//
+// Usage:
+// reporter := iris.NewErrorReporter(sendToSentry, iris.ErrorReporterOptions{SampleRate: 0.5})
+// app.OnErrorReport(reporter.Report)
+func (s *Framework) OnErrorReport(cb ErrorReportFunc) {
+	s.shutdownMu.Lock()
+	s.onErrorReport = append(s.onErrorReport, cb)
+	s.shutdownMu.Unlock()
+}
+
+// hasErrorReports reports whether there are error report callbacks, the panics are recovered only if there are
+func (s *Framework) hasErrorReports() bool {
+	s.shutdownMu.Lock()
+	n := len(s.onErrorReport)
+	s.shutdownMu.Unlock()
+	return n > 0
+}
+
+// reportError fires the error report callbacks
+func (s *Framework) reportError(ctx *Context, err error, stack []byte) {
+	s.shutdownMu.Lock()
+	callbacks := s.onErrorReport
+	s.shutdownMu.Unlock()
+	for _, cb := range callbacks {
+		cb(ctx, err, stack)
+	}
+}
+
+// serveRecover serves the request and recovers its panic, which is reported and answered with the 500 error handler
+func (s *Framework) serveRecover(serve func(*Context), ctx *Context) {
+	defer func() {
+		if v := recover(); v != nil {
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err, ok := v.(error)
+			if !ok {
+				err = errRequestPanic.Format(v)
+			}
+			stack := debug.Stack()
+			ctx.StopExecution()
+			s.mux.fireError(StatusInternalServerError, ctx)
+			s.reportError(ctx, err, stack)
+		}
+	}()
+	serve(ctx)
+}
+
+// ErrorReport the copy of a reported error and its request, which is kept after the request is released
+type ErrorReport struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Route      string    `json:"route"`
+	RemoteAddr string    `json:"remoteAddr"`
+	StatusCode int       `json:"statusCode"`
+	Error      string    `json:"error"`
+	Stack      string    `json:"stack"`
+}
+
+// ErrorReporterOptions the options of the ErrorReporter
+type ErrorReporterOptions struct {
+	// SampleRate the fraction of the errors which are reported, from 0 to 1
+	// Defaults to 1, all the errors
+	SampleRate float64
+	// BatchSize the max reports which are sent together
+	// Defaults to the DefaultErrorReporterBatchSize
+	BatchSize int
+	// FlushInterval the interval which the pending reports are sent, even if they're less than the BatchSize
+	// Defaults to the DefaultErrorReporterFlushInterval
+	FlushInterval time.Duration
+	// MaxPending the max reports which wait to be sent, the next ones are dropped, i.e while the collector is down
+	// Defaults to the DefaultErrorReporterMaxPending
+	MaxPending int
+	// OnSendError receives the errors of the send func, optional
+	OnSendError func(error)
+}
+
+// ErrorReporter samples the reported errors and sends them in batches, on its own goroutine, to a collector
+type ErrorReporter struct {
+	send    func([]ErrorReport) error
+	options ErrorReporterOptions
+
+	mu      sync.Mutex
+	pending []ErrorReport
+	dropped uint64
+	full    chan struct{}
+	done    chan struct{}
+	closed  sync.Once
+	wg      sync.WaitGroup
+}
+
+// NewErrorReporter returns a new ErrorReporter which sends the batches of the reports to the 'send', i.e a Sentry client,
+// its Report is the callback of the OnErrorReport
+func NewErrorReporter(send func([]ErrorReport) error, options ...ErrorReporterOptions) *ErrorReporter {
+	var o ErrorReporterOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.SampleRate <= 0 || o.SampleRate > 1 {
+		o.SampleRate = 1
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultErrorReporterBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultErrorReporterFlushInterval
+	}
+	if o.MaxPending <= 0 {
+		o.MaxPending = DefaultErrorReporterMaxPending
+	}
+
+	r := &ErrorReporter{
+		send:    send,
+		options: o,
+		full:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.loop()
+	return r
+}
+
+// Report samples the error and adds it to the pending reports
+func (r *ErrorReporter) Report(ctx *Context, err error, stack []byte) {
+	if r.options.SampleRate < 1 && rand.Float64() >= r.options.SampleRate {
+		return
+	}
+
+	status := ctx.ResponseWriter.StatusCode()
+	if terr, ok := err.(TransactionErrResult); ok && terr.StatusCode > 0 {
+		status = terr.StatusCode
+	}
+	report := ErrorReport{
+		Time:       ctx.framework.now(),
+		Method:     ctx.Method(),
+		URL:        ctx.Request.URL.String(),
+		Route:      ctx.framework.mux.routeOf(ctx),
+		RemoteAddr: ctx.RemoteAddr(),
+		StatusCode: status,
+		Error:      err.Error(),
+		Stack:      string(stack),
+	}
+
+	r.mu.Lock()
+	if len(r.pending) >= r.options.MaxPending {
+		r.mu.Unlock()
+		atomic.AddUint64(&r.dropped, 1)
+		return
+	}
+	r.pending = append(r.pending, report)
+	full := len(r.pending) >= r.options.BatchSize
+	r.mu.Unlock()
+
+	if full {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of the reports which are dropped because the MaxPending was reached
+func (r *ErrorReporter) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
+
+func (r *ErrorReporter) loop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.full:
+		}
+		if err := r.Flush(); err != nil && r.options.OnSendError != nil {
+			r.options.OnSendError(err)
+		}
+	}
+}
+
+// Flush sends the pending reports, in batches of the BatchSize, it returns the first error of the send
+func (r *ErrorReporter) Flush() error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	var firstErr error
+	for len(pending) > 0 {
+		n := r.options.BatchSize
+		if n > len(pending) {
+			n = len(pending)
+		}
+		if err := r.send(pending[:n]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		pending = pending[n:]
+	}
+	return firstErr
+}
+
+// Close stops the background sending and sends the pending reports, it can be registered to the OnShutdown
+func (r *ErrorReporter) Close() error {
+	r.closed.Do(func() { close(r.done) })
+	r.wg.Wait()
+	return r.Flush()
+}
+
+// String returns the summary of the report, i.e "GET /users/42: Request panic: database is closed"
+func (e ErrorReport) String() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Error)
+}
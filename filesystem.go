@@ -0,0 +1,210 @@
+package iris
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errFileSystemType   = errors.New("Unsupported file system type %T, expected a directory(string or iris.Dir), an fs.FS or an http.FileSystem")
+	errFileSystemNotDir = errors.New("not a directory")
+)
+
+// toFS converts the 'fileSystem' to an fs.FS,
+// it accepts a system directory (string or Dir), an fs.FS (i.e an embed.FS) or an http.FileSystem.
+func toFS(fileSystem interface{}) (fs.FS, error) {
+	switch v := fileSystem.(type) {
+	case string:
+		return os.DirFS(v), nil
+	case Dir:
+		return os.DirFS(string(v)), nil
+	case fs.FS:
+		return v, nil
+	case http.FileSystem:
+		return httpFS{v}, nil
+	default:
+		return nil, errFileSystemType.Format(fileSystem)
+	}
+}
+
+// toHTTPFileSystem converts the 'fileSystem' to an http.FileSystem, the opposite of toFS
+func toHTTPFileSystem(fileSystem interface{}) (http.FileSystem, error) {
+	switch v := fileSystem.(type) {
+	case string:
+		return http.Dir(v), nil
+	case Dir:
+		return http.Dir(v), nil
+	case http.FileSystem:
+		return v, nil
+	case fs.FS:
+		return http.FS(v), nil
+	default:
+		return nil, errFileSystemType.Format(fileSystem)
+	}
+}
+
+// fileSystemName returns a printable name of the 'fileSystem', used for the error messages
+func fileSystemName(fileSystem interface{}) string {
+	switch dir := fileSystem.(type) {
+	case string:
+		return dir
+	case Dir:
+		return string(dir)
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", fileSystem), "*")
+}
+
+// httpFS adapts an http.FileSystem to an fs.FS
+type httpFS struct {
+	http.FileSystem
+}
+
+func (h httpFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	f, err := h.FileSystem.Open(path.Join("/", name))
+	if err != nil {
+		return nil, err
+	}
+	return httpFile{f}, nil
+}
+
+// httpFile adapts an http.File to an fs.ReadDirFile
+type httpFile struct {
+	http.File
+}
+
+func (f httpFile) ReadDir(count int) ([]fs.DirEntry, error) {
+	infos, err := f.File.Readdir(count)
+	entries := make([]fs.DirEntry, len(infos))
+	for i := range infos {
+		entries[i] = fs.FileInfoToDirEntry(infos[i])
+	}
+	return entries, err
+}
+
+// overlayFS is a layered file system, look OverlayFS
+type overlayFS []fs.FS
+
+var _ fs.ReadDirFS = overlayFS{}
+
+// OverlayFS returns a file system of the 'layers', the first layer which contains a file wins,
+// the directories are merged, their entries are the entries of all the layers.
+//
+// Useful to ship the default assets or templates in the binary while allowing on-disk overrides:
+//     app.HandleDir("/assets", iris.OverlayFS(iris.Dir("./assets"), embeddedAssets))
+//     app.AdaptView(iris.HTML(iris.OverlayFS(iris.Dir("./templates"), embeddedTemplates), ".html"))
+func OverlayFS(layers ...fs.FS) fs.FS {
+	return overlayFS(layers)
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, layer := range o {
+		f, err := layer.Open(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if !info.IsDir() {
+			return f, nil
+		}
+		return &overlayDir{File: f, fs: o, name: name}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns the entries of the directory 'name' of all the layers, sorted by name,
+// an entry of an upper layer hides the entry with the same name of the lower layers.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		entries []fs.DirEntry
+		seen    = make(map[string]bool)
+		found   bool
+	)
+	for _, layer := range o {
+		info, err := fs.Stat(layer, name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !info.IsDir() {
+			// a file hides the directories of the lower layers
+			if !found {
+				return nil, &fs.PathError{Op: "readdir", Path: name, Err: errFileSystemNotDir}
+			}
+			continue
+		}
+		found = true
+		layerEntries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range layerEntries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// overlayDir is a directory of the overlayFS, its entries are merged from all the layers
+type overlayDir struct {
+	fs.File
+	fs      overlayFS
+	name    string
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *overlayDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(d.entries) {
+		count = len(d.entries)
+	}
+	entries := d.entries[:count]
+	d.entries = d.entries[count:]
+	return entries, nil
+}
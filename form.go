@@ -0,0 +1,237 @@
+package iris
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errFormKeys  = errors.New("ReadForm: %d keys exceed the limit of %d")
+	errFormKey   = errors.New("ReadForm: the key %q: %s")
+	errFormIndex = errors.New("the index %d exceeds the limit of %d")
+)
+
+// parseFormKey splits a form key to its parts, i.e the "items[0].name" to the "items", "0" and "name",
+// the "attrs[color]" to the "attrs" and "color", and the "tags[]" to the "tags" and an empty part, which appends
+func parseFormKey(key string) []string {
+	var tokens []string
+	for len(key) > 0 {
+		switch key[0] {
+		case '.':
+			key = key[1:]
+		case '[':
+			end := strings.IndexByte(key, ']')
+			if end < 0 {
+				// not closed, it's a name
+				return append(tokens, key)
+			}
+			tokens = append(tokens, key[1:end])
+			key = key[end+1:]
+		default:
+			end := strings.IndexAny(key, ".[")
+			if end < 0 {
+				end = len(key)
+			}
+			tokens = append(tokens, key[:end])
+			key = key[end:]
+		}
+	}
+	return tokens
+}
+
+// formDecoder decodes the form and query values to the structs, their slices, maps and nested structs
+type formDecoder struct {
+	// maxIndex the highest index of the slices, see the Config.MaxFormSliceIndex
+	maxIndex int
+}
+
+// decodeForm decodes the 'values' to the 'ptr', a pointer to a struct or to a map,
+// with the Config.MaxFormKeys and the Config.MaxFormSliceIndex limits
+func (ctx *Context) decodeForm(values map[string][]string, ptr interface{}) error {
+	c := ctx.framework.Config
+	maxKeys, maxIndex := c.MaxFormKeys, c.MaxFormSliceIndex
+	if maxKeys <= 0 {
+		maxKeys = DefaultMaxFormKeys
+	}
+	if maxIndex <= 0 {
+		maxIndex = DefaultMaxFormSliceIndex
+	}
+	if len(values) > maxKeys {
+		return errFormKeys.Format(len(values), maxKeys)
+	}
+
+	d := formDecoder{maxIndex: maxIndex}
+	val := reflect.ValueOf(ptr)
+	// the keys are sorted, so the items[2] and the items[10] are decoded in the same order on each request
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := d.decode(val, parseFormKey(key), values[key]); err != nil {
+			return errFormKey.Format(key, err)
+		}
+	}
+	return nil
+}
+
+// decode sets the part of the 'v' of the 'tokens' to the 'values'
+func (d formDecoder) decode(v reflect.Value, tokens []string, values []string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return nil
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if len(tokens) == 0 {
+		return d.set(v, values)
+	}
+
+	token := tokens[0]
+	switch v.Kind() {
+	case reflect.Struct:
+		if f, ok := formField(v, token); ok {
+			return d.decode(f, tokens[1:], values)
+		}
+		// the unknown keys are skipped
+		return nil
+	case reflect.Slice:
+		if token == "" {
+			// the "tags[]", each value is appended
+			if len(tokens) == 1 {
+				return d.set(v, append(d.stringsOf(v), values...))
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(elem, tokens[1:], values); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, elem))
+			return nil
+		}
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 {
+			return nil
+		}
+		if i > d.maxIndex {
+			return errFormIndex.Format(i, d.maxIndex)
+		}
+		if i >= v.Len() {
+			grown := reflect.MakeSlice(v.Type(), i+1, i+1)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		return d.decode(v.Index(i), tokens[1:], values)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.New(v.Type().Key()).Elem()
+		if err := setString(key, token); err != nil {
+			return err
+		}
+		// the map's values are not addressable, the existing one is decoded to a copy
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := d.decode(elem, tokens[1:], values); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	}
+	return nil
+}
+
+// set sets the 'v' to the 'values', all of them if it's a slice, otherwise the first one
+func (d formDecoder) set(v reflect.Value, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		v.SetBytes([]byte(values[0]))
+		return nil
+	}
+	if v.Kind() == reflect.Slice {
+		if len(values)-1 > d.maxIndex {
+			return errFormIndex.Format(len(values)-1, d.maxIndex)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := setString(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+		// the "a=b" of a nested value, i.e a struct which is not a TextUnmarshaler, is skipped
+		if _, ok := v.Addr().Interface().(interface{ UnmarshalText([]byte) error }); !ok {
+			return nil
+		}
+	}
+	return setString(v, values[0])
+}
+
+// stringsOf returns the values of a slice of strings, nil if it's not
+func (d formDecoder) stringsOf(v reflect.Value) []string {
+	if v.Type().Elem().Kind() != reflect.String {
+		return nil
+	}
+	values := make([]string, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).String()
+	}
+	return values
+}
+
+// formField returns the field of the struct 'v' with the 'name', by its form tag, its name or its case-insensitive name,
+// the fields of the embedded structs too
+func formField(v reflect.Value, name string) (reflect.Value, bool) {
+	typ := v.Type()
+	fold := -1
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("form"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == name || (tag == "" && f.Name == name) {
+			return v.Field(i), true
+		}
+		if f.Anonymous && tag == "" {
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if fv, ok := formField(embedded, name); ok {
+					return fv, true
+				}
+			}
+			continue
+		}
+		if fold < 0 && tag == "" && strings.EqualFold(f.Name, name) {
+			fold = i
+		}
+	}
+	if fold >= 0 {
+		return v.Field(fold), true
+	}
+	return reflect.Value{}, false
+}
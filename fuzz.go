@@ -0,0 +1,134 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// FuzzOptions the options of the FuzzRoute
+type FuzzOptions struct {
+	// Body the binding struct of the route's body, i.e CreateOrder{}, its zero and its sample json are added to the seed corpus
+	// Defaults to nil, the seeds are generic json values
+	Body interface{}
+	// ContentType the Content-Type of the fuzzed bodies
+	// Defaults to "application/json"
+	ContentType string
+	// Seeds the additional seed values of the path parameters
+	// Defaults to nil
+	Seeds []string
+	// ValidStatus reports whether the status code of a response is valid
+	// Defaults to nil, the status codes less than 500
+	ValidStatus func(status int) bool
+}
+
+// fuzzParamSeeds the seed values of the path parameters, the edge cases of the numbers, the strings and the paths
+var fuzzParamSeeds = []string{"1", "0", "-1", "9223372036854775808", "abc", "", " ", "../", "%00", "é", strings.Repeat("a", 1024)}
+
+// fuzzBodySeeds the generic seed bodies
+var fuzzBodySeeds = []string{"", "{}", "null", "[]", `{"a":`, `"\u0000"`}
+
+// FuzzRoute fuzzes the route of the 'method' and the 'path', its registered path, i.e "/users/:id", with the go test -fuzz,
+// its named and wildcard parameters are replaced by the fuzzed string and its body by the fuzzed bytes.
+// The parameters are not typed by the router, so the seeds are the edge cases of the numbers, the strings and the paths,
+// and the seed bodies are the json of the zero and of a sample value of the FuzzOptions.Body.
+// A panic of the handlers, if they're not recovered, or a response with an invalid status code, the 5xx by default, fails the fuzz test.
+// The requests are served in-process by the app's router, which is built if it's not built already.
+//
+// Usage:
+// func FuzzCreateOrder(f *testing.F) {
+//	iris.FuzzRoute(f, app, iris.MethodPost, "/users/:id/orders", iris.FuzzOptions{Body: CreateOrder{}})
+// }
+// go test -fuzz=FuzzCreateOrder
+func FuzzRoute(f *testing.F, app *Framework, method, path string, options ...FuzzOptions) {
+	var o FuzzOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.ContentType == "" {
+		o.ContentType = contentJSON
+	}
+	if o.ValidStatus == nil {
+		o.ValidStatus = func(status int) bool { return status < StatusInternalServerError }
+	}
+	app.Set(OptionDisableBanner(true))
+	if !app.Plugins.PreBuildFired() {
+		app.Build()
+	}
+
+	bodies := append([]string(nil), fuzzBodySeeds...)
+	if o.Body != nil {
+		typ := reflect.TypeOf(o.Body)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		for _, v := range []reflect.Value{reflect.New(typ).Elem(), fuzzSample(typ, 0)} {
+			if b, err := json.Marshal(v.Interface()); err == nil {
+				bodies = append(bodies, string(b))
+			}
+		}
+	}
+	for i, param := range append(fuzzParamSeeds, o.Seeds...) {
+		f.Add(param, []byte(bodies[i%len(bodies)]))
+	}
+
+	f.Fuzz(func(t *testing.T, param string, body []byte) {
+		req := httptest.NewRequest(method, "/", bytes.NewReader(body))
+		// the path is set after the request is created, the fuzzed one may not be a valid url
+		req.URL.Path = fuzzPath(path, param)
+		req.URL.RawPath = ""
+		if len(body) > 0 {
+			req.Header.Set(contentType, o.ContentType)
+		}
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		if !o.ValidStatus(w.Code) {
+			t.Fatalf("%s %s: param %q, body %q: invalid status code %d: %s", method, path, param, body, w.Code, w.Body.String())
+		}
+	})
+}
+
+// fuzzPath returns the 'path' with its named and wildcard parameters replaced by the 'param'
+func fuzzPath(path, param string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = param
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// fuzzSample returns a sample value of the 'typ', its strings, numbers and bools are set, its slices have an element
+func fuzzSample(typ reflect.Type, depth int) reflect.Value {
+	v := reflect.New(typ).Elem()
+	if depth > 3 {
+		return v
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString("a")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Ptr:
+		v.Set(fuzzSample(typ.Elem(), depth+1).Addr())
+	case reflect.Slice:
+		v.Set(reflect.Append(v, fuzzSample(typ.Elem(), depth+1)))
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath == "" {
+				v.Field(i).Set(fuzzSample(typ.Field(i).Type, depth+1))
+			}
+		}
+	}
+	return v
+}
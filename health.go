@@ -0,0 +1,249 @@
+package iris
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+const (
+	// DefaultHealthCheckTimeout the time which a health check has to return before it's reported as failed
+	DefaultHealthCheckTimeout = 5 * time.Second
+	// DefaultHealthCheckCacheTTL the time which the result of a health check is reused by the next probes,
+	// so the frequent probes of the load balancers don't overload the checked components
+	DefaultHealthCheckCacheTTL = 1 * time.Second
+
+	// HealthStatusOK the status of a passed check and of a report without failed checks
+	HealthStatusOK = "ok"
+	// HealthStatusFail the status of a failed check and of a report with failed checks
+	HealthStatusFail = "fail"
+)
+
+var (
+	errHealthCheckTimeout = errors.New("Health check timed out after %s")
+	errHealthCheckExists  = errors.New("Health check '%s' is already registered")
+)
+
+// HealthCheck checks a component of the application, i.e pings the database, it returns a non-nil error if the component is unhealthy.
+// It should return when the 'ctx' is done, which happens after the check's timeout
+type HealthCheck func(ctx context.Context) error
+
+// HealthPinger is a component which can be pinged, i.e the *sql.DB, see the HealthChecks' AddPinger
+type HealthPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthCheckOptions the options of a health check
+type HealthCheckOptions struct {
+	// Timeout the time which the check has to return, defaults to the DefaultHealthCheckTimeout
+	Timeout time.Duration
+	// CacheTTL the time which the result is reused by the next probes, defaults to the DefaultHealthCheckCacheTTL,
+	// a negative value disables the cache
+	CacheTTL time.Duration
+	// Liveness if true the check is part of the liveness probe, /healthz, too.
+	// A failed liveness probe restarts the application, so only the checks which a restart can fix,
+	// i.e a deadlock, should be part of it, the dependencies, i.e the database, should be part of the readiness probe only.
+	// Defaults to false
+	Liveness bool
+}
+
+// HealthCheckResult the result of a health check
+type HealthCheckResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// HealthReport the result of a probe, the status is HealthStatusFail if any of its checks failed
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+// HealthChecks keeps the health checks of the application's components, the database, the cache, the session store, the backplane,
+// and serves the liveness and readiness probes, see the EnableHealthEndpoints
+type HealthChecks struct {
+	mu     sync.RWMutex
+	checks map[string]*healthCheck
+}
+
+type healthCheck struct {
+	check   HealthCheck
+	options HealthCheckOptions
+
+	mu     sync.Mutex
+	result HealthCheckResult
+	// expires the time which the cached result expires
+	expires time.Time
+}
+
+// Health returns the health checks of the default iris instance, see the Framework's Health
+func Health() *HealthChecks {
+	return Default.Health()
+}
+
+// Health returns the health checks which the application's components register to, they're served by the EnableHealthEndpoints
+func (s *Framework) Health() *HealthChecks {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	if s.health == nil {
+		s.health = &HealthChecks{checks: make(map[string]*healthCheck)}
+	}
+	return s.health
+}
+
+// Add registers the 'check' of a component by its 'name', i.e "db", it returns an error if the name is already registered
+//
+// Usage:
+// app.Health().Add("cache", func(ctx context.Context) error {
+//     return redisClient.Ping(ctx).Err()
+// }, iris.HealthCheckOptions{Timeout: time.Second})
+func (h *HealthChecks) Add(name string, check HealthCheck, options ...HealthCheckOptions) error {
+	var o HealthCheckOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultHealthCheckTimeout
+	}
+	if o.CacheTTL == 0 {
+		o.CacheTTL = DefaultHealthCheckCacheTTL
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.checks[name]; ok {
+		return errHealthCheckExists.Format(name)
+	}
+	h.checks[name] = &healthCheck{check: check, options: o}
+	return nil
+}
+
+// AddPinger registers a check which pings the 'pinger', i.e the *sql.DB
+func (h *HealthChecks) AddPinger(name string, pinger HealthPinger, options ...HealthCheckOptions) error {
+	return h.Add(name, pinger.PingContext, options...)
+}
+
+// Remove unregisters the check of the 'name'
+func (h *HealthChecks) Remove(name string) {
+	h.mu.Lock()
+	delete(h.checks, name)
+	h.mu.Unlock()
+}
+
+// Names returns the sorted names of the registered checks
+func (h *HealthChecks) Names() []string {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	h.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Check runs the checks, concurrently, and returns their report,
+// the liveness checks only if 'liveness' is true, all the checks otherwise
+func (h *HealthChecks) Check(ctx context.Context, liveness bool) HealthReport {
+	h.mu.RLock()
+	checks := make(map[string]*healthCheck, len(h.checks))
+	for name, c := range h.checks {
+		if !liveness || c.options.Liveness {
+			checks[name] = c
+		}
+	}
+	h.mu.RUnlock()
+
+	report := HealthReport{Status: HealthStatusOK, Checks: make(map[string]HealthCheckResult, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, c := range checks {
+		wg.Add(1)
+		go func(name string, c *healthCheck) {
+			defer wg.Done()
+			result := c.run(ctx)
+			mu.Lock()
+			report.Checks[name] = result
+			if result.Status != HealthStatusOK {
+				report.Status = HealthStatusFail
+			}
+			mu.Unlock()
+		}(name, c)
+	}
+	wg.Wait()
+	return report
+}
+
+// run returns the cached result, if it's not expired, or runs the check with its timeout
+func (c *healthCheck) run(ctx context.Context) HealthCheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.Before(c.expires) {
+		return c.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.check(checkCtx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		// the check ignores its context, don't wait for it
+		err = errHealthCheckTimeout.Format(c.options.Timeout)
+	}
+
+	c.result = HealthCheckResult{Status: HealthStatusOK, Duration: time.Since(now).String(), CheckedAt: now}
+	if err != nil {
+		c.result.Status = HealthStatusFail
+		c.result.Error = err.Error()
+	}
+	if c.options.CacheTTL > 0 {
+		c.expires = now.Add(c.options.CacheTTL)
+	}
+	return c.result
+}
+
+// Handler returns the handler of a probe, it responds with the report's json and 200 OK if all the checks passed,
+// 503 Service Unavailable otherwise
+func (h *HealthChecks) Handler(liveness bool) HandlerFunc {
+	return func(ctx *Context) {
+		report := h.Check(ctx.Request.Context(), liveness)
+		status := StatusOK
+		if report.Status != HealthStatusOK {
+			status = StatusServiceUnavailable
+		}
+		ctx.SetHeader("Cache-Control", "no-store")
+		ctx.JSON(status, report)
+	}
+}
+
+// EnableHealthEndpoints mounts the health probes of the default iris instance, see the Framework's EnableHealthEndpoints
+func EnableHealthEndpoints(prefix string) *HealthChecks {
+	return Default.EnableHealthEndpoints(prefix)
+}
+
+// EnableHealthEndpoints mounts the health probes under the 'prefix', which can be empty, and returns the HealthChecks which the checks are registered to:
+//
+// prefix/healthz the liveness probe, it runs the liveness checks only, it's 200 OK while the server responds if there are not any
+// prefix/readyz the readiness probe, it runs all the checks, the load balancers should stop sending requests while it fails
+//
+// Usage:
+// health := app.EnableHealthEndpoints("")
+// health.AddPinger("db", db)
+func (s *Framework) EnableHealthEndpoints(prefix string) *HealthChecks {
+	health := s.Health()
+	s.Get(prefix+"/healthz", health.Handler(true))
+	s.Get(prefix+"/readyz", health.Handler(false))
+	return health
+}
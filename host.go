@@ -0,0 +1,144 @@
+package iris
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// Host is an additional server of an application, which is started and shut down with the main server,
+// so the same, or a different, route tree is served on several addresses at once,
+// i.e a :80 redirector, the https :443 and an internal admin listener on localhost.
+//
+// Usage:
+// admin := iris.New()
+// admin.Get("/metrics", metricsHandler)
+// admin.Build()
+// app.AddHost(&iris.Host{Addr: "localhost:9090", Handler: admin.Router})
+// app.Listen(":8080")
+type Host struct {
+	// Addr the tcp4 address which the server listens to, host:port
+	Addr string
+	// Listener if not nil the server accepts its connections instead of listening to the Addr
+	Listener net.Listener
+	// TLSConfig if not nil the server is a https one, with its own certificates
+	TLSConfig *tls.Config
+	// Handler the handler of the requests, i.e the Router of another, built, iris instance or an iris.ProxyHandler,
+	// nil means the Router of the application
+	Handler http.Handler
+
+	srv *http.Server
+	ln  net.Listener
+}
+
+// ListeningAddr returns the address which the host listens to, it's available after the server is started
+func (h *Host) ListeningAddr() string {
+	if h.ln == nil {
+		return ""
+	}
+	return h.ln.Addr().String()
+}
+
+// start listens and serves the host's requests, on its own goroutine
+func (h *Host) start(s *Framework) error {
+	ln := h.Listener
+	if ln == nil {
+		var err error
+		if ln, err = TCP4(h.Addr); err != nil {
+			return err
+		}
+	}
+	if h.TLSConfig != nil {
+		ln = tls.NewListener(ln, h.TLSConfig)
+	}
+	handler := h.Handler
+	if handler == nil {
+		handler = s.Router
+	}
+
+	h.ln = ln
+	h.srv = &http.Server{
+		ReadTimeout:       s.Config.ReadTimeout,
+		ReadHeaderTimeout: s.Config.ReadHeaderTimeout,
+		WriteTimeout:      s.Config.WriteTimeout,
+		IdleTimeout:       s.Config.IdleTimeout,
+		MaxHeaderBytes:    s.Config.MaxHeaderBytes,
+		ConnState:         s.stats.trackConnState(s.Config.ConnState),
+		ErrorLog:          s.serverErrorLog(),
+		Handler:           handler,
+		Addr:              ln.Addr().String(),
+	}
+	if err := s.configureHTTP2(h.srv); err != nil {
+		ln.Close()
+		return err
+	}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != http.ErrServerClosed {
+			s.log(LogLevelError, "host server", "addr", srv.Addr, "err", err)
+		}
+	}(h.srv)
+	return nil
+}
+
+// AddHost adds an additional server to the default iris instance, it's started with the main server,
+// by the Listen/Serve/Run functions, and it's gracefully shut down by the Shutdown
+func AddHost(h *Host) {
+	Default.AddHost(h)
+}
+
+// AddHost adds an additional server to the application, it's started with the main server,
+// by the Listen/Serve/Run functions, and it's gracefully shut down by the Shutdown
+func (s *Framework) AddHost(h *Host) {
+	s.shutdownMu.Lock()
+	s.hosts = append(s.hosts, h)
+	s.shutdownMu.Unlock()
+}
+
+// startHosts starts the additional servers, if one of them fails the already started are closed
+func (s *Framework) startHosts() error {
+	s.shutdownMu.Lock()
+	hosts := s.hosts
+	s.shutdownMu.Unlock()
+	for i, h := range hosts {
+		if err := h.start(s); err != nil {
+			for _, started := range hosts[:i] {
+				started.srv.Close()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdownHosts gracefully shuts down the additional servers, at the same time, it returns the first error
+func (s *Framework) shutdownHosts(ctx context.Context) error {
+	s.shutdownMu.Lock()
+	hosts := s.hosts
+	s.shutdownMu.Unlock()
+
+	var (
+		wg       sync.WaitGroup
+		firstErr error
+		errMu    sync.Mutex
+	)
+	for _, h := range hosts {
+		if h.srv == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMu.Unlock()
+			}
+		}(h.srv)
+	}
+	wg.Wait()
+	return firstErr
+}
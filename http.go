@@ -2,7 +2,6 @@ package iris
 
 import (
 	"crypto/tls"
-	"log"
 	"net"
 	"net/http"
 	"os"
@@ -90,6 +89,7 @@ const (
 	StatusRequestedRangeNotSatisfiable = 416 // RFC 7233, 4.4
 	StatusExpectationFailed            = 417 // RFC 7231, 6.5.14
 	StatusTeapot                       = 418 // RFC 7168, 2.3.3
+	StatusMisdirectedRequest           = 421 // RFC 7540, 9.1.2
 	StatusUnprocessableEntity          = 422 // RFC 4918, 11.2
 	StatusLocked                       = 423 // RFC 4918, 11.3
 	StatusFailedDependency             = 424 // RFC 4918, 11.4
@@ -156,6 +156,7 @@ var statusText = map[int]string{
 	StatusRequestedRangeNotSatisfiable: "Requested Range Not Satisfiable",
 	StatusExpectationFailed:            "Expectation Failed",
 	StatusTeapot:                       "I'm a teapot",
+	StatusMisdirectedRequest:           "Misdirected Request",
 	StatusUnprocessableEntity:          "Unprocessable Entity",
 	StatusLocked:                       "Locked",
 	StatusFailedDependency:             "Failed Dependency",
@@ -681,7 +682,9 @@ func newCachedMuxEntry(s *Framework, bodyHandler HandlerFunc, expiration time.Du
 }
 
 func (c *cachedMuxEntry) Serve(ctx *Context) {
+	span := ctx.startSpan("iris.cache")
 	c.cachedHandler.ServeHTTP(ctx.ResponseWriter, ctx.Request)
+	span.End()
 }
 
 type (
@@ -712,6 +715,8 @@ type (
 		middleware     Middleware
 		formattedPath  string
 		formattedParts int
+		// party the party which registered the route, its view engines are looked up by the build
+		party *muxAPI
 	}
 
 	bySubdomain []*route
@@ -841,7 +846,7 @@ type (
 
 		api           *muxAPI
 		errorHandlers map[int]Handler
-		logger        *log.Logger
+		logPanic      func(v interface{})
 		// the main server host's name, ex:  localhost, 127.0.0.1, 0.0.0.0, iris-go.com
 		hostname string
 		// if any of the trees contains not empty subdomain
@@ -852,18 +857,25 @@ type (
 		// if enabled then the router checks and fires an error for 405 http status method not allowed too if no method compatible method was found
 		// by default is false
 		fireMethodNotAllowed bool
-		mu                   sync.Mutex
+		// assets the file systems of the static handlers, used to resolve the cache-busting asset paths
+		assets *assetResolver
+		// views the app's view engines, the parties' view engines are registered to them
+		views *viewEngines
+		// routePaths the registered paths of the routes, by their first handler, see routeOf
+		routePaths map[*Handler]string
+		mu         sync.Mutex
 	}
 )
 
-func newServeMux(logger *log.Logger) *serveMux {
+func newServeMux(logPanic func(v interface{})) *serveMux {
 	mux := &serveMux{
 		lookups:              make([]*route, 0),
 		errorHandlers:        make(map[int]Handler, 0),
 		hostname:             DefaultServerHostname, // these are changing when the server is up
 		correctPath:          !DefaultDisablePathCorrection,
 		fireMethodNotAllowed: false,
-		logger:               logger,
+		logPanic:             logPanic,
+		assets:               newAssetResolver(),
 	}
 
 	return mux
@@ -946,8 +958,17 @@ func (mux *serveMux) build() (methodEqual func(string, string) bool) {
 
 	sort.Sort(bySubdomain(mux.lookups))
 
+	mux.routePaths = make(map[*Handler]string, len(mux.lookups))
 	for i := range mux.lookups {
 		r := mux.lookups[i]
+		if r.party != nil {
+			if views := r.party.partyViews(); views != nil {
+				r.middleware = append(Middleware{views.handler()}, r.middleware...)
+			}
+		}
+		if len(r.middleware) > 0 {
+			mux.routePaths[&r.middleware[0]] = r.subdomain + r.path
+		}
 		// add to the registry tree
 		tree := mux.getTree(r.method, r.subdomain)
 		if tree == nil {
@@ -958,7 +979,7 @@ func (mux *serveMux) build() (methodEqual func(string, string) bool) {
 		// I decide that it's better to explicit give subdomain and a path to it than registedPath(mysubdomain./something) now its: subdomain: mysubdomain., path: /something
 		// we have different tree for each of subdomains, now you can use everything you can use with the normal paths ( before you couldn't set /any/*path)
 		if err := tree.entry.add(r.path, r.middleware); err != nil {
-			mux.logger.Panic(err)
+			mux.logPanic(err)
 		}
 
 		if mp := tree.entry.paramsLen; mp > mux.maxParameters {
@@ -986,6 +1007,16 @@ func (mux *serveMux) build() (methodEqual func(string, string) bool) {
 
 }
 
+// routeOf returns the registered path of the context's route, the "unmatched" if the request matched no route
+func (mux *serveMux) routeOf(ctx *Context) string {
+	if len(ctx.Middleware) > 0 {
+		if path, ok := mux.routePaths[&ctx.Middleware[0]]; ok {
+			return path
+		}
+	}
+	return metricsUnmatchedRoute
+}
+
 func (mux *serveMux) lookup(routeName string) *route {
 	for i := range mux.lookups {
 		if r := mux.lookups[i]; r.name == routeName {
@@ -1162,6 +1193,7 @@ func CERT(addr string, cert tls.Certificate) (net.Listener, error) {
 	tlsConfig := &tls.Config{
 		Certificates:             []tls.Certificate{cert},
 		PreferServerCipherSuites: true,
+		NextProtos:               []string{"h2", "http/1.1"},
 	}
 	return tls.NewListener(ln, tlsConfig), nil
 }
@@ -0,0 +1,30 @@
+package iris
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// configureHTTP2 applies the Configuration's HTTP2 to the 'srv', its Handler should be already set
+func (s *Framework) configureHTTP2(srv *http.Server) error {
+	c := s.Config.HTTP2
+	h2s := &http2.Server{
+		MaxConcurrentStreams:         c.MaxConcurrentStreams,
+		MaxReadFrameSize:             c.MaxReadFrameSize,
+		MaxUploadBufferPerConnection: c.MaxUploadBufferPerConnection,
+		MaxUploadBufferPerStream:     c.MaxUploadBufferPerStream,
+		IdleTimeout:                  c.IdleTimeout,
+	}
+	// a custom TLSNextProto disables the HTTP/2 over TLS
+	if srv.TLSNextProto == nil {
+		if err := http2.ConfigureServer(srv, h2s); err != nil {
+			return err
+		}
+	}
+	if c.H2C {
+		srv.Handler = h2c.NewHandler(srv.Handler, h2s)
+	}
+	return nil
+}
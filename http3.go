@@ -0,0 +1,65 @@
+package iris
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// EnableHTTP3 enables the HTTP/3 (QUIC) server of the default iris instance, see the Framework's EnableHTTP3
+func EnableHTTP3(addr string, tlsConfig *tls.Config) {
+	Default.EnableHTTP3(addr, tlsConfig)
+}
+
+// EnableHTTP3 enables the HTTP/3 (QUIC) server, on the udp 'addr', which serves the same router and middleware as the main server
+// and it's started and shut down with it. The responses of the tcp servers advertise it with the Alt-Svc header,
+// so the clients which support HTTP/3 switch to it on their next requests.
+//
+// The 'tlsConfig' should have the certificates of the domain, i.e the same as the main https server's,
+// the HTTP/3 requires TLS 1.3.
+// It should be called before the Listen/Serve/Run functions.
+func (s *Framework) EnableHTTP3(addr string, tlsConfig *tls.Config) {
+	s.http3 = &http3.Server{
+		Addr:      ParseHost(addr),
+		TLSConfig: http3.ConfigureTLSConfig(tlsConfig),
+	}
+}
+
+// altSvcHandler advertises the HTTP/3 server to the clients of the 'h'
+func (s *Framework) altSvcHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 {
+			s.http3.SetQUICHeaders(w.Header())
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// startHTTP3 listens to the udp address of the HTTP/3 server and serves its requests, on its own goroutine
+func (s *Framework) startHTTP3() error {
+	if s.http3 == nil {
+		return nil
+	}
+	conn, err := net.ListenPacket("udp", s.http3.Addr)
+	if err != nil {
+		return err
+	}
+	s.http3.Handler = s.Router
+	go func(srv *http3.Server) {
+		if err := srv.Serve(conn); err != nil && err != http.ErrServerClosed {
+			s.log(LogLevelError, "HTTP/3 server", "err", err)
+		}
+	}(s.http3)
+	return nil
+}
+
+// shutdownHTTP3 gracefully shuts down the HTTP/3 server, if enabled
+func (s *Framework) shutdownHTTP3(ctx context.Context) error {
+	if s.http3 == nil {
+		return nil
+	}
+	return s.http3.Shutdown(ctx)
+}
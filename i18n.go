@@ -0,0 +1,200 @@
+package iris
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultI18nURLParameter the default url parameter which sets the language of a request, i.e ?lang=el-GR
+	DefaultI18nURLParameter = "lang"
+	// DefaultI18nCookie the default cookie which keeps the language of a client
+	DefaultI18nCookie = "lang"
+)
+
+// I18n is the translations' registry, each language has its own key-value messages.
+//
+// The language of a request is taken by its url parameter, its cookie or its Accept-Language header, in that order,
+// if none of them matches a registered language then the Default language is used.
+//
+// Usage:
+// app.I18n.Add("en-US", map[string]string{"hello": "Hello %s"})
+// app.I18n.Add("el-GR", map[string]string{"hello": "Γειά σου %s"})
+// ctx.Tr("hello", "iris") or {{ t "el-GR" "hello" .Name }} inside the templates
+type I18n struct {
+	// Default the language which is used when the request's language is missing or it's not registered
+	// Defaults to the first added language
+	Default string
+	// URLParameter the url parameter which sets the language of a request
+	// Defaults to "lang", set to empty to disable it
+	URLParameter string
+	// Cookie the cookie which sets the language of a request
+	// Defaults to "lang", set to empty to disable it
+	Cookie string
+
+	messages map[string]map[string]string
+	mu       sync.RWMutex
+}
+
+// NewI18n returns a new, empty, translations' registry
+func NewI18n() *I18n {
+	return &I18n{
+		URLParameter: DefaultI18nURLParameter,
+		Cookie:       DefaultI18nCookie,
+		messages:     make(map[string]map[string]string),
+	}
+}
+
+// Add registers the 'messages' of a language, a second call for the same language merges the messages
+func (i *I18n) Add(lang string, messages map[string]string) {
+	i.mu.Lock()
+	if i.Default == "" {
+		i.Default = lang
+	}
+	m, ok := i.messages[lang]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		i.messages[lang] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+	i.mu.Unlock()
+}
+
+// AddJSON same as Add but the messages are decoded from a json object of key-values
+func (i *I18n) AddJSON(lang string, messages []byte) error {
+	m := make(map[string]string)
+	if err := json.Unmarshal(messages, &m); err != nil {
+		return err
+	}
+	i.Add(lang, m)
+	return nil
+}
+
+// Languages returns the registered languages
+func (i *I18n) Languages() []string {
+	i.mu.RLock()
+	langs := make([]string, 0, len(i.messages))
+	for lang := range i.messages {
+		langs = append(langs, lang)
+	}
+	i.mu.RUnlock()
+	sort.Strings(langs)
+	return langs
+}
+
+// match returns the registered language for the 'lang',
+// an exact match or the first language with the same base, i.e "en" for "en-US", returns empty string if not found.
+func (i *I18n) match(lang string) string {
+	if lang == "" {
+		return ""
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for registered := range i.messages {
+		if strings.EqualFold(registered, lang) {
+			return registered
+		}
+	}
+	registered := make([]string, 0, len(i.messages))
+	for r := range i.messages {
+		registered = append(registered, r)
+	}
+	sort.Strings(registered)
+	base := strings.SplitN(lang, "-", 2)[0]
+	for _, r := range registered {
+		if strings.EqualFold(strings.SplitN(r, "-", 2)[0], base) {
+			return r
+		}
+	}
+	return ""
+}
+
+// Tr returns the translated message of the 'key' in the 'lang' language,
+// formatted with the 'args' if any, if the key is missing from the language then the Default's message is used,
+// if it is missing from both then the key is returned.
+func (i *I18n) Tr(lang string, key string, args ...interface{}) string {
+	if matched := i.match(lang); matched != "" {
+		lang = matched
+	} else {
+		lang = i.Default
+	}
+
+	i.mu.RLock()
+	msg, ok := i.messages[lang][key]
+	if !ok {
+		msg, ok = i.messages[i.Default][key]
+	}
+	i.mu.RUnlock()
+
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Language returns the registered language of the request
+func (i *I18n) Language(ctx *Context) string {
+	if i.URLParameter != "" {
+		if lang := i.match(ctx.URLParam(i.URLParameter)); lang != "" {
+			return lang
+		}
+	}
+	if i.Cookie != "" {
+		if lang := i.match(ctx.GetCookie(i.Cookie)); lang != "" {
+			return lang
+		}
+	}
+	for _, lang := range parseAcceptLanguage(ctx.RequestHeader("Accept-Language")) {
+		if matched := i.match(lang); matched != "" {
+			return matched
+		}
+	}
+	return i.Default
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header, sorted by their quality
+func parseAcceptLanguage(header string) []string {
+	if header == "" {
+		return nil
+	}
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		q := 1.0
+		if idx := strings.IndexByte(part, ';'); idx > -1 {
+			params := strings.TrimSpace(part[idx+1:])
+			part = strings.TrimSpace(part[:idx])
+			if strings.HasPrefix(params, "q=") {
+				if v, err := strconv.ParseFloat(params[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if part == "*" || q <= 0 {
+			continue
+		}
+		items = append(items, weighted{part, q})
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].q > items[b].q })
+	langs := make([]string, len(items))
+	for idx := range items {
+		langs[idx] = items[idx].lang
+	}
+	return langs
+}
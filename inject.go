@@ -0,0 +1,190 @@
+package iris
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errProvider        = errors.New("RegisterProvider: expected a func(*iris.Context) T or a func(*iris.Context) (T, error) but got %T")
+	errInjectHandler   = errors.New("Inject: expected a func but got %T")
+	errInjectVariadic  = errors.New("Inject: the %s: the variadic funcs are not supported")
+	errInjectResults   = errors.New("Inject: the %s: expected the results (), (error), (T) or (T, error)")
+	errInjectInput     = errors.New("Inject: the %s of the %s has no provider and it's not a struct to bind")
+	errInjectProvider  = errors.New("Inject: the provider of the %s failed. Trace: %s")
+	errInjectHandlerFn = errors.New("the %s failed. Trace: %s")
+)
+
+var (
+	contextPtrType = reflect.TypeOf((*Context)(nil))
+	errorType      = reflect.TypeOf((*error)(nil)).Elem()
+	timeType       = reflect.TypeOf(time.Time{})
+)
+
+// RegisterProvider registers a provider of the Inject's handlers to the default iris instance, see the Framework's RegisterProvider
+func RegisterProvider(fn interface{}) {
+	Default.RegisterProvider(fn)
+}
+
+// RegisterProvider registers the 'fn', a func(*iris.Context) T or a func(*iris.Context) (T, error), as the provider of the T arguments
+// of the Inject's handlers, i.e the current user, it replaces the previous one of the T.
+// A provider's error is rendered like the handlers' errors, see the Inject.
+// It panics if the 'fn' is not a provider.
+//
+// Usage:
+// app.RegisterProvider(func(ctx *iris.Context) (User, error) {
+//	return users.Find(ctx.Session().GetString("user_id"))
+// })
+func (s *Framework) RegisterProvider(fn interface{}) {
+	v := reflect.ValueOf(fn)
+	typ := v.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.In(0) != contextPtrType ||
+		typ.NumOut() == 0 || typ.NumOut() > 2 || typ.Out(0) == errorType || (typ.NumOut() == 2 && typ.Out(1) != errorType) {
+		panic(errProvider.Format(fn))
+	}
+	if s.providers == nil {
+		s.providers = make(map[reflect.Type]reflect.Value)
+	}
+	s.providers[typ.Out(0)] = v
+}
+
+// Inject returns the handler of the 'fn', a func of typed arguments and results, i.e func(user User, input CreateOrder) (Order, error).
+// Its arguments are resolved on each request:
+// the *iris.Context is the request's context,
+// the types of the RegisterProvider are provided by their providers,
+// the rest of the structs and the pointers to structs are bound by the ctx.Bind, from the request's body and the binding sources,
+// i.e `param:"id"`, and validated, the values which are not valid are rendered with the 422 status code and the rest of the errors with the 400.
+// Its results are (), (error), (T) or (T, error):
+// a non-nil error is rendered by its status code if it's a TransactionErrResult, as a validation error if it's a ValidationErrors,
+// otherwise it's logged and rendered with the 500 status code,
+// a nil pointer or interface T is rendered with the 404 status code, a nil map or slice as an empty one,
+// and the rest of the T values by the serializer of the request's Accept, the JSON, the XML or one of the RegisterSerializer, see the ctx.Negotiate.
+// It panics if the 'fn' is not a func, it's variadic or its results are not supported.
+//
+// Usage:
+// type CreateOrder struct {
+//	Product  string `json:"product" validate:"required"`
+//	Quantity int    `json:"quantity" validate:"min=1"`
+// }
+//
+// app.Post("/orders", iris.Inject(func(user User, input CreateOrder) (Order, error) {
+//	return orders.Create(user.ID, input)
+// }))
+func Inject(fn interface{}) HandlerFunc {
+	v := reflect.ValueOf(fn)
+	typ := v.Type()
+	if typ.Kind() != reflect.Func {
+		panic(errInjectHandler.Format(fn))
+	}
+	if typ.IsVariadic() {
+		panic(errInjectVariadic.Format(typ))
+	}
+	if typ.NumOut() > 2 || (typ.NumOut() == 2 && (typ.Out(0) == errorType || typ.Out(1) != errorType)) {
+		panic(errInjectResults.Format(typ))
+	}
+
+	return func(ctx *Context) {
+		args := make([]reflect.Value, typ.NumIn())
+		for i := range args {
+			arg, ok := ctx.resolve(typ.In(i), typ)
+			if !ok {
+				return
+			}
+			args[i] = arg
+		}
+		ctx.Dispatch(typ.String(), v.Call(args))
+	}
+}
+
+// resolve returns the argument of the 'in' type of the 'fn', false if it's not resolved and the error is rendered
+func (ctx *Context) resolve(in reflect.Type, fn reflect.Type) (reflect.Value, bool) {
+	if in == contextPtrType {
+		return reflect.ValueOf(ctx), true
+	}
+	if provider, ok := ctx.framework.providers[in]; ok {
+		results := provider.Call([]reflect.Value{reflect.ValueOf(ctx)})
+		if len(results) == 2 && !results[1].IsNil() {
+			ctx.renderInjectError(errInjectProvider.Format(in, results[1].Interface()), results[1].Interface().(error))
+			return reflect.Value{}, false
+		}
+		return results[0], true
+	}
+
+	elem := in
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct || elem == timeType {
+		ctx.framework.log(LogLevelError, errInjectInput.Format(in, fn).Error())
+		ctx.EmitError(StatusInternalServerError)
+		return reflect.Value{}, false
+	}
+	ptr := reflect.New(elem)
+	if err := ctx.Bind(ptr.Interface()); err != nil {
+		// the values which are not valid are rendered by the Bind
+		if ctx.ResponseWriter.StatusCode() != StatusUnprocessableEntity {
+			ctx.EmitError(StatusBadRequest)
+		}
+		return reflect.Value{}, false
+	}
+	if in.Kind() == reflect.Ptr {
+		return ptr, true
+	}
+	return ptr.Elem(), true
+}
+
+// Dispatch renders the 'results' of the func of the 'name', a handler of the Inject or a method of an mvc controller,
+// like the Inject renders its handlers' results, the 'name' is logged with the internal errors.
+// The results are (), (error), (T) or (T, error).
+func (ctx *Context) Dispatch(name string, results []reflect.Value) {
+	if len(results) == 0 {
+		return
+	}
+	if last := results[len(results)-1]; last.Type() == errorType {
+		if !last.IsNil() {
+			err := last.Interface().(error)
+			ctx.renderInjectError(errInjectHandlerFn.Format(name, err), err)
+			return
+		}
+		results = results[:len(results)-1]
+	}
+	if len(results) == 0 {
+		return
+	}
+	v := results[0]
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			ctx.EmitError(StatusNotFound)
+			return
+		}
+	case reflect.Slice:
+		// an empty list is not a missing resource, it's rendered as the [] of the JSON instead of the null
+		if v.IsNil() {
+			v = reflect.MakeSlice(v.Type(), 0, 0)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			v = reflect.MakeMap(v.Type())
+		}
+	}
+	ctx.Negotiate(StatusOK, v.Interface())
+}
+
+// renderInjectError renders the 'err' of a handler or a provider of the Inject, the 'trace' is logged if it's an internal error
+func (ctx *Context) renderInjectError(trace error, err error) {
+	switch e := err.(type) {
+	case TransactionErrResult:
+		if e.StatusCode > 0 {
+			ctx.EmitError(e.StatusCode)
+			return
+		}
+	case ValidationErrors:
+		ctx.renderValidationError(e)
+		return
+	}
+	ctx.framework.log(LogLevelError, trace.Error())
+	ctx.EmitError(StatusInternalServerError)
+}
@@ -55,7 +55,11 @@ package iris // import "github.com/kataras/iris"
 
 import (
 	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/tls"
 	"fmt"
+	iofs "io/fs"
 	"log"
 	"net"
 	"net/http"
@@ -67,14 +71,18 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/gavv/httpexpect"
 	"github.com/kataras/go-errors"
 	"github.com/kataras/go-fs"
 	"github.com/kataras/go-serializer"
 	"github.com/kataras/go-sessions"
 	"github.com/kataras/go-template"
 	"github.com/kataras/go-template/html"
+	"github.com/quic-go/quic-go/http3"
 )
 
 const (
@@ -146,16 +154,65 @@ type (
 		Serve(net.Listener) error
 		Listen(string)
 		ListenTLS(string, string, string)
+		ListenTLSGetCertificate(string, func(*tls.ClientHelloInfo) (*tls.Certificate, error))
 		ListenLETSENCRYPT(string, ...string)
 		ListenUNIX(string, os.FileMode)
+		RunAutoTLS(...string) error
+		Run(Runner) error
+		TestClient() *http.Client
+		EnableMetrics(string, ...MetricsOptions) *MetricsRegistry
+		EnableTracing(...TracingOptions)
+		EnableDebugEndpoints(string, ...DebugOptions)
+		DebugStats() DebugStats
+		Stats() Stats
+		EnableStatsEndpoint(string, ...DebugOptions)
+		Health() *HealthChecks
+		EnableHealthEndpoints(string) *HealthChecks
+		HTTPClient() *http.Client
+		SetLogger(StructuredLogger)
+		AddHost(*Host)
+		EnableHTTP3(string, *tls.Config)
+		RedirectHTTP(string, ...RedirectHTTPOptions) *Host
+		UseSecurity(SecurityPolicy)
+		UseBotFilter(...BotFilterOptions)
+		NewTester(httpexpect.LoggerReporter) *Tester
+		RouteCoverage() RouteCoverage
 		Close() error
+		Shutdown(context.Context) error
+		OnBuild(func())
+		OnServe(func(net.Addr) error)
+		OnSlowRequest(time.Duration, func(SlowRequest))
+		OnErrorReport(ErrorReportFunc)
+		EnableAccessLog(...AccessLogOptions) *AccessLog
+		EnableAudit(AuditOptions)
+		ResponseCache(ResponseCacheStore, time.Duration, ...ResponseCacheOptions) *ResponseCache
+		CacheInvalidate(...string) error
+		CacheWarm([]string, int) error
+		OnShutdown(func(context.Context))
+		RunWithGracefulShutdown(string, time.Duration) error
+		ListenGracefulRestart(string, time.Duration) error
 		Reserve() error
 		AcquireCtx(http.ResponseWriter, *http.Request) *Context
 		ReleaseCtx(*Context)
 		CheckForUpdates(bool)
 		UseSessionDB(sessions.Database)
+		OnSessionCreate(...SessionHookFunc)
+		OnSessionUpdate(...SessionHookFunc)
+		OnSessionDestroy(...SessionHookFunc)
+		DestroySessionByID(string)
 		UseSerializer(string, serializer.Serializer)
+		RegisterSerializer(string, serializer.Serializer, ...map[string]interface{})
+		RegisterBinder(string, BinderFunc)
+		RegisterProvider(interface{})
+		RegisterSanitizer(string, Sanitizer)
+		EncryptParam(string, interface{}) (string, error)
+		DecryptParam(string, string) (string, error)
 		UseTemplate(template.Engine) *template.Loader
+		AdaptView(ViewEngine)
+		ViewMetrics() map[string]ViewMetric
+		Asset(string) string
+		AssetManifest() map[string]string
+		NewWebsocketServer(...RealtimeConfiguration) *RealtimeServer
 		UsePreRender(PreRender)
 		UseGlobal(...Handler)
 		UseGlobalFunc(...HandlerFunc)
@@ -199,13 +256,19 @@ type (
 		StaticServe(string, ...string) RouteNameFunc
 		StaticContent(string, string, []byte) RouteNameFunc
 		StaticEmbedded(string, string, func(string) ([]byte, error), func() []string) RouteNameFunc
-		Favicon(string, ...string) RouteNameFunc
+		Favicon(interface{}, ...string) RouteNameFunc
+		WellKnown(interface{})
 		// static file system
 		StaticHandler(string, string, bool, bool) HandlerFunc
 		StaticWeb(string, string) RouteNameFunc
+		StaticFS(string, iofs.FS) RouteNameFunc
+		HandleDir(string, interface{}, ...DirOptions) RouteNameFunc
 
 		// party layout for template engines
 		Layout(string) MuxAPI
+		// party view engines
+		RegisterView(ViewEngine) MuxAPI
+		UseJSONCodec(JSONCodec, ...JSONOptions) MuxAPI
 
 		// errors
 		OnError(int, HandlerFunc)
@@ -233,15 +296,85 @@ type Framework struct {
 	// iris.ReleaseCtx(ctx) to release/put the context to the pool, at the very end of your custom handler.
 	Router http.Handler
 
-	contextPool sync.Pool
-	once        sync.Once
-	Config      *Configuration
-	sessions    sessions.Sessions
-	serializers serializer.Serializers
-	templates   *templateEngines
-	Logger      *log.Logger
-	Plugins     PluginContainer
-	Websocket   *WebsocketServer
+	contextPool  sync.Pool
+	once         sync.Once
+	Config       *Configuration
+	sessions     sessions.Sessions
+	jwtSessions  *jwtSessions
+	sessionHooks sessionHooks
+	serializers  serializer.Serializers
+	templates    *templateEngines
+	views        *viewEngines
+	Logger       *log.Logger
+	Plugins      PluginContainer
+	Websocket    *WebsocketServer
+	// I18n the translations, used by the ctx.Tr and the "t" template func
+	I18n *I18n
+	// Markdown the markdown to html renderer, used by the ctx.MarkdownBytes and the markdown view engine
+	Markdown *MarkdownRenderer
+	// Validator validates the values of the ctx.ReadJSON, ReadXML, ReadForm and the rest of the readers,
+	// its errors are rendered with the 422 status code, defaults to the DefaultValidator, nil disables the validation
+	Validator Validator
+	// Clock tells the time of the expirations and the limits of the framework, defaults to the SystemClock, see the FakeClock
+	Clock Clock
+	// IDGenerator generates the ids of the stateless sessions, the requests and the websocket connections, defaults to the RandomIDGenerator
+	IDGenerator IDGenerator
+
+	// the logger of the framework's internals, see SetLogger
+	structuredLogger StructuredLogger
+	// the serializers of the RegisterSerializer, by content type, and their content types in the order they're registered
+	registeredSerializers map[string]*registeredSerializer
+	serializerTypes       []string
+	// the JSON engine of the ctx.JSON and the ctx.ReadJSON, see UseJSONCodec
+	jsonEngine *jsonEngine
+	// the binding sources of the RegisterBinder, by struct tag
+	binders map[string]BinderFunc
+	// the providers of the Inject's arguments, by type, see RegisterProvider
+	providers map[reflect.Type]reflect.Value
+	// the sanitizers of the RegisterSanitizer, by name
+	sanitizers map[string]Sanitizer
+	// the cipher of the Config.ParamEncryptionKey, see EncryptParam
+	paramCipher cipher.AEAD
+	// the websocket servers, which are closed on Shutdown
+	realtimeServers []*RealtimeServer
+	// the lifecycle hooks, see OnBuild, OnServe and OnShutdown
+	onBuild    []func()
+	onServe    []func(addr net.Addr) error
+	onShutdown []func(context.Context)
+	// the error report callbacks, see OnErrorReport
+	onErrorReport []ErrorReportFunc
+	// the additional servers, see AddHost
+	hosts []*Host
+	// the HTTP/3 server, see EnableHTTP3
+	http3 *http3.Server
+	// the Strict-Transport-Security header of the responses, see RedirectHTTP
+	hsts string
+	// the limits and the rejections of the requests, see UseSecurity
+	security *SecurityPolicy
+	// the heuristics of the blocked bots, see UseBotFilter
+	botFilter *botFilter
+	// the built-in metrics, see EnableMetrics
+	metrics *frameworkMetrics
+	// the OpenTelemetry tracing, see EnableTracing
+	tracing *frameworkTracing
+	// the health checks, see Health
+	health *HealthChecks
+	// the watchdog of the slow requests, see OnSlowRequest
+	slowRequests *slowRequestWatchdog
+	// the audit of the sensitive routes, see EnableAudit
+	audit *frameworkAudit
+	// the access log, see EnableAccessLog
+	accessLog *AccessLog
+	// the response caches which are invalidated by the CacheInvalidate, see ResponseCache
+	responseCaches []*ResponseCache
+	// the runtime counters, see Stats
+	stats *frameworkStats
+	// the time the server is started, see DebugStats
+	startedAt time.Time
+	// closed when the server is shut down, the Serve returns
+	closed       chan struct{}
+	shutdownOnce sync.Once
+	shutdownMu   sync.Mutex
 }
 
 var _ FrameworkAPI = &Framework{}
@@ -267,12 +400,24 @@ func New(setters ...OptionSetter) *Framework {
 
 	// rendering
 	{
+		s.I18n = NewI18n()
+		s.Markdown = NewMarkdownRenderer()
+		s.Validator = DefaultValidator
+		s.Clock = SystemClock
+		s.IDGenerator = RandomIDGenerator
 		s.serializers = serializer.Serializers{}
+		// the binary formats are sent without a charset
+		s.RegisterSerializer(contentMsgpack, msgpackSerializer{}, map[string]interface{}{"charset": ""})
+		s.RegisterSerializer(contentCBOR, cborSerializer{s}, map[string]interface{}{"charset": ""})
 		// set the templates
-		s.templates = newTemplateEngines(map[string]interface{}{
-			"url":     s.URL,
-			"urlpath": s.Path,
-		})
+		s.templates = newTemplateEngines(s.templateFuncs())
+		// set the view engines, with the same shared funcs
+		s.views = newViewEngines(s.templateFuncs())
+		s.views.adapt = func(e ViewEngine) {
+			if m, ok := e.(*MarkdownEngine); ok && m.Renderer == nil {
+				m.Renderer = s.Markdown
+			}
+		}
 	}
 
 	// websocket & sessions
@@ -288,11 +433,14 @@ func New(setters ...OptionSetter) *Framework {
 	// routing
 	{
 		// set the servemux, which will provide us the public API also, with its context pool
-		mux := newServeMux(s.Logger)
+		mux := newServeMux(s.logPanic)
+		mux.views = s.views
 		mux.setCorrectPath(!s.Config.DisablePathCorrection) // correctPath is re-setted on .Set and after build*
 
 		mux.onLookup = s.Plugins.DoPreLookup
+		s.stats = &frameworkStats{}
 		s.contextPool.New = func() interface{} {
+			atomic.AddInt64(&s.stats.contextsAllocated, 1)
 			return &Context{framework: s}
 		}
 		// set the public router API (and party)
@@ -333,7 +481,7 @@ func Must(err error) {
 func (s *Framework) Must(err error) {
 	if err != nil {
 		//	s.Logger.Panicf("%s. Trace:\n%s", err, debug.Stack())
-		s.Logger.Panic(err)
+		s.logPanic(err)
 	}
 }
 
@@ -385,7 +533,12 @@ func (s *Framework) Build() {
 			}
 
 			if err := s.templates.Load(); err != nil {
-				s.Logger.Panic(err) // panic on templates loading before listening if we have an error.
+				s.logPanic(err) // panic on templates loading before listening if we have an error.
+			}
+
+			// load the view engines, if any
+			if err := s.views.load(s.Config.IsDevelopment); err != nil {
+				s.logPanic(err)
 			}
 		}
 
@@ -395,6 +548,26 @@ func (s *Framework) Build() {
 			s.sessions.Set(s.Config.Sessions, sessions.DisableAutoGC(false))
 		}
 
+		// the stateless sessions are used instead of the server-side sessions when a secret is given
+		if len(s.Config.JWTSessions.Secret) > 0 {
+			jwtSessions, err := newJWTSessions(s.Config.Sessions.Cookie, s.Config.JWTSessions, func(format string, a ...interface{}) {
+				s.log(LogLevelWarn, fmt.Sprintf(format, a...))
+			})
+			if err != nil {
+				s.logPanic(err)
+			}
+			jwtSessions.now, jwtSessions.newID = s.now, s.newID
+			s.jwtSessions = jwtSessions
+		}
+
+		if len(s.Config.ParamEncryptionKey) > 0 {
+			paramCipher, err := newParamCipher(s.Config.ParamEncryptionKey)
+			if err != nil {
+				s.logPanic(err)
+			}
+			s.paramCipher = paramCipher
+		}
+
 		//  prepare the mux runtime fields again, for any case
 		s.mux.setCorrectPath(!s.Config.DisablePathCorrection)
 		s.mux.setFireMethodNotAllowed(s.Config.FireMethodNotAllowed)
@@ -404,36 +577,98 @@ func (s *Framework) Build() {
 		if s.Router == nil {
 			// build and get the default mux' handler(*Context)
 			serve := s.mux.BuildHandler()
+			s.stats.buildRoutes(s.mux)
 			// build the net/http.Handler to bind it to the servers
 			defaultHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				ctx := s.AcquireCtx(w, r)
-				serve(ctx)
+				s.stats.startRequest()
+				var slow *slowRequestWatch
+				if s.slowRequests != nil {
+					slow = s.slowRequests.start()
+				}
+				if s.metrics != nil {
+					s.metrics.startRequest(ctx)
+				}
+				if s.tracing != nil {
+					s.tracing.startRequest(ctx)
+				}
+				if s.accessLog != nil {
+					s.accessLog.startRequest(ctx)
+				}
+				if s.hasErrorReports() {
+					s.serveRecover(serve, ctx)
+				} else {
+					serve(ctx)
+				}
+				if s.tracing != nil {
+					s.tracing.endRequest(ctx)
+				}
+				if s.metrics != nil {
+					s.metrics.observeRequest(ctx)
+				}
+				s.stats.observeRequest(ctx)
+				if s.accessLog != nil {
+					s.accessLog.observeRequest(ctx)
+				}
+				if slow != nil {
+					s.slowRequests.end(slow, ctx)
+				}
 				s.ReleaseCtx(ctx)
 			})
 
 			s.Router = defaultHandler
 		}
 
+		// advertise the HTTP/3 server on the tcp servers
+		if s.http3 != nil {
+			s.Router = s.altSvcHandler(s.Router)
+		}
+
+		// ask the browsers to use only https
+		if s.hsts != "" {
+			s.Router = s.hstsHandler(s.Router)
+		}
+
+		// block the scanners and the bots before the routing
+		if s.botFilter != nil {
+			s.Router = s.botFilterHandler(s.Router)
+		}
+
+		// reject the requests which violate the security policy before the routing
+		if s.security != nil {
+			s.Router = s.securityHandler(s.Router)
+		}
+
+		// reject the unknown hosts before the routing
+		if len(s.Config.AllowedHosts) > 0 {
+			s.Router = s.allowedHostsHandler(s.Router)
+		}
+
 		// set the mux' hostname (for multi subdomain routing)
 		s.mux.hostname = ParseHostname(s.Config.VHost)
 
 		if s.ln != nil { // user called Listen functions or Serve,
 			// create the main server
 			s.srv = &http.Server{
-				ReadTimeout:    s.Config.ReadTimeout,
-				WriteTimeout:   s.Config.WriteTimeout,
-				MaxHeaderBytes: s.Config.MaxHeaderBytes,
-				TLSNextProto:   s.Config.TLSNextProto,
-				ConnState:      s.Config.ConnState,
-				Handler:        s.Router,
-				Addr:           s.Config.VHost,
+				ReadTimeout:       s.Config.ReadTimeout,
+				ReadHeaderTimeout: s.Config.ReadHeaderTimeout,
+				WriteTimeout:      s.Config.WriteTimeout,
+				IdleTimeout:       s.idleTimeout(),
+				MaxHeaderBytes:    s.Config.MaxHeaderBytes,
+				TLSNextProto:      s.Config.TLSNextProto,
+				ConnState:         s.stats.trackConnState(s.Config.ConnState),
+				ErrorLog:          s.serverErrorLog(),
+				Handler:           s.Router,
+				Addr:              s.Config.VHost,
 			}
 			if s.Config.TLSNextProto != nil {
 				s.srv.TLSNextProto = s.Config.TLSNextProto
 			}
-			if s.Config.ConnState != nil {
-				s.srv.ConnState = s.Config.ConnState
+			if err := s.configureHTTP2(s.srv); err != nil {
+				s.logPanic(err)
 			}
+			// the hijacked, websocket, connections are not tracked by the server
+			s.srv.RegisterOnShutdown(s.closeRealtimeServers)
 		}
 
 		// updates, to cover the default station's irs.Config.checkForUpdates
@@ -444,6 +679,13 @@ func (s *Framework) Build() {
 		} else if s.Config.CheckForUpdates {
 			go s.CheckForUpdates(false)
 		}
+
+		s.shutdownMu.Lock()
+		onBuild := s.onBuild
+		s.shutdownMu.Unlock()
+		for _, cb := range onBuild {
+			cb()
+		}
 	})
 }
 
@@ -459,29 +701,98 @@ func Serve(ln net.Listener) error {
 }
 
 // Serve serves incoming connections from the given listener.
+// The listener is not closed if the server can't start, i.e if an OnServe hook fails, it's the caller's.
 //
 // Serve blocks until the given listener returns permanent error.
 func (s *Framework) Serve(ln net.Listener) error {
+	if err := s.serve(ln); err != nil {
+		return err
+	}
+	return s.wait()
+}
+
+// serveOwned serves the 'ln' which is created by the framework, i.e by the Listen or the Run(iris.Addr(":8080")), like the Serve,
+// but the 'ln' is closed if the server can't start, so its address is released
+func (s *Framework) serveOwned(ln net.Listener) error {
+	if err := s.serve(ln); err != nil {
+		ln.Close()
+		return err
+	}
+	return s.wait()
+}
+
+// wait blocks until an interrupt signal is received or the server is shut down, see the Serve
+func (s *Framework) wait() error {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	defer signal.Stop(ch)
+	select {
+	case <-ch:
+	case <-s.closed:
+		// gracefully shut down by the .Shutdown
+		return nil
+	}
+	if err := s.Close(); err != nil {
+		if s.Config.IsDevelopment {
+			s.log(LogLevelError, "closing the server", "err", err)
+		}
+		return err
+	}
+	os.Exit(1)
+	return nil
+}
+
+// serve starts the server on the listener, in its own goroutine, and fires the listen plugins
+func (s *Framework) serve(ln net.Listener) error {
 	if s.IsRunning() {
 		return errServerAlreadyStarted
 	}
 	// maybe a 'race' here but user should not call .Serve more than one time especially in more than one go routines...
+	ln = LimitListener(ln, s.Config.MaxConnections, s.Config.MaxConnectionsPerIP)
 	s.ln = ln
+	s.startedAt = time.Now()
+	s.closed = make(chan struct{})
+	s.shutdownOnce = sync.Once{}
 
 	s.Build()
+	if err := s.startHosts(); err != nil {
+		s.ln = nil
+		return err
+	}
+	if err := s.startHTTP3(); err != nil {
+		s.shutdownHosts(context.Background())
+		s.ln = nil
+		return err
+	}
 	s.Plugins.DoPreListen(s)
 
+	s.shutdownMu.Lock()
+	onServe := s.onServe
+	s.shutdownMu.Unlock()
+	for _, cb := range onServe {
+		if err := cb(ln.Addr()); err != nil {
+			s.shutdownHTTP3(context.Background())
+			s.shutdownHosts(context.Background())
+			s.ln = nil
+			return err
+		}
+	}
+
 	// This didn't helped me ,here, but maybe can help you:
 	// https://www.oreilly.com/learning/run-strikingly-fast-parallel-file-searches-in-go-with-sync-errgroup?utm_source=golangweekly&utm_medium=email
 	// new experimental package: errgroup
 
 	defer func() {
 		if err := recover(); err != nil {
-			s.Logger.Panic(err)
+			s.logPanic(err)
 		}
 	}()
 	// start the server in goroutine, .Available will block instead
-	go func() { s.Must(s.srv.Serve(ln)) }()
+	go func() {
+		if err := s.srv.Serve(ln); err != http.ErrServerClosed {
+			s.Must(err)
+		}
+	}()
 
 	if !s.Config.DisableBanner {
 		bannerMessage := fmt.Sprintf("%s: Running at %s", time.Now().Format(s.Config.TimeFormat), s.Config.VHost)
@@ -495,16 +806,6 @@ func (s *Framework) Serve(ln net.Listener) error {
 	s.Plugins.DoPostListen(s)
 
 	go func() { s.Available <- true }()
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
-	<-ch
-	if err := s.Close(); err != nil {
-		if s.Config.IsDevelopment {
-			s.Logger.Printf("Error while closing the server: %s\n", err)
-		}
-		return err
-	}
-	os.Exit(1)
 	return nil
 }
 
@@ -531,10 +832,10 @@ func (s *Framework) Listen(addr string) {
 
 	ln, err := TCP4(addr)
 	if err != nil {
-		s.Logger.Panic(err)
+		s.logPanic(err)
 	}
 
-	s.Must(s.Serve(ln))
+	s.Must(s.serveOwned(ln))
 }
 
 // ListenTLS Starts a https server with certificates,
@@ -555,6 +856,9 @@ func ListenTLS(addr string, certFile string, keyFile string) {
 // which listens to the addr parameter which as the form of
 // host:port
 //
+// The certFile and keyFile are checked for changes every DefaultCertReloadInterval,
+// renewed certificates are served without restarting the server
+//
 // It panics on error if you need a func to return an error, use the Serve
 // ex: iris.ListenTLS(":8080","yourfile.cert","yourfile.key")
 func (s *Framework) ListenTLS(addr string, certFile, keyFile string) {
@@ -564,11 +868,15 @@ func (s *Framework) ListenTLS(addr string, certFile, keyFile string) {
 		// this will be set as the front-end listening addr
 	}
 
-	ln, err := TLS(addr, certFile, keyFile)
+	reloader, err := s.watchCert(certFile, keyFile)
 	if err != nil {
-		s.Logger.Panic(err)
+		s.logPanic(err)
 	}
-	s.Must(s.Serve(ln))
+	ln, err := s.listenGetCert(addr, reloader.GetCertificate)
+	if err != nil {
+		s.logPanic(err)
+	}
+	s.Must(s.serveOwned(ln))
 }
 
 // ListenLETSENCRYPT starts a server listening at the specific nat address
@@ -605,12 +913,12 @@ func (s *Framework) ListenLETSENCRYPT(addr string, cacheFileOptional ...string)
 	}
 	ln, err := LETSENCRYPT(addr, cacheFileOptional...)
 	if err != nil {
-		s.Logger.Panic(err)
+		s.logPanic(err)
 	}
 
 	// starts a second server which listening on :80 to redirect all requests to the :443 (https://)
 	Proxy(":80", "https://"+addr)
-	s.Must(s.Serve(ln))
+	s.Must(s.serveOwned(ln))
 }
 
 // ListenUNIX starts the process of listening to the new requests using a 'socket file', this works only on unix
@@ -633,10 +941,10 @@ func (s *Framework) ListenUNIX(addr string, mode os.FileMode) {
 	}
 	ln, err := UNIX(addr, mode)
 	if err != nil {
-		s.Logger.Panic(err)
+		s.logPanic(err)
 	}
 
-	s.Must(s.Serve(ln))
+	s.Must(s.serveOwned(ln))
 }
 
 // IsRunning returns true if server is running
@@ -668,6 +976,152 @@ func (s *Framework) Close() error {
 	return nil
 }
 
+// Shutdown gracefully shuts down the server and the additional hosts, it stops accepting new connections,
+// closes the websocket connections with a going away close frame, waits for the in-flight requests to finish
+// and fires the OnShutdown callbacks.
+// If the 'ctx' expires before the in-flight requests are finished its error is returned, the callbacks are fired anyway.
+//
+// A blocked .Serve/.Listen returns after the Shutdown.
+func Shutdown(ctx context.Context) error {
+	return Default.Shutdown(ctx)
+}
+
+// Shutdown gracefully shuts down the server and the additional hosts, it stops accepting new connections,
+// closes the websocket connections with a going away close frame, waits for the in-flight requests to finish
+// and fires the OnShutdown callbacks.
+// If the 'ctx' expires before the in-flight requests are finished its error is returned, the callbacks are fired anyway.
+//
+// A blocked .Serve/.Listen returns after the Shutdown.
+func (s *Framework) Shutdown(ctx context.Context) error {
+	if s.srv == nil || !s.IsRunning() {
+		return nil
+	}
+	s.Plugins.DoPreClose(s)
+	err := s.srv.Shutdown(ctx)
+	if herr := s.shutdownHosts(ctx); err == nil {
+		err = herr
+	}
+	if herr := s.shutdownHTTP3(ctx); err == nil {
+		err = herr
+	}
+
+	s.shutdownMu.Lock()
+	onShutdown := s.onShutdown
+	s.shutdownMu.Unlock()
+	for _, cb := range onShutdown {
+		cb(ctx)
+	}
+
+	s.Available = make(chan bool)
+	s.shutdownOnce.Do(func() { close(s.closed) })
+	return err
+}
+
+// OnShutdown registers a callback which is fired by the Shutdown, after the in-flight requests are finished,
+// to release the application's resources, i.e to close the database connections.
+// The callbacks are fired in the order they are registered, the 'ctx' is the Shutdown's one
+func OnShutdown(cb func(ctx context.Context)) {
+	Default.OnShutdown(cb)
+}
+
+// OnShutdown registers a callback which is fired by the Shutdown, after the in-flight requests are finished,
+// to release the application's resources, i.e to close the database connections.
+// The callbacks are fired in the order they are registered, the 'ctx' is the Shutdown's one
+func (s *Framework) OnShutdown(cb func(ctx context.Context)) {
+	s.shutdownMu.Lock()
+	s.onShutdown = append(s.onShutdown, cb)
+	s.shutdownMu.Unlock()
+}
+
+// OnBuild registers a callback which is fired once by the Build, after the router is built and the configuration is final,
+// i.e to warm up the caches from the registered routes.
+// The callbacks are fired in the order they are registered
+func OnBuild(cb func()) {
+	Default.OnBuild(cb)
+}
+
+// OnBuild registers a callback which is fired once by the Build, after the router is built and the configuration is final,
+// i.e to warm up the caches from the registered routes.
+// The callbacks are fired in the order they are registered
+func (s *Framework) OnBuild(cb func()) {
+	s.shutdownMu.Lock()
+	s.onBuild = append(s.onBuild, cb)
+	s.shutdownMu.Unlock()
+}
+
+// OnServe registers a callback which is fired by the Serve/Listen functions with the listening address,
+// before the server accepts its first connection, i.e to start the schedulers or to connect to the brokers.
+// If a callback returns an error the server is not started and the error is returned by the Serve.
+// The callbacks are fired in the order they are registered, on each start of the server
+func OnServe(cb func(addr net.Addr) error) {
+	Default.OnServe(cb)
+}
+
+// OnServe registers a callback which is fired by the Serve/Listen functions with the listening address,
+// before the server accepts its first connection, i.e to start the schedulers or to connect to the brokers.
+// If a callback returns an error the server is not started and the error is returned by the Serve.
+// The callbacks are fired in the order they are registered, on each start of the server
+func (s *Framework) OnServe(cb func(addr net.Addr) error) {
+	s.shutdownMu.Lock()
+	s.onServe = append(s.onServe, cb)
+	s.shutdownMu.Unlock()
+}
+
+// closeRealtimeServers closes the connections of the websocket servers, it's called when the Shutdown starts
+func (s *Framework) closeRealtimeServers() {
+	s.shutdownMu.Lock()
+	servers := s.realtimeServers
+	s.shutdownMu.Unlock()
+	for _, ws := range servers {
+		ws.Close()
+	}
+}
+
+// RunWithGracefulShutdown starts the standalone http server, like the Listen,
+// and blocks until an interrupt or a termination signal is received,
+// then it gracefully shuts down the server and it waits up to the 'timeout' for the in-flight requests.
+//
+// It returns the error of the listener or the Shutdown's error
+func RunWithGracefulShutdown(addr string, timeout time.Duration) error {
+	return Default.RunWithGracefulShutdown(addr, timeout)
+}
+
+// RunWithGracefulShutdown starts the standalone http server, like the Listen,
+// and blocks until an interrupt or a termination signal is received,
+// then it gracefully shuts down the server and it waits up to the 'timeout' for the in-flight requests.
+//
+// It returns the error of the listener or the Shutdown's error
+func (s *Framework) RunWithGracefulShutdown(addr string, timeout time.Duration) error {
+	addr = ParseHost(addr)
+	if s.Config.VHost == "" {
+		s.Config.VHost = addr
+		// this will be set as the front-end listening addr
+	}
+
+	ln, err := TCP4(addr)
+	if err != nil {
+		return err
+	}
+	if err = s.serve(ln); err != nil {
+		ln.Close()
+		return err
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(ch)
+	select {
+	case <-ch:
+	case <-s.closed:
+		// already shut down
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.Shutdown(ctx)
+}
+
 // Reserve re-starts the server using the last .Serve's listener
 func Reserve() error {
 	return Default.Reserve()
@@ -693,6 +1147,7 @@ func (s *Framework) AcquireCtx(w http.ResponseWriter, r *http.Request) *Context
 	if ctx.Request.Body != nil {
 		ctx.Request.Body = http.MaxBytesReader(w, r.Body, s.Config.MaxRequestBodySize)
 	}
+	atomic.AddInt64(&s.stats.contextsInUse, 1)
 	return ctx
 }
 
@@ -705,6 +1160,10 @@ func ReleaseCtx(ctx *Context) {
 // ReleaseCtx puts the Iris' Context back to the pool in order to be re-used
 // see .AcquireCtx & .Serve
 func (s *Framework) ReleaseCtx(ctx *Context) {
+	// write the stateless session's cookie, if changed, before the headers are sent
+	if sess, ok := unwrapSession(ctx.session).(*jwtSession); ok {
+		s.jwtSessions.commit(ctx.ResponseWriter, ctx.Request, sess)
+	}
 	// flush the body when all finished
 	ctx.ResponseWriter.flushResponse()
 
@@ -714,6 +1173,7 @@ func (s *Framework) ReleaseCtx(ctx *Context) {
 	releaseResponseWriter(ctx.ResponseWriter)
 	ctx.values.Reset()
 
+	atomic.AddInt64(&s.stats.contextsInUse, -1)
 	s.contextPool.Put(ctx)
 }
 
@@ -765,9 +1225,7 @@ func (s *Framework) CheckForUpdates(force bool) {
 	}
 
 	if updated { // if updated, then do not run the web server
-		if s.Logger != nil {
-			s.Logger.Println("exiting now...")
-		}
+		s.log(LogLevelInfo, "exiting now...")
 		os.Exit(1)
 	}
 
@@ -789,10 +1247,77 @@ func UseSessionDB(db sessions.Database) {
 // a session database doesn't have write access to the session, it doesn't accept the context, so forget 'cookie database' for sessions, I will never allow that, for your protection.
 //
 // Note: Don't worry if no session database is registered, your context.Session will continue to work.
+// The SQLSessionDB without an Expires expires its rows by the Config.Sessions.Expires, at the time of the Framework's Clock.
 func (s *Framework) UseSessionDB(db sessions.Database) {
+	if sqlDB, ok := db.(*SQLSessionDB); ok {
+		if sqlDB.Expires == 0 {
+			sqlDB.Expires = s.Config.Sessions.Expires
+		}
+		sqlDB.now = s.now
+	}
 	s.sessions.UseDatabase(db)
 }
 
+// OnSessionCreate registers listeners which are fired when a new session is started,
+// useful to audit logins or to keep track of the sessions of each user
+func OnSessionCreate(hooks ...SessionHookFunc) {
+	Default.OnSessionCreate(hooks...)
+}
+
+// OnSessionCreate registers listeners which are fired when a new session is started,
+// useful to audit logins or to keep track of the sessions of each user
+func (s *Framework) OnSessionCreate(hooks ...SessionHookFunc) {
+	s.sessionHooks.mu.Lock()
+	s.sessionHooks.create = append(s.sessionHooks.create, hooks...)
+	s.sessionHooks.mu.Unlock()
+}
+
+// OnSessionUpdate registers listeners which are fired on each change of the session's values or flash messages,
+// i.e to enforce a concurrent-session limit when a user id is stored to the session
+func OnSessionUpdate(hooks ...SessionHookFunc) {
+	Default.OnSessionUpdate(hooks...)
+}
+
+// OnSessionUpdate registers listeners which are fired on each change of the session's values or flash messages,
+// i.e to enforce a concurrent-session limit when a user id is stored to the session
+func (s *Framework) OnSessionUpdate(hooks ...SessionHookFunc) {
+	s.sessionHooks.mu.Lock()
+	s.sessionHooks.update = append(s.sessionHooks.update, hooks...)
+	s.sessionHooks.mu.Unlock()
+}
+
+// OnSessionDestroy registers listeners which are fired on context.SessionDestroy, before the session's values are removed
+func OnSessionDestroy(hooks ...SessionHookFunc) {
+	Default.OnSessionDestroy(hooks...)
+}
+
+// OnSessionDestroy registers listeners which are fired on context.SessionDestroy, before the session's values are removed
+func (s *Framework) OnSessionDestroy(hooks ...SessionHookFunc) {
+	s.sessionHooks.mu.Lock()
+	s.sessionHooks.destroy = append(s.sessionHooks.destroy, hooks...)
+	s.sessionHooks.mu.Unlock()
+}
+
+// DestroySessionByID invalidates a session remotely, by its id,
+// the next request of this session's client will start a fresh session.
+//
+// Note: the OnSessionDestroy listeners are not fired, there is no request's context here.
+func DestroySessionByID(sid string) {
+	Default.DestroySessionByID(sid)
+}
+
+// DestroySessionByID invalidates a session remotely, by its id,
+// the next request of this session's client will start a fresh session.
+//
+// Note: the OnSessionDestroy listeners are not fired, there is no request's context here.
+func (s *Framework) DestroySessionByID(sid string) {
+	if s.jwtSessions != nil {
+		s.jwtSessions.revoke(sid)
+		return
+	}
+	s.sessions.DestroyByID(sid)
+}
+
 // UseSerializer accepts a Serializer and the key or content type on which the developer wants to register this serializer
 // the gzip and charset are automatically supported by Iris, by passing the iris.RenderOptions{} map on the context.Render
 // context.Render renders this response or a template engine if no response engine with the 'key' found
@@ -861,6 +1386,75 @@ func (s *Framework) UseTemplate(e template.Engine) *template.Loader {
 	return s.templates.AddEngine(e)
 }
 
+// AdaptView registers a view engine, i.e iris.HTML("./templates", ".html"),
+// ctx.Render("page.html", binding) is executed by the view engine which is responsible for the ".html" files.
+// It does not build/load them yet
+func AdaptView(e ViewEngine) {
+	Default.AdaptView(e)
+}
+
+// AdaptView registers a view engine, i.e iris.HTML("./templates", ".html"),
+// ctx.Render("page.html", binding) is executed by the view engine which is responsible for the ".html" files.
+// It does not build/load them yet
+func (s *Framework) AdaptView(e ViewEngine) {
+	s.views.add(e)
+}
+
+// RegisterView same as AdaptView, registers a view engine,
+// i.e iris.RegisterView(iris.HTML(embeddedTemplates, ".html"))
+func RegisterView(e ViewEngine) MuxAPI {
+	return Default.RegisterView(e)
+}
+
+// RegisterView same as AdaptView, registers a view engine,
+// i.e app.RegisterView(iris.HTML(embeddedTemplates, ".html"))
+func (s *Framework) RegisterView(e ViewEngine) MuxAPI {
+	s.AdaptView(e)
+	return s
+}
+
+// Asset returns the cache-busted request path of a static file, the same as the {{ asset "/static/app.js" }} template func,
+// i.e "/static/app.js?v=3f9ab2c1" or "/assets/app.3f9ab2c1.js" when it's served by a HandleDir with the Fingerprint option.
+// The 'reqPath' is returned as it's if the file is not served by any static handler
+func Asset(reqPath string) string {
+	return Default.Asset(reqPath)
+}
+
+// Asset returns the cache-busted request path of a static file, the same as the {{ asset "/static/app.js" }} template func,
+// i.e "/static/app.js?v=3f9ab2c1" or "/assets/app.3f9ab2c1.js" when it's served by a HandleDir with the Fingerprint option.
+// The 'reqPath' is returned as it's if the file is not served by any static handler
+func (s *Framework) Asset(reqPath string) string {
+	return s.mux.assets.resolve(reqPath)
+}
+
+// AssetManifest returns the fingerprinted request path of each file which is served by a HandleDir with the Fingerprint option,
+// by its original request path, i.e {"/assets/app.js": "/assets/app.3f9ab2c1.js"},
+// useful to pass the assets to the client-side code or to a CDN
+func AssetManifest() map[string]string {
+	return Default.AssetManifest()
+}
+
+// AssetManifest returns the fingerprinted request path of each file which is served by a HandleDir with the Fingerprint option,
+// by its original request path, i.e {"/assets/app.js": "/assets/app.3f9ab2c1.js"},
+// useful to pass the assets to the client-side code or to a CDN
+func (s *Framework) AssetManifest() map[string]string {
+	return s.mux.assets.manifest()
+}
+
+// ViewMetrics returns the render statistics of the views, by template name,
+// the number of renders and errors, the total and the slowest render's duration.
+// The templates are parsed once, at the Build, so the durations are the executions only
+func ViewMetrics() map[string]ViewMetric {
+	return Default.ViewMetrics()
+}
+
+// ViewMetrics returns the render statistics of the views, by template name,
+// the number of renders and errors, the total and the slowest render's duration.
+// The templates are parsed once, at the Build, so the durations are the executions only
+func (s *Framework) ViewMetrics() map[string]ViewMetric {
+	return s.views.metrics.snapshot()
+}
+
 // UseGlobal registers Handler middleware  to the beginning, prepends them instead of append
 //
 // Use it when you want to add a global middleware to all parties, to all routes in  all subdomains
@@ -1091,6 +1685,14 @@ func (s *Framework) TemplateString(templateFile string, pageContext interface{},
 		return ""
 	}
 
+	if e := s.views.find(templateFile); e != nil {
+		res, err := s.views.executeString(e, templateFile, pageContext, options)
+		if err != nil {
+			return ""
+		}
+		return res
+	}
+
 	res, err := s.templates.ExecuteString(templateFile, pageContext, options...)
 	if err != nil {
 		return ""
@@ -1130,10 +1732,11 @@ func SerializeToString(keyOrContentType string, obj interface{}, options ...map[
 // does not render it to the client
 // returns empty string on error
 func (s *Framework) SerializeToString(keyOrContentType string, obj interface{}, options ...map[string]interface{}) string {
-	res, err := s.serializers.SerializeToString(keyOrContentType, obj, options...)
+	b, _, err := s.serialize(keyOrContentType, obj, options)
+	res := string(b)
 	if err != nil {
 		if s.Config.IsDevelopment {
-			s.Logger.Printf("Error on SerializeToString, Key(content-type): %s. Trace: %s\n", keyOrContentType, err)
+			s.log(LogLevelError, "SerializeToString", "key", keyOrContentType, "err", err)
 		}
 		return ""
 	}
@@ -1179,6 +1782,10 @@ type muxAPI struct {
 	apiRoutes      []*route // used to register the .Done middleware
 	relativePath   string
 	middleware     Middleware
+	// views the party's own view engines, if any
+	views *viewEngines
+	// parent the party which this one is created from, nil for the root
+	parent *muxAPI
 }
 
 var _ MuxAPI = &muxAPI{}
@@ -1210,7 +1817,7 @@ func (api *muxAPI) Party(relativePath string, handlersFn ...HandlerFunc) MuxAPI
 	// append the parent's +child's handlers
 	middleware = joinMiddleware(api.middleware, middleware)
 
-	return &muxAPI{relativePath: fullpath, mux: api.mux, apiRoutes: make([]*route, 0), middleware: middleware, doneMiddleware: api.doneMiddleware}
+	return &muxAPI{relativePath: fullpath, mux: api.mux, apiRoutes: make([]*route, 0), middleware: middleware, doneMiddleware: api.doneMiddleware, parent: api}
 }
 
 // Use registers Handler middleware
@@ -1387,6 +1994,7 @@ func (api *muxAPI) Handle(method string, registedPath string, handlers ...Handle
 		middleware = append(middleware, api.doneMiddleware...) // register the done middleware, if any
 	}
 	r := api.mux.register(method, subdomain, path, middleware)
+	r.party = api
 	api.apiRoutes = append(api.apiRoutes, r)
 
 	// should we remove the api.apiRoutes on the .Party (new children party) ?, No, because the user maybe use this party later
@@ -1816,77 +2424,46 @@ func (api *muxAPI) StaticEmbedded(requestPath string, vdir string, assetFn func(
 
 // Favicon serves static favicon
 // accepts 2 parameters, second is optional
-// favPath (string), declare the system directory path of the __.ico
+// favicon (string), declare the system path of the __.ico or its directory,
+// or an fs.FS/http.FileSystem (i.e a go:embed embed.FS) which contains a favicon.ico or a favicon.png at its root
 // requestPath (string), it's the route's path, by default this is the "/favicon.ico" because some browsers tries to get this by default first,
 // you can declare your own path if you have more than one favicon (desktop, mobile and so on)
 //
 // this func will add a route for you which will static serve the /yuorpath/yourfile.ico to the /yourfile.ico (nothing special that you can't handle by yourself)
 // Note that you have to call it on every favicon you have to serve automatically (dekstop, mobile and so on)
 //
+// The favicon is served from memory, with its ETag and the FaviconCacheControl.
+//
 // panics on error
-func Favicon(favPath string, requestPath ...string) RouteNameFunc {
-	return Default.Favicon(favPath, requestPath...)
+func Favicon(favicon interface{}, requestPath ...string) RouteNameFunc {
+	return Default.Favicon(favicon, requestPath...)
 }
 
 // Favicon serves static favicon
 // accepts 2 parameters, second is optional
-// favPath (string), declare the system directory path of the __.ico
+// favicon (string), declare the system path of the __.ico or its directory,
+// or an fs.FS/http.FileSystem (i.e a go:embed embed.FS) which contains a favicon.ico or a favicon.png at its root
 // requestPath (string), it's the route's path, by default this is the "/favicon.ico" because some browsers tries to get this by default first,
 // you can declare your own path if you have more than one favicon (desktop, mobile and so on)
 //
 // this func will add a route for you which will static serve the /yuorpath/yourfile.ico to the /yourfile.ico (nothing special that you can't handle by yourself)
 // Note that you have to call it on every favicon you have to serve automatically (dekstop, mobile and so on)
 //
+// The favicon is served from memory, with its ETag and the FaviconCacheControl.
+//
 // panics on error
-func (api *muxAPI) Favicon(favPath string, requestPath ...string) RouteNameFunc {
-	f, err := os.Open(favPath)
+func (api *muxAPI) Favicon(favicon interface{}, requestPath ...string) RouteNameFunc {
+	name, content, modTime, err := readFavicon(favicon)
 	if err != nil {
-		panic(errDirectoryFileNotFound.Format(favPath, err.Error()))
-	}
-	defer f.Close()
-	fi, _ := f.Stat()
-	if fi.IsDir() { // if it's dir the try to get the favicon.ico
-		fav := path.Join(favPath, "favicon.ico")
-		f, err = os.Open(fav)
-		if err != nil {
-			//we try again with .png
-			return api.Favicon(path.Join(favPath, "favicon.png"))
-		}
-		favPath = fav
-		fi, _ = f.Stat()
-	}
-
-	cType := fs.TypeByExtension(favPath)
-	// copy the bytes here in order to cache and not read the ico on each request.
-	cacheFav := make([]byte, fi.Size())
-	if _, err = f.Read(cacheFav); err != nil {
-		panic(errDirectoryFileNotFound.Format(favPath, "Couldn't read the data bytes for Favicon: "+err.Error()))
-	}
-	modtime := ""
-	h := func(ctx *Context) {
-		if modtime == "" {
-			modtime = fi.ModTime().UTC().Format(ctx.framework.Config.TimeFormat)
-		}
-		if t, err := time.Parse(ctx.framework.Config.TimeFormat, ctx.RequestHeader(ifModifiedSince)); err == nil && fi.ModTime().Before(t.Add(StaticCacheDuration)) {
-
-			ctx.ResponseWriter.Header().Del(contentType)
-			ctx.ResponseWriter.Header().Del(contentLength)
-			ctx.SetStatusCode(StatusNotModified)
-			return
-		}
-
-		ctx.ResponseWriter.Header().Set(contentType, cType)
-		ctx.ResponseWriter.Header().Set(lastModified, modtime)
-		ctx.SetStatusCode(StatusOK)
-		ctx.ResponseWriter.SetBody(cacheFav)
+		panic(err)
 	}
 
-	reqPath := "/favicon" + path.Ext(fi.Name()) //we could use the filename, but because standards is /favicon.ico/.png.
+	reqPath := "/favicon" + path.Ext(name) //we could use the filename, but because standards is /favicon.ico/.png.
 	if len(requestPath) > 0 {
 		reqPath = requestPath[0]
 	}
 
-	return api.registerResourceRoute(reqPath, h)
+	return api.registerResourceRoute(reqPath, memoryFileHandler(name, content, modTime, FaviconCacheControl))
 }
 
 // StripPrefix returns a handler that serves HTTP requests
@@ -1920,7 +2497,13 @@ func (api *muxAPI) StaticHandler(reqPath string, systemPath string, showList boo
 		Listing(showList).
 		Gzip(enableGzip).
 		Build()
+	api.mux.assets.add(api.relativePath+reqPath, http.Dir(systemPath))
 
+	return api.managedStaticHandler(h)
+}
+
+// managedStaticHandler fires the custom error handlers on static handler's errors and continues to the next middleware
+func (api *muxAPI) managedStaticHandler(h HandlerFunc) HandlerFunc {
 	managedStaticHandler := func(ctx *Context) {
 		h(ctx)
 		prevStatusCode := ctx.ResponseWriter.StatusCode()
@@ -1974,6 +2557,30 @@ func (api *muxAPI) StaticWeb(reqPath string, systemPath string) RouteNameFunc {
 	return api.registerResourceRoute(routePath, h)
 }
 
+// StaticFS serves the files of an fs.FS, i.e a go:embed embed.FS, under the 'reqPath',
+// so single-binary deployments can serve their assets without the system's directory.
+//
+//     //go:embed public
+//     var public embed.FS
+//     assets, _ := fs.Sub(public, "public")
+//     iris.StaticFS("/static", assets)
+func StaticFS(reqPath string, fileSystem iofs.FS) RouteNameFunc {
+	return Default.StaticFS(reqPath, fileSystem)
+}
+
+// StaticFS serves the files of an fs.FS, i.e a go:embed embed.FS, under the 'reqPath',
+// so single-binary deployments can serve their assets without the system's directory.
+//
+//     //go:embed public
+//     var public embed.FS
+//     assets, _ := fs.Sub(public, "public")
+//     app.StaticFS("/static", assets)
+//
+// StaticFS calls the HandleDir(reqPath, fileSystem) with the default options.
+func (api *muxAPI) StaticFS(reqPath string, fileSystem iofs.FS) RouteNameFunc {
+	return api.HandleDir(reqPath, fileSystem)
+}
+
 // Layout oerrides the parent template layout with a more specific layout for this Party
 // returns this Party, to continue as normal
 // example:
@@ -2007,6 +2614,39 @@ func (api *muxAPI) Layout(tmplLayoutFile string) MuxAPI {
 	return api
 }
 
+// RegisterView registers a view engine for this Party and its children only,
+// the party's templates are isolated from the rest of the app's templates,
+// a ctx.Render inside this Party is executed by the party's view engine which is responsible for the file's extension, if any,
+// otherwise by the app's view engines.
+// The engines are looked up when the app is built, so they're used by all the party's routes and its children's,
+// the ones which are registered before the RegisterView too, the nearest party's engines are used.
+// returns this Party, to continue as normal
+// example:
+// admin := app.Party("/admin").RegisterView(iris.HTML("./admin/templates", ".html").Layout("layouts/admin.html"))
+// 	{
+// 		admin.Get("/", func(ctx *iris.Context) {
+// 			ctx.MustRender("index.html", nil) // ./admin/templates/index.html
+// 		})
+// 	}
+//
+func (api *muxAPI) RegisterView(e ViewEngine) MuxAPI {
+	if api.views == nil {
+		api.views = api.mux.views.party()
+	}
+	api.views.add(e)
+	return api
+}
+
+// partyViews returns the view engines of the nearest party, this or a parent one, nil if none has its own
+func (api *muxAPI) partyViews() *viewEngines {
+	for p := api; p != nil; p = p.parent {
+		if p.views != nil {
+			return p.views
+		}
+	}
+	return nil
+}
+
 // OnError registers a custom http error handler
 func OnError(statusCode int, handlerFn HandlerFunc) {
 	Default.OnError(statusCode, handlerFn)
@@ -0,0 +1,175 @@
+package iris
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/kataras/go-fs"
+)
+
+// jsonCodecContextKey the context's value of the party's json codec, set by the party's UseJSONCodec
+const jsonCodecContextKey = "iris.json"
+
+// JSONEncoder encodes the values to a writer, the *json.Encoder is a JSONEncoder
+type JSONEncoder interface {
+	Encode(v interface{}) error
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+}
+
+// JSONCodec the JSON engine of the ctx.JSON and the ctx.ReadJSON, i.e the encoding/json, the jsoniter or the sonic,
+// see the jsoncodec package for the adapters
+type JSONCodec interface {
+	NewEncoder(w io.Writer) JSONEncoder
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdJSONCodec the JSONCodec of the encoding/json
+var StdJSONCodec JSONCodec = stdJSONCodec{}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) NewEncoder(w io.Writer) JSONEncoder         { return json.NewEncoder(w) }
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// JSONOptions the options of the ctx.JSON, of the Config.JSON, the UseJSONCodec and each ctx.JSON call,
+// the non-zero fields of each one override the previous ones
+type JSONOptions struct {
+	// DisableHTMLEscape doesn't escape the <, > and & of the strings
+	// Defaults to false, they're escaped so the json can be embedded to html
+	DisableHTMLEscape bool
+	// Indent the indentation of the encoded values, i.e "  "
+	// Defaults to "", compact, or to two spaces if the Config.IsDevelopment is true
+	Indent string
+	// Compact disables the indentation, even if the Config.IsDevelopment is true, it overrides the previous Indent
+	// Defaults to false
+	Compact bool
+	// ErrorDetails writes the encoding error, i.e {"error":"json: unsupported type: chan int"}, with the 500 status code,
+	// instead of an empty response
+	// Defaults to false, or to true if the Config.IsDevelopment is true
+	ErrorDetails bool
+}
+
+// override returns the options overridden by the non-zero fields of the 'o'
+func (opts JSONOptions) override(o JSONOptions) JSONOptions {
+	if o.DisableHTMLEscape {
+		opts.DisableHTMLEscape = true
+	}
+	if o.Indent != "" {
+		opts.Indent, opts.Compact = o.Indent, false
+	}
+	if o.Compact {
+		opts.Indent, opts.Compact = "", true
+	}
+	if o.ErrorDetails {
+		opts.ErrorDetails = true
+	}
+	return opts
+}
+
+// jsonEngine a codec of the UseJSONCodec and its options
+type jsonEngine struct {
+	codec   JSONCodec
+	options JSONOptions
+}
+
+// UseJSONCodec sets the JSON engine of the default iris instance, see the Framework's UseJSONCodec
+func UseJSONCodec(codec JSONCodec, options ...JSONOptions) MuxAPI {
+	return Default.UseJSONCodec(codec, options...)
+}
+
+// UseJSONCodec sets the JSON engine of the ctx.JSON and the ctx.ReadJSON of all the routes,
+// the ctx.JSON encodes the values directly to the response's buffer, followed by a newline, like the json.Encoder.
+// A party's UseJSONCodec overrides it for the party's routes.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.UseJSONCodec(jsoncodec.Jsoniter(jsoniter.ConfigFastest))
+func (s *Framework) UseJSONCodec(codec JSONCodec, options ...JSONOptions) MuxAPI {
+	s.jsonEngine = newJSONEngine(codec, options)
+	return s
+}
+
+// UseJSONCodec sets the JSON engine of the ctx.JSON and the ctx.ReadJSON of this Party and its children only
+// returns this Party, to continue as normal
+func (api *muxAPI) UseJSONCodec(codec JSONCodec, options ...JSONOptions) MuxAPI {
+	e := newJSONEngine(codec, options)
+	api.UseFunc(func(ctx *Context) {
+		ctx.Set(jsonCodecContextKey, e)
+		ctx.Next()
+	})
+	return api
+}
+
+func newJSONEngine(codec JSONCodec, options []JSONOptions) *jsonEngine {
+	if codec == nil {
+		codec = StdJSONCodec
+	}
+	e := &jsonEngine{codec: codec}
+	if len(options) > 0 {
+		e.options = options[0]
+	}
+	return e
+}
+
+// jsonEngine returns the json engine of the request's party or of the framework, nil if there is not any
+func (ctx *Context) jsonEngine() *jsonEngine {
+	if e, ok := ctx.Get(jsonCodecContextKey).(*jsonEngine); ok {
+		return e
+	}
+	return ctx.framework.jsonEngine
+}
+
+// jsonOptions returns the options of a ctx.JSON, the Config.JSON overridden by the ones of the json engine and of the call,
+// with the defaults of the development
+func (ctx *Context) jsonOptions(e *jsonEngine, options []JSONOptions) JSONOptions {
+	opts := ctx.framework.Config.JSON
+	if e != nil {
+		opts = opts.override(e.options)
+	}
+	for _, o := range options {
+		opts = opts.override(o)
+	}
+	if ctx.framework.Config.IsDevelopment {
+		if opts.Indent == "" && !opts.Compact {
+			opts.Indent = "  "
+		}
+		opts.ErrorDetails = true
+	}
+	return opts
+}
+
+// writeJSON encodes the 'v' with the json engine to the response, gzipped if the Config.Gzip is true and the client accepts it
+func (ctx *Context) writeJSON(e *jsonEngine, status int, v interface{}, options ...JSONOptions) error {
+	if e == nil {
+		e = &jsonEngine{codec: StdJSONCodec}
+	}
+	opts := ctx.jsonOptions(e, options)
+	ctx.SetContentType(contentJSON + "; charset=" + ctx.framework.Config.Charset)
+
+	var w io.Writer = ctx.ResponseWriter
+	if ctx.framework.Config.Gzip && ctx.clientAllowsGzip() {
+		ctx.ResponseWriter.Header().Add(varyHeader, acceptEncodingHeader)
+		ctx.SetHeader(contentEncodingHeader, "gzip")
+		gzipWriter := fs.AcquireGzipWriter(ctx.ResponseWriter)
+		defer fs.ReleaseGzipWriter(gzipWriter)
+		w = gzipWriter
+	}
+
+	enc := e.codec.NewEncoder(w)
+	enc.SetEscapeHTML(!opts.DisableHTMLEscape)
+	if opts.Indent != "" {
+		enc.SetIndent("", opts.Indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		ctx.ResponseWriter.ResetBody()
+		ctx.ResponseWriter.Header().Del(contentEncodingHeader)
+		if opts.ErrorDetails {
+			ctx.SetStatusCode(StatusInternalServerError)
+			json.NewEncoder(ctx.ResponseWriter).Encode(map[string]string{"error": err.Error()})
+		}
+		return err
+	}
+	ctx.SetStatusCode(status)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Package jsoncodec adapts the jsoniter and the sonic JSON engines to the iris.JSONCodec
+//
+// Usage:
+// app := iris.New()
+// app.UseJSONCodec(jsoncodec.Jsoniter(jsoniter.ConfigCompatibleWithStandardLibrary))
+// or for a party:
+// api := app.Party("/api").UseJSONCodec(jsoncodec.Sonic(sonic.ConfigDefault), iris.JSONOptions{DisableHTMLEscape: true})
+package jsoncodec
+
+import (
+	"io"
+
+	"github.com/bytedance/sonic"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kataras/iris"
+)
+
+// Jsoniter returns a JSONCodec of the jsoniter 'api', i.e the jsoniter.ConfigFastest
+func Jsoniter(api jsoniter.API) iris.JSONCodec {
+	return jsoniterCodec{api}
+}
+
+type jsoniterCodec struct {
+	api jsoniter.API
+}
+
+func (c jsoniterCodec) NewEncoder(w io.Writer) iris.JSONEncoder {
+	return c.api.NewEncoder(w)
+}
+
+func (c jsoniterCodec) Unmarshal(data []byte, v interface{}) error {
+	return c.api.Unmarshal(data, v)
+}
+
+// Sonic returns a JSONCodec of the sonic 'api', i.e the sonic.ConfigDefault
+func Sonic(api sonic.API) iris.JSONCodec {
+	return sonicCodec{api}
+}
+
+type sonicCodec struct {
+	api sonic.API
+}
+
+func (c sonicCodec) NewEncoder(w io.Writer) iris.JSONEncoder {
+	return c.api.NewEncoder(w)
+}
+
+func (c sonicCodec) Unmarshal(data []byte, v interface{}) error {
+	return c.api.Unmarshal(data, v)
+}
@@ -0,0 +1,103 @@
+// Package logadapter adapts the log/slog, zap and logrus loggers to the iris.StructuredLogger
+//
+// Usage:
+// app := iris.New()
+// app.SetLogger(logadapter.Slog(slog.Default()))
+package logadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kataras/iris"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+// Slog returns a StructuredLogger which logs to the 'logger', the fields are its attributes
+func Slog(logger *slog.Logger) iris.StructuredLogger {
+	return slogLogger{logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Log(level iris.LogLevel, msg string, fields ...interface{}) {
+	l.logger.Log(context.Background(), slog.Level(level), msg, fields...)
+}
+
+// Zap returns a StructuredLogger which logs to the 'logger', the fields are its zap.Any fields
+func Zap(logger *zap.Logger) iris.StructuredLogger {
+	return zapLogger{logger.WithOptions(zap.AddCallerSkip(2))}
+}
+
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+func (l zapLogger) Log(level iris.LogLevel, msg string, fields ...interface{}) {
+	zapFields := make([]zap.Field, 0, (len(fields)+1)/2)
+	eachField(fields, func(key string, value interface{}) {
+		zapFields = append(zapFields, zap.Any(key, value))
+	})
+
+	switch {
+	case level < iris.LogLevelInfo:
+		l.logger.Debug(msg, zapFields...)
+	case level < iris.LogLevelWarn:
+		l.logger.Info(msg, zapFields...)
+	case level < iris.LogLevelError:
+		l.logger.Warn(msg, zapFields...)
+	default:
+		l.logger.Error(msg, zapFields...)
+	}
+}
+
+// Logrus returns a StructuredLogger which logs to the 'logger', i.e a *logrus.Logger or a *logrus.Entry,
+// the fields are its logrus.Fields
+func Logrus(logger logrus.FieldLogger) iris.StructuredLogger {
+	return logrusLogger{logger}
+}
+
+type logrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+func (l logrusLogger) Log(level iris.LogLevel, msg string, fields ...interface{}) {
+	entry := l.logger
+	if len(fields) > 0 {
+		logrusFields := make(logrus.Fields, (len(fields)+1)/2)
+		eachField(fields, func(key string, value interface{}) {
+			logrusFields[key] = value
+		})
+		entry = entry.WithFields(logrusFields)
+	}
+
+	switch {
+	case level < iris.LogLevelInfo:
+		entry.Debug(msg)
+	case level < iris.LogLevelWarn:
+		entry.Info(msg)
+	case level < iris.LogLevelError:
+		entry.Warn(msg)
+	default:
+		entry.Error(msg)
+	}
+}
+
+// eachField calls the 'fn' for each key-value pair of the 'fields', a key without value is passed as "!BADKEY", like the log/slog does
+func eachField(fields []interface{}, fn func(key string, value interface{})) {
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 == len(fields) {
+			fn("!BADKEY", fields[i])
+			return
+		}
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
+		}
+		fn(key, fields[i+1])
+	}
+}
@@ -0,0 +1,138 @@
+package iris
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// LogLevel the severity of a framework's log entry, the values are the same as the log/slog's levels
+type LogLevel int
+
+const (
+	// LogLevelDebug the verbose entries, i.e the registered routes
+	LogLevelDebug LogLevel = -4
+	// LogLevelInfo the lifecycle entries, i.e the server is shut down
+	LogLevelInfo LogLevel = 0
+	// LogLevelWarn the recoverable errors, i.e a websocket upgrade failed
+	LogLevelWarn LogLevel = 4
+	// LogLevelError the errors of the server, the handlers' panics and the invalid routes
+	LogLevelError LogLevel = 8
+)
+
+// String returns the name of the level, i.e "INFO"
+func (l LogLevel) String() string {
+	switch {
+	case l < LogLevelInfo:
+		return "DEBUG"
+	case l < LogLevelWarn:
+		return "INFO"
+	case l < LogLevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// StructuredLogger is the logger which the framework uses internally, for the router's build, the panics and the server's errors.
+// The 'fields' are key-value pairs, i.e "addr", ":8080", "err", err.
+//
+// The default one writes to the Framework's Logger, the iris/logadapter package adapts the log/slog, zap and logrus loggers
+type StructuredLogger interface {
+	Log(level LogLevel, msg string, fields ...interface{})
+}
+
+// NewStdLogger returns a StructuredLogger which writes the entries of at least the 'min' level to the 'logger',
+// as "LEVEL msg key=value key=value"
+func NewStdLogger(logger *log.Logger, min LogLevel) StructuredLogger {
+	return &stdLogger{logger: logger, min: min}
+}
+
+type stdLogger struct {
+	logger *log.Logger
+	min    LogLevel
+}
+
+func (l *stdLogger) Log(level LogLevel, msg string, fields ...interface{}) {
+	if level < l.min {
+		return
+	}
+	l.logger.Print(formatLogEntry(level, msg, fields))
+}
+
+// formatLogEntry formats an entry as "LEVEL msg key=value key=value", a key without value is printed as "!BADKEY"
+func formatLogEntry(level LogLevel, msg string, fields []interface{}) string {
+	var b bytes.Buffer
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i < len(fields); i += 2 {
+		b.WriteByte(' ')
+		if i+1 == len(fields) {
+			b.WriteString("!BADKEY=")
+			b.WriteString(formatLogValue(fields[i]))
+			break
+		}
+		b.WriteString(fmt.Sprint(fields[i]))
+		b.WriteByte('=')
+		b.WriteString(formatLogValue(fields[i+1]))
+	}
+	return b.String()
+}
+
+// formatLogValue quotes the values which contain spaces, so the entries can be parsed
+func formatLogValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || bytes.ContainsAny([]byte(s), " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// SetLogger sets the structured logger of the default iris instance, see the Framework's SetLogger
+func SetLogger(logger StructuredLogger) {
+	Default.SetLogger(logger)
+}
+
+// SetLogger sets the logger which the framework uses internally, for the router's build, the panics and the server's errors,
+// instead of the default one which writes the entries of at least the Config.LogLevel to the Logger.
+//
+// Usage:
+// app.SetLogger(logadapter.Slog(slog.Default()))
+func (s *Framework) SetLogger(logger StructuredLogger) {
+	s.structuredLogger = logger
+}
+
+// log logs an entry to the structured logger, or to the Logger if not any
+func (s *Framework) log(level LogLevel, msg string, fields ...interface{}) {
+	if s.structuredLogger != nil {
+		s.structuredLogger.Log(level, msg, fields...)
+		return
+	}
+	if level < s.Config.LogLevel {
+		return
+	}
+	s.Logger.Print(formatLogEntry(level, msg, fields))
+}
+
+// logPanic logs the 'v', an error or a recovered value, as an error entry and panics with it
+func (s *Framework) logPanic(v interface{}) {
+	s.log(LogLevelError, fmt.Sprint(v))
+	panic(v)
+}
+
+// serverErrorLog returns the http.Server's ErrorLog, which logs the errors of the connections and the handlers' panics
+// as error entries of the structured logger
+func (s *Framework) serverErrorLog() *log.Logger {
+	return log.New(serverErrorWriter{s}, "", 0)
+}
+
+type serverErrorWriter struct {
+	s *Framework
+}
+
+func (w serverErrorWriter) Write(p []byte) (int, error) {
+	w.s.log(LogLevelError, string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
@@ -0,0 +1,214 @@
+package iris
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLoginMaxAttempts the default number of the failed attempts before a lockout
+	DefaultLoginMaxAttempts = 5
+	// DefaultLoginMaxIdentifierAttempts the default number of the failed attempts of an identifier, from all the clients, before its lockout
+	DefaultLoginMaxIdentifierAttempts = 20
+	// DefaultLoginLockout the default duration of the first lockout, each next one is doubled
+	DefaultLoginLockout = time.Minute
+	// DefaultLoginMaxLockout the default limit of the lockouts' duration
+	DefaultLoginMaxLockout = time.Hour
+	// DefaultLoginWindow the default time after the last failure which the attempts and the lockouts are forgotten
+	DefaultLoginWindow = 24 * time.Hour
+)
+
+// LoginLimiterOptions the options of the NewLoginLimiter
+type LoginLimiterOptions struct {
+	// MaxAttempts the number of the failed attempts of a key before it's locked
+	// Defaults to the DefaultLoginMaxAttempts, 5
+	MaxAttempts int
+	// MaxIdentifierAttempts the number of the failed attempts of an identifier, i.e a username, from all the clients,
+	// before it's locked for all of them, the key of the LoginIdentifierKey, so the attackers can't rotate their ips
+	// Defaults to the DefaultLoginMaxIdentifierAttempts, 20
+	MaxIdentifierAttempts int
+	// Lockout the duration of the first lockout of a key, each next one is doubled, up to the MaxLockout
+	// Defaults to the DefaultLoginLockout, 1 minute
+	Lockout time.Duration
+	// MaxLockout the limit of the lockouts' duration
+	// Defaults to the DefaultLoginMaxLockout, 1 hour
+	MaxLockout time.Duration
+	// Window the time after the last failure of a key which its attempts and its lockouts are forgotten
+	// Defaults to the DefaultLoginWindow, 24 hours
+	Window time.Duration
+	// Clock tells the time of the failures and the lockouts, i.e a FakeClock in the tests
+	// Defaults to the SystemClock
+	Clock Clock
+}
+
+// loginAttempts the failures and the lockouts of a key
+type loginAttempts struct {
+	failures    int
+	lockouts    uint
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginLimiter tracks the failed login attempts of the keys, i.e the username and the ip of the client, see the LoginKey,
+// and locks a key out, for an exponential duration, after too many failures.
+// It's used by its Handler middleware or directly, by its Fail, Allowed and Reset.
+// It's safe for concurrent use, its state is kept in memory.
+type LoginLimiter struct {
+	options LoginLimiterOptions
+	mu      sync.Mutex
+	keys    map[string]*loginAttempts
+	// lastCleanup the time of the last removal of the forgotten keys
+	lastCleanup time.Time
+}
+
+// NewLoginLimiter returns a new LoginLimiter
+//
+// Usage:
+// limiter := iris.NewLoginLimiter(iris.LoginLimiterOptions{MaxAttempts: 3})
+// app.Post("/login", limiter.Handler(func(ctx *iris.Context) string { return ctx.FormValue("username") }), login)
+func NewLoginLimiter(options ...LoginLimiterOptions) *LoginLimiter {
+	var o LoginLimiterOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultLoginMaxAttempts
+	}
+	if o.MaxIdentifierAttempts <= 0 {
+		o.MaxIdentifierAttempts = DefaultLoginMaxIdentifierAttempts
+	}
+	if o.Lockout <= 0 {
+		o.Lockout = DefaultLoginLockout
+	}
+	if o.MaxLockout <= 0 {
+		o.MaxLockout = DefaultLoginMaxLockout
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultLoginWindow
+	}
+	if o.Clock == nil {
+		o.Clock = SystemClock
+	}
+	return &LoginLimiter{options: o, keys: make(map[string]*loginAttempts), lastCleanup: o.Clock.Now()}
+}
+
+// loginIdentifierSuffix the suffix of the LoginIdentifierKey, it's not an ip
+const loginIdentifierSuffix = "|*"
+
+// LoginKey returns the key of the 'identifier', i.e the username, and the ip of the client's connection,
+// so an attacker can't lock the account out for the rest of the clients.
+// The X-Real-Ip and the X-Forwarded-For headers are not used, any client can rotate them
+func LoginKey(ctx *Context, identifier string) string {
+	return identifier + "|" + ctx.connIP()
+}
+
+// LoginIdentifierKey returns the key of the 'identifier' for all the clients,
+// it's locked after the MaxIdentifierAttempts failures instead of the MaxAttempts
+func LoginIdentifierKey(identifier string) string {
+	return identifier + loginIdentifierSuffix
+}
+
+// Fail records a failed attempt of the 'key', it returns the duration of the lockout if the key is locked now, otherwise zero
+func (l *LoginLimiter) Fail(key string) time.Duration {
+	maxAttempts := l.options.MaxAttempts
+	if strings.HasSuffix(key, loginIdentifierSuffix) {
+		maxAttempts = l.options.MaxIdentifierAttempts
+	}
+	now := l.options.Clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cleanup(now)
+
+	a, ok := l.keys[key]
+	if !ok || now.Sub(a.lastFailure) > l.options.Window {
+		a = &loginAttempts{}
+		l.keys[key] = a
+	}
+	a.lastFailure = now
+	a.failures++
+	if a.failures < maxAttempts {
+		return 0
+	}
+
+	lockout := l.options.MaxLockout
+	if a.lockouts < 32 {
+		if d := l.options.Lockout << a.lockouts; d > 0 && d < lockout {
+			lockout = d
+		}
+	}
+	a.failures = 0
+	a.lockouts++
+	a.lockedUntil = now.Add(lockout)
+	return lockout
+}
+
+// Allowed returns true if the 'key' is not locked out
+func (l *LoginLimiter) Allowed(key string) bool {
+	return l.RetryAfter(key) == 0
+}
+
+// RetryAfter returns the remaining duration of the lockout of the 'key', zero if it's not locked out
+func (l *LoginLimiter) RetryAfter(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	a, ok := l.keys[key]
+	if !ok {
+		return 0
+	}
+	if remaining := a.lockedUntil.Sub(l.options.Clock.Now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Reset forgets the attempts and the lockouts of the 'key', i.e after a successful login
+func (l *LoginLimiter) Reset(key string) {
+	l.mu.Lock()
+	delete(l.keys, key)
+	l.mu.Unlock()
+}
+
+// cleanup removes the keys which are forgotten, once per Window
+func (l *LoginLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.options.Window {
+		return
+	}
+	for key, a := range l.keys {
+		if now.Sub(a.lastFailure) > l.options.Window && now.After(a.lockedUntil) {
+			delete(l.keys, key)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// Handler returns a middleware which rejects the requests of the locked out keys with the 429 status code and the Retry-After header,
+// the keys are the LoginKey and the LoginIdentifierKey of the 'identifier' of the request, i.e its username form value.
+// The next handlers' responses are recorded: the 401 and the 403 status codes are failed attempts,
+// the successful ones reset the keys, the handlers can call the Fail and the Reset themselves too.
+func (l *LoginLimiter) Handler(identifier func(ctx *Context) string) HandlerFunc {
+	return func(ctx *Context) {
+		id := identifier(ctx)
+		key, idKey := LoginKey(ctx, id), LoginIdentifierKey(id)
+		retryAfter := l.RetryAfter(key)
+		if idRetryAfter := l.RetryAfter(idKey); idRetryAfter > retryAfter {
+			retryAfter = idRetryAfter
+		}
+		if retryAfter > 0 {
+			ctx.SetHeader("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			ctx.EmitError(StatusTooManyRequests)
+			return
+		}
+		ctx.Next()
+
+		switch status := ctx.ResponseWriter.StatusCode(); {
+		case status == StatusUnauthorized || status == StatusForbidden:
+			l.Fail(key)
+			l.Fail(idKey)
+		case status < 400:
+			l.Reset(key)
+			l.Reset(idKey)
+		}
+	}
+}
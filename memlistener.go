@@ -0,0 +1,112 @@
+package iris
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// InMemoryListener is a net.Listener which speaks real HTTP over in-memory pipes,
+// its connections are made by its Dial, so the integration tests run without binding tcp ports.
+//
+// Usage:
+// ln := iris.NewInMemoryListener()
+// go app.Serve(ln)
+// resp, err := ln.Client().Get("http://localhost/")
+type InMemoryListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+var _ net.Listener = &InMemoryListener{}
+
+// NewInMemoryListener returns a new, open, InMemoryListener
+func NewInMemoryListener() *InMemoryListener {
+	return &InMemoryListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+// Accept waits for and returns the server's end of the next Dial's pipe
+func (ln *InMemoryListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-ln.conns:
+		return c, nil
+	case <-ln.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener, the blocked Accept and Dial calls return net.ErrClosed
+func (ln *InMemoryListener) Close() error {
+	ln.once.Do(func() { close(ln.closed) })
+	return nil
+}
+
+// Addr returns the listener's address, its network and string are the "memory"
+func (ln *InMemoryListener) Addr() net.Addr {
+	return inMemoryAddr{}
+}
+
+// Dial connects to the listener and returns the client's end of the pipe,
+// the 'network' and 'addr' are ignored, so it can be used as the http.Transport's DialContext
+func (ln *InMemoryListener) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case ln.conns <- server:
+		return client, nil
+	case <-ln.closed:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Client returns a http client whose connections are made to the listener, the host of the request urls is ignored
+func (ln *InMemoryListener) Client() *http.Client {
+	return &http.Client{Transport: &http.Transport{DialContext: ln.Dial}}
+}
+
+type inMemoryAddr struct{}
+
+func (inMemoryAddr) Network() string { return "memory" }
+func (inMemoryAddr) String() string  { return "memory" }
+
+// TestClient starts the default iris instance on an in-memory listener, see the Framework's TestClient
+func TestClient() *http.Client {
+	return Default.TestClient()
+}
+
+// TestClient returns a http client of the application for the integration tests,
+// if the server is not already started it's started on a new InMemoryListener, without blocking.
+// The host of the request urls is ignored, i.e client.Get("http://localhost/users/42"),
+// the server is stopped by the Shutdown.
+func (s *Framework) TestClient() *http.Client {
+	if !s.IsRunning() {
+		ln := NewInMemoryListener()
+		if err := s.serve(ln); err != nil {
+			ln.Close()
+			s.logPanic(err)
+		}
+	}
+
+	ln := s.ln
+	if limited, ok := ln.(*limitListener); ok {
+		ln = limited.Listener
+	}
+	client := &http.Client{}
+	if memory, ok := ln.(*InMemoryListener); ok {
+		client = memory.Client()
+	} else {
+		addr := ln.Addr()
+		dialer := &net.Dialer{}
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, addr.Network(), addr.String())
+			},
+		}
+	}
+	// the trace context of the tests' requests is propagated to the server, if the tracing is enabled
+	client.Transport = s.tracingTransport(client.Transport)
+	return client
+}
@@ -0,0 +1,399 @@
+package iris
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// contentPrometheus the content type of the Prometheus text exposition format
+const contentPrometheus = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultMetricsBuckets the default buckets of the histograms, in seconds, they're tailored to measure the response times
+var DefaultMetricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	errMetricsDuplicate = errors.New("Metrics: %q is already registered")
+	errMetricsLabels    = errors.New("Metrics: %q expects %d label values but got %d")
+)
+
+const (
+	metricCounter   = "counter"
+	metricGauge     = "gauge"
+	metricHistogram = "histogram"
+)
+
+// MetricsRegistry keeps the counters, the gauges and the histograms of an application
+// and writes them in the Prometheus text format.
+//
+// Usage:
+// registry := app.EnableMetrics("/metrics")
+// signups := registry.Counter("myapp_signups_total", "The number of the signups.", "plan")
+// signups.Inc("free")
+type MetricsRegistry struct {
+	mu      sync.Mutex
+	metrics []*metricVec
+	names   map[string]struct{}
+}
+
+// NewMetricsRegistry returns a new, empty, MetricsRegistry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{names: make(map[string]struct{})}
+}
+
+func (r *MetricsRegistry) register(name, help, kind string, buckets []float64, labels []string) *metricVec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.names[name]; ok {
+		panic(errMetricsDuplicate.Format(name))
+	}
+	r.names[name] = struct{}{}
+	m := &metricVec{name: name, help: help, kind: kind, buckets: buckets, labels: labels, series: make(map[string]*metricSeries)}
+	r.metrics = append(r.metrics, m)
+	return m
+}
+
+// Counter registers and returns a counter, a value which only goes up, i.e the number of the served requests.
+// It panics if the 'name' is already registered
+func (r *MetricsRegistry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(name, help, metricCounter, nil, labels)}
+}
+
+// Gauge registers and returns a gauge, a value which goes up and down, i.e the number of the open connections.
+// It panics if the 'name' is already registered
+func (r *MetricsRegistry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(name, help, metricGauge, nil, labels)}
+}
+
+// Histogram registers and returns a histogram, which counts the observations in the 'buckets', i.e the response times.
+// Nil 'buckets' means the DefaultMetricsBuckets. It panics if the 'name' is already registered
+func (r *MetricsRegistry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultMetricsBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{r.register(name, help, metricHistogram, buckets, labels)}
+}
+
+// WriteTo writes the metrics to the 'w' in the Prometheus text exposition format
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := append([]*metricVec(nil), r.metrics...)
+	r.mu.Unlock()
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, m := range metrics {
+		m.writeTo(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler returns a handler which serves the metrics in the Prometheus text exposition format
+func (r *MetricsRegistry) Handler() HandlerFunc {
+	return func(ctx *Context) {
+		ctx.SetContentType(contentPrometheus)
+		r.WriteTo(ctx.ResponseWriter)
+	}
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// Counter is a metric whose value only goes up, see the MetricsRegistry's Counter
+type Counter struct{ vec *metricVec }
+
+// Inc increments the counter of the 'labelValues' by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the 'v', which should not be negative, to the counter of the 'labelValues'
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.vec.update(labelValues, func(s *metricSeries) { s.value += v })
+}
+
+// Gauge is a metric whose value goes up and down, see the MetricsRegistry's Gauge
+type Gauge struct{ vec *metricVec }
+
+// Set sets the gauge of the 'labelValues' to the 'v'
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.vec.update(labelValues, func(s *metricSeries) { s.value = v })
+}
+
+// Add adds the 'v', which can be negative, to the gauge of the 'labelValues'
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.vec.update(labelValues, func(s *metricSeries) { s.value += v })
+}
+
+// Inc increments the gauge of the 'labelValues' by one
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge of the 'labelValues' by one
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Histogram is a metric which counts the observations in buckets, see the MetricsRegistry's Histogram
+type Histogram struct{ vec *metricVec }
+
+// Observe adds the 'v' to the histogram of the 'labelValues'
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	buckets := h.vec.buckets
+	h.vec.update(labelValues, func(s *metricSeries) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(buckets))
+		}
+		for i, upper := range buckets {
+			if v <= upper {
+				s.counts[i]++
+			}
+		}
+		s.count++
+		s.value += v
+	})
+}
+
+// ObserveDuration adds the 'd', in seconds, to the histogram of the 'labelValues'
+func (h *Histogram) ObserveDuration(d time.Duration, labelValues ...string) {
+	h.Observe(d.Seconds(), labelValues...)
+}
+
+type metricVec struct {
+	name    string
+	help    string
+	kind    string
+	buckets []float64
+	labels  []string
+
+	mu     sync.Mutex
+	series map[string]*metricSeries
+}
+
+// metricSeries the value of a metric for a set of label values,
+// the value is the sum of the observations of a histogram
+type metricSeries struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+func (m *metricVec) update(labelValues []string, fn func(*metricSeries)) {
+	if len(labelValues) != len(m.labels) {
+		panic(errMetricsLabels.Format(m.name, len(m.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	m.mu.Lock()
+	s, ok := m.series[key]
+	if !ok {
+		s = &metricSeries{labelValues: append([]string(nil), labelValues...)}
+		m.series[key] = s
+	}
+	fn(s)
+	m.mu.Unlock()
+}
+
+func (m *metricVec) writeTo(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.help != "" {
+		w.WriteString("# HELP " + m.name + " " + escapeMetricHelp(m.help) + "\n")
+	}
+	w.WriteString("# TYPE " + m.name + " " + m.kind + "\n")
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := m.series[key]
+		labels := metricLabels(m.labels, s.labelValues)
+		if m.kind != metricHistogram {
+			writeMetricSample(w, m.name, labels, s.value)
+			continue
+		}
+		for i, upper := range m.buckets {
+			writeMetricSample(w, m.name+"_bucket", appendMetricLabel(labels, "le", formatMetricValue(upper)), float64(s.counts[i]))
+		}
+		writeMetricSample(w, m.name+"_bucket", appendMetricLabel(labels, "le", "+Inf"), float64(s.count))
+		writeMetricSample(w, m.name+"_sum", labels, s.value)
+		writeMetricSample(w, m.name+"_count", labels, float64(s.count))
+	}
+}
+
+// metricLabels returns the name="value" pairs of the labels, without the braces
+func metricLabels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i := range names {
+		pairs[i] = names[i] + `="` + escapeMetricLabel(values[i]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func appendMetricLabel(labels, name, value string) string {
+	pair := name + `="` + value + `"`
+	if labels == "" {
+		return pair
+	}
+	return labels + "," + pair
+}
+
+func writeMetricSample(w *bufio.Writer, name, labels string, v float64) {
+	w.WriteString(name)
+	if labels != "" {
+		w.WriteString("{" + labels + "}")
+	}
+	w.WriteString(" " + formatMetricValue(v) + "\n")
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	metricHelpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	metricLabelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeMetricHelp(s string) string {
+	return metricHelpReplacer.Replace(s)
+}
+
+func escapeMetricLabel(s string) string {
+	return metricLabelReplacer.Replace(s)
+}
+
+// MetricsOptions the options of the EnableMetrics
+type MetricsOptions struct {
+	// Username and Password if not empty the metrics endpoint is protected by the basic authentication
+	Username string
+	Password string
+}
+
+// frameworkMetrics the built-in metrics of the router, the ResponseWriter and the transactions
+type frameworkMetrics struct {
+	registry      *MetricsRegistry
+	requests      *Counter
+	duration      *Histogram
+	responseSize  *Histogram
+	inFlight      *Gauge
+	transactions  *Counter
+	responseCache *Counter
+	blockedBots   *Counter
+}
+
+func newFrameworkMetrics(registry *MetricsRegistry) *frameworkMetrics {
+	return &frameworkMetrics{
+		registry:      registry,
+		requests:      registry.Counter("iris_http_requests_total", "The number of the served http requests.", "method", "route", "status"),
+		duration:      registry.Histogram("iris_http_request_duration_seconds", "The time to serve the http requests.", nil, "method", "route"),
+		responseSize:  registry.Histogram("iris_http_response_size_bytes", "The size of the buffered http responses.", []float64{100, 1000, 10000, 100000, 1000000, 10000000}, "method", "route"),
+		inFlight:      registry.Gauge("iris_http_requests_in_flight", "The number of the http requests which are currently served."),
+		transactions:  registry.Counter("iris_transactions_total", "The number of the completed transactions.", "result"),
+		responseCache: registry.Counter("iris_response_cache_requests_total", "The number of the cacheable requests of the ResponseCache.", "result"),
+		blockedBots:   registry.Counter("iris_blocked_bots_total", "The number of the requests which are blocked by the UseBotFilter.", "reason"),
+	}
+}
+
+// metricsUnmatchedRoute the route label of the requests which matched no route
+const metricsUnmatchedRoute = "unmatched"
+
+// startRequest marks the start of a request, before the routing
+func (m *frameworkMetrics) startRequest(ctx *Context) {
+	ctx.start = time.Now()
+	m.inFlight.Inc()
+}
+
+// observeRequest records the metrics of a served request, before its response is flushed
+func (m *frameworkMetrics) observeRequest(ctx *Context) {
+	route := ctx.framework.mux.routeOf(ctx)
+	method := ctx.Request.Method
+	status := ctx.ResponseWriter.StatusCode()
+	if status == 0 {
+		status = StatusOK
+	}
+	m.requests.Inc(method, route, strconv.Itoa(status))
+	m.duration.ObserveDuration(time.Since(ctx.start), method, route)
+	m.responseSize.Observe(float64(len(ctx.ResponseWriter.Body())), method, route)
+	m.inFlight.Dec()
+}
+
+// observeTransaction records the result of a completed transaction
+func (m *frameworkMetrics) observeTransaction(t *Transaction) {
+	result := "committed"
+	if t.hasError {
+		result = "failed"
+	}
+	m.transactions.Inc(result)
+}
+
+// EnableMetrics enables the built-in metrics of the default iris instance and serves them on the 'path', see the Framework's EnableMetrics
+func EnableMetrics(path string, options ...MetricsOptions) *MetricsRegistry {
+	return Default.EnableMetrics(path, options...)
+}
+
+// EnableMetrics enables the built-in metrics, the requests, their duration and response size per route, the in-flight requests
+// and the transactions, and serves them, with the application's ones, on the 'path' in the Prometheus text format.
+// It returns the registry of the metrics, the application's counters, gauges and histograms are registered there.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// registry := app.EnableMetrics("/metrics", iris.MetricsOptions{Username: "prometheus", Password: "secret"})
+func (s *Framework) EnableMetrics(path string, options ...MetricsOptions) *MetricsRegistry {
+	if s.metrics != nil {
+		return s.metrics.registry
+	}
+	s.metrics = newFrameworkMetrics(NewMetricsRegistry())
+
+	handler := s.metrics.registry.Handler()
+	if len(options) > 0 && (options[0].Username != "" || options[0].Password != "") {
+		handler = basicAuthHandler(options[0].Username, options[0].Password, "metrics", handler)
+	}
+	s.Get(path, handler)
+	return s.metrics.registry
+}
+
+// basicAuthHandler protects the 'h' with the basic authentication of the 'username' and 'password'
+func basicAuthHandler(username, password, realm string, h HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		u, p, ok := ctx.Request.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(u), []byte(username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(p), []byte(password)) != 1 {
+			ctx.SetHeader("WWW-Authenticate", `Basic realm="`+realm+`"`)
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+		h(ctx)
+	}
+}
@@ -0,0 +1,27 @@
+package iris
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// contentMsgpack the content type of the MessagePack data
+const contentMsgpack = "application/msgpack"
+
+// msgpackSerializer the serializer of the "application/msgpack", it's registered by default, see the RegisterSerializer
+type msgpackSerializer struct{}
+
+// Serialize marshals the 'v' to MessagePack, the 'options' are ignored
+func (msgpackSerializer) Serialize(v interface{}, options ...map[string]interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// MsgPack marshals the 'v' to MessagePack and writes it with the "application/msgpack" content type,
+// the msgpack struct tags name the fields
+func (ctx *Context) MsgPack(status int, v interface{}) error {
+	return ctx.RenderWithStatus(status, contentMsgpack, v)
+}
+
+// ReadMsgPack reads the MessagePack request's body to the 'v'
+func (ctx *Context) ReadMsgPack(v interface{}) error {
+	return ctx.UnmarshalBody(v, UnmarshalerFunc(msgpack.Unmarshal))
+}
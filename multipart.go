@@ -0,0 +1,160 @@
+package iris
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/kataras/go-errors"
+)
+
+var errMultipartClosed = errors.New("Multipart: the %s part is written after the Close")
+
+// MultipartWriter composes a multipart response, i.e a json part of metadata and a binary attachment, see the ctx.Multipart
+type MultipartWriter struct {
+	ctx     *Context
+	subtype string
+	writer  *multipart.Writer
+	// out the buffered response writer or, when streaming, the underline response writer, set by the first part
+	out     io.Writer
+	stream  bool
+	flusher http.Flusher
+	closed  bool
+}
+
+// multipartOut writes the parts to the out of the MultipartWriter, which is known after the boundary is fixed
+type multipartOut struct{ m *MultipartWriter }
+
+func (o multipartOut) Write(p []byte) (int, error) {
+	o.m.start()
+	return o.m.out.Write(p)
+}
+
+// Multipart returns a composer of a multipart response of the 'subtype', i.e "mixed" or "form-data",
+// its parts are written to the response's buffer, the Content-Type and its boundary are set by the first part.
+// Its Close should be called after the last part.
+//
+// Usage:
+// m := ctx.Multipart("mixed")
+// m.JSON("metadata", report)
+// m.File("report", "report.pdf", "application/pdf", pdf)
+// m.Close()
+func (ctx *Context) Multipart(subtype string) *MultipartWriter {
+	m := &MultipartWriter{ctx: ctx, subtype: subtype}
+	m.writer = multipart.NewWriter(multipartOut{m})
+	return m
+}
+
+// MultipartStream like the Multipart but its parts are written directly to the client,
+// which is flushed after each part, so the large attachments are not kept in memory
+func (ctx *Context) MultipartStream(subtype string) *MultipartWriter {
+	m := ctx.Multipart(subtype)
+	m.stream = true
+	return m
+}
+
+// Boundary returns the boundary of the parts
+func (m *MultipartWriter) Boundary() string {
+	return m.writer.Boundary()
+}
+
+// SetBoundary overrides the random boundary of the parts, it should be called before the first part
+func (m *MultipartWriter) SetBoundary(boundary string) error {
+	return m.writer.SetBoundary(boundary)
+}
+
+// start sets the Content-Type and the status code of the response, once, before the first part
+func (m *MultipartWriter) start() {
+	if m.out != nil {
+		return
+	}
+	w := m.ctx.ResponseWriter
+	w.Header().Set(contentType, mime.FormatMediaType("multipart/"+m.subtype, map[string]string{"boundary": m.writer.Boundary()}))
+	if w.StatusCode() == 0 {
+		m.ctx.SetStatusCode(StatusOK)
+	}
+	if !m.stream {
+		m.out = w
+		return
+	}
+	out := w.stream()
+	m.out = out
+	m.flusher, _ = out.(http.Flusher)
+}
+
+// flush flushes the client when streaming
+func (m *MultipartWriter) flush() {
+	if m.flusher != nil {
+		m.flusher.Flush()
+	}
+}
+
+// Part starts a new part with the 'header', it returns the writer of its body, which is valid until the next part
+func (m *MultipartWriter) Part(header textproto.MIMEHeader) (io.Writer, error) {
+	if m.closed {
+		return nil, errMultipartClosed.Format(header.Get(contentType))
+	}
+	// the previous part is complete
+	m.flush()
+	return m.writer.CreatePart(header)
+}
+
+// partHeader returns the header of a part, with the form-data Content-Disposition if the subtype is the form-data,
+// otherwise the attachment one if there is a filename
+func (m *MultipartWriter) partHeader(name, filename, partContentType string) textproto.MIMEHeader {
+	header := make(textproto.MIMEHeader)
+	params := make(map[string]string)
+	if filename != "" {
+		params["filename"] = filename
+	}
+	if m.subtype == "form-data" {
+		params["name"] = name
+		header.Set(contentDisposition, mime.FormatMediaType("form-data", params))
+	} else if filename != "" {
+		header.Set(contentDisposition, mime.FormatMediaType("attachment", params))
+	}
+	header.Set(contentType, partContentType)
+	return header
+}
+
+// JSON writes the 'v' as a json part, the 'name' is the form field of a form-data response
+func (m *MultipartWriter) JSON(name string, v interface{}) error {
+	pw, err := m.Part(m.partHeader(name, "", contentJSON+"; charset="+m.ctx.framework.Config.Charset))
+	if err != nil {
+		return err
+	}
+	codec := StdJSONCodec
+	if e := m.ctx.jsonEngine(); e != nil {
+		codec = e.codec
+	}
+	return codec.NewEncoder(pw).Encode(v)
+}
+
+// File writes the 'r' as an attachment part of the 'partContentType', i.e "application/pdf",
+// the 'name' is the form field of a form-data response
+func (m *MultipartWriter) File(name, filename, partContentType string, r io.Reader) error {
+	if partContentType == "" {
+		partContentType = contentBinary
+	}
+	pw, err := m.Part(m.partHeader(name, filename, partContentType))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(pw, r)
+	return err
+}
+
+// Close writes the closing boundary, no part can be written after it
+func (m *MultipartWriter) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if err := m.writer.Close(); err != nil {
+		return err
+	}
+	m.flush()
+	return nil
+}
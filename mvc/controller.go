@@ -0,0 +1,317 @@
+package mvc
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kataras/go-errors"
+	"github.com/kataras/iris"
+)
+
+var (
+	errControllerType     = errors.New("mvc: expected a pointer to a struct controller but got %T")
+	errControllerMethod   = errors.New("mvc: the %s has no %s method")
+	errControllerArgument = errors.New("mvc: the %s.%s: the %s arguments are not supported, only the strings, the numbers and the bools of the path parameters")
+	errControllerResults  = errors.New("mvc: the %s.%s: expected the results (), (error), (T) or (T, error)")
+	errControllerParams   = errors.New("mvc: the %s.%s: the path %q has %d parameters but the method has %d arguments")
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// BeforeActivation customizes the routes of a controller, its BeforeActivation(b mvc.BeforeActivation) method is called
+// by the Application's Handle before the routes of its methods are registered.
+//
+// Usage:
+// func (c *UserController) BeforeActivation(b mvc.BeforeActivation) {
+//	b.Router().UseFunc(iris.RequireScopes("users:read"))
+//	b.Handle(iris.MethodPut, "/:id/avatar", "UploadAvatar")
+// }
+type BeforeActivation interface {
+	// Router returns the party of the controller's routes, i.e to add a middleware to them, its changes don't affect the Application's party
+	Router() iris.MuxAPI
+	// Register registers the dependencies of the controller's fields, see the Application's Register
+	Register(dependencies ...interface{})
+	// Handle registers the 'method' and the 'path' to the controller's method of the 'funcName',
+	// the path parameters are passed to its arguments in their order, the method is not mapped by its name
+	Handle(method, path, funcName string, middleware ...iris.HandlerFunc)
+}
+
+// controller the methods and the fields of a struct controller
+type controller struct {
+	app  *Application
+	name string
+	typ  reflect.Type
+	// proto the controller which is passed to the Handle, it's copied on each request
+	proto reflect.Value
+	// routes the routes of the BeforeActivation's Handle, they're registered by the activate
+	routes []controllerRoute
+	// handled the methods of the routes
+	handled map[string]bool
+}
+
+// controllerRoute a route of a controller's method
+type controllerRoute struct {
+	method     string
+	path       string
+	m          reflect.Method
+	params     []string
+	middleware []iris.HandlerFunc
+}
+
+var _ BeforeActivation = &controller{}
+
+func newController(app *Application, v interface{}) *controller {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		panic(errControllerType.Format(v))
+	}
+	return &controller{
+		app:     &Application{Router: app.Router.Party(""), dependencies: append([]reflect.Value(nil), app.dependencies...)},
+		name:    val.Elem().Type().Name(),
+		typ:     val.Type(),
+		proto:   val,
+		handled: make(map[string]bool),
+	}
+}
+
+// Router returns the party of the controller's routes
+func (c *controller) Router() iris.MuxAPI {
+	return c.app.Router
+}
+
+// Register registers the dependencies of the controller's fields
+func (c *controller) Register(dependencies ...interface{}) {
+	c.app.Register(dependencies...)
+}
+
+// Handle registers the 'method' and the 'path' to the method of the 'funcName'
+func (c *controller) Handle(method, path, funcName string, middleware ...iris.HandlerFunc) {
+	m, ok := c.typ.MethodByName(funcName)
+	if !ok {
+		panic(errControllerMethod.Format(c.name, funcName))
+	}
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+		}
+	}
+	if len(params) != m.Type.NumIn()-1 {
+		panic(errControllerParams.Format(c.name, funcName, path, len(params), m.Type.NumIn()-1))
+	}
+	c.handled[funcName] = true
+	c.routes = append(c.routes, controllerRoute{method: method, path: path, m: m, params: params, middleware: middleware})
+}
+
+// activate registers the routes of the BeforeActivation's Handle and of the methods which are mapped by their names
+func (c *controller) activate() {
+	for i := 0; i < c.typ.NumMethod(); i++ {
+		m := c.typ.Method(i)
+		if c.handled[m.Name] || m.Name == "BeforeActivation" {
+			continue
+		}
+		if method, path, params, ok := parseMethodName(m.Name, m.Type.NumIn()-1); ok {
+			c.routes = append(c.routes, controllerRoute{method: method, path: path, m: m, params: params})
+		}
+	}
+
+	inject := c.injector()
+	for _, r := range c.routes {
+		c.register(r, inject)
+	}
+}
+
+// parseMethodName returns the http method, the path and the names of the path parameters of a method's name,
+// of the method with the 'numIn' arguments, false if it's not a route, i.e a helper method
+func parseMethodName(name string, numIn int) (method string, path string, params []string, ok bool) {
+	words := splitCamelCase(name)
+	if len(words) == 0 {
+		return
+	}
+	if method = strings.ToUpper(words[0]); method == "ANY" {
+		method = ""
+	} else if !isMethod(method) {
+		return
+	}
+
+	var bys int
+	for _, w := range words[1:] {
+		if w == "By" {
+			bys++
+		}
+	}
+	if (bys == 0) != (numIn == 0) || bys > numIn {
+		return
+	}
+
+	for _, w := range words[1:] {
+		if w != "By" {
+			path += "/" + strings.ToLower(w)
+			continue
+		}
+		bys--
+		// the last "By" takes the rest of the arguments
+		n := 1
+		if bys == 0 {
+			n = numIn - len(params)
+		}
+		for j := 0; j < n; j++ {
+			param := "param" + strconv.Itoa(len(params)+1)
+			params = append(params, param)
+			path += "/:" + param
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return method, path, params, true
+}
+
+// isMethod returns true if the 'method' is one of the iris.AllMethods
+func isMethod(method string) bool {
+	for _, m := range iris.AllMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCamelCase splits the 's' to its words, i.e the "GetAPIKeysBy" to the "Get", "API", "Keys" and "By"
+func splitCamelCase(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// register registers the route 'r', the arguments of its method are parsed from its path parameters
+func (c *controller) register(r controllerRoute, inject func(ctx *iris.Context) reflect.Value) {
+	m, mt := r.m, r.m.Type
+	for i := 1; i < mt.NumIn(); i++ {
+		if !isParamKind(mt.In(i).Kind()) {
+			panic(errControllerArgument.Format(c.name, m.Name, mt.In(i)))
+		}
+	}
+	switch {
+	case mt.NumOut() > 2,
+		mt.NumOut() == 2 && mt.Out(1) != errorType,
+		mt.NumOut() == 2 && mt.Out(0) == errorType:
+		panic(errControllerResults.Format(c.name, m.Name))
+	}
+
+	handler := func(ctx *iris.Context) {
+		args := make([]reflect.Value, mt.NumIn())
+		args[0] = inject(ctx)
+		for i, param := range r.params {
+			arg := reflect.New(mt.In(i + 1)).Elem()
+			if err := parseParam(arg, ctx.Param(param)); err != nil {
+				ctx.EmitError(iris.StatusNotFound)
+				return
+			}
+			args[i+1] = arg
+		}
+		ctx.Dispatch(c.name+"."+m.Name, m.Func.Call(args))
+	}
+	handlers := append(append([]iris.HandlerFunc(nil), r.middleware...), handler)
+	c.app.Router.HandleFunc(r.method, r.path, handlers...)
+}
+
+// injector returns the func which copies the controller and sets its fields of the request
+func (c *controller) injector() func(ctx *iris.Context) reflect.Value {
+	typ := c.typ.Elem()
+	proto := reflect.New(typ).Elem()
+	proto.Set(c.proto.Elem())
+
+	var (
+		contextFields []int
+		dynamic       = make(map[int]reflect.Value)
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || !proto.Field(i).IsZero() {
+			continue
+		}
+		if f.Type == contextType {
+			contextFields = append(contextFields, i)
+			continue
+		}
+		for _, d := range c.app.dependencies {
+			if d.Type().AssignableTo(f.Type) {
+				proto.Field(i).Set(d)
+				break
+			}
+			if dt := d.Type(); dt.Kind() == reflect.Func && dt.NumIn() == 1 && dt.In(0) == contextType &&
+				dt.NumOut() == 1 && dt.Out(0).AssignableTo(f.Type) {
+				dynamic[i] = d
+				break
+			}
+		}
+	}
+
+	return func(ctx *iris.Context) reflect.Value {
+		ptr := reflect.New(typ)
+		val := ptr.Elem()
+		val.Set(proto)
+		ctxValue := reflect.ValueOf(ctx)
+		for _, i := range contextFields {
+			val.Field(i).Set(ctxValue)
+		}
+		for i, fn := range dynamic {
+			val.Field(i).Set(fn.Call([]reflect.Value{ctxValue})[0])
+		}
+		return ptr
+	}
+}
+
+// isParamKind returns true if a path parameter can be parsed to the 'k'
+func isParamKind(k reflect.Kind) bool {
+	return k == reflect.String || k == reflect.Bool || (k >= reflect.Int && k <= reflect.Float64 && k != reflect.Uintptr)
+}
+
+// parseParam parses the path parameter 's' to the 'v' by its kind
+func parseParam(v reflect.Value, s string) error {
+	switch k := v.Kind(); {
+	case k == reflect.String:
+		v.SetString(s)
+	case k == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case k >= reflect.Int && k <= reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case k >= reflect.Uint && k <= reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	}
+	return nil
+}
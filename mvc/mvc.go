@@ -0,0 +1,80 @@
+// Package mvc registers the methods of the struct controllers as the routes of an iris party.
+//
+// The methods are mapped to routes by their names, the first word is the http method and the rest are the path segments,
+// the "By" words are the path parameters of the method's arguments:
+// Get() is the GET /, GetBy(id int64) is the GET /:param1, PostLogin() is the POST /login,
+// GetFollowersBy(id int64) is the GET /followers/:param1 and GetByFollowers(id int64) is the GET /:param1/followers.
+// The "Any" first word registers the method for all the http methods.
+// The results of the methods are rendered like the results of the iris.Inject's handlers, see the ctx.Dispatch.
+//
+// Usage:
+// type UserController struct {
+//	Ctx   *iris.Context
+//	Users *UserService
+// }
+//
+// func (c *UserController) GetBy(id int64) (*User, error) {
+//	return c.Users.Find(id)
+// }
+//
+// func (c *UserController) PostLogin() error {
+//	return c.Users.Login(c.Ctx.FormValue("username"), c.Ctx.FormValue("password"))
+// }
+//
+// mvc.New(app.Party("/users")).Register(users).Handle(new(UserController))
+package mvc
+
+import (
+	"reflect"
+
+	"github.com/kataras/iris"
+)
+
+var contextType = reflect.TypeOf((*iris.Context)(nil))
+
+// Application registers the controllers of a party, with its dependencies
+type Application struct {
+	// Router the party of the controllers' routes
+	Router       iris.MuxAPI
+	dependencies []reflect.Value
+}
+
+// New returns a new Application of the 'party'
+func New(party iris.MuxAPI) *Application {
+	return &Application{Router: party}
+}
+
+// Register registers the 'dependencies' of the fields of the next controllers, by their types.
+// A dependency is a value, i.e a service, which is set once to the fields of its type,
+// or a func(*iris.Context) T, which sets the fields of the T on each request, i.e the current user.
+// The fields which are set already, by the controller which is passed to the Handle, are kept.
+func (app *Application) Register(dependencies ...interface{}) *Application {
+	for _, d := range dependencies {
+		app.dependencies = append(app.dependencies, reflect.ValueOf(d))
+	}
+	return app
+}
+
+// Party returns a new Application of the child party of the 'relativePath', with the dependencies of this one
+func (app *Application) Party(relativePath string, middleware ...iris.HandlerFunc) *Application {
+	return &Application{
+		Router:       app.Router.Party(relativePath, middleware...),
+		dependencies: append([]reflect.Value(nil), app.dependencies...),
+	}
+}
+
+// Handle registers the routes of the methods of the 'controller', a pointer to a struct, see the package's doc.
+// A copy of the controller is created on each request, its *iris.Context fields are set to the request's context
+// and the rest of its exported fields to the registered dependencies of their types.
+// Its BeforeActivation, if any, is called before the routes are registered.
+// It panics if the controller is not a pointer to a struct or a method's arguments or results are not supported.
+func (app *Application) Handle(controller interface{}) *Application {
+	c := newController(app, controller)
+	if b, ok := controller.(interface {
+		BeforeActivation(BeforeActivation)
+	}); ok {
+		b.BeforeActivation(c)
+	}
+	c.activate()
+	return app
+}
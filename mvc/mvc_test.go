@@ -0,0 +1,90 @@
+package mvc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/httptest"
+	"github.com/kataras/iris/mvc"
+)
+
+type user struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type userService struct {
+	users map[int64]*user
+}
+
+type userController struct {
+	Ctx     *iris.Context
+	Service *userService
+	Caller  string
+}
+
+func (c *userController) BeforeActivation(b mvc.BeforeActivation) {
+	b.Router().UseFunc(func(ctx *iris.Context) {
+		ctx.SetHeader("X-Controller", "users")
+		ctx.Next()
+	})
+	b.Handle(iris.MethodPut, "/:id/name", "Rename")
+	b.Handle(iris.MethodGet, "/:id/followers", "Followers")
+}
+
+func (c *userController) Get() []*user {
+	return []*user{c.Service.users[1]}
+}
+
+func (c *userController) GetBy(id int64) (*user, error) {
+	if id < 0 {
+		return nil, errors.New("negative id")
+	}
+	if id == 0 {
+		return nil, iris.TransactionErrResult{StatusCode: iris.StatusForbidden}
+	}
+	return c.Service.users[id], nil
+}
+
+func (c *userController) Followers(id int64) []*user {
+	return nil
+}
+
+func (c *userController) PostLogin() {
+	c.Ctx.WriteString("welcome " + c.Caller)
+}
+
+func (c *userController) Rename(id int64) *user {
+	u := c.Service.users[id]
+	if u != nil {
+		u.Name = c.Ctx.URLParam("name")
+	}
+	return u
+}
+
+// helper is not a route
+func (c *userController) Helper() {}
+
+func TestHandle(t *testing.T) {
+	service := &userService{users: map[int64]*user{1: {ID: 1, Name: "kataras"}}}
+	app := iris.New()
+	mvc.New(app.Party("/users")).
+		Register(service, func(ctx *iris.Context) string { return ctx.RequestHeader("X-Caller") }).
+		Handle(new(userController))
+	app.Get("/", func(ctx *iris.Context) {})
+
+	e := httptest.New(app, t)
+	e.GET("/users/").Expect().Status(iris.StatusOK).Header("X-Controller").Equal("users")
+	e.GET("/users/1").Expect().Status(iris.StatusOK).JSON().Object().ValueEqual("name", "kataras")
+	e.GET("/users/2").Expect().Status(iris.StatusNotFound)
+	e.GET("/users/abc").Expect().Status(iris.StatusNotFound)
+	e.GET("/users/-1").Expect().Status(iris.StatusInternalServerError)
+	// the results are rendered like the ones of the iris.Inject
+	e.GET("/users/0").Expect().Status(iris.StatusForbidden)
+	e.GET("/users/1/followers").Expect().Status(iris.StatusOK).JSON().Array().Empty()
+	e.PUT("/users/1/name").WithQuery("name", "makis").Expect().Status(iris.StatusOK).JSON().Object().ValueEqual("name", "makis")
+	e.POST("/users/login").WithHeader("X-Caller", "kataras").Expect().Status(iris.StatusOK).Body().Equal("welcome kataras")
+	// the controller's middleware doesn't affect the rest of the routes
+	e.GET("/").Expect().Status(iris.StatusOK).Header("X-Controller").Empty()
+}
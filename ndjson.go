@@ -0,0 +1,98 @@
+package iris
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+
+	"github.com/kataras/go-errors"
+)
+
+// contentNDJSON the content type of the newline-delimited json
+const contentNDJSON = "application/x-ndjson"
+
+// NDJSONStream streams the values of the 'ch', until it's closed, as newline-delimited json, one value per line,
+// encoded by the json engine of the UseJSONCodec, without indentation.
+// Each value is written directly to the client, which is flushed when there is no other value ready,
+// the next value is received only after the previous one is written, so a slow client slows down the producer.
+// It stops and returns the request context's error if the client goes away,
+// so the producer of the values should stop on the ctx.Request.Context().Done() too.
+//
+// Usage:
+// events := make(chan interface{})
+// go produce(ctx.Request.Context(), events) // closes the events when it's done
+// ctx.NDJSONStream(events)
+func (ctx *Context) NDJSONStream(ch <-chan interface{}) error {
+	ctx.SetContentType(contentNDJSON + "; charset=" + ctx.framework.Config.Charset)
+	if ctx.ResponseWriter.StatusCode() == 0 {
+		ctx.SetStatusCode(StatusOK)
+	}
+
+	out := ctx.ResponseWriter.stream()
+	flusher, _ := out.(http.Flusher)
+	codec, escapeHTML := StdJSONCodec, true
+	if e := ctx.jsonEngine(); e != nil {
+		codec, escapeHTML = e.codec, !e.options.DisableHTMLEscape
+	}
+	enc := codec.NewEncoder(out)
+	enc.SetEscapeHTML(escapeHTML)
+
+	done := ctx.Request.Context().Done()
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+			if flusher != nil && len(ch) == 0 {
+				flusher.Flush()
+			}
+		case <-done:
+			return ctx.Request.Context().Err()
+		}
+	}
+}
+
+// ReadNDJSON reads the request's body as newline-delimited json and calls the 'fn' with each of its lines, the empty lines are skipped.
+// The body is read line by line, the next line is read only after the 'fn' returns, so the large uploads are not kept in memory,
+// the 'raw' is valid only until the 'fn' returns.
+// It stops and returns the error of the 'fn', if any, or the request context's error if the client goes away,
+// each line is limited to the Config.MaxRequestBodySize.
+//
+// Usage:
+// err := ctx.ReadNDJSON(func(raw []byte) error {
+//	var e Event
+//	if err := json.Unmarshal(raw, &e); err != nil {
+//		return err
+//	}
+//	return store.Save(e)
+// })
+func (ctx *Context) ReadNDJSON(fn func(raw []byte) error) error {
+	if ctx.Request.Body == nil {
+		return errors.New("Empty body, please send request body!")
+	}
+	limit := ctx.framework.Config.MaxRequestBodySize
+	if limit <= 0 {
+		limit = DefaultMaxRequestBodySize
+	}
+	scanner := bufio.NewScanner(ctx.Request.Body)
+	scanner.Buffer(make([]byte, 0, 4096), int(limit))
+
+	reqCtx := ctx.Request.Context()
+	for scanner.Scan() {
+		if err := reqCtx.Err(); err != nil {
+			return err
+		}
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,85 @@
+package iris
+
+import (
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+const (
+	// DefaultNonceHeader the default header of the nonces of the RequireNonce
+	DefaultNonceHeader = "X-Nonce"
+	// DefaultNonceTTL the default time which the RequireNonce remembers a nonce
+	DefaultNonceTTL = 24 * time.Hour
+	// DefaultNonceMaxLength the default length limit of the nonces
+	DefaultNonceMaxLength = 128
+)
+
+var errNonceCache = errors.New("RequireNonce: the nonce cache failed. Trace: %s")
+
+// NonceOptions the options of the RequireNonce
+type NonceOptions struct {
+	// Header the request header of the nonce, i.e a random uuid which the client generates for each request
+	// Defaults to the DefaultNonceHeader, "X-Nonce"
+	Header string
+	// TTL the time which a nonce is remembered, the clients should not retry a request after it
+	// Defaults to the DefaultNonceTTL, 24 hours
+	TTL time.Duration
+	// MaxLength the length limit of the nonces, the longer ones are rejected with the 400 status code
+	// Defaults to the DefaultNonceMaxLength, 128
+	MaxLength int
+	// Cache remembers the nonces, see the ReplayCache
+	// Defaults to a new MemoryReplayCache of the DefaultMemoryReplayCacheMaxEntries, use a RedisReplayCache for many instances
+	Cache ReplayCache
+}
+
+// RequireNonce returns a middleware which requires a unique nonce header on each request,
+// so a captured request, i.e a payment, can't be sent again.
+// The requests without a nonce are rejected with the 400 status code, the ones with a nonce which is already used with the 409
+// and the rest with the 503 while the Cache is full, see the MemoryReplayCache's MaxEntries.
+// The routes which share a Cache share their nonces too.
+//
+// Usage:
+// cache := iris.NewRedisReplayCache(client, "iris-nonce:")
+// app.Post("/payments", iris.RequireNonce(iris.NonceOptions{Cache: cache}), createPayment)
+func RequireNonce(options ...NonceOptions) HandlerFunc {
+	var o NonceOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Header == "" {
+		o.Header = DefaultNonceHeader
+	}
+	if o.TTL <= 0 {
+		o.TTL = DefaultNonceTTL
+	}
+	if o.MaxLength <= 0 {
+		o.MaxLength = DefaultNonceMaxLength
+	}
+	if o.Cache == nil {
+		o.Cache = NewMemoryReplayCache()
+	}
+
+	return func(ctx *Context) {
+		nonce := ctx.RequestHeader(o.Header)
+		if nonce == "" || len(nonce) > o.MaxLength {
+			ctx.EmitError(StatusBadRequest)
+			return
+		}
+		ok, err := addReplay(ctx, o.Cache, nonce, o.TTL)
+		if err == ErrReplayCacheFull {
+			ctx.EmitError(StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			ctx.framework.log(LogLevelError, errNonceCache.Format(err).Error())
+			ctx.EmitError(StatusInternalServerError)
+			return
+		}
+		if !ok {
+			ctx.EmitError(StatusConflict)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,112 @@
+package iris
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errParamEncryptionKey     = errors.New("Encrypted params: the key is invalid. Trace: %s")
+	errParamEncryptionMissing = errors.New("Encrypted params: the Config.ParamEncryptionKey is empty")
+	errParamMalformed         = errors.New("Encrypted params: the token is malformed or it's not encrypted by this key")
+)
+
+// newParamCipher returns the AES-GCM of the Config.ParamEncryptionKey
+func newParamCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errParamEncryptionKey.Format(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errParamEncryptionKey.Format(err)
+	}
+	return aead, nil
+}
+
+// EncryptParam returns the opaque, url-safe, token of the 'v' by the default iris instance, see the Framework's EncryptParam
+func EncryptParam(name string, v interface{}) (string, error) {
+	return Default.EncryptParam(name, v)
+}
+
+// EncryptParam returns the opaque, url-safe, token of the 'v', i.e an id, encrypted with the Config.ParamEncryptionKey,
+// so the internal ids of the urls can't be guessed or enumerated. Each call returns a different token of the same value.
+// The token is bound to the 'name', i.e the path parameter's name, it's decrypted only by the same name,
+// so a token of an order can't be used as the token of a user.
+// It should be called after the Build, i.e by the handlers, the ctx.EncryptParam is the same.
+//
+// Usage:
+// token, _ := ctx.EncryptParam("order", order.ID)
+// ctx.Redirect("/orders/" + token)
+func (s *Framework) EncryptParam(name string, v interface{}) (string, error) {
+	if s.paramCipher == nil {
+		return "", errParamEncryptionMissing
+	}
+	nonce := make([]byte, s.paramCipher.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := s.paramCipher.Seal(nonce, nonce, []byte(fmt.Sprint(v)), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptParam returns the value of a 'token' of the EncryptParam by the default iris instance, see the Framework's DecryptParam
+func DecryptParam(name string, token string) (string, error) {
+	return Default.DecryptParam(name, token)
+}
+
+// DecryptParam returns the value of a 'token' of the EncryptParam of the same 'name',
+// an error if it's malformed, tampered, encrypted by another key or for another name
+func (s *Framework) DecryptParam(name string, token string) (string, error) {
+	if s.paramCipher == nil {
+		return "", errParamEncryptionMissing
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.paramCipher.NonceSize() {
+		return "", errParamMalformed
+	}
+	n := s.paramCipher.NonceSize()
+	plain, err := s.paramCipher.Open(nil, sealed[:n], sealed[n:], []byte(name))
+	if err != nil {
+		return "", errParamMalformed
+	}
+	return string(plain), nil
+}
+
+// EncryptParam returns the opaque, url-safe, token of the 'v', see the Framework's EncryptParam
+func (ctx *Context) EncryptParam(name string, v interface{}) (string, error) {
+	return ctx.framework.EncryptParam(name, v)
+}
+
+// DecryptParam returns the value of a 'token' of the EncryptParam, see the Framework's DecryptParam
+func (ctx *Context) DecryptParam(name string, token string) (string, error) {
+	return ctx.framework.DecryptParam(name, token)
+}
+
+// EncryptedParams returns a middleware which decrypts the path parameters of the 'names', the tokens of the EncryptParam of the same names,
+// so the next handlers get their values by the ctx.Param and the ctx.ParamInt.
+// The requests with a token which can't be decrypted are rejected with the 404 status code.
+//
+// Usage:
+// app.Get("/orders/:order", iris.EncryptedParams("order"), func(ctx *iris.Context) {
+//	id, _ := ctx.ParamInt("order")
+// })
+func EncryptedParams(names ...string) HandlerFunc {
+	return func(ctx *Context) {
+		for _, name := range names {
+			v, err := ctx.DecryptParam(name, ctx.Param(name))
+			if err != nil {
+				ctx.EmitError(StatusNotFound)
+				return
+			}
+			ctx.Set(name, v)
+		}
+		ctx.Next()
+	}
+}
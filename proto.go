@@ -0,0 +1,102 @@
+package iris
+
+import (
+	"io"
+	"io/ioutil"
+	"mime"
+
+	"github.com/kataras/go-errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// contentProtobuf the content type of the protobuf messages
+const contentProtobuf = "application/x-protobuf"
+
+var (
+	errProtoContentType = errors.New("ReadProto: unsupported content type %q")
+	errProtoTooLarge    = errors.New("ReadProto: the body exceeds the %d bytes")
+)
+
+// protobufContentTypes the content types of the protobuf request bodies
+var protobufContentTypes = map[string]bool{
+	contentProtobuf:                   true,
+	"application/protobuf":            true,
+	"application/vnd.google.protobuf": true,
+	contentBinary:                     true,
+}
+
+// Proto marshals the protobuf 'msg' and writes it with the "application/x-protobuf" content type
+func (ctx *Context) Proto(status int, msg proto.Message) error {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx.SetContentType(contentProtobuf)
+	ctx.SetStatusCode(status)
+	_, err = ctx.ResponseWriter.Write(b)
+	return err
+}
+
+// protoJSON writes the 'msg' as json, with the field names of the protobuf json mapping
+func (ctx *Context) protoJSON(status int, msg proto.Message) error {
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx.SetContentType(contentJSON + "; charset=" + ctx.framework.Config.Charset)
+	ctx.SetStatusCode(status)
+	_, err = ctx.ResponseWriter.Write(b)
+	return err
+}
+
+// ReadProto reads the request's body to the protobuf 'msg', it's decoded by its Content-Type,
+// the protobuf binary format or its json mapping, so the same handler serves both.
+// The body is limited to the Config.MaxRequestBodySize.
+func (ctx *Context) ReadProto(msg proto.Message) error {
+	if ctx.Request.Body == nil {
+		return errors.New("Empty body, please send request body!")
+	}
+	mediaType := contentProtobuf
+	if ct := ctx.RequestHeader(contentType); ct != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+			return errProtoContentType.Format(ct)
+		}
+	}
+
+	limit := ctx.framework.Config.MaxRequestBodySize
+	if limit <= 0 {
+		limit = DefaultMaxRequestBodySize
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(ctx.Request.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) > limit {
+		return errProtoTooLarge.Format(limit)
+	}
+
+	switch {
+	case mediaType == contentJSON:
+		return protojson.Unmarshal(b, msg)
+	case protobufContentTypes[mediaType]:
+		return proto.Unmarshal(b, msg)
+	}
+	return errProtoContentType.Format(mediaType)
+}
+
+// negotiateProto writes the 'msg' as protobuf or as json, by the request's Accept header, the json if there is no Accept header
+func (ctx *Context) negotiateProto(status int, msg proto.Message) error {
+	offers := []string{contentJSON, contentProtobuf}
+	ctx.Vary("Accept")
+	accept := ctx.RequestHeader("Accept")
+	switch negotiateContentType(accept, offers) {
+	case contentJSON:
+		return ctx.protoJSON(status, msg)
+	case contentProtobuf:
+		return ctx.Proto(status, msg)
+	}
+	ctx.EmitError(StatusNotAcceptable)
+	return errNotAcceptable.Format(contentJSON+", "+contentProtobuf, accept)
+}
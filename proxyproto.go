@@ -0,0 +1,319 @@
+package iris
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// DefaultProxyProtocolHeaderTimeout the time which a connection has to send its PROXY protocol header
+const DefaultProxyProtocolHeaderTimeout = 5 * time.Second
+
+var (
+	errProxyProtocol        = errors.New("Invalid PROXY protocol header: %s")
+	errProxyProtocolTrusted = errors.New("Invalid trusted proxy: %s")
+
+	proxyProtocolV1Prefix  = []byte("PROXY")
+	proxyProtocolV2Sig     = []byte("\r\n\r\n\x00\r\nQUIT\n")
+	proxyProtocolV1MaxSize = 107
+)
+
+// ProxyProtocol returns a listener which parses the HAProxy PROXY protocol, v1 and v2, header of the 'ln' connections,
+// so the ctx.RemoteAddr and the Request.RemoteAddr are the real client's address when the server runs behind a tcp load balancer,
+// i.e the HAProxy, the AWS NLB or the nginx stream module.
+//
+// The 'trusted' are the IPs or the CIDRs of the load balancers, at least one is required,
+// the headers of the other connections are not parsed, so the clients can't spoof their address.
+// Connections without a header keep their address.
+//
+// The header of each connection is read by its own goroutine, the Accept returns the connections which have their header read,
+// so a slow client doesn't block the other connections and the RemoteAddr never blocks.
+//
+// Usage:
+// ln, err := iris.TCP4(":8080")
+// app.Serve(iris.ProxyProtocol(ln, "10.0.0.0/8"))
+func ProxyProtocol(ln net.Listener, trusted ...string) net.Listener {
+	if len(trusted) == 0 {
+		panic(errProxyProtocolTrusted.Format("at least one trusted proxy is required"))
+	}
+	networks, err := parseNetworks(trusted)
+	if err != nil {
+		panic(errProxyProtocolTrusted.Format(err.Error()))
+	}
+	return &proxyProtocolListener{
+		Listener: ln,
+		trusted:  networks,
+		timeout:  DefaultProxyProtocolHeaderTimeout,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error),
+		stopped:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// parseNetworks parses the IPs and the CIDRs, a single IP is a network of its own
+func parseNetworks(ips []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(ips))
+	for _, ip := range ips {
+		if !strings.Contains(ip, "/") {
+			if strings.Contains(ip, ":") {
+				ip += "/128"
+			} else {
+				ip += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(ip)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// containsIP reports whether the 'ip' is in one of the 'networks'
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type proxyProtocolListener struct {
+	net.Listener
+	trusted []*net.IPNet
+	timeout time.Duration
+
+	startOnce sync.Once
+	// conns the connections which have their header read
+	conns chan net.Conn
+	// errs the temporary errors of the Accept
+	errs chan error
+	// stopped is closed when the Accept fails, with the err
+	stopped chan struct{}
+	err     error
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Accept returns the next connection which has its header read
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	l.startOnce.Do(func() { go l.serve() })
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.stopped:
+		return nil, l.err
+	}
+}
+
+// serve accepts the connections and reads the header of each one in its own goroutine
+func (l *proxyProtocolListener) serve() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				select {
+				case l.errs <- err:
+					continue
+				case <-l.done:
+				}
+			}
+			l.err = err
+			close(l.stopped)
+			return
+		}
+		if !l.trust(conn.RemoteAddr()) {
+			go l.deliver(conn)
+			continue
+		}
+		go func(c *proxyProtocolConn) {
+			c.once.Do(c.readHeader)
+			if c.err != nil {
+				// closed by the readHeader
+				return
+			}
+			l.deliver(c)
+		}(&proxyProtocolConn{Conn: conn, r: bufio.NewReader(conn), timeout: l.timeout})
+	}
+}
+
+// deliver passes the 'conn' to the Accept, it's closed if the listener is closed
+func (l *proxyProtocolListener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+func (l *proxyProtocolListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+func (l *proxyProtocolListener) trust(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && containsIP(l.trusted, tcpAddr.IP)
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once   sync.Once
+	remote net.Addr
+	local  net.Addr
+	err    error
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+// RemoteAddr returns the client's address of the header, the header is read before the Accept returns the connection
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	if c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readHeader reads the PROXY protocol header, if any, and keeps the addresses of the original connection
+func (c *proxyProtocolConn) readHeader() {
+	if c.timeout > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+		defer c.Conn.SetReadDeadline(time.Time{})
+	}
+
+	prefix, err := c.r.Peek(len(proxyProtocolV1Prefix))
+	if err != nil {
+		// let the next Read report the error, if any, of the short connection
+		return
+	}
+	switch {
+	case bytes.Equal(prefix, proxyProtocolV1Prefix):
+		c.err = c.readV1()
+	case bytes.Equal(prefix, proxyProtocolV2Sig[:len(prefix)]):
+		c.err = c.readV2()
+	}
+	if c.err != nil {
+		c.Conn.Close()
+	}
+}
+
+// readV1 reads the human-readable header, i.e "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"
+func (c *proxyProtocolConn) readV1() error {
+	var line []byte
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return errProxyProtocol.Format(err.Error())
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= proxyProtocolV1MaxSize {
+			return errProxyProtocol.Format("v1 header is too long")
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return errProxyProtocol.Format("v1 header doesn't end with CRLF")
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return errProxyProtocol.Format(strconv.Quote(string(line)))
+	}
+	src, dst := net.ParseIP(fields[2]), net.ParseIP(fields[3])
+	srcPort, errSrc := strconv.ParseUint(fields[4], 10, 16)
+	dstPort, errDst := strconv.ParseUint(fields[5], 10, 16)
+	if src == nil || dst == nil || errSrc != nil || errDst != nil {
+		return errProxyProtocol.Format(strconv.Quote(string(line)))
+	}
+	c.remote = &net.TCPAddr{IP: src, Port: int(srcPort)}
+	c.local = &net.TCPAddr{IP: dst, Port: int(dstPort)}
+	return nil
+}
+
+// readV2 reads the binary header, the 12 bytes signature, the version and command,
+// the family and protocol, the length of the addresses and the addresses
+func (c *proxyProtocolConn) readV2() error {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return errProxyProtocol.Format(err.Error())
+	}
+	if !bytes.Equal(header[:12], proxyProtocolV2Sig) {
+		return errProxyProtocol.Format("invalid v2 signature")
+	}
+	if header[12]>>4 != 2 {
+		return errProxyProtocol.Format("unsupported version " + strconv.Itoa(int(header[12]>>4)))
+	}
+	command, family := header[12]&0x0f, header[13]>>4
+	addrs := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(c.r, addrs); err != nil {
+		return errProxyProtocol.Format(err.Error())
+	}
+
+	// LOCAL, i.e the health checks of the proxy, keep the connection's addresses
+	if command == 0 {
+		return nil
+	}
+	if command != 1 {
+		return errProxyProtocol.Format("unsupported command " + strconv.Itoa(int(command)))
+	}
+
+	var ipLen int
+	switch family {
+	case 1: // AF_INET
+		ipLen = net.IPv4len
+	case 2: // AF_INET6
+		ipLen = net.IPv6len
+	default: // AF_UNSPEC and AF_UNIX, keep the connection's addresses
+		return nil
+	}
+	if len(addrs) < 2*ipLen+4 {
+		return errProxyProtocol.Format("v2 addresses are too short")
+	}
+	c.remote = &net.TCPAddr{
+		IP:   net.IP(addrs[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(addrs[2*ipLen:])),
+	}
+	c.local = &net.TCPAddr{
+		IP:   net.IP(addrs[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(addrs[2*ipLen+2:])),
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package iris
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// DefaultRateLimitTier the tier of the principals whose tier is not in the RateLimitOptions' Tiers
+const DefaultRateLimitTier = "default"
+
+// RateLimitTier the quota of the principals of a tier, i.e 100 requests per minute
+type RateLimitTier struct {
+	// Requests the number of the requests of each Per, it's the burst of a principal too
+	Requests int
+	// Per the duration of the Requests
+	Per time.Duration
+}
+
+// rate returns the requests per second of the tier
+func (t RateLimitTier) rate() float64 {
+	return float64(t.Requests) / t.Per.Seconds()
+}
+
+// RateLimitOptions the options of the RateLimit
+type RateLimitOptions struct {
+	// Principal returns the key of the request's principal, its quota is shared by its requests
+	// Defaults to the "key:" and the ID of the ctx.APIKey, if any, otherwise the "ip:" and the ip of the connection,
+	// the X-Real-Ip and the X-Forwarded-For headers are not used, any client can change them on each request
+	Principal func(ctx *Context) string
+	// Tier returns the tier of the request's principal, i.e "free" or "pro", by its api key, its session or its claims
+	// Defaults to nil, the DefaultRateLimitTier for all
+	Tier func(ctx *Context) string
+	// Tiers the quotas by tier, the DefaultRateLimitTier is the quota of the unknown tiers and of the requests without a Tier
+	Tiers map[string]RateLimitTier
+}
+
+var (
+	errRateLimitDefaultTier = errors.New("RateLimit: the Tiers don't contain the %q tier")
+	errRateLimitTier        = errors.New("RateLimit: the Requests and the Per of the %q tier should be positive")
+)
+
+// rateLimitBucket the tokens of a principal
+type rateLimitBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter the token buckets of the principals of a RateLimit
+type rateLimiter struct {
+	options RateLimitOptions
+	mu      sync.Mutex
+	buckets map[string]*rateLimitBucket
+	// lastCleanup the time of the last removal of the full buckets
+	lastCleanup time.Time
+	// maxPer the longest Per of the tiers, after it the bucket of a principal is full
+	maxPer time.Duration
+}
+
+// RateLimit returns a middleware which limits the requests of each principal, the authenticated api key by default or the client's ip,
+// by the quota of its tier, so the paid customers get higher quotas on the same routes. It should be used after the authentication middleware, i.e the APIKeyAuth.
+// The responses have the X-RateLimit-Limit and the X-RateLimit-Remaining headers,
+// the requests over the quota are rejected with the 429 status code and the Retry-After header.
+// The quotas are token buckets, kept in memory.
+//
+// Usage:
+// api := app.Party("/api", iris.APIKeyAuth(store), iris.RateLimit(iris.RateLimitOptions{
+//	Tier: func(ctx *iris.Context) string { return plans[ctx.APIKey().Owner] },
+//	Tiers: map[string]iris.RateLimitTier{
+//		iris.DefaultRateLimitTier: {Requests: 60, Per: time.Minute},
+//		"pro":                     {Requests: 6000, Per: time.Minute},
+//	},
+// }))
+func RateLimit(options RateLimitOptions) HandlerFunc {
+	if options.Principal == nil {
+		options.Principal = defaultRateLimitPrincipal
+	}
+	if _, ok := options.Tiers[DefaultRateLimitTier]; !ok {
+		panic(errRateLimitDefaultTier.Format(DefaultRateLimitTier))
+	}
+	l := &rateLimiter{options: options, buckets: make(map[string]*rateLimitBucket)}
+	for name, tier := range options.Tiers {
+		if tier.Requests <= 0 || tier.Per <= 0 {
+			panic(errRateLimitTier.Format(name))
+		}
+		if tier.Per > l.maxPer {
+			l.maxPer = tier.Per
+		}
+	}
+
+	return func(ctx *Context) {
+		tierName := DefaultRateLimitTier
+		if options.Tier != nil {
+			if name := options.Tier(ctx); name != "" {
+				tierName = name
+			}
+		}
+		tier, ok := options.Tiers[tierName]
+		if !ok {
+			tierName, tier = DefaultRateLimitTier, options.Tiers[DefaultRateLimitTier]
+		}
+
+		// the principals of each tier have their own buckets, so an upgrade takes effect immediately
+		remaining, retryAfter := l.take(tierName+"|"+options.Principal(ctx), tier, ctx.framework.now())
+		ctx.SetHeader("X-RateLimit-Limit", strconv.Itoa(tier.Requests))
+		ctx.SetHeader("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if retryAfter > 0 {
+			ctx.SetHeader("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ctx.EmitError(StatusTooManyRequests)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// defaultRateLimitPrincipal returns the key of the request's api key, if any, otherwise of its ip
+func defaultRateLimitPrincipal(ctx *Context) string {
+	if k := ctx.APIKey(); k != nil {
+		return "key:" + k.ID
+	}
+	return "ip:" + ctx.connIP()
+}
+
+// take takes a token of the bucket of the 'key' at the 'now', it returns the remaining tokens,
+// or the time until the next token if the bucket is empty
+func (l *rateLimiter) take(key string, tier RateLimitTier, now time.Time) (int, time.Duration) {
+	rate := tier.rate()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cleanup(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateLimitBucket{tokens: float64(tier.Requests), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(tier.Requests), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	b.tokens--
+	return int(b.tokens), 0
+}
+
+// cleanup removes the buckets which are full again, once per the longest Per
+func (l *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.maxPer {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > l.maxPer {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
@@ -0,0 +1,578 @@
+package iris
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// the transports of the realtime connections
+const (
+	// RealtimeTransportWebsocket the transport of the connections which are upgraded by the RealtimeServer's Handler
+	RealtimeTransportWebsocket = "websocket"
+	// RealtimeTransportPolling the transport of the connections which are opened by the RealtimeServer's LongPollHandler
+	RealtimeTransportPolling = "polling"
+)
+
+var errRealtimeSlowConnection = errors.New("Realtime: connection '%s' can't keep up with its messages, it's closed")
+
+// RealtimeMessage is the message of an event, with the default RealtimeJSON codec the client sends and receives the json {"event": "chat", "room": "lobby", "data": ...}
+type RealtimeMessage struct {
+	// Event the name of the event
+	Event string `json:"event"`
+	// Room the room which the message is sent to, empty if it's sent to the connection or to all
+	Room string `json:"room,omitempty"`
+	// Data the data of the message, encoded by the server's codec
+	Data json.RawMessage `json:"data,omitempty"`
+
+	codec RealtimeCodec
+}
+
+// Decode decodes the message's data to the 'v', by the server's codec
+func (m RealtimeMessage) Decode(v interface{}) error {
+	if m.codec == nil {
+		return json.Unmarshal(m.Data, v)
+	}
+	return m.codec.Unmarshal(m.Data, v)
+}
+
+// RealtimeServer is the first-party websocket server, it keeps the connections and their rooms
+// and it dispatches the clients' events to the connections' listeners.
+//
+// Usage:
+// ws := app.NewWebsocketServer(iris.RealtimeConfiguration{})
+// ws.OnConnect(func(c *iris.RealtimeConnection) {
+//     c.Join("lobby")
+//     c.On("chat", func(msg iris.RealtimeMessage) {
+//         c.To("lobby").Emit("chat", msg.Data)
+//     })
+// })
+// app.Get("/ws", ws.Handler())
+type RealtimeServer struct {
+	// the Metrics' counters, first for the 64-bit alignment of the atomic operations
+	rejected   uint64
+	dropped    uint64
+	slowClosed uint64
+
+	config  RealtimeConfiguration
+	station *Framework
+	// the id of this instance, the backplane's messages which are published by it are ignored
+	id        string
+	backplane RealtimeBackplane
+
+	connections  map[string]*RealtimeConnection
+	rooms        map[string]map[string]*RealtimeConnection
+	onUpgrade    []func(*Context) (interface{}, error)
+	onConnect    []func(*RealtimeConnection)
+	onDisconnect []func(*RealtimeConnection)
+	onError      []func(*RealtimeConnection, error)
+	// the connections, and the handshakes in progress, in total and by ip
+	reserved int
+	ips      map[string]int
+	mu       sync.RWMutex
+}
+
+// NewWebsocketServer returns a new websocket server, its Handler should be registered to a GET route,
+// i.e app.Get("/ws", ws.Handler()) and its LongPollHandler, optionally, to any method, i.e app.Any("/ws/poll", ws.LongPollHandler()).
+//
+// The zero fields of the 'cfg' are filled by the DefaultRealtimeConfiguration.
+func (s *Framework) NewWebsocketServer(cfg ...RealtimeConfiguration) *RealtimeServer {
+	ws := &RealtimeServer{
+		config:      mergeRealtimeConfiguration(cfg),
+		station:     s,
+		id:          s.newID(),
+		connections: make(map[string]*RealtimeConnection),
+		rooms:       make(map[string]map[string]*RealtimeConnection),
+		ips:         make(map[string]int),
+	}
+	s.shutdownMu.Lock()
+	s.realtimeServers = append(s.realtimeServers, ws)
+	s.shutdownMu.Unlock()
+	return ws
+}
+
+// mergeRealtimeConfiguration returns the DefaultRealtimeConfiguration with the non-zero fields of the 'cfg'
+func mergeRealtimeConfiguration(cfg []RealtimeConfiguration) RealtimeConfiguration {
+	c := DefaultRealtimeConfiguration()
+	if len(cfg) > 0 {
+		if cfg[0].WriteTimeout > 0 {
+			c.WriteTimeout = cfg[0].WriteTimeout
+		}
+		if cfg[0].PongTimeout > 0 {
+			c.PongTimeout = cfg[0].PongTimeout
+			c.PingPeriod = (c.PongTimeout * 9) / 10
+		}
+		if cfg[0].PingPeriod != 0 {
+			c.PingPeriod = cfg[0].PingPeriod
+		}
+		if cfg[0].MaxMessageSize > 0 {
+			c.MaxMessageSize = cfg[0].MaxMessageSize
+		}
+		if cfg[0].Error != nil {
+			c.Error = cfg[0].Error
+		}
+		if cfg[0].CheckOrigin != nil {
+			c.CheckOrigin = cfg[0].CheckOrigin
+		}
+		if cfg[0].Compression {
+			c.Compression = true
+			c.CompressionContextTakeover = cfg[0].CompressionContextTakeover
+		}
+		if cfg[0].CompressionLevel != 0 {
+			c.CompressionLevel = cfg[0].CompressionLevel
+		}
+		if cfg[0].CompressionThreshold > 0 {
+			c.CompressionThreshold = cfg[0].CompressionThreshold
+		}
+		if cfg[0].LongPollHoldTimeout > 0 {
+			c.LongPollHoldTimeout = cfg[0].LongPollHoldTimeout
+		}
+		if cfg[0].LongPollSessionTimeout > 0 {
+			c.LongPollSessionTimeout = cfg[0].LongPollSessionTimeout
+		}
+		if cfg[0].MaxConnections > 0 {
+			c.MaxConnections = cfg[0].MaxConnections
+		}
+		if cfg[0].MaxConnectionsPerIP > 0 {
+			c.MaxConnectionsPerIP = cfg[0].MaxConnectionsPerIP
+		}
+		if cfg[0].SendQueueSize > 0 {
+			c.SendQueueSize = cfg[0].SendQueueSize
+		}
+		c.SlowConsumer = cfg[0].SlowConsumer
+		if cfg[0].Codec != nil {
+			c.Codec = cfg[0].Codec
+		}
+	}
+	return c
+}
+
+// OnConnect registers a callback which is fired when a client is connected,
+// the connection's event listeners should be registered here
+func (ws *RealtimeServer) OnConnect(cb func(*RealtimeConnection)) {
+	ws.mu.Lock()
+	ws.onConnect = append(ws.onConnect, cb)
+	ws.mu.Unlock()
+}
+
+// OnDisconnect registers a callback which is fired when a connection is closed, after it has left all of its rooms
+func (ws *RealtimeServer) OnDisconnect(cb func(*RealtimeConnection)) {
+	ws.mu.Lock()
+	ws.onDisconnect = append(ws.onDisconnect, cb)
+	ws.mu.Unlock()
+}
+
+// Handler returns the handler which upgrades the requests to websocket connections,
+// it blocks until the connection is closed
+func (ws *RealtimeServer) Handler() HandlerFunc {
+	return func(ctx *Context) {
+		ip, status, err := ws.reserve(ctx)
+		if err != nil {
+			ws.config.Error(ctx, status, err)
+			return
+		}
+
+		var identity interface{}
+		conn, status, err := upgradeWebsocket(ctx, ws.config, func() (status int, err error) {
+			identity, status, err = ws.authorize(ctx)
+			return
+		})
+		if err != nil {
+			ws.mu.Lock()
+			ws.release(ip)
+			ws.mu.Unlock()
+			ws.config.Error(ctx, status, err)
+			return
+		}
+
+		c := newRealtimeConnection(ws, RealtimeTransportWebsocket, conn, ctx.Request)
+		c.identity = identity
+		c.ip = ip
+		go c.writeLoop()
+		ws.connect(c)
+		c.readLoop()
+
+		ws.disconnect(c)
+	}
+}
+
+// connect adds the connection to the server and fires the OnConnect callbacks
+func (ws *RealtimeServer) connect(c *RealtimeConnection) {
+	ws.mu.Lock()
+	ws.connections[c.id] = c
+	onConnect := ws.onConnect
+	ws.mu.Unlock()
+
+	for _, cb := range onConnect {
+		cb(c)
+	}
+}
+
+// disconnect removes the connection from the server and its rooms and fires the OnDisconnect callbacks
+func (ws *RealtimeServer) disconnect(c *RealtimeConnection) {
+	ws.mu.Lock()
+	delete(ws.connections, c.id)
+	ws.release(c.ip)
+	for room := range c.rooms {
+		ws.leave(room, c)
+	}
+	onDisconnect := ws.onDisconnect
+	ws.mu.Unlock()
+
+	for _, cb := range onDisconnect {
+		cb(c)
+	}
+}
+
+// leave removes the connection from the room, the caller should hold the lock
+func (ws *RealtimeServer) leave(room string, c *RealtimeConnection) {
+	if members, ok := ws.rooms[room]; ok {
+		delete(members, c.id)
+		if len(members) == 0 {
+			delete(ws.rooms, room)
+		}
+	}
+	delete(c.rooms, room)
+}
+
+// Close closes all the connections of the server, the websocket ones with a going away close frame,
+// the OnDisconnect callbacks are fired. It's called by the app.Shutdown
+func (ws *RealtimeServer) Close() error {
+	ws.mu.RLock()
+	connections := make([]*RealtimeConnection, 0, len(ws.connections))
+	for _, c := range ws.connections {
+		connections = append(connections, c)
+	}
+	ws.mu.RUnlock()
+
+	for _, c := range connections {
+		c.closeWith(websocketCloseGoingAway)
+	}
+	return nil
+}
+
+// Len returns the number of the connected clients
+func (ws *RealtimeServer) Len() int {
+	ws.mu.RLock()
+	n := len(ws.connections)
+	ws.mu.RUnlock()
+	return n
+}
+
+// Connection returns the connection with the 'id', or nil if it's not connected
+func (ws *RealtimeServer) Connection(id string) *RealtimeConnection {
+	ws.mu.RLock()
+	c := ws.connections[id]
+	ws.mu.RUnlock()
+	return c
+}
+
+// Rooms returns the names of the rooms which have at least one connection
+func (ws *RealtimeServer) Rooms() []string {
+	ws.mu.RLock()
+	rooms := make([]string, 0, len(ws.rooms))
+	for room := range ws.rooms {
+		rooms = append(rooms, room)
+	}
+	ws.mu.RUnlock()
+	sort.Strings(rooms)
+	return rooms
+}
+
+// Broadcast emits the event to all the connections, of all the instances if the server uses a backplane
+func (ws *RealtimeServer) Broadcast(event string, data interface{}) error {
+	payload, err := ws.encode(event, "", data)
+	if err != nil {
+		return err
+	}
+	ws.deliver("", "", payload)
+	return ws.publish("", "", payload)
+}
+
+// deliver sends the encoded message to the local connections of the room, or to all if the 'room' is empty,
+// except the connection with the 'except' id
+func (ws *RealtimeServer) deliver(room string, except string, payload []byte) {
+	ws.mu.RLock()
+	members := ws.connections
+	if room != "" {
+		members = ws.rooms[room]
+	}
+	targets := make([]*RealtimeConnection, 0, len(members))
+	for id, c := range members {
+		if id != except {
+			targets = append(targets, c)
+		}
+	}
+	ws.mu.RUnlock()
+
+	for _, c := range targets {
+		c.send(payload)
+	}
+}
+
+// To returns the room's emitter, which sends the events to all the connections of the room
+func (ws *RealtimeServer) To(room string) *RealtimeRoom {
+	return &RealtimeRoom{server: ws, name: room}
+}
+
+// RealtimeRoom emits the events to the connections of a room
+type RealtimeRoom struct {
+	server *RealtimeServer
+	name   string
+	except *RealtimeConnection
+}
+
+// Name returns the name of the room
+func (r *RealtimeRoom) Name() string {
+	return r.name
+}
+
+// Len returns the number of the room's connections
+func (r *RealtimeRoom) Len() int {
+	r.server.mu.RLock()
+	n := len(r.server.rooms[r.name])
+	r.server.mu.RUnlock()
+	return n
+}
+
+// Emit sends the event to the room's connections, of all the instances if the server uses a backplane,
+// except the sender if the room is taken by the connection's To
+func (r *RealtimeRoom) Emit(event string, data interface{}) error {
+	payload, err := r.server.encode(event, r.name, data)
+	if err != nil {
+		return err
+	}
+	except := ""
+	if r.except != nil {
+		except = r.except.id
+	}
+	r.server.deliver(r.name, except, payload)
+	return r.server.publish(r.name, except, payload)
+}
+
+// encode encodes the message of the event by the server's codec, a json.RawMessage 'data' is already encoded and it's sent as it's
+func (ws *RealtimeServer) encode(event string, room string, data interface{}) ([]byte, error) {
+	msg := RealtimeMessage{Event: event, Room: room}
+	switch d := data.(type) {
+	case nil:
+	case json.RawMessage:
+		msg.Data = d
+	default:
+		b, err := ws.config.Codec.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		msg.Data = b
+	}
+	return ws.config.Codec.EncodeMessage(msg)
+}
+
+// RealtimeConnection is a client's connection of a RealtimeServer, a websocket or a long-polling one
+type RealtimeConnection struct {
+	id        string
+	server    *RealtimeServer
+	transport string
+	// nil on long-polling
+	conn    *websocketConn
+	request *http.Request
+	// set by the OnUpgrade callbacks
+	identity interface{}
+	// the client's ip, which the connection is counted to
+	ip string
+
+	// protected by the server's mu
+	rooms map[string]struct{}
+
+	listeners map[string][]func(RealtimeMessage)
+	mu        sync.RWMutex
+
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	// long-polling, signals the start and the end of a poll
+	polled chan struct{}
+}
+
+func newRealtimeConnection(ws *RealtimeServer, transport string, conn *websocketConn, r *http.Request) *RealtimeConnection {
+	return &RealtimeConnection{
+		id:        ws.station.newID(),
+		server:    ws,
+		transport: transport,
+		conn:      conn,
+		request:   r,
+		rooms:     make(map[string]struct{}),
+		listeners: make(map[string][]func(RealtimeMessage)),
+		queue:     make(chan []byte, ws.config.SendQueueSize),
+		done:      make(chan struct{}),
+		polled:    make(chan struct{}, 1),
+	}
+}
+
+// ID returns the unique identifier of the connection
+func (c *RealtimeConnection) ID() string {
+	return c.id
+}
+
+// Transport returns the transport of the connection, RealtimeTransportWebsocket or RealtimeTransportPolling
+func (c *RealtimeConnection) Transport() string {
+	return c.transport
+}
+
+// Request returns the http request which is upgraded to this connection, it should be used only for reading
+func (c *RealtimeConnection) Request() *http.Request {
+	return c.request
+}
+
+// On registers a listener for the client's 'event'
+func (c *RealtimeConnection) On(event string, cb func(RealtimeMessage)) {
+	c.mu.Lock()
+	c.listeners[event] = append(c.listeners[event], cb)
+	c.mu.Unlock()
+}
+
+// Emit sends the event to this connection
+func (c *RealtimeConnection) Emit(event string, data interface{}) error {
+	payload, err := c.server.encode(event, "", data)
+	if err != nil {
+		return err
+	}
+	return c.send(payload)
+}
+
+// To returns the room's emitter, which sends the events to all the connections of the room except this connection
+func (c *RealtimeConnection) To(room string) *RealtimeRoom {
+	return &RealtimeRoom{server: c.server, name: room, except: c}
+}
+
+// Join adds the connection to the room
+func (c *RealtimeConnection) Join(room string) {
+	ws := c.server
+	ws.mu.Lock()
+	if _, connected := ws.connections[c.id]; connected {
+		members, ok := ws.rooms[room]
+		if !ok {
+			members = make(map[string]*RealtimeConnection)
+			ws.rooms[room] = members
+		}
+		members[c.id] = c
+		c.rooms[room] = struct{}{}
+	}
+	ws.mu.Unlock()
+}
+
+// Leave removes the connection from the room
+func (c *RealtimeConnection) Leave(room string) {
+	c.server.mu.Lock()
+	c.server.leave(room, c)
+	c.server.mu.Unlock()
+}
+
+// Rooms returns the rooms which the connection has joined
+func (c *RealtimeConnection) Rooms() []string {
+	c.server.mu.RLock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	c.server.mu.RUnlock()
+	sort.Strings(rooms)
+	return rooms
+}
+
+// Close closes the connection, the OnDisconnect callbacks are fired
+func (c *RealtimeConnection) Close() error {
+	return c.closeWith(websocketCloseNormal)
+}
+
+// closeWith closes the connection, a websocket one with the close 'code'
+func (c *RealtimeConnection) closeWith(code int) error {
+	c.closeOnce.Do(func() { close(c.done) })
+	if c.conn == nil {
+		return nil
+	}
+	c.conn.close(code)
+	return nil
+}
+
+// send queues the encoded message, when the queue is full the SlowConsumer policy is applied
+func (c *RealtimeConnection) send(payload []byte) error {
+	select {
+	case <-c.done:
+		return errWebsocketClosed
+	default:
+	}
+
+	select {
+	case c.queue <- payload:
+		return nil
+	default:
+		return c.overflow(payload)
+	}
+}
+
+// writeLoop writes the queued messages and the pings until the connection is closed
+func (c *RealtimeConnection) writeLoop() {
+	opcode := websocketOpText
+	if c.server.config.Codec.Binary() {
+		opcode = websocketOpBinary
+	}
+	var ping <-chan time.Time
+	if period := c.server.config.PingPeriod; period > 0 {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ping:
+			if err := c.conn.writeFrame(websocketOpPing, nil); err != nil {
+				c.Close()
+				return
+			}
+		case payload := <-c.queue:
+			if err := c.conn.WriteMessage(opcode, payload); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+// readLoop dispatches the client's events to their listeners until the connection is closed,
+// or it's considered dead because nothing is received for the PongTimeout
+func (c *RealtimeConnection) readLoop() {
+	defer c.Close()
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := c.server.config.Codec.DecodeMessage(data)
+		if err != nil {
+			// not an event, report and ignore it
+			c.server.fireError(c, errRealtimeDecodeMessage.Format(c.id, err.Error()))
+			continue
+		}
+		c.dispatch(msg)
+	}
+}
+
+// dispatch fires the listeners of the client's event
+func (c *RealtimeConnection) dispatch(msg RealtimeMessage) {
+	if msg.Event == "" {
+		return
+	}
+	msg.codec = c.server.config.Codec
+	c.mu.RLock()
+	listeners := c.listeners[msg.Event]
+	c.mu.RUnlock()
+	for _, cb := range listeners {
+		cb(msg)
+	}
+}
@@ -0,0 +1,71 @@
+package iris
+
+import (
+	"github.com/kataras/go-errors"
+)
+
+var errRealtimeUnauthorized = errors.New("Realtime: the connection is not authorized")
+
+// RealtimeUpgradeError rejects the upgrade of a connection with its Status code, it's returned by the OnUpgrade callbacks,
+// i.e return nil, &iris.RealtimeUpgradeError{Status: iris.StatusForbidden, Reason: err}
+type RealtimeUpgradeError struct {
+	// Status the status code of the response, zero means StatusUnauthorized
+	Status int
+	// Reason the error which is passed to the Error config, may be nil
+	Reason error
+}
+
+// Error returns the reason's message
+func (e *RealtimeUpgradeError) Error() string {
+	if e.Reason == nil {
+		return errRealtimeUnauthorized.Error()
+	}
+	return e.Reason.Error()
+}
+
+// OnUpgrade registers a callback which is fired before the handshake of a websocket, or the opening of a long-polling, connection is completed,
+// it receives the request's Context so it can check the client's credentials, i.e a JWT or a session.
+//
+// A non-nil error rejects the connection, the response is sent by the Error config
+// with the status of a *RealtimeUpgradeError or the StatusUnauthorized for any other error.
+// The non-nil 'identity' is attached to the connection and it's returned by its Identity, the last one is kept if more than one callbacks return one.
+func (ws *RealtimeServer) OnUpgrade(cb func(ctx *Context) (identity interface{}, err error)) {
+	ws.mu.Lock()
+	ws.onUpgrade = append(ws.onUpgrade, cb)
+	ws.mu.Unlock()
+}
+
+// authorize fires the OnUpgrade callbacks, it returns the connection's identity
+// or the status code and the reason of the rejection
+func (ws *RealtimeServer) authorize(ctx *Context) (interface{}, int, error) {
+	ws.mu.RLock()
+	onUpgrade := ws.onUpgrade
+	ws.mu.RUnlock()
+
+	var identity interface{}
+	for _, cb := range onUpgrade {
+		id, err := cb(ctx)
+		if err != nil {
+			if rejection, ok := err.(*RealtimeUpgradeError); ok {
+				status := rejection.Status
+				if status == 0 {
+					status = StatusUnauthorized
+				}
+				if rejection.Reason == nil {
+					return nil, status, errRealtimeUnauthorized
+				}
+				return nil, status, rejection.Reason
+			}
+			return nil, StatusUnauthorized, err
+		}
+		if id != nil {
+			identity = id
+		}
+	}
+	return identity, StatusOK, nil
+}
+
+// Identity returns the identity which is attached to the connection by the OnUpgrade callbacks, or nil
+func (c *RealtimeConnection) Identity() interface{} {
+	return c.identity
+}
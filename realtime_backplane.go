@@ -0,0 +1,77 @@
+package iris
+
+import (
+	"encoding/json"
+
+	"github.com/kataras/go-errors"
+)
+
+var errRealtimeBackplane = errors.New("Realtime: backplane failed. Trace: %s")
+
+// RealtimeBackplane propagates the broadcasts and the room emits of a RealtimeServer to the servers of the other instances,
+// so the clients of a horizontally scaled application receive the same events no matter which instance they are connected to.
+//
+// See NewRedisBackplane for the Redis pub/sub implementation.
+type RealtimeBackplane interface {
+	// Publish sends the message to all the subscribers, the publisher's too
+	Publish(msg []byte) error
+	// Subscribe calls the 'receive' for each published message, until the backplane is closed
+	Subscribe(receive func(msg []byte)) error
+	// Close stops the subscription and releases the backplane's resources
+	Close() error
+}
+
+// realtimeEnvelope is the message of the backplane, it keeps the target of an encoded RealtimeMessage
+type realtimeEnvelope struct {
+	// Origin the id of the publisher's server
+	Origin string `json:"origin"`
+	// Room the target room, empty for all the connections
+	Room string `json:"room,omitempty"`
+	// Except the id of the connection which doesn't receive the message
+	Except string `json:"except,omitempty"`
+	// Payload the RealtimeMessage, encoded by the server's codec
+	Payload []byte `json:"payload"`
+}
+
+// UseBackplane subscribes the server to the backplane, from now on the Broadcast and the room emits reach
+// the connections of all the instances which use the same backplane.
+// It should be called once, before the server accepts connections.
+func (ws *RealtimeServer) UseBackplane(b RealtimeBackplane) error {
+	ws.mu.Lock()
+	ws.backplane = b
+	ws.mu.Unlock()
+	return b.Subscribe(ws.receiveBackplane)
+}
+
+// publish sends the encoded message to the other instances, if the server uses a backplane
+func (ws *RealtimeServer) publish(room string, except string, payload []byte) error {
+	ws.mu.RLock()
+	b := ws.backplane
+	ws.mu.RUnlock()
+	if b == nil {
+		return nil
+	}
+
+	msg, err := json.Marshal(realtimeEnvelope{Origin: ws.id, Room: room, Except: except, Payload: payload})
+	if err != nil {
+		return err
+	}
+	if err = b.Publish(msg); err != nil {
+		return errRealtimeBackplane.Format(err.Error())
+	}
+	return nil
+}
+
+// receiveBackplane delivers the message of another instance to the local connections
+func (ws *RealtimeServer) receiveBackplane(msg []byte) {
+	var e realtimeEnvelope
+	if err := json.Unmarshal(msg, &e); err != nil {
+		ws.station.log(LogLevelWarn, errRealtimeBackplane.Format(err.Error()).Error())
+		return
+	}
+	if e.Origin == ws.id {
+		// already delivered by this instance
+		return
+	}
+	ws.deliver(e.Room, e.Except, e.Payload)
+}
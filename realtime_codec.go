@@ -0,0 +1,206 @@
+package iris
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/kataras/go-errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errRealtimeDecodeMessage   = errors.New("Realtime: unable to decode a message of connection '%s'. Trace: %s")
+	errRealtimeDecodeData      = errors.New("Realtime: unable to decode the data of the '%s' event of connection '%s'. Trace: %s")
+	errRealtimeProtobufMessage = errors.New("Realtime: protobuf codec expects a proto.Message but got %T")
+	errRealtimePollingCodec    = errors.New("Realtime: long-polling is available only with the RealtimeJSON codec")
+)
+
+// RealtimeCodec encodes and decodes the messages of a RealtimeServer, the RealtimeConfiguration's Codec,
+// so all the events of a server, its namespace, are marshaled the same way.
+//
+// The built-in codecs are the RealtimeJSON, the default, RealtimeMsgpack and RealtimeProtobuf.
+type RealtimeCodec interface {
+	// Binary reports whether the messages are sent as binary websocket frames, otherwise as text frames
+	Binary() bool
+	// Marshal encodes the data of a message
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal decodes the data of a message to the 'v'
+	Unmarshal(data []byte, v interface{}) error
+	// EncodeMessage encodes the message, its Data is already encoded by the Marshal
+	EncodeMessage(msg RealtimeMessage) ([]byte, error)
+	// DecodeMessage decodes a client's message, its Data is decoded later by the Unmarshal
+	DecodeMessage(b []byte) (RealtimeMessage, error)
+}
+
+var (
+	// RealtimeJSON the default codec, the messages are the json text {"event": "chat", "room": "lobby", "data": ...}
+	RealtimeJSON RealtimeCodec = realtimeJSONCodec{}
+	// RealtimeMsgpack the MessagePack codec, the messages are the binary map {"event": "chat", "room": "lobby", "data": ...}
+	RealtimeMsgpack RealtimeCodec = realtimeMsgpackCodec{}
+	// RealtimeProtobuf the Protocol Buffers codec, the data of a message should be a proto.Message
+	// and the messages are the binary protobuf message { string event = 1; string room = 2; bytes data = 3; }
+	RealtimeProtobuf RealtimeCodec = realtimeProtobufCodec{}
+)
+
+type realtimeJSONCodec struct{}
+
+func (realtimeJSONCodec) Binary() bool {
+	return false
+}
+
+func (realtimeJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (realtimeJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (realtimeJSONCodec) EncodeMessage(msg RealtimeMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (realtimeJSONCodec) DecodeMessage(b []byte) (msg RealtimeMessage, err error) {
+	err = json.Unmarshal(b, &msg)
+	return
+}
+
+type realtimeMsgpackCodec struct{}
+
+// realtimeMsgpackMessage the msgpack's form of a RealtimeMessage
+type realtimeMsgpackMessage struct {
+	Event string             `msgpack:"event"`
+	Room  string             `msgpack:"room,omitempty"`
+	Data  msgpack.RawMessage `msgpack:"data,omitempty"`
+}
+
+func (realtimeMsgpackCodec) Binary() bool {
+	return true
+}
+
+func (realtimeMsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (realtimeMsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+func (realtimeMsgpackCodec) EncodeMessage(msg RealtimeMessage) ([]byte, error) {
+	return msgpack.Marshal(realtimeMsgpackMessage{Event: msg.Event, Room: msg.Room, Data: msgpack.RawMessage(msg.Data)})
+}
+
+func (realtimeMsgpackCodec) DecodeMessage(b []byte) (RealtimeMessage, error) {
+	var m realtimeMsgpackMessage
+	if err := msgpack.Unmarshal(b, &m); err != nil {
+		return RealtimeMessage{}, err
+	}
+	return RealtimeMessage{Event: m.Event, Room: m.Room, Data: json.RawMessage(m.Data)}, nil
+}
+
+type realtimeProtobufCodec struct{}
+
+func (realtimeProtobufCodec) Binary() bool {
+	return true
+}
+
+func (realtimeProtobufCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, errRealtimeProtobufMessage.Format(v)
+	}
+	return proto.Marshal(m)
+}
+
+func (realtimeProtobufCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return errRealtimeProtobufMessage.Format(v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+func (realtimeProtobufCodec) EncodeMessage(msg RealtimeMessage) ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, msg.Event)
+	if msg.Room != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, msg.Room)
+	}
+	if len(msg.Data) > 0 {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, msg.Data)
+	}
+	return b, nil
+}
+
+func (realtimeProtobufCodec) DecodeMessage(b []byte) (msg RealtimeMessage, err error) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return msg, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			// unknown fields of other types are skipped
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return msg, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return msg, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch num {
+		case 1:
+			msg.Event = string(v)
+		case 2:
+			msg.Room = string(v)
+		case 3:
+			msg.Data = append(json.RawMessage(nil), v...)
+		}
+	}
+	return msg, nil
+}
+
+// OnError registers a callback which is fired when a client's message can't be decoded by the server's codec,
+// or the data of an OnValue's event can't be decoded to its type
+func (ws *RealtimeServer) OnError(cb func(c *RealtimeConnection, err error)) {
+	ws.mu.Lock()
+	ws.onError = append(ws.onError, cb)
+	ws.mu.Unlock()
+}
+
+// fireError fires the OnError callbacks
+func (ws *RealtimeServer) fireError(c *RealtimeConnection, err error) {
+	ws.mu.RLock()
+	onError := ws.onError
+	ws.mu.RUnlock()
+	for _, cb := range onError {
+		cb(c, err)
+	}
+}
+
+// OnValue registers a listener for the client's 'event' which receives the message's data decoded to a new value of the 'prototype's type,
+// i.e c.OnValue("chat", ChatMessage{}, func(v interface{}) { msg := v.(*ChatMessage) }),
+// the messages which can't be decoded are not passed to the listener, their errors are passed to the OnError callbacks
+func (c *RealtimeConnection) OnValue(event string, prototype interface{}, cb func(v interface{})) {
+	typ := reflect.TypeOf(prototype)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	c.On(event, func(msg RealtimeMessage) {
+		v := reflect.New(typ).Interface()
+		if err := msg.Decode(v); err != nil {
+			c.server.fireError(c, errRealtimeDecodeData.Format(event, c.id, err.Error()))
+			return
+		}
+		cb(v)
+	})
+}
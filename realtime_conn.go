@@ -0,0 +1,108 @@
+package iris
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// the message types of a WebsocketConn
+const (
+	// WebsocketTextMessage the type of the utf-8 text messages
+	WebsocketTextMessage = websocketOpText
+	// WebsocketBinaryMessage the type of the binary messages
+	WebsocketBinaryMessage = websocketOpBinary
+)
+
+// WebsocketConn is a raw websocket connection, which is returned by the ctx.Upgrade,
+// for the applications which read and write their own messages instead of the RealtimeServer's events and rooms.
+//
+// ReadMessage should be called by one goroutine, WriteMessage is safe for concurrent use.
+type WebsocketConn struct {
+	conn      *websocketConn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Upgrade upgrades the request to a websocket connection, the handshake is validated and its errors are sent by the 'cfg's Error,
+// the only used fields of the RealtimeConfiguration are those of the handshake and the frames:
+// WriteTimeout, PongTimeout, PingPeriod, MaxMessageSize, Error, CheckOrigin and the Compression ones.
+//
+// The connection is hijacked, nothing should be written to the Context after the upgrade,
+// the WebsocketConn keeps working after the handler returns, until it's closed.
+//
+// Usage:
+// app.Get("/echo", func(ctx *iris.Context) {
+//     conn, err := ctx.Upgrade()
+//     if err != nil {
+//         return
+//     }
+//     defer conn.Close()
+//     for {
+//         typ, msg, err := conn.ReadMessage()
+//         if err != nil {
+//             return
+//         }
+//         conn.WriteMessage(typ, msg)
+//     }
+// })
+func (ctx *Context) Upgrade(cfg ...RealtimeConfiguration) (*WebsocketConn, error) {
+	c := mergeRealtimeConfiguration(cfg)
+	conn, status, err := upgradeWebsocket(ctx, c, nil)
+	if err != nil {
+		if status >= StatusInternalServerError {
+			ctx.framework.log(LogLevelError, "websocket upgrade", "remoteAddr", ctx.Request.RemoteAddr, "err", err)
+		}
+		c.Error(ctx, status, err)
+		return nil, err
+	}
+
+	wc := &WebsocketConn{conn: conn, done: make(chan struct{})}
+	if c.PingPeriod > 0 {
+		go wc.pingLoop(c.PingPeriod)
+	}
+	return wc, nil
+}
+
+// pingLoop pings the client until the connection is closed
+func (c *WebsocketConn) pingLoop(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.writeFrame(websocketOpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ReadMessage reads the next message, its type is the WebsocketTextMessage or the WebsocketBinaryMessage,
+// the pings are answered and the fragmented and compressed messages are joined and decompressed.
+// An error means that the connection is closed.
+func (c *WebsocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	messageType, data, err = c.conn.ReadMessage()
+	if err != nil {
+		c.Close()
+	}
+	return
+}
+
+// WriteMessage writes a WebsocketTextMessage or a WebsocketBinaryMessage
+func (c *WebsocketConn) WriteMessage(messageType int, data []byte) error {
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// RemoteAddr returns the network address of the client
+func (c *WebsocketConn) RemoteAddr() net.Addr {
+	return c.conn.conn.RemoteAddr()
+}
+
+// Close sends a normal close frame and closes the connection
+func (c *WebsocketConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.conn.Close()
+}
@@ -0,0 +1,134 @@
+package iris
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// the permessage-deflate extension, RFC 7692
+	websocketDeflateExtension = "permessage-deflate"
+	// the sliding window of the deflate, the 15 max window bits
+	websocketDeflateWindow = 32 << 10
+)
+
+// websocketDeflateTail is appended to a compressed message before its decompression,
+// the removed tail of the sender's sync flush and a final empty block which ends the stream
+var websocketDeflateTail = []byte{0x00, 0x00, 0xff, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff}
+
+// negotiateWebsocketDeflate accepts the first permessage-deflate offer of the client's Sec-WebSocket-Extensions 'header'
+// which can be served and returns the extension's response,
+// if the 'contextTakeover' is false then both sides are asked to compress each message on its own.
+func negotiateWebsocketDeflate(header string, contextTakeover bool) (response string, serverTakeover bool, clientTakeover bool, ok bool) {
+	for _, offer := range strings.Split(header, ",") {
+		params := strings.Split(offer, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), websocketDeflateExtension) {
+			continue
+		}
+
+		serverTakeover, clientTakeover, ok = contextTakeover, contextTakeover, true
+		for _, param := range params[1:] {
+			name, value := strings.TrimSpace(param), ""
+			if idx := strings.IndexByte(name, '='); idx > -1 {
+				name, value = strings.TrimSpace(name[:idx]), strings.Trim(strings.TrimSpace(name[idx+1:]), `"`)
+			}
+			switch strings.ToLower(name) {
+			case "server_no_context_takeover":
+				serverTakeover = false
+			case "client_no_context_takeover":
+				clientTakeover = false
+			case "server_max_window_bits":
+				// the compress/flate uses a window of 15 bits only
+				if bits, err := strconv.Atoi(value); err != nil || bits != 15 {
+					ok = false
+				}
+			case "client_max_window_bits":
+				// any window is decompressed by the 15 bits window
+			default:
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		response = websocketDeflateExtension
+		if !serverTakeover {
+			response += "; server_no_context_takeover"
+		}
+		if !clientTakeover {
+			response += "; client_no_context_takeover"
+		}
+		return
+	}
+	return "", false, false, false
+}
+
+// websocketDeflate compresses and decompresses the messages of a connection which has negotiated the permessage-deflate.
+//
+// The compression's context is kept by not resetting the writer between the messages
+// and the decompression's context by using the last decompressed bytes as the dictionary of the next message.
+type websocketDeflate struct {
+	level          int
+	threshold      int
+	serverTakeover bool
+	clientTakeover bool
+
+	w    *flate.Writer
+	wbuf bytes.Buffer
+	r    io.ReadCloser
+	dict []byte
+}
+
+// compress compresses the message, the result is valid until the next call, the caller should hold the write lock
+func (d *websocketDeflate) compress(data []byte) ([]byte, error) {
+	d.wbuf.Reset()
+	if d.w == nil {
+		w, err := flate.NewWriter(&d.wbuf, d.level)
+		if err != nil {
+			return nil, err
+		}
+		d.w = w
+	} else if !d.serverTakeover {
+		d.w.Reset(&d.wbuf)
+	}
+
+	if _, err := d.w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := d.w.Flush(); err != nil {
+		return nil, err
+	}
+	// remove the 0x00 0x00 0xff 0xff of the sync flush
+	return bytes.TrimSuffix(d.wbuf.Bytes(), websocketDeflateTail[:4]), nil
+}
+
+// decompress decompresses the message, up to 'max'+1 bytes if it's positive, it's called by the reader only
+func (d *websocketDeflate) decompress(data []byte, max int64) ([]byte, error) {
+	src := io.MultiReader(bytes.NewReader(data), bytes.NewReader(websocketDeflateTail))
+	if d.r == nil {
+		d.r = flate.NewReaderDict(src, d.dict)
+	} else if err := d.r.(flate.Resetter).Reset(src, d.dict); err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = d.r
+	if max > 0 {
+		r = io.LimitReader(d.r, max+1)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.clientTakeover {
+		d.dict = append(d.dict, out...)
+		if len(d.dict) > websocketDeflateWindow {
+			d.dict = append(d.dict[:0], d.dict[len(d.dict)-websocketDeflateWindow:]...)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,343 @@
+package iris
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// the websocket protocol, RFC 6455
+const (
+	websocketGUID    = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	websocketVersion = "13"
+
+	websocketOpContinuation = 0x0
+	websocketOpText         = 0x1
+	websocketOpBinary       = 0x2
+	websocketOpClose        = 0x8
+	websocketOpPing         = 0x9
+	websocketOpPong         = 0xA
+
+	websocketCloseNormal          = 1000
+	websocketCloseGoingAway       = 1001
+	websocketCloseProtocolError   = 1002
+	websocketCloseMessageTooLarge = 1009
+
+	websocketMaxControlPayload = 125
+)
+
+var (
+	errWebsocketHandshake       = errors.New("Websocket: bad handshake, %s")
+	errWebsocketOrigin          = errors.New("Websocket: request origin is not allowed")
+	errWebsocketProtocol        = errors.New("Websocket: protocol error, %s")
+	errWebsocketMessageTooLarge = errors.New("Websocket: message is larger than %d bytes")
+	errWebsocketClosed          = errors.New("Websocket: connection is closed")
+)
+
+// websocketAcceptKey returns the Sec-WebSocket-Accept of the client's Sec-WebSocket-Key
+func websocketAcceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+websocketGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken reports whether the comma separated 'header' contains the 'token', case-insensitive
+func headerContainsToken(header string, token string) bool {
+	for _, t := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// websocketConn is a server-side websocket connection over a hijacked net.Conn,
+// it reads the (masked) client's frames and writes unmasked frames.
+//
+// ReadMessage should be called by one goroutine, WriteMessage is safe for concurrent use.
+type websocketConn struct {
+	conn           net.Conn
+	br             *bufio.Reader
+	maxMessageSize int64
+	writeTimeout   time.Duration
+	// the time allowed to read the next frame, zero for no deadline
+	readTimeout time.Duration
+	// nil if the permessage-deflate is not negotiated
+	deflate *websocketDeflate
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// upgradeWebsocket validates the websocket handshake of the request, negotiates the permessage-deflate if the 'config' has Compression,
+// hijacks the connection and writes the 101 Switching Protocols response, on failure it returns the status code which should be sent to the client.
+//
+// The 'accept', if not nil, is called after the handshake is validated and before the connection is hijacked, its error rejects the upgrade.
+func upgradeWebsocket(ctx *Context, config RealtimeConfiguration, accept func() (int, error)) (*websocketConn, int, error) {
+	r := ctx.Request
+	if r.Method != MethodGet {
+		return nil, StatusMethodNotAllowed, errWebsocketHandshake.Format("request method is not GET")
+	}
+	if !headerContainsToken(r.Header.Get("Connection"), "upgrade") {
+		return nil, StatusBadRequest, errWebsocketHandshake.Format("'upgrade' token not found in the 'Connection' header")
+	}
+	if !headerContainsToken(r.Header.Get("Upgrade"), "websocket") {
+		return nil, StatusBadRequest, errWebsocketHandshake.Format("'websocket' token not found in the 'Upgrade' header")
+	}
+	if r.Header.Get("Sec-Websocket-Version") != websocketVersion {
+		ctx.SetHeader("Sec-Websocket-Version", websocketVersion)
+		return nil, StatusUpgradeRequired, errWebsocketHandshake.Format("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		return nil, StatusBadRequest, errWebsocketHandshake.Format("'Sec-WebSocket-Key' header is missing")
+	}
+	if config.CheckOrigin != nil && !config.CheckOrigin(r) {
+		return nil, StatusForbidden, errWebsocketOrigin
+	}
+	if accept != nil {
+		if status, err := accept(); err != nil {
+			return nil, status, err
+		}
+	}
+
+	c := &websocketConn{maxMessageSize: config.MaxMessageSize, writeTimeout: config.WriteTimeout}
+	if config.PingPeriod > 0 {
+		c.readTimeout = config.PongTimeout
+	}
+	extensions := ""
+	if config.Compression {
+		if response, serverTakeover, clientTakeover, ok := negotiateWebsocketDeflate(r.Header.Get("Sec-Websocket-Extensions"), config.CompressionContextTakeover); ok {
+			extensions = "Sec-WebSocket-Extensions: " + response + "\r\n"
+			c.deflate = &websocketDeflate{
+				level:          config.CompressionLevel,
+				threshold:      config.CompressionThreshold,
+				serverTakeover: serverTakeover,
+				clientTakeover: clientTakeover,
+			}
+		}
+	}
+
+	netConn, brw, err := ctx.ResponseWriter.Hijack()
+	if err != nil {
+		return nil, StatusInternalServerError, err
+	}
+	// the server's read/write deadlines, if any, are not valid after the hijack
+	netConn.SetDeadline(time.Time{})
+
+	c.conn, c.br = netConn, brw.Reader
+	if c.br == nil {
+		c.br = bufio.NewReader(netConn)
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAcceptKey(key) + "\r\n" +
+		extensions + "\r\n"
+	if _, err = io.WriteString(netConn, response); err != nil {
+		netConn.Close()
+		return nil, StatusInternalServerError, err
+	}
+
+	return c, StatusSwitchingProtocols, nil
+}
+
+// readFrame reads the next frame, the payload is unmasked,
+// 'compressed' reports the RSV1 bit, which is valid only on the first frame of a message of a permessage-deflate connection
+func (c *websocketConn) readFrame() (fin bool, compressed bool, opcode byte, payload []byte, err error) {
+	if c.readTimeout > 0 {
+		// any frame, a pong too, proves that the client is alive
+		c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+	}
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0f
+	compressed = head[0]&0x40 != 0
+	if compressed && (c.deflate == nil || (opcode != websocketOpText && opcode != websocketOpBinary)) || head[0]&0x30 != 0 {
+		err = c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("unexpected reserved bits"))
+		return
+	}
+	if head[1]&0x80 == 0 {
+		err = c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("client's frame is not masked"))
+		return
+	}
+
+	length := int64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if opcode >= websocketOpClose && (!fin || length > websocketMaxControlPayload) {
+		err = c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("invalid control frame"))
+		return
+	}
+	if length < 0 || (c.maxMessageSize > 0 && length > c.maxMessageSize) {
+		err = c.fail(websocketCloseMessageTooLarge, errWebsocketMessageTooLarge.Format(c.maxMessageSize))
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// ReadMessage reads the next text or binary message, the fragmented messages are joined,
+// the pings are answered and a close frame returns the errWebsocketClosed.
+func (c *websocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	compressedMessage := false
+	for {
+		fin, compressed, opcode, payload, err := c.readFrame()
+		if err != nil {
+			// the protocol errors are already closed with their close code
+			c.closeOnce.Do(func() { c.conn.Close() })
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case websocketOpPing:
+			if err = c.writeFrame(websocketOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case websocketOpPong:
+			continue
+		case websocketOpClose:
+			code := websocketCloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.close(code)
+			return 0, nil, errWebsocketClosed
+		case websocketOpText, websocketOpBinary:
+			if messageType != 0 {
+				return 0, nil, c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("expected a continuation frame"))
+			}
+			messageType = int(opcode)
+			compressedMessage = compressed
+		case websocketOpContinuation:
+			if messageType == 0 {
+				return 0, nil, c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("unexpected continuation frame"))
+			}
+		default:
+			return 0, nil, c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format("unknown opcode"))
+		}
+
+		data = append(data, payload...)
+		if c.maxMessageSize > 0 && int64(len(data)) > c.maxMessageSize {
+			return 0, nil, c.fail(websocketCloseMessageTooLarge, errWebsocketMessageTooLarge.Format(c.maxMessageSize))
+		}
+		if !fin {
+			continue
+		}
+		if compressedMessage {
+			if data, err = c.deflate.decompress(data, c.maxMessageSize); err != nil {
+				return 0, nil, c.fail(websocketCloseProtocolError, errWebsocketProtocol.Format(err.Error()))
+			}
+			if c.maxMessageSize > 0 && int64(len(data)) > c.maxMessageSize {
+				return 0, nil, c.fail(websocketCloseMessageTooLarge, errWebsocketMessageTooLarge.Format(c.maxMessageSize))
+			}
+		}
+		return messageType, data, nil
+	}
+}
+
+// WriteMessage writes a text or binary message as a single frame,
+// on a permessage-deflate connection the messages which are not smaller than the threshold are compressed
+func (c *websocketConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.deflate != nil && len(data) >= c.deflate.threshold {
+		compressed, err := c.deflate.compress(data)
+		if err != nil {
+			return err
+		}
+		return c.writeFrameLocked(byte(messageType), true, compressed)
+	}
+	return c.writeFrameLocked(byte(messageType), false, data)
+}
+
+// writeFrame writes a final, uncompressed, frame
+func (c *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.writeFrameLocked(opcode, false, payload)
+}
+
+// writeFrameLocked writes a final, unmasked, frame, the caller should hold the write lock
+func (c *websocketConn) writeFrameLocked(opcode byte, compressed bool, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+10)
+	if compressed {
+		opcode |= 0x40
+	}
+	frame = append(frame, 0x80|opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	if c.writeTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// fail closes the connection with the close 'code' and returns the 'err'
+func (c *websocketConn) fail(code int, err error) error {
+	c.close(code)
+	return err
+}
+
+// close sends a close frame with the 'code' and closes the underline connection, once
+func (c *websocketConn) close(code int) {
+	c.closeOnce.Do(func() {
+		payload := make([]byte, 2)
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		c.writeFrame(websocketOpClose, payload)
+		c.conn.Close()
+	})
+}
+
+// Close sends a normal close frame and closes the connection
+func (c *websocketConn) Close() error {
+	c.close(websocketCloseNormal)
+	return nil
+}
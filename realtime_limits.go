@@ -0,0 +1,124 @@
+package iris
+
+import (
+	"sync/atomic"
+
+	"github.com/kataras/go-errors"
+)
+
+// RealtimeSlowConsumerPolicy decides what happens to a message when the send queue of its connection is full,
+// see the RealtimeConfiguration's SlowConsumer
+type RealtimeSlowConsumerPolicy int
+
+const (
+	// RealtimeSlowConsumerClose closes the connection which can't keep up with its messages, it's the default policy
+	RealtimeSlowConsumerClose RealtimeSlowConsumerPolicy = iota
+	// RealtimeSlowConsumerDropNewest drops the new message, the queued ones are kept
+	RealtimeSlowConsumerDropNewest
+	// RealtimeSlowConsumerDropOldest drops the oldest queued message to make room for the new one
+	RealtimeSlowConsumerDropOldest
+)
+
+var (
+	errRealtimeTooManyConnections      = errors.New("Realtime: the server has reached its limit of %d connections")
+	errRealtimeTooManyConnectionsForIP = errors.New("Realtime: '%s' has reached the limit of %d connections per ip")
+	errRealtimeMessageDropped          = errors.New("Realtime: the send queue of connection '%s' is full, the message is dropped")
+)
+
+// RealtimeMetrics keeps the statistics of a RealtimeServer, look its Metrics
+type RealtimeMetrics struct {
+	// Connections the number of the connected clients
+	Connections int
+	// QueuedMessages the number of the messages which wait to be sent, of all the connections
+	QueuedMessages int
+	// MaxQueueDepth the number of the queued messages of the slowest connection
+	MaxQueueDepth int
+	// Rejected the number of the connections which are rejected because of the MaxConnections or the MaxConnectionsPerIP
+	Rejected uint64
+	// Dropped the number of the messages which are dropped because of the slow consumers
+	Dropped uint64
+	// SlowClosed the number of the connections which are closed because they couldn't keep up with their messages
+	SlowClosed uint64
+}
+
+// Metrics returns the current statistics of the server, the connections and the depth of their send queues
+func (ws *RealtimeServer) Metrics() RealtimeMetrics {
+	m := RealtimeMetrics{
+		Rejected:   atomic.LoadUint64(&ws.rejected),
+		Dropped:    atomic.LoadUint64(&ws.dropped),
+		SlowClosed: atomic.LoadUint64(&ws.slowClosed),
+	}
+	ws.mu.RLock()
+	m.Connections = len(ws.connections)
+	for _, c := range ws.connections {
+		depth := len(c.queue)
+		m.QueuedMessages += depth
+		if depth > m.MaxQueueDepth {
+			m.MaxQueueDepth = depth
+		}
+	}
+	ws.mu.RUnlock()
+	return m
+}
+
+// reserve takes a place for a new connection of the client,
+// it returns the client's ip or the status code and the reason of the rejection when a limit is reached
+func (ws *RealtimeServer) reserve(ctx *Context) (string, int, error) {
+	ip := ctx.connIP()
+	max, maxPerIP := ws.config.MaxConnections, ws.config.MaxConnectionsPerIP
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if max > 0 && ws.reserved >= max {
+		atomic.AddUint64(&ws.rejected, 1)
+		return "", StatusServiceUnavailable, errRealtimeTooManyConnections.Format(max)
+	}
+	if maxPerIP > 0 && ws.ips[ip] >= maxPerIP {
+		atomic.AddUint64(&ws.rejected, 1)
+		return "", StatusTooManyRequests, errRealtimeTooManyConnectionsForIP.Format(ip, maxPerIP)
+	}
+	ws.reserved++
+	ws.ips[ip]++
+	return ip, StatusOK, nil
+}
+
+// release frees the place of a connection of the 'ip', the caller should hold the lock
+func (ws *RealtimeServer) release(ip string) {
+	ws.reserved--
+	if ws.ips[ip]--; ws.ips[ip] <= 0 {
+		delete(ws.ips, ip)
+	}
+}
+
+// QueueLen returns the number of the messages which wait to be sent to this connection
+func (c *RealtimeConnection) QueueLen() int {
+	return len(c.queue)
+}
+
+// overflow applies the SlowConsumer policy on a message which doesn't fit in the full send queue
+func (c *RealtimeConnection) overflow(payload []byte) error {
+	ws := c.server
+	switch ws.config.SlowConsumer {
+	case RealtimeSlowConsumerDropOldest:
+		select {
+		case <-c.queue:
+			atomic.AddUint64(&ws.dropped, 1)
+		default:
+		}
+		select {
+		case c.queue <- payload:
+			return nil
+		default:
+		}
+		fallthrough
+	case RealtimeSlowConsumerDropNewest:
+		atomic.AddUint64(&ws.dropped, 1)
+		return errRealtimeMessageDropped.Format(c.id)
+	default:
+		c.Close()
+		atomic.AddUint64(&ws.slowClosed, 1)
+		err := errRealtimeSlowConnection.Format(c.id)
+		ws.station.log(LogLevelWarn, err.Error())
+		return err
+	}
+}
@@ -0,0 +1,199 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// the url parameter which keeps the id of a long-polling connection
+const realtimePollingIDParam = "sid"
+
+var (
+	errRealtimePollingNotFound = errors.New("Realtime: long-polling connection '%s' not found")
+	errRealtimePollingMethod   = errors.New("Realtime: long-polling doesn't accept the '%s' method")
+	errRealtimePollingMessage  = errors.New("Realtime: long-polling message is invalid. Trace: %s")
+)
+
+// LongPollHandler returns the handler of the http long-polling transport, for the clients which can't use websockets,
+// i.e behind restrictive proxies, it should be registered to any method, i.e app.Any("/ws/poll", ws.LongPollHandler()).
+//
+// The long-polling connections have the same events, rooms and callbacks as the websocket connections:
+// a POST without the 'sid' url parameter opens a connection and responds its id as {"sid": "..."},
+// a GET ?sid=... waits, up to the LongPollHoldTimeout, for the connection's messages and responds them as a json array,
+// a POST ?sid=... sends a message, or a json array of messages, to the server
+// and a DELETE ?sid=... closes the connection.
+//
+// An unknown or closed connection is answered with 404, the client should open a new one.
+// The connections which are not polled for the LongPollSessionTimeout are closed.
+func (ws *RealtimeServer) LongPollHandler() HandlerFunc {
+	return func(ctx *Context) {
+		sid := ctx.URLParam(realtimePollingIDParam)
+		if sid == "" {
+			if ctx.Method() != MethodPost {
+				ws.config.Error(ctx, StatusMethodNotAllowed, errRealtimePollingMethod.Format(ctx.Method()))
+				return
+			}
+			ws.openPolling(ctx)
+			return
+		}
+
+		c := ws.Connection(sid)
+		if c == nil || c.transport != RealtimeTransportPolling {
+			ws.config.Error(ctx, StatusNotFound, errRealtimePollingNotFound.Format(sid))
+			return
+		}
+
+		switch ctx.Method() {
+		case MethodGet:
+			c.poll(ctx)
+		case MethodPost:
+			c.receive(ctx)
+		case MethodDelete:
+			c.Close()
+			ctx.SetStatusCode(StatusNoContent)
+		default:
+			ws.config.Error(ctx, StatusMethodNotAllowed, errRealtimePollingMethod.Format(ctx.Method()))
+		}
+	}
+}
+
+// openPolling opens a new long-polling connection and responds its id
+func (ws *RealtimeServer) openPolling(ctx *Context) {
+	if _, ok := ws.config.Codec.(realtimeJSONCodec); !ok {
+		ws.config.Error(ctx, StatusNotImplemented, errRealtimePollingCodec)
+		return
+	}
+	if ws.config.CheckOrigin != nil && !ws.config.CheckOrigin(ctx.Request) {
+		ws.config.Error(ctx, StatusForbidden, errWebsocketOrigin)
+		return
+	}
+	ip, status, err := ws.reserve(ctx)
+	if err != nil {
+		ws.config.Error(ctx, status, err)
+		return
+	}
+	identity, status, err := ws.authorize(ctx)
+	if err != nil {
+		ws.mu.Lock()
+		ws.release(ip)
+		ws.mu.Unlock()
+		ws.config.Error(ctx, status, err)
+		return
+	}
+
+	c := newRealtimeConnection(ws, RealtimeTransportPolling, nil, ctx.Request)
+	c.identity = identity
+	c.ip = ip
+	go c.pollLoop(ws.config.LongPollSessionTimeout)
+	ws.connect(c)
+
+	ctx.SetHeader("Cache-Control", "no-store")
+	ctx.SetContentType(contentJSON)
+	ctx.WriteString(`{"` + realtimePollingIDParam + `":"` + c.id + `"}`)
+}
+
+// touch signals the session's watcher that the client is still there
+func (c *RealtimeConnection) touch() {
+	select {
+	case c.polled <- struct{}{}:
+	default:
+	}
+}
+
+// pollLoop closes the long-polling connection when it's not polled for the 'timeout'
+// and removes it from the server when it's closed
+func (c *RealtimeConnection) pollLoop(timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-c.done:
+			c.server.disconnect(c)
+			return
+		case <-c.polled:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			c.Close()
+		}
+	}
+}
+
+// poll waits for the queued messages, up to the LongPollHoldTimeout, and responds them as a json array
+func (c *RealtimeConnection) poll(ctx *Context) {
+	c.touch()
+	defer c.touch()
+
+	hold := time.NewTimer(c.server.config.LongPollHoldTimeout)
+	defer hold.Stop()
+
+	var messages [][]byte
+	select {
+	case payload := <-c.queue:
+		messages = append(messages, payload)
+	drain:
+		for {
+			select {
+			case payload = <-c.queue:
+				messages = append(messages, payload)
+			default:
+				break drain
+			}
+		}
+	case <-hold.C:
+	case <-c.done:
+		c.server.config.Error(ctx, StatusNotFound, errRealtimePollingNotFound.Format(c.id))
+		return
+	case <-ctx.Request.Context().Done():
+		// the client is gone, keep the messages for the next poll
+		return
+	}
+
+	ctx.SetHeader("Cache-Control", "no-store")
+	ctx.SetContentType(contentJSON)
+	ctx.WriteString("[")
+	ctx.Write(bytes.Join(messages, []byte(",")))
+	ctx.WriteString("]")
+}
+
+// receive dispatches the message, or the json array of messages, of the request's body
+func (c *RealtimeConnection) receive(ctx *Context) {
+	c.touch()
+
+	max := c.server.config.MaxMessageSize
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, max+1))
+	if err != nil {
+		c.server.config.Error(ctx, StatusBadRequest, errRealtimePollingMessage.Format(err.Error()))
+		return
+	}
+	if int64(len(body)) > max {
+		c.server.config.Error(ctx, StatusRequestEntityTooLarge, errWebsocketMessageTooLarge.Format(max))
+		return
+	}
+
+	var messages []RealtimeMessage
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &messages)
+	} else {
+		messages = make([]RealtimeMessage, 1)
+		err = json.Unmarshal(body, &messages[0])
+	}
+	if err != nil {
+		c.server.config.Error(ctx, StatusBadRequest, errRealtimePollingMessage.Format(err.Error()))
+		return
+	}
+
+	for _, msg := range messages {
+		c.dispatch(msg)
+	}
+	ctx.SetStatusCode(StatusNoContent)
+}
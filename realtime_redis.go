@@ -0,0 +1,74 @@
+package iris
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisBackplaneChannel the default pub/sub channel of the RedisBackplane
+const DefaultRedisBackplaneChannel = "iris-realtime"
+
+// RedisBackplane is the Redis pub/sub RealtimeBackplane,
+// all the instances should use the same Redis server, or cluster, and the same channel.
+//
+// Usage:
+// client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+// ws.UseBackplane(iris.NewRedisBackplane(client, ""))
+type RedisBackplane struct {
+	client  redis.UniversalClient
+	channel string
+
+	pubsub *redis.PubSub
+	mu     sync.Mutex
+}
+
+var _ RealtimeBackplane = &RedisBackplane{}
+
+// NewRedisBackplane returns a new Redis pub/sub backplane, an empty 'channel' means the DefaultRedisBackplaneChannel
+func NewRedisBackplane(client redis.UniversalClient, channel string) *RedisBackplane {
+	if channel == "" {
+		channel = DefaultRedisBackplaneChannel
+	}
+	return &RedisBackplane{client: client, channel: channel}
+}
+
+// Publish publishes the message to the backplane's channel
+func (b *RedisBackplane) Publish(msg []byte) error {
+	return b.client.Publish(context.Background(), b.channel, msg).Err()
+}
+
+// Subscribe subscribes to the backplane's channel, it returns when the subscription is confirmed by the server
+// and the 'receive' is called by another goroutine for each message, the reconnections are handled by the client
+func (b *RedisBackplane) Subscribe(receive func(msg []byte)) error {
+	ctx := context.Background()
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	b.mu.Lock()
+	b.pubsub = pubsub
+	b.mu.Unlock()
+
+	go func() {
+		for m := range pubsub.Channel() {
+			receive([]byte(m.Payload))
+		}
+	}()
+	return nil
+}
+
+// Close closes the subscription, the client is not closed
+func (b *RedisBackplane) Close() error {
+	b.mu.Lock()
+	pubsub := b.pubsub
+	b.pubsub = nil
+	b.mu.Unlock()
+	if pubsub == nil {
+		return nil
+	}
+	return pubsub.Close()
+}
@@ -0,0 +1,682 @@
+// Black-box Testing
+package iris_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	nethttptest "net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// testWebsocketClient is a minimal websocket client, it writes masked text frames and reads the server's messages
+type testWebsocketClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+	// the permessage-deflate's response, the client doesn't keep its compression context
+	extensions    string
+	dict          []byte
+	lastFrameSize int
+}
+
+func dialTestWebsocket(t *testing.T, srv *nethttptest.Server, path string, extensions ...string) *testWebsocketClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, 16)
+	rand.Read(key)
+	io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: "+srv.Listener.Addr().String()+
+		"\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: "+
+		base64.StdEncoding.EncodeToString(key)+"\r\n"+strings.Join(extensions, "")+"\r\n")
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != iris.StatusSwitchingProtocols {
+		t.Fatalf("expected status %d but got %d", iris.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	return &testWebsocketClient{conn: conn, br: br, extensions: resp.Header.Get("Sec-Websocket-Extensions")}
+}
+
+func (c *testWebsocketClient) emit(t *testing.T, event string, room string, data interface{}) {
+	b, _ := json.Marshal(data)
+	payload, _ := json.Marshal(iris.RealtimeMessage{Event: event, Room: room, Data: b})
+	c.writeFrame(t, 0x1, payload)
+}
+
+// writeFrame writes a masked, final, frame of the 'opcode'
+func (c *testWebsocketClient) writeFrame(t *testing.T, opcode byte, payload []byte) {
+	head := 0x80 | opcode
+	if c.extensions != "" {
+		var buf bytes.Buffer
+		w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+		w.Write(payload)
+		w.Flush()
+		payload = bytes.TrimSuffix(buf.Bytes(), []byte{0, 0, 0xff, 0xff})
+		head |= 0x40
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{head, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i := range payload {
+		frame = append(frame, payload[i]^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testWebsocketClient) read(t *testing.T) iris.RealtimeMessage {
+	_, payload := c.readFrame(t)
+	var msg iris.RealtimeMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatalf("%s: %q", err, payload)
+	}
+	return msg
+}
+
+// readFrame reads a server's frame, it returns its opcode and its, decompressed, payload
+func (c *testWebsocketClient) readFrame(t *testing.T) (byte, []byte) {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, head); err != nil {
+		t.Fatal(err)
+	}
+	length := int(head[1] & 0x7f)
+	if length == 126 {
+		ext := make([]byte, 2)
+		io.ReadFull(c.br, ext)
+		length = int(binary.BigEndian.Uint16(ext))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	if head[0]&0x40 != 0 {
+		r := flate.NewReaderDict(io.MultiReader(bytes.NewReader(payload), bytes.NewReader([]byte{0, 0, 0xff, 0xff, 1, 0, 0, 0xff, 0xff})), c.dict)
+		var err error
+		if payload, err = io.ReadAll(r); err != nil {
+			t.Fatal(err)
+		}
+		c.dict = append(c.dict, payload...)
+	}
+	c.lastFrameSize = length
+	return head[0] & 0x0f, payload
+}
+
+func TestRealtimeRooms(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{})
+	disconnected := make(chan string, 2)
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.On("join", func(msg iris.RealtimeMessage) {
+			var room string
+			msg.Decode(&room)
+			c.Join(room)
+			c.Emit("joined", room)
+		})
+		c.On("chat", func(msg iris.RealtimeMessage) {
+			c.To(msg.Room).Emit("chat", msg.Data)
+		})
+	})
+	ws.OnDisconnect(func(c *iris.RealtimeConnection) {
+		disconnected <- c.ID()
+	})
+	app.Get("/ws", ws.Handler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	// not an upgrade request
+	resp, err := http.Get(srv.URL + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != iris.StatusBadRequest {
+		t.Fatalf("expected status %d but got %d", iris.StatusBadRequest, resp.StatusCode)
+	}
+
+	a := dialTestWebsocket(t, srv, "/ws")
+	b := dialTestWebsocket(t, srv, "/ws")
+	defer b.conn.Close()
+
+	for _, c := range []*testWebsocketClient{a, b} {
+		c.emit(t, "join", "", "lobby")
+		if msg := c.read(t); msg.Event != "joined" || string(msg.Data) != `"lobby"` {
+			t.Fatalf("unexpected message %#v", msg)
+		}
+	}
+	if ws.Len() != 2 || ws.To("lobby").Len() != 2 {
+		t.Fatalf("expected 2 connections in the lobby but got %d(%d)", ws.To("lobby").Len(), ws.Len())
+	}
+
+	// the sender is excluded
+	a.emit(t, "chat", "lobby", "hello")
+	if msg := b.read(t); msg.Event != "chat" || msg.Room != "lobby" || string(msg.Data) != `"hello"` {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+
+	ws.Broadcast("news", iris.Map{"title": "iris"})
+	for _, c := range []*testWebsocketClient{a, b} {
+		if msg := c.read(t); msg.Event != "news" || string(msg.Data) != `{"title":"iris"}` {
+			t.Fatalf("unexpected message %#v", msg)
+		}
+	}
+
+	a.conn.Close()
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnDisconnect is not fired")
+	}
+	if ws.Len() != 1 || ws.To("lobby").Len() != 1 {
+		t.Fatalf("expected 1 connection in the lobby but got %d(%d)", ws.To("lobby").Len(), ws.Len())
+	}
+}
+
+func TestRealtimeLongPolling(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{LongPollHoldTimeout: 200 * time.Millisecond})
+	disconnected := make(chan string, 1)
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.Join("lobby")
+		c.On("chat", func(msg iris.RealtimeMessage) {
+			c.To("lobby").Emit("chat", msg.Data)
+		})
+	})
+	ws.OnDisconnect(func(c *iris.RealtimeConnection) {
+		if c.Transport() == iris.RealtimeTransportPolling {
+			disconnected <- c.ID()
+		}
+	})
+	app.Get("/ws", ws.Handler())
+	app.Any("/ws/poll", ws.LongPollHandler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	do := func(method string, url string, body string) (int, []byte) {
+		req, _ := http.NewRequest(method, srv.URL+url, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, b
+	}
+	poll := func(sid string) []iris.RealtimeMessage {
+		status, b := do(iris.MethodGet, "/ws/poll?sid="+sid, "")
+		if status != iris.StatusOK {
+			t.Fatalf("expected status %d but got %d", iris.StatusOK, status)
+		}
+		var messages []iris.RealtimeMessage
+		if err := json.Unmarshal(b, &messages); err != nil {
+			t.Fatalf("%s: %q", err, b)
+		}
+		return messages
+	}
+
+	status, b := do(iris.MethodPost, "/ws/poll", "")
+	var opened struct {
+		SID string `json:"sid"`
+	}
+	if err := json.Unmarshal(b, &opened); status != iris.StatusOK || err != nil || opened.SID == "" {
+		t.Fatalf("unexpected open response %d %q", status, b)
+	}
+	sid := opened.SID
+
+	w := dialTestWebsocket(t, srv, "/ws")
+	defer w.conn.Close()
+
+	// from the long-polling client to the websocket client
+	if status, _ = do(iris.MethodPost, "/ws/poll?sid="+sid, `{"event":"chat","room":"lobby","data":"hi"}`); status != iris.StatusNoContent {
+		t.Fatalf("expected status %d but got %d", iris.StatusNoContent, status)
+	}
+	if msg := w.read(t); msg.Event != "chat" || string(msg.Data) != `"hi"` {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+
+	// from the websocket client to the long-polling client, queued until the poll
+	w.emit(t, "chat", "lobby", "hello")
+	time.Sleep(50 * time.Millisecond)
+	ws.Broadcast("news", "iris")
+	if messages := poll(sid); len(messages) != 2 || string(messages[0].Data) != `"hello"` || messages[1].Event != "news" {
+		t.Fatalf("unexpected messages %#v", messages)
+	}
+	// nothing to receive, answered after the hold timeout
+	if messages := poll(sid); len(messages) != 0 {
+		t.Fatalf("expected no messages but got %#v", messages)
+	}
+
+	if status, _ = do(iris.MethodDelete, "/ws/poll?sid="+sid, ""); status != iris.StatusNoContent {
+		t.Fatalf("expected status %d but got %d", iris.StatusNoContent, status)
+	}
+	select {
+	case id := <-disconnected:
+		if id != sid {
+			t.Fatalf("expected %s to be disconnected but got %s", sid, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnDisconnect is not fired")
+	}
+	if status, _ = do(iris.MethodGet, "/ws/poll?sid="+sid, ""); status != iris.StatusNotFound {
+		t.Fatalf("expected status %d but got %d", iris.StatusNotFound, status)
+	}
+}
+
+func TestRealtimeCompression(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{Compression: true, CompressionContextTakeover: true, CompressionThreshold: 64})
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.On("echo", func(msg iris.RealtimeMessage) {
+			c.Emit("echo", msg.Data)
+		})
+	})
+	app.Get("/ws", ws.Handler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	// not offered
+	plain := dialTestWebsocket(t, srv, "/ws")
+	plain.conn.Close()
+	if plain.extensions != "" {
+		t.Fatalf("expected no extensions but got %q", plain.extensions)
+	}
+
+	c := dialTestWebsocket(t, srv, "/ws", "Sec-WebSocket-Extensions: permessage-deflate; client_no_context_takeover; client_max_window_bits\r\n")
+	defer c.conn.Close()
+	if expected := "permessage-deflate; client_no_context_takeover"; c.extensions != expected {
+		t.Fatalf("expected extensions %q but got %q", expected, c.extensions)
+	}
+
+	// smaller than the threshold, not compressed
+	c.emit(t, "echo", "", "hi")
+	if msg := c.read(t); string(msg.Data) != `"hi"` || c.lastFrameSize != len(`{"event":"echo","data":"hi"}`) {
+		t.Fatalf("unexpected message %#v of %d bytes", msg, c.lastFrameSize)
+	}
+
+	large := strings.Repeat("iris realtime ", 100)
+	c.emit(t, "echo", "", large)
+	msg := c.read(t)
+	if string(msg.Data) != `"`+large+`"` {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	first := c.lastFrameSize
+	if first >= len(large) {
+		t.Fatalf("expected a compressed message but got %d bytes", first)
+	}
+	// the context is kept, the same message is compressed better
+	c.emit(t, "echo", "", large)
+	if msg = c.read(t); string(msg.Data) != `"`+large+`"` || c.lastFrameSize >= first {
+		t.Fatalf("unexpected message of %d bytes, the first was %d bytes", c.lastFrameSize, first)
+	}
+}
+
+func TestRealtimeUpgradeAuthorization(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{})
+	ws.OnUpgrade(func(ctx *iris.Context) (interface{}, error) {
+		switch ctx.URLParam("token") {
+		case "":
+			return nil, errors.New("token is missing")
+		case "secret":
+			return "kataras", nil
+		default:
+			return nil, &iris.RealtimeUpgradeError{Status: iris.StatusForbidden}
+		}
+	})
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.Emit("welcome", c.Identity())
+	})
+	app.Get("/ws", ws.Handler())
+	app.Any("/ws/poll", ws.LongPollHandler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	for token, expected := range map[string]int{"": iris.StatusUnauthorized, "invalid": iris.StatusForbidden} {
+		req, _ := http.NewRequest(iris.MethodGet, srv.URL+"/ws?token="+token, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Fatalf("expected status %d for the token %q but got %d", expected, token, resp.StatusCode)
+		}
+
+		// the long-polling transport is authorized the same way
+		if resp, err = http.Post(srv.URL+"/ws/poll?token="+token, "application/json", nil); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Fatalf("expected long-polling status %d for the token %q but got %d", expected, token, resp.StatusCode)
+		}
+	}
+	if ws.Len() != 0 {
+		t.Fatalf("expected no connections but got %d", ws.Len())
+	}
+
+	c := dialTestWebsocket(t, srv, "/ws?token=secret")
+	defer c.conn.Close()
+	if msg := c.read(t); msg.Event != "welcome" || string(msg.Data) != `"kataras"` {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+}
+
+// testBackplane is an in-memory backplane, the messages are published to all of its subscribers
+type testBackplane struct {
+	subscribers []func([]byte)
+	mu          sync.Mutex
+}
+
+func (b *testBackplane) Publish(msg []byte) error {
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.mu.Unlock()
+	for _, receive := range subscribers {
+		go receive(msg)
+	}
+	return nil
+}
+
+func (b *testBackplane) Subscribe(receive func([]byte)) error {
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, receive)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *testBackplane) Close() error {
+	return nil
+}
+
+func TestRealtimeBackplane(t *testing.T) {
+	backplane := &testBackplane{}
+	var clients []*testWebsocketClient
+	var servers []*iris.RealtimeServer
+	// two instances of the same application
+	for i := 0; i < 2; i++ {
+		app := iris.New()
+		ws := app.NewWebsocketServer(iris.RealtimeConfiguration{})
+		if err := ws.UseBackplane(backplane); err != nil {
+			t.Fatal(err)
+		}
+		ws.OnConnect(func(c *iris.RealtimeConnection) {
+			c.Join("lobby")
+			c.On("chat", func(msg iris.RealtimeMessage) {
+				c.To("lobby").Emit("chat", msg.Data)
+			})
+			c.Emit("joined", nil)
+		})
+		app.Get("/ws", ws.Handler())
+		app.Build()
+
+		srv := nethttptest.NewServer(app.Router)
+		defer srv.Close()
+		c := dialTestWebsocket(t, srv, "/ws")
+		defer c.conn.Close()
+		c.read(t)
+		clients = append(clients, c)
+		servers = append(servers, ws)
+	}
+
+	// a room's emit reaches the other instance, the sender is excluded
+	clients[0].emit(t, "chat", "lobby", "hello")
+	if msg := clients[1].read(t); msg.Event != "chat" || msg.Room != "lobby" || string(msg.Data) != `"hello"` {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+
+	// a broadcast is received once by all the instances' connections
+	servers[1].Broadcast("news", "iris")
+	servers[0].Broadcast("news", "iris 2")
+	for _, c := range clients {
+		first, second := c.read(t), c.read(t)
+		if first.Event != "news" || second.Event != "news" || string(first.Data) == string(second.Data) {
+			t.Fatalf("unexpected messages %#v %#v", first, second)
+		}
+	}
+}
+
+func TestRealtimeLimits(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{
+		MaxConnectionsPerIP: 1,
+		SendQueueSize:       1,
+		SlowConsumer:        iris.RealtimeSlowConsumerDropNewest,
+		LongPollHoldTimeout: 200 * time.Millisecond,
+	})
+	disconnected := make(chan struct{}, 1)
+	ws.OnDisconnect(func(c *iris.RealtimeConnection) {
+		disconnected <- struct{}{}
+	})
+	app.Any("/ws/poll", ws.LongPollHandler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	do := func(method string, url string) (int, []byte) {
+		req, _ := http.NewRequest(method, srv.URL+url, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, b
+	}
+	open := func(expected int) string {
+		status, b := do(iris.MethodPost, "/ws/poll")
+		if status != expected {
+			t.Fatalf("expected status %d but got %d", expected, status)
+		}
+		var opened struct {
+			SID string `json:"sid"`
+		}
+		json.Unmarshal(b, &opened)
+		return opened.SID
+	}
+
+	sid := open(iris.StatusOK)
+	// the same ip
+	open(iris.StatusTooManyRequests)
+
+	// the long-polling connection is not polled, its queue keeps only the first message
+	for i := 1; i <= 3; i++ {
+		ws.Broadcast("count", i)
+	}
+	m := ws.Metrics()
+	if m.Connections != 1 || m.QueuedMessages != 1 || m.MaxQueueDepth != 1 || m.Dropped != 2 || m.Rejected != 1 || m.SlowClosed != 0 {
+		t.Fatalf("unexpected metrics %#v", m)
+	}
+
+	_, b := do(iris.MethodGet, "/ws/poll?sid="+sid)
+	var messages []iris.RealtimeMessage
+	if err := json.Unmarshal(b, &messages); err != nil || len(messages) != 1 || string(messages[0].Data) != "1" {
+		t.Fatalf("unexpected messages %q", b)
+	}
+
+	// the place of a closed connection is released
+	do(iris.MethodDelete, "/ws/poll?sid="+sid)
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnDisconnect is not fired")
+	}
+	open(iris.StatusOK)
+}
+
+func TestRealtimeCodec(t *testing.T) {
+	type point struct {
+		X int `msgpack:"x"`
+		Y int `msgpack:"y"`
+	}
+
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{Codec: iris.RealtimeMsgpack})
+	errs := make(chan error, 1)
+	ws.OnError(func(c *iris.RealtimeConnection, err error) {
+		errs <- err
+	})
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.OnValue("move", point{}, func(v interface{}) {
+			p := v.(*point)
+			c.Emit("moved", point{X: p.X + 1, Y: p.Y + 1})
+		})
+	})
+	app.Get("/ws", ws.Handler())
+	app.Any("/ws/poll", ws.LongPollHandler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	c := dialTestWebsocket(t, srv, "/ws")
+	defer c.conn.Close()
+
+	data, _ := msgpack.Marshal(point{X: 1, Y: 2})
+	payload, _ := msgpack.Marshal(map[string]interface{}{"event": "move", "data": msgpack.RawMessage(data)})
+	c.writeFrame(t, 0x2, payload)
+
+	opcode, payload := c.readFrame(t)
+	if opcode != 0x2 {
+		t.Fatalf("expected a binary frame but got opcode %d", opcode)
+	}
+	var msg struct {
+		Event string             `msgpack:"event"`
+		Data  msgpack.RawMessage `msgpack:"data"`
+	}
+	var p point
+	if err := msgpack.Unmarshal(payload, &msg); err != nil || msg.Event != "moved" {
+		t.Fatalf("unexpected message %q: %v", payload, err)
+	}
+	if err := msgpack.Unmarshal(msg.Data, &p); err != nil || p.X != 2 || p.Y != 3 {
+		t.Fatalf("unexpected data %#v: %v", p, err)
+	}
+
+	// the data doesn't match the event's type
+	data, _ = msgpack.Marshal("not a point")
+	payload, _ = msgpack.Marshal(map[string]interface{}{"event": "move", "data": msgpack.RawMessage(data)})
+	c.writeFrame(t, 0x2, payload)
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "'move'") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError is not fired")
+	}
+
+	// the long-polling transport speaks json only
+	resp, err := http.Post(srv.URL+"/ws/poll", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != iris.StatusNotImplemented {
+		t.Fatalf("expected status %d but got %d", iris.StatusNotImplemented, resp.StatusCode)
+	}
+}
+
+func TestRealtimeHeartbeat(t *testing.T) {
+	app := iris.New()
+	ws := app.NewWebsocketServer(iris.RealtimeConfiguration{PongTimeout: 300 * time.Millisecond, PingPeriod: 100 * time.Millisecond})
+	disconnected := make(chan struct{}, 1)
+	ws.OnConnect(func(c *iris.RealtimeConnection) {
+		c.Join("lobby")
+	})
+	ws.OnDisconnect(func(c *iris.RealtimeConnection) {
+		disconnected <- struct{}{}
+	})
+	app.Get("/ws", ws.Handler())
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	c := dialTestWebsocket(t, srv, "/ws")
+	defer c.conn.Close()
+	if opcode, _ := c.readFrame(t); opcode != 0x9 {
+		t.Fatalf("expected a ping frame but got opcode %d", opcode)
+	}
+
+	// the client doesn't answer, it's reaped after the pong timeout
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dead connection is not reaped")
+	}
+	if ws.Len() != 0 || len(ws.Rooms()) != 0 {
+		t.Fatalf("expected no connections and rooms but got %d and %v", ws.Len(), ws.Rooms())
+	}
+}
+
+func TestContextUpgrade(t *testing.T) {
+	app := iris.New()
+	app.Get("/echo", func(ctx *iris.Context) {
+		conn, err := ctx.Upgrade()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			typ, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(typ, append([]byte("echo: "), msg...))
+		}
+	})
+	app.Build()
+
+	srv := nethttptest.NewServer(app.Router)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != iris.StatusBadRequest {
+		t.Fatalf("expected status %d but got %d", iris.StatusBadRequest, resp.StatusCode)
+	}
+
+	c := dialTestWebsocket(t, srv, "/echo")
+	defer c.conn.Close()
+	for _, opcode := range []byte{iris.WebsocketTextMessage, iris.WebsocketBinaryMessage} {
+		c.writeFrame(t, opcode, []byte("hello"))
+		if typ, payload := c.readFrame(t); typ != opcode || string(payload) != "echo: hello" {
+			t.Fatalf("unexpected frame %d %q", typ, payload)
+		}
+	}
+}
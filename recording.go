@@ -0,0 +1,253 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+const (
+	// DefaultRecordingsDir the default directory of the files of the RecordRequests and the Tester's Replay
+	DefaultRecordingsDir = "testdata/recordings"
+	// replayHeader marks the requests of the Tester's Replay, they're not recorded again
+	replayHeader = "X-Iris-Replay"
+)
+
+var errRecordWrite = errors.New("RecordRequests: unable to write the recording '%s'. Trace: %s")
+
+// Recording a request and its response which are recorded by the RecordRequests, one json file for each
+type Recording struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest the request of a Recording
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse the response of a Recording
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordOptions the options of the RecordRequests
+type RecordOptions struct {
+	// Dir the directory of the recordings
+	// Defaults to the DefaultRecordingsDir, "testdata/recordings"
+	Dir string
+	// Filter if not nil then only the requests which it returns true for are recorded, i.e a sample of them
+	// Defaults to nil, all the requests
+	Filter func(ctx *Context) bool
+	// RedactHeaders the headers whose values are replaced by [REDACTED]
+	// Defaults to the DefaultDumpRedactHeaders
+	RedactHeaders []string
+	// RedactFields the json and form fields, of the bodies and the url, whose values are replaced by [REDACTED]
+	// Defaults to the DefaultDumpRedactFields
+	RedactFields []string
+}
+
+// RecordRequests returns a middleware which records the requests and their responses, with their secrets redacted,
+// to json files of the options' Dir, which are replayed by the Tester's Replay as regression tests, the replayed requests are not recorded.
+// The bodies are recorded as text, the recordings should be used for the text apis, i.e the json ones.
+//
+// Usage:
+// app.UseFunc(iris.RecordRequests(iris.RecordOptions{Filter: func(ctx *iris.Context) bool { return ctx.RequestHeader("X-Record") != "" }}))
+func RecordRequests(options ...RecordOptions) HandlerFunc {
+	var o RecordOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Dir == "" {
+		o.Dir = DefaultRecordingsDir
+	}
+	if o.RedactHeaders == nil {
+		o.RedactHeaders = DefaultDumpRedactHeaders
+	}
+	if o.RedactFields == nil {
+		o.RedactFields = DefaultDumpRedactFields
+	}
+	r := newDumpRedactor(o.RedactHeaders, o.RedactFields)
+	var seq uint64
+
+	return func(ctx *Context) {
+		if ctx.Request.Header.Get(replayHeader) != "" || (o.Filter != nil && !o.Filter(ctx)) {
+			ctx.Next()
+			return
+		}
+		body, err := ctx.BodyBytes()
+		if err != nil {
+			ctx.EmitError(StatusBadRequest)
+			return
+		}
+		rec := Recording{Request: RecordedRequest{
+			Method: ctx.Method(),
+			URL:    string(r.redactFields([]byte(ctx.Request.URL.RequestURI()))),
+			Header: r.redactHeader(ctx.Request.Header),
+			Body:   string(r.redactFields(body)),
+		}}
+		ctx.Next()
+
+		status := ctx.ResponseWriter.StatusCode()
+		if status == 0 {
+			status = StatusOK
+		}
+		rec.Response = RecordedResponse{
+			Status: status,
+			Header: r.redactHeader(ctx.ResponseWriter.Header()),
+			Body:   string(r.redactFields(ctx.ResponseWriter.Body())),
+		}
+
+		name := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + strconv.FormatUint(atomic.AddUint64(&seq, 1), 10) + ".json"
+		if err := writeRecording(filepath.Join(o.Dir, name), rec); err != nil {
+			ctx.framework.log(LogLevelError, errRecordWrite.Format(name, err).Error())
+		}
+	}
+}
+
+// redactHeader returns a copy of the 'h' with the values of the secret headers redacted
+func (r *dumpRedactor) redactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for k, v := range h {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			v = []string{dumpRedacted}
+		}
+		redacted[k] = append([]string(nil), v...)
+	}
+	return redacted
+}
+
+// writeRecording writes the 'rec' to the json 'file'
+func writeRecording(file string, rec Recording) error {
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+// ReplayOptions the options of the Tester's Replay
+type ReplayOptions struct {
+	// Header the headers which are set to each replayed request, i.e the credentials of the test instance instead of the redacted ones
+	// Defaults to nil
+	Header http.Header
+	// Headers the response headers which are compared
+	// Defaults to nil, the Content-Type only
+	Headers []string
+	// Normalizers the normalizers of the bodies, the recorded and the replayed ones, i.e the NormalizeTimestamps
+	// Defaults to nil
+	Normalizers []SnapshotNormalizer
+	// RedactFields the json and form fields of the replayed bodies whose values are replaced by [REDACTED], like the recorded ones
+	// Defaults to the DefaultDumpRedactFields
+	RedactFields []string
+}
+
+// Replay re-executes the requests of the recordings of the 'dir', of the RecordRequests, against the Tester's application
+// and reports to its 't' the responses which differ from the recorded ones, by their status code, their headers or their body,
+// the json bodies are compared by their values. An empty 'dir' means the DefaultRecordingsDir.
+//
+// Usage:
+// e := app.NewTester(t)
+// e.Replay("testdata/recordings", iris.ReplayOptions{Header: http.Header{"Authorization": {"Bearer " + testToken}}})
+func (e *Tester) Replay(dir string, options ...ReplayOptions) {
+	var o ReplayOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if dir == "" {
+		dir = DefaultRecordingsDir
+	}
+	if o.Headers == nil {
+		o.Headers = []string{contentType}
+	}
+	if o.RedactFields == nil {
+		o.RedactFields = DefaultDumpRedactFields
+	}
+	r := newDumpRedactor(nil, o.RedactFields)
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		e.t.Errorf("replay %s: %s", dir, err)
+		return
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			e.t.Errorf("replay %s: %s", file, err)
+			continue
+		}
+		var rec Recording
+		if err = json.Unmarshal(b, &rec); err != nil {
+			e.t.Errorf("replay %s: %s", file, err)
+			continue
+		}
+		for _, diff := range e.replay(rec, r, o) {
+			e.t.Errorf("replay %s: %s %s: %s", filepath.Base(file), rec.Request.Method, rec.Request.URL, diff)
+		}
+	}
+}
+
+// replay executes the request of the 'rec' and returns the differences of its response
+func (e *Tester) replay(rec Recording, r *dumpRedactor, o ReplayOptions) []string {
+	req := httptest.NewRequest(rec.Request.Method, rec.Request.URL, strings.NewReader(rec.Request.Body))
+	for k, v := range rec.Request.Header {
+		req.Header[k] = v
+	}
+	for k, v := range o.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set(replayHeader, "1")
+	w := httptest.NewRecorder()
+	e.app.Router.ServeHTTP(w, req)
+
+	var diffs []string
+	if w.Code != rec.Response.Status {
+		diffs = append(diffs, "expected the status code "+strconv.Itoa(rec.Response.Status)+" but got "+strconv.Itoa(w.Code))
+	}
+	for _, key := range o.Headers {
+		expected, actual := rec.Response.Header.Get(key), w.Header().Get(key)
+		if expected != actual {
+			diffs = append(diffs, "expected the "+key+" header "+strconv.Quote(expected)+" but got "+strconv.Quote(actual))
+		}
+	}
+	if expected, actual := normalizeReplayBody(rec.Response.Body, o), normalizeReplayBody(string(r.redactFields(w.Body.Bytes())), o); expected != actual {
+		diffs = append(diffs, "expected the body\n"+expected+"\nbut got\n"+actual)
+	}
+	return diffs
+}
+
+// normalizeReplayBody returns the 'body' normalized, the json bodies are re-encoded, so their formatting and their keys' order don't matter
+func normalizeReplayBody(body string, o ReplayOptions) string {
+	var v interface{}
+	if json.Unmarshal([]byte(body), &v) == nil {
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetIndent("", "  ")
+		if enc.Encode(v) == nil {
+			body = b.String()
+		}
+	}
+	for _, normalize := range o.Normalizers {
+		body = normalize(body)
+	}
+	return strings.TrimSpace(body)
+}
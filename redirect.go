@@ -0,0 +1,106 @@
+package iris
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRedirectHTTPExclude the paths of the ACME HTTP-01 challenges, which are not redirected to https
+const DefaultRedirectHTTPExclude = "/.well-known/acme-challenge/"
+
+// RedirectHTTPOptions the options of the RedirectHTTP
+type RedirectHTTPOptions struct {
+	// Host the https host, host[:port], which the requests are redirected to
+	// Defaults to empty, the request's hostname with the port of the main server, if it's not the 443
+	Host string
+	// Exclude the path prefixes which are not redirected, they are served by the application's router
+	// Defaults to the DefaultRedirectHTTPExclude, the ACME challenges
+	Exclude []string
+	// HSTSMaxAge if not zero the responses of the main, https, server have the Strict-Transport-Security header,
+	// the browsers use https for the domain, without asking the http server first, for that duration
+	// Defaults to 0, no HSTS
+	HSTSMaxAge time.Duration
+	// HSTSIncludeSubdomains if true then the HSTS is applied to the subdomains too
+	// Defaults to false
+	HSTSIncludeSubdomains bool
+	// HSTSPreload if true then the domain asks to be included in the browsers' HSTS preload list,
+	// the list requires a HSTSMaxAge of at least one year and the HSTSIncludeSubdomains
+	// Defaults to false
+	HSTSPreload bool
+}
+
+// hstsHeader returns the value of the Strict-Transport-Security header, empty if the HSTS is disabled
+func (o RedirectHTTPOptions) hstsHeader() string {
+	if o.HSTSMaxAge <= 0 {
+		return ""
+	}
+	h := "max-age=" + strconv.FormatInt(int64(o.HSTSMaxAge/time.Second), 10)
+	if o.HSTSIncludeSubdomains {
+		h += "; includeSubDomains"
+	}
+	if o.HSTSPreload {
+		h += "; preload"
+	}
+	return h
+}
+
+// RedirectHTTP starts, with the main server, a lightweight http server on the 'addr', i.e ":80",
+// which redirects the requests to the https host, see the Framework's RedirectHTTP
+func RedirectHTTP(addr string, options ...RedirectHTTPOptions) *Host {
+	return Default.RedirectHTTP(addr, options...)
+}
+
+// RedirectHTTP starts, with the main server, a lightweight http server on the 'addr', i.e ":80",
+// which redirects the requests to the https host, the GET and HEAD requests with 301 Moved Permanently
+// and the rest with 308 Permanent Redirect, so their method and body are kept.
+// The excluded paths, the ACME challenges by default, are served by the application's router.
+//
+// The HSTS options add the Strict-Transport-Security header to the responses of the main server.
+// It should be called before the Listen/Serve/Run functions, the returned Host is the redirect server.
+func (s *Framework) RedirectHTTP(addr string, options ...RedirectHTTPOptions) *Host {
+	o := RedirectHTTPOptions{Exclude: []string{DefaultRedirectHTTPExclude}}
+	if len(options) > 0 {
+		o = options[0]
+		if o.Exclude == nil {
+			o.Exclude = []string{DefaultRedirectHTTPExclude}
+		}
+	}
+	s.hsts = o.hstsHeader()
+
+	h := &Host{Addr: ParseHost(addr)}
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range o.Exclude {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				s.Router.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		host := o.Host
+		if host == "" {
+			host = ParseHostname(r.Host)
+			if port := ParsePort(s.Config.VHost); port > 0 && port != 443 {
+				host += ":" + strconv.Itoa(port)
+			}
+		}
+		target := SchemeHTTPS + host + r.URL.RequestURI()
+
+		status := StatusPermanentRedirect
+		if r.Method == MethodGet || r.Method == MethodHead {
+			status = StatusMovedPermanently
+		}
+		http.Redirect(w, r, target, status)
+	})
+	s.AddHost(h)
+	return h
+}
+
+// hstsHandler adds the Strict-Transport-Security header to the responses of the 'h'
+func (s *Framework) hstsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", s.hsts)
+		h.ServeHTTP(w, r)
+	})
+}
@@ -50,6 +50,8 @@ func releaseResponseWriter(w *ResponseWriter) {
 	w.ResponseWriter = nil
 	w.statusCode = 0
 	w.beforeFlush = nil
+	w.timings = w.timings[0:0]
+	w.streaming = false
 	w.ResetBody()
 	rpool.Put(w)
 }
@@ -72,6 +74,10 @@ type ResponseWriter struct {
 	chunks     []byte      // keep track of the body in order to be resetable and useful inside custom transactions
 	statusCode int         // the saved status code which will be used from the cache service
 	headers    http.Header // the saved headers
+	// timings the metrics of the Server-Timing header, see Context.RecordTiming
+	timings []serverTiming
+	// streaming is true after the status code and the headers are sent by the stream, the body is written directly
+	streaming bool
 }
 
 // Header returns the header map that will be sent by
@@ -194,14 +200,43 @@ func (w *ResponseWriter) SetBeforeFlush(cb func()) {
 	w.beforeFlush = cb
 }
 
+// stream sends the status code and the headers, and the body which is written so far, once,
+// and returns the underline response writer, for the responses which are written directly, i.e the ctx.CSV
+func (w *ResponseWriter) stream() http.ResponseWriter {
+	if !w.streaming {
+		w.flushResponse()
+		w.ResetBody()
+		w.streaming = true
+	}
+	return w.ResponseWriter
+}
+
+// Streaming returns true if the response is streamed, its status code and headers are sent and its body is not buffered
+func (w *ResponseWriter) Streaming() bool {
+	return w.streaming
+}
+
 // flushResponse the full body, headers and status code to the underline response writer
 // called automatically at the end of each request, see ReleaseCtx
 func (w *ResponseWriter) flushResponse() {
+	if w.streaming {
+		// the status code and the headers are already sent
+		if len(w.chunks) > 0 {
+			w.ResponseWriter.Write(w.chunks)
+		}
+		return
+	}
 
 	if w.beforeFlush != nil {
 		w.beforeFlush()
 	}
 
+	if len(w.timings) > 0 {
+		w.headers.Set(serverTimingHeader, formatServerTiming(w.timings))
+		// the headers are sent by the first flush, the metrics which a streaming handler records after it are dropped
+		w.timings = w.timings[0:0]
+	}
+
 	if w.statusCode > 0 {
 		w.ResponseWriter.WriteHeader(w.statusCode)
 	}
@@ -274,6 +309,9 @@ func (w *ResponseWriter) writeTo(to *ResponseWriter) {
 		to.Write(w.chunks)
 	}
 
+	// append the Server-Timing metrics
+	to.timings = append(to.timings, w.timings...)
+
 	if w.beforeFlush != nil {
 		to.SetBeforeFlush(w.beforeFlush)
 	}
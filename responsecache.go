@@ -0,0 +1,871 @@
+package iris
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/go-errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultMemoryCacheStoreMaxEntries the max entries of the MemoryCacheStore, the least recently used ones are evicted
+	DefaultMemoryCacheStoreMaxEntries = 10000
+	// DefaultRedisCacheStorePrefix the prefix of the keys of the RedisCacheStore
+	DefaultRedisCacheStorePrefix = "iris-cache:"
+
+	// cacheTagsContextKey the tags of the response, see CacheTags
+	cacheTagsContextKey = "__IRIS_CACHE_TAGS__"
+)
+
+var errCacheTagsNotSupported = errors.New("Cache invalidation: the store %T doesn't support tags")
+
+// ResponseCacheStore stores the cached responses of the ResponseCache, the entries expire after their 'ttl'
+type ResponseCacheStore interface {
+	// Get returns the value of the 'key', false if it doesn't exist or it's expired
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// ResponseCacheTagStore is a ResponseCacheStore which keeps the tags of its entries, see CacheTags and CacheInvalidate,
+// the MemoryCacheStore and the RedisCacheStore are ResponseCacheTagStores
+type ResponseCacheTagStore interface {
+	ResponseCacheStore
+	// Tag adds the 'tags' to the entry of the 'key', the tags are kept for its 'ttl' at least
+	Tag(key string, tags []string, ttl time.Duration) error
+	// InvalidateTags removes the entries of the 'tags' and returns their number
+	InvalidateTags(tags ...string) (int, error)
+}
+
+// ResponseCache is a middleware which stores the flushed status code, headers and body of the GET and HEAD requests,
+// keyed by their method, url and the normalized request headers of their response's Vary, to a ResponseCacheStore,
+// and serves them, without calling the next handlers, until they expire.
+// The responses with a Set-Cookie header or a "Cache-Control: no-store" or "private" are not cached,
+// the requests with an Authorization header, unless there is a Principal, or a "Cache-Control: no-cache" are not served from the cache.
+//
+// Unlike the Cache, which wraps one handler and keeps its responses in memory,
+// the store can be shared by the instances of the application, i.e the RedisCacheStore.
+type ResponseCache struct {
+	store   ResponseCacheStore
+	ttl     time.Duration
+	options ResponseCacheOptions
+	hits    uint64
+	misses  uint64
+	stale   uint64
+	// the keys which are revalidated in the background
+	revalidating sync.Map
+	// the keys which are regenerated by a request, see the Singleflight
+	flightsMu sync.Mutex
+	flights   map[string]chan struct{}
+}
+
+// VaryNormalizer returns the value of a request header which is part of a cache key,
+// the values which select the same response should be normalized to the same one, i.e "en-US,en;q=0.9" and "en" to "en"
+type VaryNormalizer func(ctx *Context, value string) string
+
+// DefaultVaryNormalizers the normalizers of the Accept-Encoding, to the supported encodings, and of the Accept-Language,
+// to the language of the I18n, or to the preferred one if there are no translations.
+// The values of the other headers are lowercased and their comma separated items are sorted.
+var DefaultVaryNormalizers = map[string]VaryNormalizer{
+	acceptEncodingHeader: normalizeAcceptEncoding,
+	"Accept-Language":    normalizeAcceptLanguage,
+}
+
+// ResponseCacheOptions the options of the ResponseCache, they can be set per route by its Route
+type ResponseCacheOptions struct {
+	// StaleWhileRevalidate the duration, after the ttl, which the expired response is served
+	// while it's refreshed, by the next handlers, in the background
+	// Defaults to 0, the expired responses are not served
+	StaleWhileRevalidate time.Duration
+	// StaleIfError the duration, after the ttl, which the expired response is served
+	// when the next handlers respond with a 5xx status code
+	// Defaults to 0, the expired responses are not served
+	StaleIfError time.Duration
+	// VaryBy the request headers which are part of the key of every response, in addition to the ones of the response's Vary,
+	// i.e the headers which the handlers read but forget to add to the Vary
+	VaryBy []string
+	// Principal returns the authenticated user of the request, i.e the subject of its token, which is part of the keys,
+	// so the requests with an Authorization header and the "private" responses are cached per user
+	// Defaults to nil, the requests with an Authorization header are not cached
+	Principal func(ctx *Context) string
+	// Normalizers the normalizers of the request headers of the keys, by their canonical name
+	// Defaults to the DefaultVaryNormalizers
+	Normalizers map[string]VaryNormalizer
+	// Singleflight lets one request per key regenerate a missing or an expired response,
+	// the other requests of the key wait for it and they're served from the cache,
+	// or they receive the expired response, if it's kept by the StaleIfError, without waiting
+	Singleflight bool
+	// SingleflightTimeout the max wait of the other requests, then they call the next handlers too
+	// Defaults to 0, they wait until the regenerating request is served or they're canceled
+	SingleflightTimeout time.Duration
+}
+
+// cachedResponse the stored response
+type cachedResponse struct {
+	// Vary the request headers of the Vary, the entry of the url only keeps them, see the ResponseCache's key
+	Vary       []string    `json:"vary,omitempty"`
+	StatusCode int         `json:"status,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	// Expires the time after which the response is stale
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// responseCacheRevalidateKey the request context key of the background revalidations, they're not served from the cache
+type responseCacheRevalidateKey struct{}
+
+// NewResponseCache returns a new ResponseCache which keeps the responses to the 'store' for 'ttl',
+// the Framework's ResponseCache returns one which is invalidated by its CacheInvalidate too
+//
+// Usage:
+// cache := iris.NewResponseCache(iris.NewMemoryCacheStore(0), time.Minute)
+// app.Get("/products", cache.Serve, listProducts)
+// or for all the routes:
+// app.UseFunc(cache.Serve)
+func NewResponseCache(store ResponseCacheStore, ttl time.Duration, options ...ResponseCacheOptions) *ResponseCache {
+	c := &ResponseCache{store: store, ttl: ttl}
+	if len(options) > 0 {
+		c.options = options[0]
+	}
+	return c
+}
+
+// ResponseCache returns a new ResponseCache, see NewResponseCache, whose tagged responses are removed by the CacheInvalidate
+func (s *Framework) ResponseCache(store ResponseCacheStore, ttl time.Duration, options ...ResponseCacheOptions) *ResponseCache {
+	c := NewResponseCache(store, ttl, options...)
+	s.shutdownMu.Lock()
+	s.responseCaches = append(s.responseCaches, c)
+	s.shutdownMu.Unlock()
+	return c
+}
+
+// CacheInvalidate removes the responses of the 'tags' from the response caches of the default iris instance,
+// see the Framework's CacheInvalidate
+func CacheInvalidate(tags ...string) error {
+	return Default.CacheInvalidate(tags...)
+}
+
+// CacheInvalidate removes the responses which are tagged with any of the 'tags', see CacheTags,
+// from the response caches which are created by the ResponseCache,
+// so the write endpoints purge only the pages they affect. It returns the first error of the stores.
+//
+// Usage:
+// app.Put("/users/:id", func(ctx *iris.Context) {
+//	updateUser(ctx)
+//	app.CacheInvalidate("user:" + ctx.Param("id"))
+// })
+func (s *Framework) CacheInvalidate(tags ...string) error {
+	s.shutdownMu.Lock()
+	caches := s.responseCaches
+	s.shutdownMu.Unlock()
+
+	var firstErr error
+	for _, c := range caches {
+		if _, err := c.Invalidate(tags...); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Invalidate removes the responses which are tagged with any of the 'tags' and returns their number,
+// the store should be a ResponseCacheTagStore
+func (c *ResponseCache) Invalidate(tags ...string) (int, error) {
+	store, ok := c.store.(ResponseCacheTagStore)
+	if !ok {
+		return 0, errCacheTagsNotSupported.Format(c.store)
+	}
+	return store.InvalidateTags(tags...)
+}
+
+// CacheTags tags the response, so it's removed from the response cache by the CacheInvalidate of any of the 'tags',
+// i.e ctx.CacheTags("user:42", "orders")
+func (ctx *Context) CacheTags(tags ...string) {
+	existing, _ := ctx.Get(cacheTagsContextKey).([]string)
+	ctx.Set(cacheTagsContextKey, append(existing, tags...))
+}
+
+// Hits returns the number of the requests which are served from the cache
+func (c *ResponseCache) Hits() uint64 {
+	return atomic.LoadUint64(&c.hits)
+}
+
+// Misses returns the number of the cacheable requests which are served by the next handlers
+func (c *ResponseCache) Misses() uint64 {
+	return atomic.LoadUint64(&c.misses)
+}
+
+// Stale returns the number of the requests which are served with an expired response, see the ResponseCacheOptions
+func (c *ResponseCache) Stale() uint64 {
+	return atomic.LoadUint64(&c.stale)
+}
+
+// Serve serves the request from the cache, or calls the next handlers and caches their response
+func (c *ResponseCache) Serve(ctx *Context) {
+	c.serve(ctx, c.options)
+}
+
+// Route returns the middleware of the cache with the options of a route
+//
+// Usage:
+// app.Get("/feed", cache.Route(iris.ResponseCacheOptions{StaleWhileRevalidate: time.Minute, StaleIfError: time.Hour}), feed)
+func (c *ResponseCache) Route(options ResponseCacheOptions) HandlerFunc {
+	return func(ctx *Context) {
+		c.serve(ctx, options)
+	}
+}
+
+func (c *ResponseCache) serve(ctx *Context, options ResponseCacheOptions) {
+	r := ctx.Request
+	if r.Method != MethodGet && r.Method != MethodHead {
+		ctx.Next()
+		return
+	}
+	principal := ""
+	if options.Principal != nil {
+		principal = options.Principal(ctx)
+	}
+	if principal == "" && r.Header.Get("Authorization") != "" {
+		// a response to a credential shouldn't be shared
+		ctx.Next()
+		return
+	}
+	span := ctx.startSpan("iris.response_cache")
+	defer span.End()
+
+	// the key of the url, its entry is the response or the Vary of the response
+	urlKey := r.Method + " " + r.Host + r.URL.RequestURI()
+	if len(options.VaryBy) > 0 {
+		urlKey = varyKey(ctx, urlKey, parseVary(strings.Join(options.VaryBy, ",")), options.Normalizers)
+	}
+	if principal != "" {
+		urlKey += "\nPrincipal: " + principal
+	}
+
+	var stale *cachedResponse
+	if r.Context().Value(responseCacheRevalidateKey{}) == nil && !strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache") {
+		cached, key, ok := c.lookup(ctx, urlKey, options)
+		if ok {
+			now := ctx.framework.now()
+			switch {
+			case !now.After(cached.Expires):
+				c.observe(ctx, "hit")
+				c.write(ctx, cached)
+				return
+			case now.Before(cached.Expires.Add(options.StaleWhileRevalidate)):
+				c.observe(ctx, "stale")
+				c.write(ctx, cached)
+				c.revalidate(ctx, key)
+				return
+			case now.Before(cached.Expires.Add(options.StaleIfError)):
+				stale = cached
+			}
+		}
+
+		if options.Singleflight {
+			if done, leader := c.joinFlight(key); leader {
+				defer c.leaveFlight(key, done)
+			} else if stale != nil {
+				// the response is regenerated by another request
+				c.observe(ctx, "stale")
+				c.write(ctx, stale)
+				return
+			} else if c.waitFlight(ctx, done, options.SingleflightTimeout) {
+				if cached, _, ok := c.lookup(ctx, urlKey, options); ok && !ctx.framework.now().After(cached.Expires) {
+					c.observe(ctx, "hit")
+					c.write(ctx, cached)
+					return
+				}
+			}
+		}
+	}
+
+	if stale != nil {
+		// a panic of the next handlers is an error too
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				ctx.framework.logPanic(v)
+				c.observe(ctx, "stale")
+				c.write(ctx, stale)
+			}
+		}()
+	}
+	ctx.Next()
+	if stale != nil && ctx.ResponseWriter.StatusCode() >= StatusInternalServerError {
+		c.observe(ctx, "stale")
+		c.write(ctx, stale)
+		return
+	}
+	c.observe(ctx, "miss")
+	c.save(ctx, urlKey, options, principal != "")
+}
+
+// write replaces the response with the cached one and stops the next handlers
+func (c *ResponseCache) write(ctx *Context, cached *cachedResponse) {
+	w := ctx.ResponseWriter
+	w.Reset()
+	for k, v := range cached.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(cached.StatusCode)
+	w.SetBody(cached.Body)
+	ctx.StopExecution()
+}
+
+// revalidate serves a copy of the request, by the framework's router, in the background, so its response is saved again,
+// one revalidation of a key runs at a time
+func (c *ResponseCache) revalidate(ctx *Context, key string) {
+	if _, running := c.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	s := ctx.framework
+	req := ctx.Request.Clone(context.WithValue(context.Background(), responseCacheRevalidateKey{}, true))
+	go func() {
+		defer c.revalidating.Delete(key)
+		defer func() {
+			if v := recover(); v != nil {
+				s.logPanic(v)
+			}
+		}()
+		s.Router.ServeHTTP(&discardResponseWriter{header: make(http.Header)}, req)
+	}()
+}
+
+// joinFlight returns the channel which is closed when the response of the key is regenerated,
+// true if the caller is the one which regenerates it and it should call the leaveFlight
+func (c *ResponseCache) joinFlight(key string) (chan struct{}, bool) {
+	c.flightsMu.Lock()
+	defer c.flightsMu.Unlock()
+	if done, ok := c.flights[key]; ok {
+		return done, false
+	}
+	if c.flights == nil {
+		c.flights = make(map[string]chan struct{})
+	}
+	done := make(chan struct{})
+	c.flights[key] = done
+	return done, true
+}
+
+// leaveFlight releases the requests which wait for the response of the key
+func (c *ResponseCache) leaveFlight(key string, done chan struct{}) {
+	c.flightsMu.Lock()
+	delete(c.flights, key)
+	c.flightsMu.Unlock()
+	close(done)
+}
+
+// waitFlight waits for the response of the key to be regenerated, it returns false if the request is canceled or the 'timeout' is passed
+func (c *ResponseCache) waitFlight(ctx *Context, done chan struct{}, timeout time.Duration) bool {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-done:
+		return true
+	case <-ctx.Request.Context().Done():
+		return false
+	case <-expired:
+		return false
+	}
+}
+
+// lookup returns the cached response, and its key, of the url's key or of the key of its Vary headers
+func (c *ResponseCache) lookup(ctx *Context, urlKey string, options ResponseCacheOptions) (*cachedResponse, string, bool) {
+	cached, ok := c.get(ctx, urlKey)
+	if !ok || len(cached.Vary) == 0 {
+		return cached, urlKey, ok
+	}
+	key := varyKey(ctx, urlKey, cached.Vary, options.Normalizers)
+	cached, ok = c.get(ctx, key)
+	return cached, key, ok
+}
+
+func (c *ResponseCache) get(ctx *Context, key string) (*cachedResponse, bool) {
+	var (
+		b   []byte
+		ok  bool
+		err error
+	)
+	// the memory store expires its entries by the framework's Clock
+	if m, isMemory := c.store.(*MemoryCacheStore); isMemory {
+		b, ok, err = m.getAt(key, ctx.framework.now())
+	} else {
+		b, ok, err = c.store.Get(key)
+	}
+	if err != nil {
+		ctx.framework.log(LogLevelError, "response cache", "key", key, "err", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	cached := &cachedResponse{}
+	if err := json.Unmarshal(b, cached); err != nil {
+		return nil, false
+	}
+	return cached, true
+}
+
+// save stores the response of the next handlers, if it's cacheable, it's kept until its stale modes end,
+// the "private" responses are cacheable if they're keyed by their principal
+func (c *ResponseCache) save(ctx *Context, urlKey string, options ResponseCacheOptions, private bool) {
+	w := ctx.ResponseWriter
+	status := w.StatusCode()
+	if status == 0 {
+		status = StatusOK
+	}
+	// the streamed bodies are not buffered
+	if w.Streaming() || !isCacheableStatus(status) || w.Header().Get("Set-Cookie") != "" {
+		return
+	}
+	cacheControl := strings.ToLower(w.Header().Get("Cache-Control"))
+	if strings.Contains(cacheControl, "no-store") || (!private && strings.Contains(cacheControl, "private")) {
+		return
+	}
+
+	ttl := c.ttl
+	if options.StaleWhileRevalidate > options.StaleIfError {
+		ttl += options.StaleWhileRevalidate
+	} else {
+		ttl += options.StaleIfError
+	}
+
+	keys := []string{urlKey}
+	vary := parseVary(strings.Join(w.Header()[varyHeader], ","))
+	if len(vary) > 0 {
+		if vary[0] == "*" {
+			return
+		}
+		// the url's entry keeps the Vary, the response is keyed by the request's values of them
+		c.set(ctx, urlKey, &cachedResponse{Vary: vary}, ttl)
+		keys = append(keys, varyKey(ctx, urlKey, vary, options.Normalizers))
+	}
+
+	header := make(http.Header, len(w.Header()))
+	for k, v := range w.Header() {
+		header[k] = append([]string(nil), v...)
+	}
+	cached := &cachedResponse{StatusCode: status, Header: header, Body: w.Body(), Expires: ctx.framework.now().Add(c.ttl)}
+	c.set(ctx, keys[len(keys)-1], cached, ttl)
+
+	if tags, _ := ctx.Get(cacheTagsContextKey).([]string); len(tags) > 0 {
+		store, ok := c.store.(ResponseCacheTagStore)
+		if !ok {
+			ctx.framework.log(LogLevelWarn, "response cache", "err", errCacheTagsNotSupported.Format(c.store))
+			return
+		}
+		// the url's entry is tagged too, its Vary may change
+		for _, key := range keys {
+			if err := store.Tag(key, tags, ttl); err != nil {
+				ctx.framework.log(LogLevelError, "response cache", "key", key, "err", err)
+			}
+		}
+	}
+}
+
+func (c *ResponseCache) set(ctx *Context, key string, cached *cachedResponse, ttl time.Duration) {
+	b, err := json.Marshal(cached)
+	if m, ok := c.store.(*MemoryCacheStore); ok && err == nil {
+		err = m.setAt(key, b, ttl, ctx.framework.now())
+	} else if err == nil {
+		err = c.store.Set(key, b, ttl)
+	}
+	if err != nil {
+		ctx.framework.log(LogLevelError, "response cache", "key", key, "err", err)
+	}
+}
+
+// observe counts a "hit", a "miss" or a "stale" response, to the framework's metrics too if they're enabled
+func (c *ResponseCache) observe(ctx *Context, result string) {
+	switch result {
+	case "hit":
+		atomic.AddUint64(&c.hits, 1)
+	case "stale":
+		atomic.AddUint64(&c.stale, 1)
+	default:
+		atomic.AddUint64(&c.misses, 1)
+	}
+	if m := ctx.framework.metrics; m != nil {
+		m.responseCache.Inc(result)
+	}
+}
+
+// discardResponseWriter the http.ResponseWriter of the background revalidations and the cache warming,
+// the response is saved by the cache, only its status code is kept
+type discardResponseWriter struct {
+	header     http.Header
+	statusCode int
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(statusCode int)  { w.statusCode = statusCode }
+
+// isCacheableStatus reports whether the responses of the 'status' are cacheable by default, see the RFC 7231 6.1
+func isCacheableStatus(status int) bool {
+	switch status {
+	case StatusOK, StatusNonAuthoritativeInfo, StatusNoContent, StatusMultipleChoices, StatusMovedPermanently,
+		StatusNotFound, StatusMethodNotAllowed, StatusGone, StatusRequestURITooLong, StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// parseVary returns the sorted, canonical, header names of a Vary header
+func parseVary(vary string) []string {
+	if vary == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(vary, ",") {
+		if name = strings.TrimSpace(name); name == "*" {
+			return []string{"*"}
+		} else if name != "" {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// varyKey returns the key of the url's response to the request's normalized values of the 'vary' headers,
+// a nil 'normalizers' means the DefaultVaryNormalizers
+func varyKey(ctx *Context, urlKey string, vary []string, normalizers map[string]VaryNormalizer) string {
+	if normalizers == nil {
+		normalizers = DefaultVaryNormalizers
+	}
+	var b strings.Builder
+	b.WriteString(urlKey)
+	for _, name := range vary {
+		value := strings.Join(ctx.Request.Header[name], ",")
+		if normalize, ok := normalizers[name]; ok {
+			value = normalize(ctx, value)
+		} else {
+			value = normalizeHeaderValue(value)
+		}
+		b.WriteString("\n")
+		b.WriteString(name)
+		b.WriteString(": ")
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// normalizeHeaderValue lowercases the value and sorts its comma separated items, i.e "B, a" to "a,b"
+func normalizeHeaderValue(value string) string {
+	items := strings.Split(strings.ToLower(value), ",")
+	n := 0
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			items[n] = item
+			n++
+		}
+	}
+	items = items[:n]
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// normalizeAcceptEncoding returns the encodings of the Accept-Encoding which the framework and the precompressed files support,
+// i.e "gzip, deflate, br" to "br,gzip" and "identity" to ""
+func normalizeAcceptEncoding(ctx *Context, value string) string {
+	var encodings []string
+	for _, part := range strings.Split(strings.ToLower(value), ",") {
+		coding := strings.TrimSpace(part)
+		if idx := strings.IndexByte(coding, ';'); idx > -1 {
+			params := strings.TrimSpace(coding[idx+1:])
+			coding = strings.TrimSpace(coding[:idx])
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err == nil && q <= 0 {
+				continue
+			}
+		}
+		switch coding {
+		case "br", "gzip":
+			encodings = append(encodings, coding)
+		case "*":
+			encodings = append(encodings, "br", "gzip")
+		}
+	}
+	sort.Strings(encodings)
+	n := 0
+	for i, coding := range encodings {
+		if i == 0 || coding != encodings[i-1] {
+			encodings[n] = coding
+			n++
+		}
+	}
+	return strings.Join(encodings[:n], ",")
+}
+
+// normalizeAcceptLanguage returns the language of the I18n for the Accept-Language,
+// or its preferred language, lowercased, if there are no translations
+func normalizeAcceptLanguage(ctx *Context, value string) string {
+	langs := parseAcceptLanguage(value)
+	if i := ctx.framework.I18n; i != nil && len(i.Languages()) > 0 {
+		for _, lang := range langs {
+			if matched := i.match(lang); matched != "" {
+				return matched
+			}
+		}
+		return i.Default
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	return strings.ToLower(langs[0])
+}
+
+// MemoryCacheStore is the in-memory ResponseCacheStore, it evicts the least recently used entries
+type MemoryCacheStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	tags    map[string]map[string]struct{}
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+	tags    []string
+}
+
+var _ ResponseCacheTagStore = &MemoryCacheStore{}
+
+// NewMemoryCacheStore returns a new MemoryCacheStore which keeps up to 'maxEntries',
+// zero means the DefaultMemoryCacheStoreMaxEntries
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheStoreMaxEntries
+	}
+	return &MemoryCacheStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+// Get returns the value of the 'key', false if it doesn't exist or it's expired
+func (s *MemoryCacheStore) Get(key string) ([]byte, bool, error) {
+	return s.getAt(key, time.Now())
+}
+
+// getAt returns the value of the 'key' at the 'now', see the Get
+func (s *MemoryCacheStore) getAt(key string, now time.Time) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*memoryCacheEntry)
+	if !now.Before(e.expires) {
+		s.remove(el)
+		return nil, false, nil
+	}
+	s.lru.MoveToFront(el)
+	return e.value, true, nil
+}
+
+// Set sets the value of the 'key' which expires after the 'ttl'
+func (s *MemoryCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	return s.setAt(key, value, ttl, time.Now())
+}
+
+// setAt sets the value of the 'key' which expires after the 'ttl' since the 'now', see the Set
+func (s *MemoryCacheStore) setAt(key string, value []byte, ttl time.Duration, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &memoryCacheEntry{key: key, value: value, expires: now.Add(ttl)}
+	if el, ok := s.entries[key]; ok {
+		// the tags are kept, the response of a url is set again after its Vary entry
+		e.tags = el.Value.(*memoryCacheEntry).tags
+		el.Value = e
+		s.lru.MoveToFront(el)
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(e)
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+	return nil
+}
+
+// Delete removes the 'key'
+func (s *MemoryCacheStore) Delete(key string) error {
+	s.mu.Lock()
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Tag adds the 'tags' to the entry of the 'key', if it exists
+func (s *MemoryCacheStore) Tag(key string, tags []string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*memoryCacheEntry)
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = struct{}{}
+			e.tags = append(e.tags, tag)
+		}
+	}
+	return nil
+}
+
+// InvalidateTags removes the entries of the 'tags' and returns their number
+func (s *MemoryCacheStore) InvalidateTags(tags ...string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if el, ok := s.entries[key]; ok {
+				s.remove(el)
+				n++
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return n, nil
+}
+
+// remove removes the entry and its key from its tags, the lock is held by the caller
+func (s *MemoryCacheStore) remove(el *list.Element) {
+	e := el.Value.(*memoryCacheEntry)
+	s.lru.Remove(el)
+	delete(s.entries, e.key)
+	for _, tag := range e.tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
+
+// Len returns the number of the entries, the expired ones which are not removed yet too
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// RedisCacheStore is the Redis ResponseCacheStore, the instances of the application share its entries
+//
+// Usage:
+// client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+// cache := iris.NewResponseCache(iris.NewRedisCacheStore(client, ""), time.Minute)
+type RedisCacheStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ ResponseCacheTagStore = &RedisCacheStore{}
+
+// NewRedisCacheStore returns a new RedisCacheStore, an empty 'prefix' means the DefaultRedisCacheStorePrefix
+func NewRedisCacheStore(client redis.UniversalClient, prefix string) *RedisCacheStore {
+	if prefix == "" {
+		prefix = DefaultRedisCacheStorePrefix
+	}
+	return &RedisCacheStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of the 'key', false if it doesn't exist or it's expired
+func (s *RedisCacheStore) Get(key string) ([]byte, bool, error) {
+	b, err := s.client.Get(context.Background(), s.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// Set sets the value of the 'key' which expires after the 'ttl'
+func (s *RedisCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(context.Background(), s.prefix+key, value, ttl).Err()
+}
+
+// Delete removes the 'key'
+func (s *RedisCacheStore) Delete(key string) error {
+	return s.client.Del(context.Background(), s.prefix+key).Err()
+}
+
+// Tag adds the 'key' to the sets of the 'tags', which expire after the 'ttl' of their longest-lived entry,
+// a shorter 'ttl' never shrinks a set's expiration. It requires Redis 7.0 or newer, for the EXPIRE NX and GT
+func (s *RedisCacheStore) Tag(key string, tags []string, ttl time.Duration) error {
+	c := context.Background()
+	_, err := s.client.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.SAdd(c, s.tagKey(tag), key)
+			// the NX sets the expiration of a new set, the GT only extends the one of an existing set
+			pipe.ExpireNX(c, s.tagKey(tag), ttl)
+			pipe.ExpireGT(c, s.tagKey(tag), ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// InvalidateTags removes the entries of the 'tags' and their sets and returns the number of the removed entries
+func (s *RedisCacheStore) InvalidateTags(tags ...string) (int, error) {
+	c := context.Background()
+	n := 0
+	for _, tag := range tags {
+		keys, err := s.client.SMembers(c, s.tagKey(tag)).Result()
+		if err != nil {
+			return n, err
+		}
+		del := make([]string, 0, len(keys)+1)
+		for _, key := range keys {
+			del = append(del, s.prefix+key)
+		}
+		deleted, err := s.client.Del(c, append(del, s.tagKey(tag))...).Result()
+		if err != nil {
+			return n, err
+		}
+		if len(keys) > 0 && deleted > 0 {
+			// the tag's set is one of the deleted keys
+			n += int(deleted) - 1
+		}
+	}
+	return n, nil
+}
+
+// tagKey the key of the set of the tag's entries, the cache keys start with their method so they don't collide
+func (s *RedisCacheStore) tagKey(tag string) string {
+	return s.prefix + "tag:" + tag
+}
@@ -0,0 +1,176 @@
+package iris
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+// the environment variable which tells to the new process that it inherits the listener of the old one,
+// the listener is the file descriptor 3 and the readiness pipe is the 4
+const (
+	envInheritedListener = "IRIS_INHERITED_LISTENER"
+	inheritedListenerFd  = 3
+	inheritedReadyFd     = 4
+)
+
+var (
+	errRestartNotSupported = errors.New("Restart: the graceful restart is not supported on this operating system")
+	errRestartListener     = errors.New("Restart: the listener %T can't be passed to a new process")
+	errRestartInherit      = errors.New("Restart: unable to inherit the listener. Trace: %s")
+	errRestartChild        = errors.New("Restart: the new process failed to start. Trace: %s")
+)
+
+// InheritedListener returns the listener which is passed by the old process on a graceful restart,
+// or nil if the current process is not started by a restart, see ListenGracefulRestart
+func InheritedListener() (net.Listener, error) {
+	if os.Getenv(envInheritedListener) == "" {
+		return nil, nil
+	}
+	// the children of this process should not inherit it
+	os.Unsetenv(envInheritedListener)
+
+	f := os.NewFile(inheritedListenerFd, "iris-listener")
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, errRestartInherit.Format(err.Error())
+	}
+	return ln, nil
+}
+
+// ReusePortTCP4 returns a new tcp4 Listener with the SO_REUSEPORT option, where it's available,
+// so more than one processes can listen on the same address, otherwise it's the same as the TCP4
+func ReusePortTCP4(addr string) (net.Listener, error) {
+	lc := net.ListenConfig{Control: reusePortControl}
+	return lc.Listen(context.Background(), "tcp4", ParseHost(addr))
+}
+
+// ListenGracefulRestart starts the standalone http server, like the RunWithGracefulShutdown,
+// which is restarted without dropping any connection when the process receives the SIGUSR2 signal:
+// the executable, which may be a new build, is started again with the same arguments and it inherits the listener,
+// when it's ready to serve the old process is gracefully shut down, waiting up to the 'timeout' for its in-flight requests.
+//
+// If the new process fails to start, the old one keeps serving.
+// The restart is not supported on Windows, the server is still shut down gracefully on interrupt.
+func ListenGracefulRestart(addr string, timeout time.Duration) error {
+	return Default.ListenGracefulRestart(addr, timeout)
+}
+
+// ListenGracefulRestart starts the standalone http server, like the RunWithGracefulShutdown,
+// which is restarted without dropping any connection when the process receives the SIGUSR2 signal:
+// the executable, which may be a new build, is started again with the same arguments and it inherits the listener,
+// when it's ready to serve the old process is gracefully shut down, waiting up to the 'timeout' for its in-flight requests.
+//
+// If the new process fails to start, the old one keeps serving.
+// The restart is not supported on Windows, the server is still shut down gracefully on interrupt.
+func (s *Framework) ListenGracefulRestart(addr string, timeout time.Duration) error {
+	addr = ParseHost(addr)
+	if s.Config.VHost == "" {
+		s.Config.VHost = addr
+		// this will be set as the front-end listening addr
+	}
+
+	ln, err := InheritedListener()
+	if err != nil {
+		return err
+	}
+	inherited := ln != nil
+	if !inherited {
+		if ln, err = ReusePortTCP4(addr); err != nil {
+			return err
+		}
+	}
+	if err = s.serve(ln); err != nil {
+		ln.Close()
+		return err
+	}
+	if inherited {
+		// tell the old process that it can shut down
+		ready := os.NewFile(inheritedReadyFd, "iris-ready")
+		ready.Write([]byte{1})
+		ready.Close()
+	}
+
+	signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if restartSignal != nil {
+		signals = append(signals, restartSignal)
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case sig := <-ch:
+			if sig == restartSignal {
+				if err = s.restart(ln, timeout); err != nil {
+					s.log(LogLevelError, "restarting the server", "err", err)
+					continue
+				}
+			}
+		case <-s.closed:
+			// already shut down
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = s.Shutdown(ctx)
+		cancel()
+		return err
+	}
+}
+
+// restart starts the new process with the listener and waits, up to the 'timeout', until it's ready to serve
+func (s *Framework) restart(ln net.Listener, timeout time.Duration) error {
+	if restartSignal == nil {
+		return errRestartNotSupported
+	}
+	fl, ok := ln.(interface {
+		File() (*os.File, error)
+	})
+	if !ok {
+		return errRestartListener.Format(ln)
+	}
+	lnFile, err := fl.File()
+	if err != nil {
+		return errRestartChild.Format(err.Error())
+	}
+	defer lnFile.Close()
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return errRestartChild.Format(err.Error())
+	}
+	defer readyR.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		readyW.Close()
+		return errRestartChild.Format(err.Error())
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), envInheritedListener+"=1")
+	// the fds 3 and 4 of the new process
+	cmd.ExtraFiles = []*os.File{lnFile, readyW}
+	err = cmd.Start()
+	// the new process keeps its own copy
+	readyW.Close()
+	if err != nil {
+		return errRestartChild.Format(err.Error())
+	}
+	go cmd.Wait()
+
+	readyR.SetReadDeadline(time.Now().Add(timeout))
+	if _, err = readyR.Read(make([]byte, 1)); err != nil {
+		cmd.Process.Kill()
+		return errRestartChild.Format(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package iris
+
+import "syscall"
+
+// soReusePort the SO_REUSEPORT socket option
+const soReusePort = syscall.SO_REUSEPORT
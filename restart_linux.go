@@ -0,0 +1,6 @@
+//go:build linux && !(mips || mipsle || mips64 || mips64le)
+
+package iris
+
+// soReusePort the SO_REUSEPORT socket option, which the syscall package doesn't define on linux
+const soReusePort = 0xf
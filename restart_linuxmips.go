@@ -0,0 +1,6 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package iris
+
+// soReusePort the SO_REUSEPORT socket option of the mips, which the syscall package doesn't define on linux
+const soReusePort = 0x200
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package iris
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignal is nil, the graceful restart is not supported
+var restartSignal os.Signal
+
+// reusePortControl does nothing, the SO_REUSEPORT is not supported
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package iris
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignal the signal which triggers a graceful restart
+var restartSignal os.Signal = syscall.SIGUSR2
+
+// reusePortControl sets the SO_REUSEADDR and SO_REUSEPORT options to the listener's socket
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	var err error
+	if cerr := c.Control(func(fd uintptr) {
+		if err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1); err != nil {
+			return
+		}
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	}); cerr != nil {
+		return cerr
+	}
+	return err
+}
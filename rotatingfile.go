@@ -0,0 +1,170 @@
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFileTimeFormat the suffix of the rotated files, i.e access.log.20161225-150405.000
+const rotatingFileTimeFormat = "20060102-150405.000"
+
+// RotatingFileOptions the options of the RotatingFile
+type RotatingFileOptions struct {
+	// MaxSize the max bytes of the file, it's rotated before a write which exceeds it
+	// Defaults to 0, no size limit
+	MaxSize int64
+	// Interval the file is rotated every interval, i.e 24 * time.Hour
+	// Defaults to 0, no time rotation
+	Interval time.Duration
+	// MaxBackups the max rotated files which are kept, the oldest ones are removed
+	// Defaults to 0, all are kept
+	MaxBackups int
+	// Perm the permissions of the created files
+	// Defaults to 0644
+	Perm os.FileMode
+}
+
+// RotatingFile is an io.WriteCloser which appends to a file and renames it to a timestamped backup,
+// i.e access.log.20161225-150405.000, when it exceeds its size or its interval
+type RotatingFile struct {
+	filename string
+	options  RotatingFileOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	rotateAt time.Time
+}
+
+// NewRotatingFile opens, or creates, the 'filename' for appending and returns a new RotatingFile
+func NewRotatingFile(filename string, options ...RotatingFileOptions) (*RotatingFile, error) {
+	var o RotatingFileOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Perm == 0 {
+		o.Perm = 0644
+	}
+	f := &RotatingFile{filename: filename, options: o}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	if dir := filepath.Dir(f.filename); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(f.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.options.Perm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	if f.options.Interval > 0 {
+		f.rotateAt = time.Now().Add(f.options.Interval)
+	}
+	return nil
+}
+
+// Write appends the 'p' to the file, it rotates the file before, if the write exceeds its MaxSize or its Interval is passed
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if (f.options.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.options.MaxSize) ||
+		(!f.rotateAt.IsZero() && !time.Now().Before(f.rotateAt)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate renames the file to a timestamped backup and opens a new one, i.e on a SIGHUP
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	backup := f.filename + "." + time.Now().Format(rotatingFileTimeFormat)
+	if err := os.Rename(f.filename, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.removeOldBackups()
+}
+
+// removeOldBackups removes the oldest backups which exceed the MaxBackups
+func (f *RotatingFile) removeOldBackups() error {
+	if f.options.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := f.Backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > f.options.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Backups returns the rotated files, the oldest first
+func (f *RotatingFile) Backups() ([]string, error) {
+	matches, err := filepath.Glob(f.filename + ".*")
+	if err != nil {
+		return nil, err
+	}
+	backups := matches[:0]
+	for _, m := range matches {
+		if _, err := time.Parse(rotatingFileTimeFormat, strings.TrimPrefix(m, f.filename+".")); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	// the timestamps are sorted as strings
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Close closes the file, the next writes return an error
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,62 @@
+package iris
+
+import (
+	"net"
+	"os"
+)
+
+// Runner starts the server of an application, it's passed to the app.Run,
+// i.e app.Run(iris.Addr(":8080")), app.Run(iris.UnixAddr("/tmp/app.sock", 0666)) or app.Run(iris.Listener(ln))
+type Runner func(*Framework) error
+
+// Run starts the server by the 'runner', it blocks like the Serve
+func Run(runner Runner) error {
+	return Default.Run(runner)
+}
+
+// Run starts the server by the 'runner', it blocks like the Serve
+func (s *Framework) Run(runner Runner) error {
+	return runner(s)
+}
+
+// Addr returns the Runner which listens to the tcp4 'addr', host:port
+func Addr(addr string) Runner {
+	return func(s *Framework) error {
+		addr = ParseHost(addr)
+		if s.Config.VHost == "" {
+			s.Config.VHost = addr
+			// this will be set as the front-end listening addr
+		}
+		ln, err := TCP4(addr)
+		if err != nil {
+			return err
+		}
+		return s.serveOwned(ln)
+	}
+}
+
+// Listener returns the Runner which serves the connections of the 'ln', any net.Listener,
+// i.e a listener which is passed by a process manager or an in-memory listener of the tests
+func Listener(ln net.Listener) Runner {
+	return func(s *Framework) error {
+		return s.Serve(ln)
+	}
+}
+
+// UnixAddr returns the Runner which listens to the unix domain socket 'path', which is created with the 'mode' permissions,
+// for the applications which are served behind a local reverse proxy, i.e nginx.
+// The socket file is removed when the listener is closed, on Shutdown.
+func UnixAddr(path string, mode os.FileMode) Runner {
+	return func(s *Framework) error {
+		// *on unix listen we don't parse the host, because sometimes it causes problems to the user
+		if s.Config.VHost == "" {
+			s.Config.VHost = path
+			// this will be set as the front-end listening addr
+		}
+		ln, err := UNIX(path, mode)
+		if err != nil {
+			return err
+		}
+		return s.serveOwned(ln)
+	}
+}
@@ -0,0 +1,153 @@
+package iris
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/kataras/go-errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+var errSanitizer = errors.New("Sanitize: unknown sanitizer %q of the %s field")
+
+// Sanitizer cleans a string value of the request, see the RegisterSanitizer
+type Sanitizer func(s string) string
+
+// defaultSanitizers the built'n sanitizers, by name
+var defaultSanitizers = map[string]Sanitizer{
+	// trim removes the leading and the trailing white space
+	"trim": strings.TrimSpace,
+	// strip removes the control characters, except the new lines and the tabs
+	"strip": stripControl,
+	// escape escapes the <, >, &, ' and " to their html entities
+	"escape": html.EscapeString,
+	// nfc normalizes the unicode to its canonical composition, so the "é" and the "é" are the same
+	"nfc": norm.NFC.String,
+	// nfkc normalizes the unicode to its compatibility composition, i.e the "ﬁ" to the "fi" and the full-width letters to the ascii ones
+	"nfkc": norm.NFKC.String,
+}
+
+// stripControl removes the control characters of the 's', except the \n, \r and \t
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// RegisterSanitizer registers a sanitizer to the default iris instance, see the Framework's RegisterSanitizer
+func RegisterSanitizer(name string, fn Sanitizer) {
+	Default.RegisterSanitizer(name, fn)
+}
+
+// RegisterSanitizer registers the 'fn' as the sanitizer of the 'name', which is used by the `sanitize` struct tags,
+// it replaces the previous one of the name and the built'n ones, the "trim", "strip", "escape", "nfc" and "nfkc".
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.RegisterSanitizer("lower", strings.ToLower)
+//
+// type Signup struct {
+//	Email string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+//	Bio   string `json:"bio" sanitize:"strip,nfc,escape"`
+// }
+func (s *Framework) RegisterSanitizer(name string, fn Sanitizer) {
+	if s.sanitizers == nil {
+		s.sanitizers = make(map[string]Sanitizer)
+	}
+	s.sanitizers[name] = fn
+}
+
+// sanitizer returns the sanitizer of the 'name', nil if there is not any
+func (s *Framework) sanitizer(name string) Sanitizer {
+	if fn, ok := s.sanitizers[name]; ok {
+		return fn
+	}
+	return defaultSanitizers[name]
+}
+
+// sanitize applies the sanitizers of the `sanitize` struct tags to the string, *string and []string fields of the 'v',
+// the nested structs, the pointers to structs and the slices of structs too, in the order of the tag
+func (s *Framework) sanitize(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		// the values which are not bound through a pointer can't be changed
+		if !v.CanAddr() {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := s.sanitize(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		fv := v.Field(i)
+		tag := f.Tag.Get("sanitize")
+		if tag == "" || tag == "-" {
+			if err := s.sanitize(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		var chain []Sanitizer
+		for _, name := range strings.Split(tag, ",") {
+			name = strings.TrimSpace(name)
+			fn := s.sanitizer(name)
+			if fn == nil {
+				return errSanitizer.Format(name, f.Name)
+			}
+			chain = append(chain, fn)
+		}
+		sanitizeValue(fv, chain)
+	}
+	return nil
+}
+
+// sanitizeValue applies the 'chain' to the string, *string or []string 'fv'
+func sanitizeValue(fv reflect.Value, chain []Sanitizer) {
+	switch fv.Kind() {
+	case reflect.String:
+		str := fv.String()
+		for _, fn := range chain {
+			str = fn(str)
+		}
+		fv.SetString(str)
+	case reflect.Ptr:
+		if !fv.IsNil() {
+			sanitizeValue(fv.Elem(), chain)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			sanitizeValue(fv.Index(i), chain)
+		}
+	}
+}
+
+// bound sanitizes and validates the value which is bound by the ctx.Bind and the readers
+func (ctx *Context) bound(v interface{}) error {
+	if err := ctx.framework.sanitize(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	return ctx.validate(v)
+}
@@ -0,0 +1,157 @@
+package iris
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultSecurityMethods the methods which the UseSecurity allows by default, the TRACE and the CONNECT are rejected
+	DefaultSecurityMethods = []string{MethodGet, MethodHead, MethodPost, MethodPut, MethodPatch, MethodDelete, MethodOptions}
+	// DefaultBlockedPaths the path segments which the UseSecurity blocks by default, the files of the version control,
+	// the environment and the server configuration which are often probed by the scanners
+	DefaultBlockedPaths = []string{".git", ".svn", ".hg", ".env", ".htaccess", ".htpasswd", ".DS_Store"}
+)
+
+const (
+	// DefaultSecurityReadHeaderTimeout the time which the UseSecurity allows to read the request headers by default,
+	// the slow clients which keep the connections open, i.e the slowloris attacks, are disconnected
+	DefaultSecurityReadHeaderTimeout = 10 * time.Second
+	// DefaultSecurityMaxHeaderBytes the size of the request headers which the UseSecurity allows by default
+	DefaultSecurityMaxHeaderBytes = 64 << 10
+	// DefaultSecurityMaxHeaders the number of the request headers which the UseSecurity allows by default
+	DefaultSecurityMaxHeaders = 100
+)
+
+// SecurityPolicy the limits and the rejections of the UseSecurity, its zero fields are set to their secure defaults
+type SecurityPolicy struct {
+	// MaxRequestBodySize the size of the request bodies, the larger ones are rejected with the 413 status code, see the Config.MaxRequestBodySize
+	// Defaults to the Config.MaxRequestBodySize
+	MaxRequestBodySize int64
+	// MaxHeaderBytes the size of the request headers, see the Config.MaxHeaderBytes
+	// Defaults to the DefaultSecurityMaxHeaderBytes, 64KB
+	MaxHeaderBytes int
+	// MaxHeaders the number of the request headers, the requests with more are rejected with the 431 status code
+	// Defaults to the DefaultSecurityMaxHeaders, 100
+	MaxHeaders int
+	// ReadHeaderTimeout the time to read the request headers, see the Config.ReadHeaderTimeout
+	// Defaults to the DefaultSecurityReadHeaderTimeout, 10 seconds
+	ReadHeaderTimeout time.Duration
+	// AllowedMethods the methods of the requests, the rest are rejected with the 405 status code
+	// Defaults to the DefaultSecurityMethods
+	AllowedMethods []string
+	// BlockedPaths the path segments which are rejected with the 404 status code, i.e the "/.git/config",
+	// the paths with the ".." segments, the encoded slashes and dots and the NUL bytes are always rejected with the 400 status code
+	// Defaults to the DefaultBlockedPaths
+	BlockedPaths []string
+}
+
+// UseSecurity applies the security policy to the default iris instance, see the Framework's UseSecurity
+func UseSecurity(policy SecurityPolicy) {
+	Default.UseSecurity(policy)
+}
+
+// UseSecurity applies the limits of the 'policy' to the server and rejects the requests which violate it before the routing,
+// its body, header and timeout limits set the Config's ones.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.UseSecurity(iris.SecurityPolicy{MaxRequestBodySize: 1 << 20, AllowedMethods: []string{iris.MethodGet, iris.MethodPost}})
+func (s *Framework) UseSecurity(policy SecurityPolicy) {
+	if policy.MaxRequestBodySize <= 0 {
+		policy.MaxRequestBodySize = s.Config.MaxRequestBodySize
+	}
+	if policy.MaxHeaderBytes <= 0 {
+		policy.MaxHeaderBytes = DefaultSecurityMaxHeaderBytes
+	}
+	if policy.MaxHeaders <= 0 {
+		policy.MaxHeaders = DefaultSecurityMaxHeaders
+	}
+	if policy.ReadHeaderTimeout <= 0 {
+		policy.ReadHeaderTimeout = DefaultSecurityReadHeaderTimeout
+	}
+	if len(policy.AllowedMethods) == 0 {
+		policy.AllowedMethods = DefaultSecurityMethods
+	}
+	if policy.BlockedPaths == nil {
+		policy.BlockedPaths = DefaultBlockedPaths
+	}
+
+	s.Config.MaxRequestBodySize = policy.MaxRequestBodySize
+	s.Config.MaxHeaderBytes = policy.MaxHeaderBytes
+	s.Config.ReadHeaderTimeout = policy.ReadHeaderTimeout
+	s.security = &policy
+}
+
+// securityHandler rejects the requests of the 'h' which violate the UseSecurity's policy
+func (s *Framework) securityHandler(h http.Handler) http.Handler {
+	policy := s.security
+	methods := make(map[string]bool, len(policy.AllowedMethods))
+	for _, m := range policy.AllowedMethods {
+		methods[strings.ToUpper(m)] = true
+	}
+	blocked := make(map[string]bool, len(policy.BlockedPaths))
+	for _, p := range policy.BlockedPaths {
+		blocked[strings.ToLower(strings.Trim(p, "/"))] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusOK
+		switch {
+		case !methods[r.Method]:
+			status = StatusMethodNotAllowed
+		case len(r.Header) > policy.MaxHeaders:
+			status = StatusRequestHeaderFieldsTooLarge
+		case r.ContentLength > policy.MaxRequestBodySize:
+			status = StatusRequestEntityTooLarge
+		case isDangerousPath(r.URL.EscapedPath()):
+			status = StatusBadRequest
+		case isBlockedPath(blocked, r.URL.Path):
+			status = StatusNotFound
+		}
+		if status != StatusOK {
+			if status == StatusMethodNotAllowed {
+				w.Header().Set("Allow", strings.Join(policy.AllowedMethods, ", "))
+			}
+			ctx := s.AcquireCtx(w, r)
+			s.mux.fireError(status, ctx)
+			s.ReleaseCtx(ctx)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// isDangerousPath reports whether the escaped 'path' contains a ".." segment, an encoded slash, backslash or dot, or a NUL byte,
+// which are used to escape from the served directories
+func isDangerousPath(path string) bool {
+	lower := strings.ToLower(path)
+	for _, encoded := range []string{"%2f", "%5c", "%2e", "%00"} {
+		if strings.Contains(lower, encoded) {
+			return true
+		}
+	}
+	if strings.ContainsAny(path, "\x00\\") {
+		return true
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlockedPath reports whether a segment of the 'path' is one of the 'blocked'
+func isBlockedPath(blocked map[string]bool, path string) bool {
+	if len(blocked) == 0 {
+		return false
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && blocked[strings.ToLower(segment)] {
+			return true
+		}
+	}
+	return false
+}
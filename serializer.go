@@ -0,0 +1,182 @@
+package iris
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kataras/go-errors"
+	"github.com/kataras/go-serializer"
+	"google.golang.org/protobuf/proto"
+)
+
+var errNotAcceptable = errors.New("Negotiate: none of the %s is acceptable by the Accept: %q")
+
+// registeredSerializer a serializer of the RegisterSerializer and its default options
+type registeredSerializer struct {
+	serializer serializer.Serializer
+	options    map[string]interface{}
+}
+
+// RegisterSerializer registers a serializer of a content type to the default iris instance, see the Framework's RegisterSerializer
+func RegisterSerializer(contentType string, e serializer.Serializer, options ...map[string]interface{}) {
+	Default.RegisterSerializer(contentType, e, options...)
+}
+
+// RegisterSerializer sets the serializer of the 'contentType', i.e "application/vnd.api+json",
+// which is used by the ctx.Render, ctx.RenderWithStatus and ctx.Negotiate,
+// it replaces the previous one, the ones of the UseSerializer and the built'n JSON and XML ones too.
+// The 'options' are the defaults of its renders, i.e {"charset": "UTF-8", "gzip": true, "indent": true},
+// the options of each render override them.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// app.RegisterSerializer("application/vnd.api+json", jsonAPISerializer, map[string]interface{}{"gzip": true})
+// ctx.Render("application/vnd.api+json", article)
+func (s *Framework) RegisterSerializer(contentType string, e serializer.Serializer, options ...map[string]interface{}) {
+	if s.registeredSerializers == nil {
+		s.registeredSerializers = make(map[string]*registeredSerializer)
+	}
+	if _, ok := s.registeredSerializers[contentType]; !ok {
+		s.serializerTypes = append(s.serializerTypes, contentType)
+	}
+	r := &registeredSerializer{serializer: e}
+	if len(options) > 0 {
+		r.options = options[0]
+	}
+	s.registeredSerializers[contentType] = r
+}
+
+// serialize serializes the 'obj' with the registered serializer of the 'contentType', or with the UseSerializer's ones,
+// it returns the options of the render merged with the defaults of the registered serializer
+func (s *Framework) serialize(contentType string, obj interface{}, options []map[string]interface{}) ([]byte, []map[string]interface{}, error) {
+	r, ok := s.registeredSerializers[contentType]
+	if !ok {
+		b, err := s.serializers.Serialize(contentType, obj, options...)
+		return b, options, err
+	}
+	if len(r.options) > 0 {
+		merged := make(map[string]interface{}, len(r.options))
+		for k, v := range r.options {
+			merged[k] = v
+		}
+		if len(options) > 0 {
+			for k, v := range options[0] {
+				merged[k] = v
+			}
+		}
+		options = []map[string]interface{}{merged}
+	}
+	b, err := r.serializer.Serialize(obj, options...)
+	return b, options, err
+}
+
+// negotiableTypes returns the content types which the ctx.Negotiate can render, the JSON and the XML first
+func (s *Framework) negotiableTypes() []string {
+	types := []string{contentJSON, contentXML}
+	for _, t := range s.serializerTypes {
+		if t != contentJSON && t != contentXML {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Negotiate renders the 'v' with the serializer of the content type which the request's Accept header prefers,
+// the JSON, the XML or one of the RegisterSerializer, the JSON if there is no Accept header.
+// The protobuf messages are rendered as protobuf, see the ctx.Proto, or as their json mapping.
+// It responds with the 406 Not Acceptable status code and returns an error if none of them is acceptable.
+//
+// Usage:
+// app.RegisterSerializer("application/x-yaml", yamlSerializer)
+// ctx.Negotiate(iris.StatusOK, user)
+func (ctx *Context) Negotiate(status int, v interface{}, options ...map[string]interface{}) error {
+	if msg, ok := v.(proto.Message); ok {
+		return ctx.negotiateProto(status, msg)
+	}
+	offers := ctx.framework.negotiableTypes()
+	ctx.Vary("Accept")
+	accept := ctx.RequestHeader("Accept")
+	contentType := negotiateContentType(accept, offers)
+	if contentType == "" {
+		ctx.EmitError(StatusNotAcceptable)
+		return errNotAcceptable.Format(strings.Join(offers, ", "), accept)
+	}
+	return ctx.RenderWithStatus(status, contentType, v, options...)
+}
+
+// acceptRange a media range of an Accept header
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// acceptAliases the media types which are rendered by a serializer of another content type
+var acceptAliases = map[string]string{
+	"application/xml":                 contentXML,
+	"application/protobuf":            contentProtobuf,
+	"application/vnd.google.protobuf": contentProtobuf,
+	"application/x-msgpack":           contentMsgpack,
+}
+
+// parseAccept returns the media ranges of an Accept header
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if alias, ok := acceptAliases[mediaType]; ok {
+			mediaType = alias
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	return ranges
+}
+
+// negotiateContentType returns the offer with the highest quality in the 'accept', the first one on a tie,
+// empty if none is acceptable
+func negotiateContentType(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the quality of the most specific media range which matches the 'offer'
+func acceptQuality(ranges []acceptRange, offer string) float64 {
+	offer = strings.ToLower(offer)
+	offerType := strings.SplitN(offer, "/", 2)[0]
+	q, specificity := 0.0, 0
+	for _, r := range ranges {
+		s := 0
+		switch {
+		case r.mediaType == offer:
+			s = 3
+		case r.mediaType == offerType+"/*":
+			s = 2
+		case r.mediaType == "*/*":
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,62 @@
+package iris
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serverTimingHeader the header of the backend timings, see https://www.w3.org/TR/server-timing/
+const serverTimingHeader = "Server-Timing"
+
+// serverTiming a metric of the Server-Timing header
+type serverTiming struct {
+	name string
+	dur  time.Duration
+	desc string
+}
+
+// RecordTiming records the duration of a phase of the request, i.e the "db" query or the template "render",
+// the metrics are sent with the Server-Timing header when the response is flushed,
+// so the network panel of the browsers' devtools shows them next to the request's timings.
+// The 'desc' is optional, a zero 'dur' records the metric without duration, i.e a "cache" hit.
+//
+// Usage:
+// start := time.Now()
+// users, err := db.Users()
+// ctx.RecordTiming("db", time.Since(start), "Users query")
+func (ctx *Context) RecordTiming(name string, dur time.Duration, desc string) {
+	w := ctx.ResponseWriter
+	w.timings = append(w.timings, serverTiming{name: name, dur: dur, desc: desc})
+}
+
+// StartTiming starts the timing of a phase of the request and returns the func which records it, see RecordTiming
+//
+// Usage:
+// defer ctx.StartTiming("render", "")()
+func (ctx *Context) StartTiming(name string, desc string) (stop func()) {
+	start := time.Now()
+	return func() {
+		ctx.RecordTiming(name, time.Since(start), desc)
+	}
+}
+
+// formatServerTiming formats the metrics as the value of the Server-Timing header, i.e `db;dur=53.2;desc="Users query", cache`
+func formatServerTiming(timings []serverTiming) string {
+	var b strings.Builder
+	for i, t := range timings {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(t.name)
+		if t.dur > 0 {
+			b.WriteString(";dur=")
+			b.WriteString(strconv.FormatFloat(float64(t.dur)/float64(time.Millisecond), 'f', -1, 64))
+		}
+		if t.desc != "" {
+			b.WriteString(";desc=")
+			b.WriteString(strconv.Quote(t.desc))
+		}
+	}
+	return b.String()
+}
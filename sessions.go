@@ -0,0 +1,104 @@
+package iris
+
+import (
+	"sync"
+
+	"github.com/kataras/go-sessions"
+)
+
+// SessionHookFunc the type of the session's lifecycle event listeners,
+// see Framework.OnSessionCreate, OnSessionUpdate and OnSessionDestroy
+type SessionHookFunc func(ctx *Context, sess sessions.Session)
+
+// sessionHooks keeps the registered session's lifecycle event listeners
+type sessionHooks struct {
+	create  []SessionHookFunc
+	update  []SessionHookFunc
+	destroy []SessionHookFunc
+	mu      sync.RWMutex
+}
+
+func (h *sessionHooks) empty() bool {
+	h.mu.RLock()
+	empty := len(h.create) == 0 && len(h.update) == 0 && len(h.destroy) == 0
+	h.mu.RUnlock()
+	return empty
+}
+
+// fire calls the listeners of the 'hooks', which is read under the lock,
+// the listeners are called after its release, so they can register more
+func (h *sessionHooks) fire(hooks *[]SessionHookFunc, ctx *Context, sess sessions.Session) {
+	h.mu.RLock()
+	listeners := *hooks
+	h.mu.RUnlock()
+	for i := range listeners {
+		listeners[i](ctx, sess)
+	}
+}
+
+func (h *sessionHooks) fireCreate(ctx *Context, sess sessions.Session) {
+	h.fire(&h.create, ctx, sess)
+}
+
+func (h *sessionHooks) fireUpdate(ctx *Context, sess sessions.Session) {
+	h.fire(&h.update, ctx, sess)
+}
+
+func (h *sessionHooks) fireDestroy(ctx *Context, sess sessions.Session) {
+	h.fire(&h.destroy, ctx, sess)
+}
+
+// hookedSession wraps a session in order to fire the OnSessionUpdate listeners
+// on each change of its values or its flash messages
+type hookedSession struct {
+	sessions.Session
+	ctx *Context
+}
+
+func (s *hookedSession) updated() {
+	s.ctx.framework.sessionHooks.fireUpdate(s.ctx, s)
+}
+
+// Set fills the session with an entry, it receives a key and a value
+func (s *hookedSession) Set(key string, value interface{}) {
+	s.Session.Set(key, value)
+	s.updated()
+}
+
+// SetFlash sets a flash message by its key
+func (s *hookedSession) SetFlash(key string, value interface{}) {
+	s.Session.SetFlash(key, value)
+	s.updated()
+}
+
+// Delete removes an entry by its key
+func (s *hookedSession) Delete(key string) {
+	s.Session.Delete(key)
+	s.updated()
+}
+
+// DeleteFlash removes a flash message by its key
+func (s *hookedSession) DeleteFlash(key string) {
+	s.Session.DeleteFlash(key)
+	s.updated()
+}
+
+// Clear removes all entries
+func (s *hookedSession) Clear() {
+	s.Session.Clear()
+	s.updated()
+}
+
+// ClearFlashes removes all flash messages
+func (s *hookedSession) ClearFlashes() {
+	s.Session.ClearFlashes()
+	s.updated()
+}
+
+// unwrapSession returns the underline session of a hooked session
+func unwrapSession(sess sessions.Session) sessions.Session {
+	if h, ok := sess.(*hookedSession); ok {
+		return h.Session
+	}
+	return sess
+}
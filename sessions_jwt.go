@@ -0,0 +1,514 @@
+package iris
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+	"github.com/kataras/go-sessions"
+)
+
+// DefaultJWTSessionsMaxCookieSize the default maximum size of the stateless session's cookie,
+// browsers are required to accept at least 4096 bytes per cookie (name, value and attributes).
+const DefaultJWTSessionsMaxCookieSize = 4096
+
+// DefaultJWTSessionsMaxRevoked the default maximum number of the remotely destroyed stateless sessions which are remembered
+const DefaultJWTSessionsMaxRevoked = 10000
+
+var (
+	errJWTSessionCookieTooLarge = errors.New("Stateless session's cookie is too large, %d bytes, maximum is %d bytes. Session's changes are not saved")
+	errJWTSessionEncryptionKey  = errors.New("Stateless session's encryption key is invalid. Trace: %s")
+	errJWTSessionMalformed      = errors.New("Stateless session's token is malformed")
+	errJWTSessionSignature      = errors.New("Stateless session's token signature is invalid")
+	errJWTSessionExpired        = errors.New("Stateless session's token is expired")
+	errJWTSessionRevoked        = errors.New("Stateless session's token is revoked")
+)
+
+// the header is always the same, we only support the HS256 algorithm.
+var jwtSessionHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// jwtSessionClaims the payload of the session's token
+type jwtSessionClaims struct {
+	ID       string                 `json:"sid"`
+	IssuedAt int64                  `json:"iat"`
+	Expires  int64                  `json:"exp,omitempty"`
+	Values   map[string]interface{} `json:"data,omitempty"`
+	Flashes  map[string]interface{} `json:"flash,omitempty"`
+	// Encrypted contains the base64 AES-GCM sealed json of the values and flashes, when encryption is enabled
+	Encrypted string `json:"enc,omitempty"`
+}
+
+// jwtSessionData is the (encrypted) part of the claims which holds the actual session's values
+type jwtSessionData struct {
+	Values  map[string]interface{} `json:"data,omitempty"`
+	Flashes map[string]interface{} `json:"flash,omitempty"`
+}
+
+// jwtSessions is the stateless session manager,
+// the whole session lives inside a signed (and optionally encrypted) cookie, nothing is stored server-side.
+type jwtSessions struct {
+	config JWTSessionsConfiguration
+	cookie string
+	aead   cipher.AEAD
+	logger func(format string, a ...interface{})
+	// now and newID are the framework's Clock and IDGenerator
+	now   func() time.Time
+	newID func() string
+	// revoked keeps the ids of the remotely destroyed sessions with their revoke time,
+	// a token can't be taken back from the client so we have to remember them until they expire.
+	revoked map[string]time.Time
+	// revocations the revokes in their order, the oldest are forgotten first
+	revocations []jwtRevocation
+	mu          sync.RWMutex
+}
+
+// jwtRevocation a revoke of a session
+type jwtRevocation struct {
+	sid string
+	at  time.Time
+}
+
+func newJWTSessions(cookie string, c JWTSessionsConfiguration, logger func(format string, a ...interface{})) (*jwtSessions, error) {
+	if c.MaxCookieSize <= 0 {
+		c.MaxCookieSize = DefaultJWTSessionsMaxCookieSize
+	}
+	if c.MaxRevoked <= 0 {
+		c.MaxRevoked = DefaultJWTSessionsMaxRevoked
+	}
+	if cookie == "" {
+		cookie = DefaultCookieName
+	}
+	m := &jwtSessions{config: c, cookie: cookie, logger: logger, now: time.Now, newID: newRandomID, revoked: make(map[string]time.Time)}
+	if len(c.EncryptionKey) > 0 {
+		block, err := aes.NewCipher(c.EncryptionKey)
+		if err != nil {
+			return nil, errJWTSessionEncryptionKey.Format(err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errJWTSessionEncryptionKey.Format(err)
+		}
+		m.aead = aead
+	}
+	return m, nil
+}
+
+func (m *jwtSessions) sign(unsigned string) string {
+	h := hmac.New(sha256.New, m.config.Secret)
+	h.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// encode returns the signed token of the session
+func (m *jwtSessions) encode(s *jwtSession) (string, error) {
+	claims := jwtSessionClaims{ID: s.sid, IssuedAt: m.now().Unix()}
+	if m.config.Expires > 0 {
+		claims.Expires = m.now().Add(m.config.Expires).Unix()
+	}
+
+	if m.aead != nil {
+		plain, err := json.Marshal(jwtSessionData{Values: s.values, Flashes: s.flashes})
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, m.aead.NonceSize())
+		if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+		claims.Encrypted = base64.RawURLEncoding.EncodeToString(m.aead.Seal(nonce, nonce, plain, nil))
+	} else {
+		claims.Values = s.values
+		claims.Flashes = s.flashes
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := jwtSessionHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + m.sign(unsigned), nil
+}
+
+// decode verifies the token and returns its claims, with the values and the flashes decrypted
+func (m *jwtSessions) decode(token string) (*jwtSessionClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtSessionHeader {
+		return nil, errJWTSessionMalformed
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(m.sign(parts[0]+"."+parts[1]))) {
+		return nil, errJWTSessionSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errJWTSessionMalformed.AppendErr(err)
+	}
+
+	claims := &jwtSessionClaims{}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return nil, errJWTSessionMalformed.AppendErr(err)
+	}
+
+	if claims.Expires > 0 && m.now().Unix() > claims.Expires {
+		return nil, errJWTSessionExpired
+	}
+
+	if m.isRevoked(claims.ID, claims.IssuedAt) {
+		return nil, errJWTSessionRevoked
+	}
+
+	if claims.Encrypted != "" {
+		if m.aead == nil {
+			return nil, errJWTSessionMalformed
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(claims.Encrypted)
+		if err != nil || len(sealed) < m.aead.NonceSize() {
+			return nil, errJWTSessionMalformed
+		}
+		nonce, ciphertext := sealed[:m.aead.NonceSize()], sealed[m.aead.NonceSize():]
+		plain, err := m.aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return nil, errJWTSessionSignature.AppendErr(err)
+		}
+		data := jwtSessionData{}
+		if err = json.Unmarshal(plain, &data); err != nil {
+			return nil, errJWTSessionMalformed.AppendErr(err)
+		}
+		claims.Values = data.Values
+		claims.Flashes = data.Flashes
+		claims.Encrypted = ""
+	}
+
+	return claims, nil
+}
+
+// revoke invalidates all the tokens of a session issued until now,
+// the revokes older than the Expires, or than the MaxRevoked latest ones, are forgotten
+func (m *jwtSessions) revoke(sid string) {
+	now := m.now()
+	m.mu.Lock()
+	m.revoked[sid] = now
+	m.revocations = append(m.revocations, jwtRevocation{sid: sid, at: now})
+	for len(m.revocations) > 0 {
+		oldest := m.revocations[0]
+		// tokens issued before that are already expired, no need to remember them
+		expired := m.config.Expires > 0 && now.Sub(oldest.at) > m.config.Expires
+		if !expired && len(m.revocations) <= m.config.MaxRevoked {
+			break
+		}
+		m.revocations = m.revocations[1:]
+		// the session may be revoked again since then
+		if t, ok := m.revoked[oldest.sid]; ok && t.Equal(oldest.at) {
+			delete(m.revoked, oldest.sid)
+		}
+	}
+	m.mu.Unlock()
+}
+
+func (m *jwtSessions) isRevoked(sid string, issuedAt int64) bool {
+	m.mu.RLock()
+	t, ok := m.revoked[sid]
+	m.mu.RUnlock()
+	return ok && issuedAt <= t.Unix()
+}
+
+// Start returns the stateless session of the request's cookie,
+// a new empty session is returned if the cookie is missing, invalid or expired.
+func (m *jwtSessions) Start(r *http.Request) *jwtSession {
+	s := &jwtSession{manager: m}
+	if cookie, err := r.Cookie(m.cookie); err == nil && cookie.Value != "" {
+		if claims, err := m.decode(cookie.Value); err == nil {
+			s.sid = claims.ID
+			s.values = claims.Values
+			s.flashes = claims.Flashes
+		} else {
+			// the client sends a cookie we can't use, replace it with a fresh one on the next write
+			s.dirty = true
+		}
+	}
+	if s.sid == "" {
+		s.sid = m.newID()
+		s.isNew = true
+	}
+	if s.values == nil {
+		s.values = make(map[string]interface{})
+	}
+	if s.flashes == nil {
+		s.flashes = make(map[string]interface{})
+	}
+	return s
+}
+
+// commit writes the session's cookie to the response, if the session has been changed.
+// It's called by the framework before the response is flushed to the client.
+func (m *jwtSessions) commit(w http.ResponseWriter, r *http.Request, s *jwtSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return
+	}
+	s.dirty = false
+
+	if s.destroyed || (len(s.values) == 0 && len(s.flashes) == 0) {
+		if _, err := r.Cookie(m.cookie); err == nil {
+			http.SetCookie(w, m.newCookie(r, "", -1))
+		}
+		return
+	}
+
+	token, err := m.encode(s)
+	if err != nil {
+		m.logger("%s", errJWTSessionMalformed.AppendErr(err).Error())
+		return
+	}
+
+	maxAge := 0
+	if m.config.Expires > 0 {
+		maxAge = int(m.config.Expires.Seconds())
+	}
+	cookie := m.newCookie(r, token, maxAge)
+	// guard the size, a browser silently drops a cookie which is too large,
+	// so we prefer to keep the previous one and report that.
+	if size := len(cookie.String()); size > m.config.MaxCookieSize {
+		m.logger("%s", errJWTSessionCookieTooLarge.Format(size, m.config.MaxCookieSize).Error())
+		return
+	}
+	http.SetCookie(w, cookie)
+}
+
+func (m *jwtSessions) newCookie(r *http.Request, value string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     m.cookie,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		MaxAge:   maxAge,
+	}
+	if maxAge > 0 {
+		cookie.Expires = m.now().Add(time.Duration(maxAge) * time.Second)
+	} else if maxAge < 0 {
+		cookie.Expires = m.now().Add(-time.Duration(1) * time.Minute)
+	}
+	return cookie
+}
+
+// jwtSession is the sessions.Session implementation of the stateless sessions
+type jwtSession struct {
+	manager   *jwtSessions
+	sid       string
+	values    map[string]interface{}
+	flashes   map[string]interface{}
+	dirty     bool
+	destroyed bool
+	isNew     bool
+	mu        sync.RWMutex
+}
+
+var _ sessions.Session = &jwtSession{}
+
+// ID returns the session's id
+func (s *jwtSession) ID() string {
+	return s.sid
+}
+
+// Get returns the value of an entry by its key
+func (s *jwtSession) Get(key string) interface{} {
+	s.mu.RLock()
+	v := s.values[key]
+	s.mu.RUnlock()
+	return v
+}
+
+// HasFlash returns true if this request has available flash messages
+func (s *jwtSession) HasFlash() bool {
+	s.mu.RLock()
+	has := len(s.flashes) > 0
+	s.mu.RUnlock()
+	return has
+}
+
+// GetFlash returns a flash message which removed on the next request
+func (s *jwtSession) GetFlash(key string) interface{} {
+	s.mu.Lock()
+	v, ok := s.flashes[key]
+	if ok {
+		delete(s.flashes, key)
+		s.dirty = true
+	}
+	s.mu.Unlock()
+	return v
+}
+
+// GetString same as Get but returns as string, if nil then returns an empty string
+func (s *jwtSession) GetString(key string) string {
+	if v, ok := s.Get(key).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// GetFlashString same as GetFlash but returns as string, if nil then returns an empty string
+func (s *jwtSession) GetFlashString(key string) string {
+	if v, ok := s.GetFlash(key).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// GetInt same as Get but returns as int, if not found then returns -1 and an error
+func (s *jwtSession) GetInt(key string) (int, error) {
+	v, err := s.GetFloat64(key)
+	return int(v), err
+}
+
+// GetInt64 same as Get but returns as int64, if not found then returns -1 and an error
+func (s *jwtSession) GetInt64(key string) (int64, error) {
+	v, err := s.GetFloat64(key)
+	return int64(v), err
+}
+
+// GetFloat32 same as Get but returns as float32, if not found then returns -1 and an error
+func (s *jwtSession) GetFloat32(key string) (float32, error) {
+	v, err := s.GetFloat64(key)
+	return float32(v), err
+}
+
+// GetFloat64 same as Get but returns as float64, if not found then returns -1 and an error
+func (s *jwtSession) GetFloat64(key string) (float64, error) {
+	// the values are coming from json, numbers are float64
+	switch v := s.Get(key).(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return -1, errIntParse.Format(v)
+	}
+}
+
+// GetBoolean same as Get but returns as boolean, if not found then returns -1 and an error
+func (s *jwtSession) GetBoolean(key string) (bool, error) {
+	switch v := s.Get(key).(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, errIntParse.Format(v)
+	}
+}
+
+// GetAll returns a copy of all session's values
+func (s *jwtSession) GetAll() map[string]interface{} {
+	s.mu.RLock()
+	items := make(map[string]interface{}, len(s.values))
+	for k, v := range s.values {
+		items[k] = v
+	}
+	s.mu.RUnlock()
+	return items
+}
+
+// GetFlashes returns all flash messages, they are removed on the next request
+func (s *jwtSession) GetFlashes() map[string]interface{} {
+	s.mu.Lock()
+	flashes := s.flashes
+	if len(flashes) > 0 {
+		s.flashes = make(map[string]interface{})
+		s.dirty = true
+	}
+	s.mu.Unlock()
+	return flashes
+}
+
+// VisitAll loop each one entry and calls the callback function func(key,value)
+func (s *jwtSession) VisitAll(cb func(k string, v interface{})) {
+	for k, v := range s.GetAll() {
+		cb(k, v)
+	}
+}
+
+// Set fills the session with an entry, it receives a key and a value
+func (s *jwtSession) Set(key string, value interface{}) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+// SetFlash sets a flash message by its key, it will be available only on the next request
+func (s *jwtSession) SetFlash(key string, value interface{}) {
+	s.mu.Lock()
+	s.flashes[key] = value
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+// Delete removes an entry by its key
+func (s *jwtSession) Delete(key string) {
+	s.mu.Lock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.dirty = true
+	}
+	s.mu.Unlock()
+}
+
+// DeleteFlash removes a flash message by its key
+func (s *jwtSession) DeleteFlash(key string) {
+	s.mu.Lock()
+	if _, ok := s.flashes[key]; ok {
+		delete(s.flashes, key)
+		s.dirty = true
+	}
+	s.mu.Unlock()
+}
+
+// Clear removes all entries
+func (s *jwtSession) Clear() {
+	s.mu.Lock()
+	s.values = make(map[string]interface{})
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+// ClearFlashes removes all flash messages
+func (s *jwtSession) ClearFlashes() {
+	s.mu.Lock()
+	s.flashes = make(map[string]interface{})
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+// destroy clears the session and marks its cookie for removal,
+// the session takes a new id because the old one is revoked
+func (s *jwtSession) destroy() {
+	s.mu.Lock()
+	s.sid = s.manager.newID()
+	s.values = make(map[string]interface{})
+	s.flashes = make(map[string]interface{})
+	s.destroyed = true
+	s.dirty = true
+	s.mu.Unlock()
+}
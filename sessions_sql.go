@@ -0,0 +1,380 @@
+package iris
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+	"github.com/kataras/go-sessions"
+)
+
+// SQLDialect the sql dialect of a SQLSessionDB, see SQLDialectPostgres, SQLDialectMySQL and SQLDialectSQLite
+type SQLDialect int
+
+const (
+	// SQLDialectPostgres the dialect for PostgreSQL databases
+	SQLDialectPostgres SQLDialect = iota
+	// SQLDialectMySQL the dialect for MySQL and MariaDB databases
+	SQLDialectMySQL
+	// SQLDialectSQLite the dialect for SQLite3 databases
+	SQLDialectSQLite
+)
+
+// DefaultSQLSessionsTable the default table name of the SQLSessionDB
+const DefaultSQLSessionsTable = "iris_sessions"
+
+var (
+	errSQLSessionConflict = errors.New("SQL Session store: session '%s' has been modified concurrently, changes are not saved")
+	errSQLSessionQuery    = errors.New("SQL Session store: query failed for session '%s'. Trace: %s")
+	errSQLSessionMigrate  = errors.New("SQL Session store: unable to migrate the '%s' table. Trace: %s")
+	errSQLSessionCleanup  = errors.New("SQL Session store: unable to remove the expired sessions of the '%s' table. Trace: %s")
+)
+
+// param returns the n(1-based) placeholder of a query
+func (d SQLDialect) param(n int) string {
+	if d == SQLDialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+func (d SQLDialect) dataType() string {
+	switch d {
+	case SQLDialectMySQL:
+		return "LONGTEXT"
+	default:
+		return "TEXT"
+	}
+}
+
+func (d SQLDialect) sidType() string {
+	if d == SQLDialectMySQL {
+		return "VARCHAR(255)"
+	}
+	return "TEXT"
+}
+
+// insertIgnore returns the insert statement which does nothing if the row is already there
+func (d SQLDialect) insertIgnore(table string) string {
+	values := "(sid, data, version, updated_at) VALUES (" + d.param(1) + ", " + d.param(2) + ", 1, " + d.param(3) + ")"
+	switch d {
+	case SQLDialectMySQL:
+		return "INSERT IGNORE INTO " + table + " " + values
+	case SQLDialectSQLite:
+		return "INSERT OR IGNORE INTO " + table + " " + values
+	default:
+		return "INSERT INTO " + table + " " + values + " ON CONFLICT (sid) DO NOTHING"
+	}
+}
+
+// SQLSessionDB is a database/sql session store which implements the sessions.Database,
+// register it with app.UseSessionDB(iris.NewSQLSessionDB(db, iris.SQLDialectPostgres, "")).
+//
+// Each write is protected by optimistic locking, a row's version must be the same as it was when the session was loaded,
+// otherwise the write is rejected and reported to the ErrorHandler.
+//
+// Call its Migrate once, before the server starts, to create or upgrade the sessions table.
+type SQLSessionDB struct {
+	// ErrorHandler receives the errors of the Load and Update, which can't return them to the session manager
+	// Defaults to nil, errors are ignored
+	ErrorHandler func(sid string, err error)
+	// Expires the lifetime of a session since its last write, the expired sessions are loaded empty
+	// and their rows are removed by the Cleanup, which runs on its own goroutine once per Expires, since the first write
+	// Defaults to the Config.Sessions.Expires of the UseSessionDB, zero means the rows are kept until their sessions are destroyed
+	Expires time.Duration
+
+	db      *sql.DB
+	dialect SQLDialect
+	table   string
+
+	stmtLoad    *sql.Stmt
+	stmtVersion *sql.Stmt
+	stmtInsert  *sql.Stmt
+	stmtUpdate  *sql.Stmt
+	stmtDelete  *sql.Stmt
+	stmtExpire  *sql.Stmt
+	stmtCleanup *sql.Stmt
+	prepareErr  error
+	prepareOnce sync.Once
+
+	// versions keeps the row's version as it was on the last Load or Update of each session,
+	// the ones of the missing and the expired rows are removed
+	versions map[string]sqlSessionVersion
+	// mu protects the versions only, it's never held while a query runs
+	mu sync.Mutex
+
+	// now is the Clock of the framework, see the UseSessionDB
+	now func() time.Time
+
+	cleanupOnce sync.Once
+	closeOnce   sync.Once
+	done        chan struct{}
+}
+
+// sqlSessionVersion the version of a session's row and the unix time of its last Load or Update
+type sqlSessionVersion struct {
+	version int64
+	seen    int64
+}
+
+var _ sessions.Database = &SQLSessionDB{}
+
+// NewSQLSessionDB returns a new session store which saves the sessions to the 'table' of the 'db',
+// if the table is empty then the DefaultSQLSessionsTable is used
+func NewSQLSessionDB(db *sql.DB, dialect SQLDialect, table string) *SQLSessionDB {
+	if table == "" {
+		table = DefaultSQLSessionsTable
+	}
+	return &SQLSessionDB{
+		db:       db,
+		dialect:  dialect,
+		table:    table,
+		versions: make(map[string]sqlSessionVersion),
+		now:      time.Now,
+		done:     make(chan struct{}),
+	}
+}
+
+// Migrate creates the sessions table if not exists,
+// if it exists but it has been created by an older version, without the optimistic locking columns, then it adds them
+func (s *SQLSessionDB) Migrate() error {
+	d := s.dialect
+	create := "CREATE TABLE IF NOT EXISTS " + s.table + " (" +
+		"sid " + d.sidType() + " NOT NULL PRIMARY KEY, " +
+		"data " + d.dataType() + " NOT NULL, " +
+		"version BIGINT NOT NULL DEFAULT 1, " +
+		"updated_at BIGINT NOT NULL DEFAULT 0)"
+	if _, err := s.db.Exec(create); err != nil {
+		return errSQLSessionMigrate.Format(s.table, err)
+	}
+
+	for _, column := range []string{"version BIGINT NOT NULL DEFAULT 1", "updated_at BIGINT NOT NULL DEFAULT 0"} {
+		name := column[:strings.IndexByte(column, ' ')]
+		// probe the column, the query fails if it's missing
+		if rows, err := s.db.Query("SELECT " + name + " FROM " + s.table + " WHERE 1 = 0"); err == nil {
+			rows.Close()
+			continue
+		}
+		if _, err := s.db.Exec("ALTER TABLE " + s.table + " ADD COLUMN " + column); err != nil {
+			return errSQLSessionMigrate.Format(s.table, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLSessionDB) prepare() error {
+	s.prepareOnce.Do(func() {
+		d, t := s.dialect, s.table
+		queries := []struct {
+			stmt  **sql.Stmt
+			query string
+		}{
+			{&s.stmtLoad, "SELECT data, version, updated_at FROM " + t + " WHERE sid = " + d.param(1)},
+			{&s.stmtVersion, "SELECT version FROM " + t + " WHERE sid = " + d.param(1)},
+			{&s.stmtInsert, d.insertIgnore(t)},
+			{&s.stmtUpdate, "UPDATE " + t + " SET data = " + d.param(1) + ", version = version + 1, updated_at = " + d.param(2) +
+				" WHERE sid = " + d.param(3) + " AND version = " + d.param(4)},
+			{&s.stmtDelete, "DELETE FROM " + t + " WHERE sid = " + d.param(1)},
+			{&s.stmtExpire, "DELETE FROM " + t + " WHERE sid = " + d.param(1) + " AND version = " + d.param(2)},
+			{&s.stmtCleanup, "DELETE FROM " + t + " WHERE updated_at < " + d.param(1)},
+		}
+		for _, q := range queries {
+			stmt, err := s.db.Prepare(q.query)
+			if err != nil {
+				s.prepareErr = err
+				return
+			}
+			*q.stmt = stmt
+		}
+	})
+	return s.prepareErr
+}
+
+func (s *SQLSessionDB) report(sid string, err error) {
+	if s.ErrorHandler != nil {
+		s.ErrorHandler(sid, err)
+	}
+}
+
+// Load returns the saved values of a session, it's called by the session manager when a session is started
+func (s *SQLSessionDB) Load(sid string) map[string]interface{} {
+	values := make(map[string]interface{})
+	if err := s.prepare(); err != nil {
+		s.report(sid, errSQLSessionQuery.Format(sid, err))
+		return values
+	}
+
+	var (
+		data      string
+		version   int64
+		updatedAt int64
+	)
+	if err := s.stmtLoad.QueryRow(sid).Scan(&data, &version, &updatedAt); err != nil {
+		s.forget(sid)
+		if err != sql.ErrNoRows {
+			s.report(sid, errSQLSessionQuery.Format(sid, err))
+		}
+		return values
+	}
+
+	now := s.now().Unix()
+	if s.expired(updatedAt, now) {
+		s.forget(sid)
+		// the row is removed only if it's not written meanwhile, so the next write inserts it again
+		if _, err := s.stmtExpire.Exec(sid, version); err != nil {
+			s.report(sid, errSQLSessionQuery.Format(sid, err))
+		}
+		return values
+	}
+
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		s.report(sid, errSQLSessionQuery.Format(sid, err))
+		return values
+	}
+
+	s.remember(sid, version, now)
+	return values
+}
+
+// expired returns true if the row which is written at the 'updatedAt' is expired at the 'now'
+func (s *SQLSessionDB) expired(updatedAt, now int64) bool {
+	return s.Expires > 0 && updatedAt < now-int64(s.Expires/time.Second)
+}
+
+// forget removes the version of the 'sid'
+func (s *SQLSessionDB) forget(sid string) {
+	s.mu.Lock()
+	delete(s.versions, sid)
+	s.mu.Unlock()
+}
+
+// Update saves the new values of a session, it's called by the session manager on each change,
+// an empty 'newValues' removes the session's row
+func (s *SQLSessionDB) Update(sid string, newValues map[string]interface{}) {
+	if err := s.prepare(); err != nil {
+		s.report(sid, errSQLSessionQuery.Format(sid, err))
+		return
+	}
+
+	s.startCleanup()
+
+	now := s.now().Unix()
+	if len(newValues) == 0 {
+		s.forget(sid)
+		if _, err := s.stmtDelete.Exec(sid); err != nil {
+			s.report(sid, errSQLSessionQuery.Format(sid, err))
+		}
+		return
+	}
+
+	data, err := json.Marshal(newValues)
+	if err != nil {
+		s.report(sid, errSQLSessionQuery.Format(sid, err))
+		return
+	}
+
+	s.mu.Lock()
+	v, known := s.versions[sid]
+	s.mu.Unlock()
+	version := v.version
+
+	var res sql.Result
+	if !known {
+		res, err = s.stmtInsert.Exec(sid, string(data), now)
+	} else {
+		res, err = s.stmtUpdate.Exec(string(data), now, sid, version)
+	}
+	if err != nil {
+		s.report(sid, errSQLSessionQuery.Format(sid, err))
+		return
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		// someone else wrote this session meanwhile, keep its data and remember its version
+		// in order for the next write to be based on that.
+		var latest int64
+		if err := s.stmtVersion.QueryRow(sid).Scan(&latest); err == nil {
+			s.remember(sid, latest, now)
+		} else {
+			s.forget(sid)
+		}
+		s.report(sid, errSQLSessionConflict.Format(sid))
+		return
+	}
+
+	s.remember(sid, version+1, now)
+}
+
+// remember keeps the 'version' of the 'sid'
+func (s *SQLSessionDB) remember(sid string, version, now int64) {
+	s.mu.Lock()
+	s.versions[sid] = sqlSessionVersion{version: version, seen: now}
+	s.mu.Unlock()
+}
+
+// startCleanup starts, once, the goroutine which runs the Cleanup every Expires until the Close,
+// its errors are reported to the ErrorHandler with an empty sid
+func (s *SQLSessionDB) startCleanup() {
+	if s.Expires <= 0 {
+		return
+	}
+	s.cleanupOnce.Do(func() {
+		go func(interval time.Duration) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-s.done:
+					return
+				case <-ticker.C:
+					if _, err := s.Cleanup(); err != nil {
+						s.report("", err)
+					}
+				}
+			}
+		}(s.Expires)
+	})
+}
+
+// Cleanup removes the rows of the sessions which are not written for longer than the Expires and forgets their versions,
+// it returns the number of the removed rows. It runs on its own goroutine once per Expires, it does nothing if the Expires is zero.
+func (s *SQLSessionDB) Cleanup() (int64, error) {
+	if s.Expires <= 0 {
+		return 0, nil
+	}
+	if err := s.prepare(); err != nil {
+		return 0, errSQLSessionCleanup.Format(s.table, err)
+	}
+	now := s.now().Unix()
+	deadline := now - int64(s.Expires/time.Second)
+
+	s.mu.Lock()
+	for sid, v := range s.versions {
+		if v.seen < deadline {
+			delete(s.versions, sid)
+		}
+	}
+	s.mu.Unlock()
+
+	res, err := s.stmtCleanup.Exec(deadline)
+	if err != nil {
+		return 0, errSQLSessionCleanup.Format(s.table, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Close stops the Cleanup's goroutine and releases the prepared statements, it doesn't close the underline *sql.DB
+func (s *SQLSessionDB) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	for _, stmt := range []*sql.Stmt{s.stmtLoad, s.stmtVersion, s.stmtInsert, s.stmtUpdate, s.stmtDelete, s.stmtExpire, s.stmtCleanup} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package iris_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+)
+
+// fakeSessionRow a row of the fakeSessionsDriver's table
+type fakeSessionRow struct {
+	data      string
+	version   int64
+	updatedAt int64
+}
+
+// fakeSessionsDriver is an in-memory database/sql driver and connector which understands the queries of the SQLSessionDB of the SQLite dialect
+type fakeSessionsDriver struct {
+	mu      sync.Mutex
+	table   string
+	exists  bool
+	columns map[string]bool
+	rows    map[string]*fakeSessionRow
+	// fail the errors of the queries, by their prefix
+	fail    map[string]error
+	queries []string
+}
+
+func newFakeSessionsDriver(table string) *fakeSessionsDriver {
+	return &fakeSessionsDriver{table: table, columns: make(map[string]bool), rows: make(map[string]*fakeSessionRow), fail: make(map[string]error)}
+}
+
+// open returns a new *sql.DB of the driver
+func (d *fakeSessionsDriver) open() *sql.DB {
+	return sql.OpenDB(d)
+}
+
+func (d *fakeSessionsDriver) Connect(context.Context) (driver.Conn, error) {
+	return &fakeSessionsConn{d}, nil
+}
+func (d *fakeSessionsDriver) Driver() driver.Driver            { return d }
+func (d *fakeSessionsDriver) Open(string) (driver.Conn, error) { return &fakeSessionsConn{d}, nil }
+
+func (d *fakeSessionsDriver) row(sid string) *fakeSessionRow {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rows[sid]
+}
+
+func (d *fakeSessionsDriver) executed(prefix string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, q := range d.queries {
+		if strings.HasPrefix(q, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeSessionsConn struct{ d *fakeSessionsDriver }
+
+func (c *fakeSessionsConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSessionsStmt{c.d, query}, nil
+}
+func (c *fakeSessionsConn) Close() error { return nil }
+func (c *fakeSessionsConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeSessionsStmt struct {
+	d     *fakeSessionsDriver
+	query string
+}
+
+func (s *fakeSessionsStmt) Close() error  { return nil }
+func (s *fakeSessionsStmt) NumInput() int { return -1 }
+
+func (s *fakeSessionsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, n, err := s.run(args)
+	return driver.RowsAffected(n), err
+}
+
+func (s *fakeSessionsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, _, err := s.run(args)
+	return rows, err
+}
+
+// run executes the query, it returns its rows or the number of the affected ones
+func (s *fakeSessionsStmt) run(args []driver.Value) (*fakeSessionsRows, int64, error) {
+	d, q := s.d, s.query
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, q)
+	for prefix, err := range d.fail {
+		if strings.HasPrefix(q, prefix) {
+			return nil, 0, err
+		}
+	}
+	str := func(i int) string { return args[i].(string) }
+	num := func(i int) int64 { return args[i].(int64) }
+	t := d.table
+
+	switch {
+	case strings.HasPrefix(q, "CREATE TABLE IF NOT EXISTS "+t):
+		if !d.exists {
+			d.exists = true
+			d.columns = map[string]bool{"version": true, "updated_at": true}
+		}
+		return nil, 0, nil
+	case strings.HasPrefix(q, "ALTER TABLE "+t+" ADD COLUMN "):
+		d.columns[strings.Fields(strings.TrimPrefix(q, "ALTER TABLE "+t+" ADD COLUMN "))[0]] = true
+		return nil, 0, nil
+	case strings.HasSuffix(q, "FROM "+t+" WHERE 1 = 0"):
+		column := strings.Fields(q)[1]
+		if !d.columns[column] {
+			return nil, 0, errors.New("no such column: " + column)
+		}
+		return &fakeSessionsRows{columns: []string{column}}, 0, nil
+	case strings.HasPrefix(q, "SELECT data, version, updated_at FROM "+t):
+		rows := &fakeSessionsRows{columns: []string{"data", "version", "updated_at"}}
+		if r, ok := d.rows[str(0)]; ok {
+			rows.values = [][]driver.Value{{r.data, r.version, r.updatedAt}}
+		}
+		return rows, 0, nil
+	case strings.HasPrefix(q, "SELECT version FROM "+t+" WHERE sid"):
+		rows := &fakeSessionsRows{columns: []string{"version"}}
+		if r, ok := d.rows[str(0)]; ok {
+			rows.values = [][]driver.Value{{r.version}}
+		}
+		return rows, 0, nil
+	case strings.HasPrefix(q, "INSERT OR IGNORE INTO "+t):
+		if _, ok := d.rows[str(0)]; ok {
+			return nil, 0, nil
+		}
+		d.rows[str(0)] = &fakeSessionRow{data: str(1), version: 1, updatedAt: num(2)}
+		return nil, 1, nil
+	case strings.HasPrefix(q, "UPDATE "+t):
+		r, ok := d.rows[str(2)]
+		if !ok || r.version != num(3) {
+			return nil, 0, nil
+		}
+		r.data, r.updatedAt = str(0), num(1)
+		r.version++
+		return nil, 1, nil
+	case strings.HasPrefix(q, "DELETE FROM "+t+" WHERE sid = ? AND version = ?"):
+		if r, ok := d.rows[str(0)]; ok && r.version == num(1) {
+			delete(d.rows, str(0))
+			return nil, 1, nil
+		}
+		return nil, 0, nil
+	case strings.HasPrefix(q, "DELETE FROM "+t+" WHERE sid = ?"):
+		if _, ok := d.rows[str(0)]; ok {
+			delete(d.rows, str(0))
+			return nil, 1, nil
+		}
+		return nil, 0, nil
+	case strings.HasPrefix(q, "DELETE FROM "+t+" WHERE updated_at < ?"):
+		var n int64
+		for sid, r := range d.rows {
+			if r.updatedAt < num(0) {
+				delete(d.rows, sid)
+				n++
+			}
+		}
+		return nil, n, nil
+	}
+	return nil, 0, errors.New("unexpected query: " + q)
+}
+
+type fakeSessionsRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeSessionsRows) Columns() []string { return r.columns }
+func (r *fakeSessionsRows) Close() error      { return nil }
+func (r *fakeSessionsRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestSQLSessionDBMigrate(t *testing.T) {
+	d := newFakeSessionsDriver("sessions")
+	// a table of an older version, without the optimistic locking columns
+	d.exists = true
+	store := iris.NewSQLSessionDB(d.open(), iris.SQLDialectSQLite, "sessions")
+
+	if err := store.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if n := d.executed("ALTER TABLE sessions ADD COLUMN "); n != 2 {
+		t.Fatalf("expected the 2 missing columns to be added but got %d", n)
+	}
+	if err := store.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if n := d.executed("ALTER TABLE sessions ADD COLUMN "); n != 2 {
+		t.Fatalf("expected the migrated table to be kept but got %d alters", n)
+	}
+
+	d.fail["CREATE TABLE"] = errors.New("permission denied")
+	if err := store.Migrate(); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("expected the error of the create but got %v", err)
+	}
+}
+
+func TestSQLSessionDB(t *testing.T) {
+	d := newFakeSessionsDriver(iris.DefaultSQLSessionsTable)
+	db := d.open()
+	var reported []error
+	store := iris.NewSQLSessionDB(db, iris.SQLDialectSQLite, "")
+	store.ErrorHandler = func(sid string, err error) { reported = append(reported, err) }
+	if err := store.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// insert, the first write of a session
+	store.Update("a", map[string]interface{}{"user": "kataras"})
+	if r := d.row("a"); r == nil || r.version != 1 || r.data != `{"user":"kataras"}` {
+		t.Fatalf("expected the session to be inserted but got %+v", r)
+	}
+	if values := store.Load("a"); values["user"] != "kataras" {
+		t.Fatalf("expected the saved values but got %v", values)
+	}
+
+	// update, based on the loaded version
+	store.Update("a", map[string]interface{}{"user": "makis"})
+	if r := d.row("a"); r.version != 2 || r.data != `{"user":"makis"}` {
+		t.Fatalf("expected the session to be updated but got %+v", r)
+	}
+
+	// conflict, another instance writes the session meanwhile
+	other := iris.NewSQLSessionDB(db, iris.SQLDialectSQLite, "")
+	other.Load("a")
+	other.Update("a", map[string]interface{}{"user": "other"})
+	store.Update("a", map[string]interface{}{"user": "stale"})
+	if r := d.row("a"); r.data != `{"user":"other"}` || r.version != 3 {
+		t.Fatalf("expected the concurrent write to be kept but got %+v", r)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "modified concurrently") {
+		t.Fatalf("expected the conflict to be reported but got %v", reported)
+	}
+	// the next write is based on the latest version
+	store.Update("a", map[string]interface{}{"user": "latest"})
+	if r := d.row("a"); r.data != `{"user":"latest"}` || r.version != 4 {
+		t.Fatalf("expected the session to be updated after the conflict but got %+v", r)
+	}
+
+	// the version of a removed session is forgotten on its Load, so the next write inserts it again
+	other.Update("a", nil)
+	if d.row("a") != nil {
+		t.Fatal("expected the session to be deleted")
+	}
+	if values := store.Load("a"); len(values) != 0 {
+		t.Fatalf("expected the deleted session to be empty but got %v", values)
+	}
+	store.Update("a", map[string]interface{}{"user": "again"})
+	if r := d.row("a"); r == nil || r.version != 1 || len(reported) != 1 {
+		t.Fatalf("expected the session to be inserted again but got %+v, %v", r, reported)
+	}
+
+	// the errors of the queries are reported
+	d.fail["UPDATE"] = errors.New("connection reset")
+	store.Load("a")
+	store.Update("a", map[string]interface{}{"user": "lost"})
+	if len(reported) != 2 || !strings.Contains(reported[1].Error(), "connection reset") {
+		t.Fatalf("expected the error of the update to be reported but got %v", reported)
+	}
+}
+
+func TestSQLSessionDBExpires(t *testing.T) {
+	d := newFakeSessionsDriver(iris.DefaultSQLSessionsTable)
+	store := iris.NewSQLSessionDB(d.open(), iris.SQLDialectSQLite, "")
+	app := iris.New(iris.OptionSessionsExpires(time.Hour))
+	app.UseSessionDB(store)
+	defer store.Close()
+	if store.Expires != time.Hour {
+		t.Fatalf("expected the Expires of the sessions but got %s", store.Expires)
+	}
+	if err := store.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Update("old", map[string]interface{}{"user": "kataras"})
+	store.Update("expired", map[string]interface{}{"user": "makis"})
+	store.Update("fresh", map[string]interface{}{"user": "gerasimos"})
+	old := time.Now().Add(-2 * time.Hour).Unix()
+	d.row("old").updatedAt, d.row("expired").updatedAt = old, old
+
+	// an expired session is loaded empty and its row is removed
+	if values := store.Load("expired"); len(values) != 0 {
+		t.Fatalf("expected the expired session to be empty but got %v", values)
+	}
+	if d.row("expired") != nil {
+		t.Fatal("expected the row of the expired session to be removed")
+	}
+
+	n, err := store.Cleanup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || d.row("old") != nil || d.row("fresh") == nil {
+		t.Fatalf("expected only the expired row to be removed but got %d", n)
+	}
+}
+
+func TestSQLSessionDBCleanup(t *testing.T) {
+	d := newFakeSessionsDriver(iris.DefaultSQLSessionsTable)
+	store := iris.NewSQLSessionDB(d.open(), iris.SQLDialectSQLite, "")
+	store.Expires = 20 * time.Millisecond
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first write starts the cleanup, which runs on its own goroutine, not on the writes
+	store.Update("old", map[string]interface{}{"user": "kataras"})
+	d.mu.Lock()
+	d.rows["old"].updatedAt = time.Now().Add(-time.Hour).Unix()
+	d.mu.Unlock()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for d.row("old") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the expired row to be removed by the background cleanup")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
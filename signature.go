@@ -0,0 +1,227 @@
+package iris
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultSignatureHeader the default header of the signatures of the VerifySignature
+	DefaultSignatureHeader = "X-Signature"
+	// DefaultSignatureTimestampHeader the default header of the unix timestamps of the signed requests
+	DefaultSignatureTimestampHeader = "X-Signature-Timestamp"
+	// DefaultSignatureTolerance the default clock skew between the signer and the server
+	DefaultSignatureTolerance = 5 * time.Minute
+	// DefaultRedisReplayCachePrefix the prefix of the keys of the RedisReplayCache
+	DefaultRedisReplayCachePrefix = "iris-replay:"
+	// DefaultMemoryReplayCacheMaxEntries the default limit of the entries of the MemoryReplayCache
+	DefaultMemoryReplayCacheMaxEntries = 100000
+)
+
+var (
+	errSignatureReplay = errors.New("VerifySignature: the replay cache failed. Trace: %s")
+	// ErrReplayCacheFull is returned by the MemoryReplayCache's Add when it has the MaxEntries entries which are not expired,
+	// the requests are rejected with the 503 status code until some of them expire
+	ErrReplayCacheFull = errors.New("The replay cache is full")
+)
+
+// ReplayCache remembers the signatures of the VerifySignature, so each signed request is accepted once,
+// the MemoryReplayCache and the RedisReplayCache are ReplayCaches
+type ReplayCache interface {
+	// Add adds the 'key' for the 'ttl', it returns false if it's already there
+	Add(key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryReplayCache is the in-memory ReplayCache of a single instance
+type MemoryReplayCache struct {
+	// MaxEntries the limit of the entries, the memory which the clients can fill,
+	// the Add fails with the ErrReplayCacheFull when it's reached and none of the entries is expired
+	// Defaults to the DefaultMemoryReplayCacheMaxEntries, 100000
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]time.Time
+	// lastCleanup the time of the last removal of the expired entries
+	lastCleanup time.Time
+}
+
+var _ ReplayCache = &MemoryReplayCache{}
+
+// NewMemoryReplayCache returns a new, empty, MemoryReplayCache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{MaxEntries: DefaultMemoryReplayCacheMaxEntries, entries: make(map[string]time.Time)}
+}
+
+// Add adds the 'key' for the 'ttl', it returns false if it's already there
+// and the ErrReplayCacheFull if the cache has the MaxEntries entries
+func (c *MemoryReplayCache) Add(key string, ttl time.Duration) (bool, error) {
+	return c.addAt(key, ttl, time.Now())
+}
+
+// addAt adds the 'key' for the 'ttl' at the 'now', see the Add
+func (c *MemoryReplayCache) addAt(key string, ttl time.Duration, now time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	full := c.MaxEntries > 0 && len(c.entries) >= c.MaxEntries
+	// the expired entries are removed once per ttl, or once per second when the cache is full
+	if since := now.Sub(c.lastCleanup); since > ttl || (full && since > time.Second) {
+		for k, expires := range c.entries {
+			if now.After(expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastCleanup = now
+	}
+	if expires, ok := c.entries[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+	if c.MaxEntries > 0 && len(c.entries) >= c.MaxEntries {
+		return false, ErrReplayCacheFull
+	}
+	c.entries[key] = now.Add(ttl)
+	return true, nil
+}
+
+// addReplay adds the 'key' to the 'cache', at the time of the framework's Clock if it's a MemoryReplayCache
+func addReplay(ctx *Context, cache ReplayCache, key string, ttl time.Duration) (bool, error) {
+	if c, ok := cache.(*MemoryReplayCache); ok {
+		return c.addAt(key, ttl, ctx.framework.now())
+	}
+	return cache.Add(key, ttl)
+}
+
+// RedisReplayCache is the Redis ReplayCache, the instances of the application share its entries
+type RedisReplayCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ ReplayCache = &RedisReplayCache{}
+
+// NewRedisReplayCache returns a new RedisReplayCache, an empty 'prefix' means the DefaultRedisReplayCachePrefix
+func NewRedisReplayCache(client redis.UniversalClient, prefix string) *RedisReplayCache {
+	if prefix == "" {
+		prefix = DefaultRedisReplayCachePrefix
+	}
+	return &RedisReplayCache{client: client, prefix: prefix}
+}
+
+// Add adds the 'key' for the 'ttl', it returns false if it's already there
+func (c *RedisReplayCache) Add(key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(context.Background(), c.prefix+key, 1, ttl).Result()
+}
+
+// SignatureOptions the options of the VerifySignature
+type SignatureOptions struct {
+	// Header the request header of the hex signature, its "sha256=" prefix, if any, is ignored
+	// Defaults to the DefaultSignatureHeader, "X-Signature"
+	Header string
+	// TimestampHeader the request header of the unix seconds of the signature
+	// Defaults to the DefaultSignatureTimestampHeader, "X-Signature-Timestamp"
+	TimestampHeader string
+	// DisableTimestamp signs only the body, i.e the GitHub webhooks, the requests can be replayed without the ReplayCache
+	// Defaults to false, the "timestamp.body" is signed
+	DisableTimestamp bool
+	// Tolerance the clock skew between the signer and the server, the older and the future timestamps are rejected,
+	// it's the time which the ReplayCache remembers a signature too
+	// Defaults to the DefaultSignatureTolerance, 5 minutes
+	Tolerance time.Duration
+	// Hash the hash of the HMAC
+	// Defaults to the sha256.New
+	Hash func() hash.Hash
+	// ReplayCache remembers the accepted signatures, so each request is accepted once
+	// Defaults to a new MemoryReplayCache, use a RedisReplayCache for many instances
+	ReplayCache ReplayCache
+}
+
+// VerifySignature returns a middleware which verifies the HMAC signature of a request with the 'secret',
+// the hex HMAC of its timestamp, a dot and its body, i.e "1700000000.{...}", like the Stripe webhooks.
+// The requests with a missing or wrong signature, a timestamp out of the Tolerance or a replayed signature are rejected with the 401 status code.
+// The body is read by the ctx.BodyBytes, so the next handlers can read it again.
+// Each route can have its own secret.
+//
+// Usage:
+// app.Post("/webhooks/payments", iris.VerifySignature([]byte(os.Getenv("PAYMENTS_SECRET"))), paymentsWebhook)
+// app.Post("/webhooks/github", iris.VerifySignature([]byte(os.Getenv("GITHUB_SECRET")),
+//	iris.SignatureOptions{Header: "X-Hub-Signature-256", DisableTimestamp: true}), githubWebhook)
+func VerifySignature(secret []byte, options ...SignatureOptions) HandlerFunc {
+	var o SignatureOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Header == "" {
+		o.Header = DefaultSignatureHeader
+	}
+	if o.TimestampHeader == "" {
+		o.TimestampHeader = DefaultSignatureTimestampHeader
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = DefaultSignatureTolerance
+	}
+	if o.Hash == nil {
+		o.Hash = sha256.New
+	}
+	if o.ReplayCache == nil {
+		o.ReplayCache = NewMemoryReplayCache()
+	}
+
+	return func(ctx *Context) {
+		signature, err := hex.DecodeString(strings.TrimPrefix(ctx.RequestHeader(o.Header), "sha256="))
+		if err != nil || len(signature) == 0 {
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+		body, err := ctx.BodyBytes()
+		if err != nil {
+			ctx.EmitError(StatusBadRequest)
+			return
+		}
+
+		mac := hmac.New(o.Hash, secret)
+		if !o.DisableTimestamp {
+			timestamp := ctx.RequestHeader(o.TimestampHeader)
+			sec, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				ctx.EmitError(StatusUnauthorized)
+				return
+			}
+			if skew := ctx.framework.now().Sub(time.Unix(sec, 0)); skew > o.Tolerance || skew < -o.Tolerance {
+				ctx.EmitError(StatusUnauthorized)
+				return
+			}
+			mac.Write([]byte(timestamp + "."))
+		}
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+
+		// the signature is checked first, so the cache keeps only the valid ones
+		ok, err := addReplay(ctx, o.ReplayCache, hex.EncodeToString(signature), 2*o.Tolerance)
+		if err == ErrReplayCacheFull {
+			ctx.EmitError(StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			ctx.framework.log(LogLevelError, errSignatureReplay.Format(err).Error())
+			ctx.EmitError(StatusInternalServerError)
+			return
+		}
+		if !ok {
+			ctx.EmitError(StatusUnauthorized)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,127 @@
+package iris
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SlowRequest a request which took longer than the threshold of the OnSlowRequest
+type SlowRequest struct {
+	Method string
+	Path   string
+	// Route the registered path of the request's route, "unmatched" if it matched no route
+	Route    string
+	Duration time.Duration
+	// Stack the stack of the request's goroutine, it's sampled when the request exceeded the threshold,
+	// so it shows where the handler was blocked, i.e a slow query
+	Stack []byte
+}
+
+// slowRequestWatchdog samples the stack of the requests which exceed the threshold
+type slowRequestWatchdog struct {
+	threshold time.Duration
+	cb        func(SlowRequest)
+}
+
+// slowRequestWatch the watch of a request
+type slowRequestWatch struct {
+	start time.Time
+	timer *time.Timer
+
+	mu    sync.Mutex
+	stack []byte
+}
+
+// OnSlowRequest registers the slow request callback of the default iris instance, see the Framework's OnSlowRequest
+func OnSlowRequest(threshold time.Duration, cb func(SlowRequest)) {
+	Default.OnSlowRequest(threshold, cb)
+}
+
+// OnSlowRequest registers the callback which is fired, after the request is served, for each request which took longer than the 'threshold',
+// with its route, its duration and a sample of its goroutine's stack, to diagnose the latency outliers in production.
+// A nil 'cb' logs the slow requests as warnings.
+// It should be called before the Listen/Serve/Run functions, a next call replaces the previous callback.
+//
+// Usage:
+// app.OnSlowRequest(2*time.Second, func(r iris.SlowRequest) {
+//     log.Printf("%s %s took %s\n%s", r.Method, r.Route, r.Duration, r.Stack)
+// })
+func (s *Framework) OnSlowRequest(threshold time.Duration, cb func(SlowRequest)) {
+	if cb == nil {
+		cb = func(r SlowRequest) {
+			s.log(LogLevelWarn, "slow request", "method", r.Method, "route", r.Route, "duration", r.Duration, "stack", string(r.Stack))
+		}
+	}
+	s.slowRequests = &slowRequestWatchdog{threshold: threshold, cb: cb}
+}
+
+// start starts watching the request which is served by the current goroutine
+func (wd *slowRequestWatchdog) start() *slowRequestWatch {
+	w := &slowRequestWatch{start: time.Now()}
+	id := goroutineID()
+	w.timer = time.AfterFunc(wd.threshold, func() {
+		stack := goroutineStack(id)
+		w.mu.Lock()
+		w.stack = stack
+		w.mu.Unlock()
+	})
+	return w
+}
+
+// end stops watching the request and fires the callback if it exceeded the threshold
+func (wd *slowRequestWatchdog) end(w *slowRequestWatch, ctx *Context) {
+	w.timer.Stop()
+	duration := time.Since(w.start)
+	if duration < wd.threshold {
+		return
+	}
+	w.mu.Lock()
+	stack := w.stack
+	w.mu.Unlock()
+
+	wd.cb(SlowRequest{
+		Method:   ctx.Method(),
+		Path:     ctx.Path(),
+		Route:    ctx.framework.mux.routeOf(ctx),
+		Duration: duration,
+		Stack:    stack,
+	})
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the id of the current goroutine, it's parsed from the first line of its stack, "goroutine 42 [running]:"
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, goroutinePrefix)
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
+
+// goroutineStack returns the stack of the goroutine of the 'id', nil if it's not running anymore
+func goroutineStack(id uint64) []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	header := append(strconv.AppendUint(append([]byte(nil), goroutinePrefix...), id, 10), ' ')
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, header) {
+			return stack
+		}
+	}
+	return nil
+}
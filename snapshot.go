@@ -0,0 +1,132 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gavv/httpexpect"
+)
+
+const (
+	// DefaultSnapshotsDir the default directory of the golden files of the MatchSnapshot
+	DefaultSnapshotsDir = "testdata/snapshots"
+	// SnapshotsUpdateEnv the environment variable which rewrites the golden files of the MatchSnapshot,
+	// i.e IRIS_UPDATE_SNAPSHOTS=1 go test ./...
+	SnapshotsUpdateEnv = "IRIS_UPDATE_SNAPSHOTS"
+)
+
+// SnapshotNormalizer replaces the parts of a snapshot which change on each run, i.e the timestamps and the ids
+type SnapshotNormalizer func(s string) string
+
+// NormalizeRegexp returns a SnapshotNormalizer which replaces the matches of the 'pattern' with the 'replacement'
+func NormalizeRegexp(pattern, replacement string) SnapshotNormalizer {
+	re := regexp.MustCompile(pattern)
+	return func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	}
+}
+
+var (
+	// NormalizeTimestamps replaces the RFC 3339 and the http timestamps with "<timestamp>"
+	NormalizeTimestamps = NormalizeRegexp(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})|`+
+		`(Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} (Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) \d{4} \d{2}:\d{2}:\d{2} GMT`, "<timestamp>")
+	// NormalizeUUIDs replaces the uuids with "<uuid>"
+	NormalizeUUIDs = NormalizeRegexp(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`, "<uuid>")
+)
+
+// SnapshotOptions the options of the MatchSnapshot
+type SnapshotOptions struct {
+	// Dir the directory of the golden files
+	// Defaults to the DefaultSnapshotsDir, "testdata/snapshots"
+	Dir string
+	// Headers the response headers of the snapshot, the rest are not compared
+	// Defaults to nil, the Content-Type only
+	Headers []string
+	// Normalizers the normalizers of the snapshot, in order, i.e the NormalizeTimestamps and the NormalizeUUIDs
+	// Defaults to nil
+	Normalizers []SnapshotNormalizer
+	// Update if true then the golden file is rewritten instead of compared, the SnapshotsUpdateEnv enables it too
+	// Defaults to false
+	Update bool
+}
+
+// MatchSnapshot compares the status, the selected headers and the body of the 'res' to the golden file of the 'name',
+// the "<Dir>/<name>.golden", the json bodies are indented so their diffs are readable.
+// A missing golden file is created, the changed ones are rewritten when the Update or the SnapshotsUpdateEnv is set,
+// otherwise a mismatch is reported to the Tester's 't'.
+//
+// Usage:
+// res := e.GET("/users/1").Expect()
+// e.MatchSnapshot("get_user", res, iris.SnapshotOptions{Normalizers: []iris.SnapshotNormalizer{iris.NormalizeTimestamps}})
+func (e *Tester) MatchSnapshot(name string, res *httpexpect.Response, options ...SnapshotOptions) {
+	var o SnapshotOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Dir == "" {
+		o.Dir = DefaultSnapshotsDir
+	}
+	if o.Headers == nil {
+		o.Headers = []string{contentType}
+	}
+
+	actual := snapshotOf(res, o)
+	file := filepath.Join(o.Dir, name+".golden")
+	expected, err := ioutil.ReadFile(file)
+	update := o.Update || os.Getenv(SnapshotsUpdateEnv) != ""
+	if os.IsNotExist(err) || (update && err == nil && string(expected) != actual) {
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+			err = ioutil.WriteFile(file, []byte(actual), 0644)
+		}
+		if err != nil {
+			e.t.Errorf("snapshot %s: %s", name, err)
+			return
+		}
+		e.t.Logf("snapshot %s: written to %s", name, file)
+		return
+	}
+	if err != nil {
+		e.t.Errorf("snapshot %s: %s", name, err)
+		return
+	}
+	if string(expected) != actual {
+		e.t.Errorf("snapshot %s: the response doesn't match the %s, set the %s=1 to update it\n--- expected\n%s\n+++ actual\n%s",
+			name, file, SnapshotsUpdateEnv, expected, actual)
+	}
+}
+
+// snapshotOf returns the normalized snapshot of the 'res', its status line, its headers and its body
+func snapshotOf(res *httpexpect.Response, o SnapshotOptions) string {
+	raw := res.Raw()
+	var b strings.Builder
+	b.WriteString("HTTP " + strconv.Itoa(raw.StatusCode) + "\n")
+	for _, key := range o.Headers {
+		for _, v := range raw.Header[http.CanonicalHeaderKey(key)] {
+			b.WriteString(http.CanonicalHeaderKey(key) + ": " + v + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	body := []byte(res.Body().Raw())
+	var indented bytes.Buffer
+	if strings.Contains(raw.Header.Get(contentType), "json") && json.Indent(&indented, body, "", "  ") == nil {
+		body = indented.Bytes()
+	}
+	b.Write(body)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		b.WriteString("\n")
+	}
+
+	s := b.String()
+	for _, normalize := range o.Normalizers {
+		s = normalize(s)
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package iris
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"sync/atomic"
+)
+
+// Stats the framework's runtime stats, the DebugStats, the connections, the requests per route and the contexts' pool
+type Stats struct {
+	DebugStats
+	// OpenConnections the http connections of the servers, the hijacked, i.e websocket, connections are not counted
+	OpenConnections int64 `json:"openConnections"`
+	// RequestsInFlight the requests which are being served
+	RequestsInFlight int64 `json:"requestsInFlight"`
+	// RequestsTotal the requests which are served since the server is started
+	RequestsTotal uint64 `json:"requestsTotal"`
+	// ContextsAllocated the contexts which are allocated by the pool, ContextsInUse the ones which are acquired,
+	// a ContextsAllocated much bigger than the peak of the ContextsInUse means that the pool is cleared by the garbage collector
+	ContextsAllocated int64 `json:"contextsAllocated"`
+	ContextsInUse     int64 `json:"contextsInUse"`
+	// RouteStats the counters of the routes, sorted by their path and method, the requests which matched no route are counted as the "unmatched"
+	RouteStats []RouteStats `json:"routeStats"`
+}
+
+// RouteStats the counters of a route
+type RouteStats struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Requests uint64 `json:"requests"`
+	// Errors the responses with 5xx status code
+	Errors uint64 `json:"errors"`
+}
+
+// frameworkStats the always enabled, lock-free, counters of the Stats
+type frameworkStats struct {
+	connections       int64
+	inFlight          int64
+	requests          uint64
+	contextsAllocated int64
+	contextsInUse     int64
+	// routes the counters of the routes, by their first handler, they're set once by the Build
+	routes    map[*Handler]*routeCounters
+	unmatched routeCounters
+}
+
+type routeCounters struct {
+	method   string
+	path     string
+	requests uint64
+	errors   uint64
+}
+
+// buildRoutes creates the counters of the mux' routes, it's called by the Build before the server is started
+func (st *frameworkStats) buildRoutes(mux *serveMux) {
+	st.routes = make(map[*Handler]*routeCounters, len(mux.lookups))
+	for _, r := range mux.lookups {
+		if len(r.middleware) > 0 {
+			st.routes[&r.middleware[0]] = &routeCounters{method: r.method, path: r.subdomain + r.path}
+		}
+	}
+	st.unmatched.path = metricsUnmatchedRoute
+}
+
+// startRequest counts a request in flight, before the routing
+func (st *frameworkStats) startRequest() {
+	atomic.AddInt64(&st.inFlight, 1)
+}
+
+// observeRequest counts a served request to its route
+func (st *frameworkStats) observeRequest(ctx *Context) {
+	atomic.AddInt64(&st.inFlight, -1)
+	atomic.AddUint64(&st.requests, 1)
+
+	counters := &st.unmatched
+	if len(ctx.Middleware) > 0 {
+		if c, ok := st.routes[&ctx.Middleware[0]]; ok {
+			counters = c
+		}
+	}
+	atomic.AddUint64(&counters.requests, 1)
+	if ctx.ResponseWriter.StatusCode() >= StatusInternalServerError {
+		atomic.AddUint64(&counters.errors, 1)
+	}
+}
+
+// trackConnState returns the servers' ConnState which counts the open connections and calls the 'next', the Config.ConnState, if any
+func (st *frameworkStats) trackConnState(next func(net.Conn, http.ConnState)) func(net.Conn, http.ConnState) {
+	return func(c net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			atomic.AddInt64(&st.connections, 1)
+		case http.StateHijacked, http.StateClosed:
+			atomic.AddInt64(&st.connections, -1)
+		}
+		if next != nil {
+			next(c, state)
+		}
+	}
+}
+
+// Stats returns the runtime stats, the DebugStats and the counters of the connections, the requests, the routes and the contexts' pool
+func (s *Framework) Stats() Stats {
+	st := s.stats
+	stats := Stats{
+		DebugStats:        s.DebugStats(),
+		OpenConnections:   atomic.LoadInt64(&st.connections),
+		RequestsInFlight:  atomic.LoadInt64(&st.inFlight),
+		RequestsTotal:     atomic.LoadUint64(&st.requests),
+		ContextsAllocated: atomic.LoadInt64(&st.contextsAllocated),
+		ContextsInUse:     atomic.LoadInt64(&st.contextsInUse),
+		RouteStats:        make([]RouteStats, 0, len(st.routes)+1),
+	}
+
+	routeStats := func(c *routeCounters) RouteStats {
+		return RouteStats{
+			Method:   c.method,
+			Path:     c.path,
+			Requests: atomic.LoadUint64(&c.requests),
+			Errors:   atomic.LoadUint64(&c.errors),
+		}
+	}
+	for _, c := range st.routes {
+		stats.RouteStats = append(stats.RouteStats, routeStats(c))
+	}
+	sort.Slice(stats.RouteStats, func(i, j int) bool {
+		a, b := stats.RouteStats[i], stats.RouteStats[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	stats.RouteStats = append(stats.RouteStats, routeStats(&st.unmatched))
+	return stats
+}
+
+// EnableStatsEndpoint serves the runtime stats of the default iris instance, see the Framework's EnableStatsEndpoint
+func EnableStatsEndpoint(path string, options ...DebugOptions) {
+	Default.EnableStatsEndpoint(path, options...)
+}
+
+// EnableStatsEndpoint serves the Stats, as json, on the 'path', i.e for the dashboards,
+// the options' credentials and IP restrictions protect it like the debug endpoints
+//
+// Usage:
+// app.EnableStatsEndpoint("/stats", iris.DebugOptions{AllowedIPs: []string{"10.0.0.0/8"}})
+func (s *Framework) EnableStatsEndpoint(path string, options ...DebugOptions) {
+	var o DebugOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	guard, err := debugGuard(o)
+	if err != nil {
+		s.logPanic(err)
+	}
+	s.Get(path, guard, func(ctx *Context) {
+		ctx.JSON(StatusOK, s.Stats())
+	})
+}
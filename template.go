@@ -83,6 +83,8 @@ func (t *templateEngines) render(isFile bool, ctx *Context, filenameOrSource str
 		}
 	}
 
+	ctx.exposeCSPNonce(binding)
+
 	// we do all these because we don't want to initialize a new map for each execution...
 	gzipEnabled := ctx.framework.Config.Gzip
 	charset := ctx.framework.Config.Charset
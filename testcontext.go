@@ -0,0 +1,78 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+)
+
+// TestContextOption sets a part of the request or of the Context of the NewTestContext
+type TestContextOption func(ctx *Context)
+
+// WithTestApp sets the application of the Context, its configuration, sessions and engines are used by the handlers
+func WithTestApp(app *Framework) TestContextOption {
+	return func(ctx *Context) {
+		ctx.framework = app
+	}
+}
+
+// WithTestHeader sets the request header 'key' to the 'value'
+func WithTestHeader(key, value string) TestContextOption {
+	return func(ctx *Context) {
+		ctx.Request.Header.Set(key, value)
+	}
+}
+
+// WithTestParam sets the named path parameter 'key' to the 'value', see the ctx.Param
+func WithTestParam(key, value string) TestContextOption {
+	return func(ctx *Context) {
+		ctx.Set(key, value)
+	}
+}
+
+// WithTestBody sets the request's body to the 'body' of the 'mediaType', i.e "application/json"
+func WithTestBody(mediaType string, body []byte) TestContextOption {
+	return func(ctx *Context) {
+		ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		ctx.Request.ContentLength = int64(len(body))
+		ctx.Request.Header.Set(contentType, mediaType)
+	}
+}
+
+// WithTestJSON sets the request's body to the json of the 'v'
+func WithTestJSON(v interface{}) TestContextOption {
+	return func(ctx *Context) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			panic(err)
+		}
+		WithTestBody(contentJSON, b)(ctx)
+	}
+}
+
+// NewTestContext returns a new Context of a 'method' request to the 'path', the path may contain a query,
+// so a handler or a middleware can be unit tested without the routing and the servers.
+// The response is recorded by its ResponseWriter, i.e the ctx.ResponseWriter.StatusCode(), Body() and Header(),
+// the handlers of its Middleware are executed by the ctx.Do.
+// The Context belongs to a new application, with the default configuration, if the WithTestApp is not given, it's not returned to any pool.
+//
+// Usage:
+// ctx := iris.NewTestContext(iris.MethodPost, "/users/42", iris.WithTestParam("id", "42"), iris.WithTestJSON(iris.Map{"name": "kataras"}))
+// ctx.Middleware = iris.Middleware{authMiddleware, updateUser}
+// ctx.Do()
+// if ctx.ResponseWriter.StatusCode() != iris.StatusOK { t.Fatal(string(ctx.ResponseWriter.Body())) }
+func NewTestContext(method, path string, options ...TestContextOption) *Context {
+	req := httptest.NewRequest(method, path, nil)
+	ctx := &Context{
+		ResponseWriter: acquireResponseWriter(httptest.NewRecorder()),
+		Request:        req,
+	}
+	for _, option := range options {
+		option(ctx)
+	}
+	if ctx.framework == nil {
+		ctx.framework = New(OptionDisableBanner(true))
+	}
+	return ctx
+}
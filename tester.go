@@ -0,0 +1,123 @@
+package iris
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gavv/httpexpect"
+)
+
+// Tester the fluent http tester of an application, it executes the requests against the application's router in-process,
+// without a listener, see the NewTester.
+// Its requests and assertions are the ones of the github.com/gavv/httpexpect, the routes which it exercises are reported by the RouteCoverage.
+type Tester struct {
+	*httpexpect.Expect
+	app *Framework
+	t   httpexpect.LoggerReporter
+}
+
+// NewTester returns a new Tester of the default iris instance, see the Framework's NewTester
+func NewTester(t httpexpect.LoggerReporter) *Tester {
+	return Default.NewTester(t)
+}
+
+// NewTester builds the application, if it's not built already, and returns a new Tester of it,
+// the failed assertions are reported to the 't', i.e the *testing.T.
+// The cookies are kept between the requests of the same Tester.
+//
+// Usage:
+// func TestUsers(t *testing.T) {
+//	e := app.NewTester(t)
+//	e.GET("/users/1").WithHeader("Accept", "application/json").Expect().
+//		Status(iris.StatusOK).JSON().Path("$.name").Equal("kataras")
+// }
+func (s *Framework) NewTester(t httpexpect.LoggerReporter) *Tester {
+	s.Set(OptionDisableBanner(true))
+	if !s.Plugins.PreBuildFired() {
+		s.Build()
+	}
+	baseURL := s.Config.VScheme + s.Config.VHost
+	if baseURL == "" {
+		baseURL = SchemeHTTP + DefaultServerAddr
+	}
+
+	e := httpexpect.WithConfig(httpexpect.Config{
+		BaseURL: baseURL,
+		Client: &http.Client{
+			Transport: httpexpect.NewBinder(s.Router),
+			Jar:       httpexpect.NewJar(),
+		},
+		Reporter: httpexpect.NewAssertReporter(t),
+	})
+	return &Tester{Expect: e, app: s, t: t}
+}
+
+// RouteCoverage the routes which are exercised by the requests of the Testers, see the Framework's RouteCoverage
+type RouteCoverage struct {
+	// Routes the registered routes, sorted by their path and method, with their requests
+	Routes []RouteStats
+	// Untested the routes without requests
+	Untested []RouteStats
+	// Percent the percentage of the tested routes, 100 if there are no routes
+	Percent float64
+}
+
+// String returns the report of the coverage, a line for each route, the untested ones are marked
+func (c RouteCoverage) String() string {
+	var b strings.Builder
+	for _, r := range c.Routes {
+		mark := " "
+		if r.Requests == 0 {
+			mark = "!"
+		}
+		fmt.Fprintf(&b, "%s %-7s %s (%d requests)\n", mark, r.Method, r.Path, r.Requests)
+	}
+	fmt.Fprintf(&b, "route coverage: %.1f%% (%d of %d routes untested)\n", c.Percent, len(c.Untested), len(c.Routes))
+	return b.String()
+}
+
+// RouteCoverage returns the routes which are exercised since the Build, by the requests of the Testers, see the Stats.
+// It's called after the tests, i.e by the TestMain, to find the untested routes of an application.
+//
+// Usage:
+// func TestMain(m *testing.M) {
+//	code := m.Run()
+//	coverage := app.RouteCoverage()
+//	fmt.Print(coverage)
+//	if code == 0 && coverage.Percent < 90 {
+//		code = 1
+//	}
+//	os.Exit(code)
+// }
+func (s *Framework) RouteCoverage() RouteCoverage {
+	var c RouteCoverage
+	for _, r := range s.Stats().RouteStats {
+		if r.Path == metricsUnmatchedRoute && r.Method == "" {
+			continue
+		}
+		c.Routes = append(c.Routes, r)
+		if r.Requests == 0 {
+			c.Untested = append(c.Untested, r)
+		}
+	}
+	c.Percent = 100
+	if len(c.Routes) > 0 {
+		c.Percent = float64(len(c.Routes)-len(c.Untested)) * 100 / float64(len(c.Routes))
+	}
+	return c
+}
+
+// RequireCoverage reports a failure to the Tester's 't' if the percentage of the tested routes of its application,
+// see the Framework's RouteCoverage, is less than the 'percent', the untested routes are reported too
+func (e *Tester) RequireCoverage(percent float64) {
+	c := e.app.RouteCoverage()
+	if c.Percent >= percent {
+		return
+	}
+	untested := make([]string, len(c.Untested))
+	for i, r := range c.Untested {
+		untested[i] = r.Method + " " + r.Path
+	}
+	e.t.Errorf("route coverage %.1f%% is less than %.1f%%, untested: %s", c.Percent, percent, strings.Join(untested, ", "))
+}
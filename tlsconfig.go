@@ -0,0 +1,63 @@
+package iris
+
+import (
+	"crypto/tls"
+	"net"
+)
+
+// apply sets the versions, the curves and the cipher suites of the 'config', its own ones are kept if they're not configured
+func (c TLSConfiguration) apply(config *tls.Config) *tls.Config {
+	if c.MinVersion != 0 {
+		config.MinVersion = c.MinVersion
+	}
+	if c.MaxVersion != 0 {
+		config.MaxVersion = c.MaxVersion
+	}
+	if len(c.CurvePreferences) > 0 {
+		config.CurvePreferences = c.CurvePreferences
+	}
+	if len(c.CipherSuites) > 0 {
+		config.CipherSuites = c.CipherSuites
+	}
+	return config
+}
+
+// weaknesses returns the descriptions of the weak settings, the versions older than the TLS 1.2
+// and the cipher suites which the crypto/tls marks as insecure, empty if there is not any
+func (c TLSConfiguration) weaknesses() []string {
+	var weak []string
+	if c.MinVersion != 0 && c.MinVersion < tls.VersionTLS12 {
+		weak = append(weak, "min version "+tls.VersionName(c.MinVersion))
+	}
+	insecure := make(map[uint16]bool)
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.ID] = true
+	}
+	for _, id := range c.CipherSuites {
+		if insecure[id] {
+			weak = append(weak, "cipher suite "+tls.CipherSuiteName(id))
+		}
+	}
+	return weak
+}
+
+// tlsConfig applies the Config.TLS to the 'config' of a server and warns about its weak settings
+func (s *Framework) tlsConfig(config *tls.Config) *tls.Config {
+	c := s.Config.TLS
+	if c.isZero() {
+		return config
+	}
+	for _, weak := range c.weaknesses() {
+		s.log(LogLevelWarn, "weak TLS configuration", "setting", weak)
+	}
+	return c.apply(config)
+}
+
+// listenGetCert returns a new TLS Listener, like the GETCERT, with the Config.TLS applied
+func (s *Framework) listenGetCert(addr string, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (net.Listener, error) {
+	ln, err := TCP4(addr)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(ln, s.tlsConfig(getCertTLSConfig(getCertificate))), nil
+}
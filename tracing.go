@@ -0,0 +1,187 @@
+package iris
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName the instrumentation name of the framework's spans
+const tracerName = "github.com/kataras/iris"
+
+// TracingOptions the options of the EnableTracing
+type TracingOptions struct {
+	// TracerProvider creates the tracer of the framework's spans
+	// Defaults to the OpenTelemetry's global TracerProvider
+	TracerProvider trace.TracerProvider
+	// Exporter if not nil the spans are batched to it, i.e an otlptrace or a stdouttrace exporter,
+	// by a new TracerProvider which is flushed and shut down by the Shutdown, the TracerProvider is ignored
+	Exporter sdktrace.SpanExporter
+	// Propagator extracts the trace context of the incoming requests and injects it to the requests of the HTTPClient
+	// Defaults to the OpenTelemetry's global TextMapPropagator
+	Propagator propagation.TextMapPropagator
+}
+
+// frameworkTracing the tracer of the router, the transactions, the view rendering and the cache
+type frameworkTracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// EnableTracing enables the OpenTelemetry spans of the default iris instance, see the Framework's EnableTracing
+func EnableTracing(options ...TracingOptions) {
+	Default.EnableTracing(options...)
+}
+
+// EnableTracing enables the OpenTelemetry spans of the requests, their transactions, template renders and cached handlers.
+// The trace context of the incoming requests is extracted, so the request's span continues the caller's trace,
+// and it's kept in the ctx.Request.Context(), which should be passed to the database and the http clients,
+// the HTTPClient injects it to the outgoing requests.
+// It should be called before the Listen/Serve/Run functions.
+//
+// Usage:
+// exporter, _ := otlptracehttp.New(context.Background())
+// app.EnableTracing(iris.TracingOptions{Exporter: exporter})
+func (s *Framework) EnableTracing(options ...TracingOptions) {
+	var o TracingOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	provider := o.TracerProvider
+	if o.Exporter != nil {
+		sdkProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(o.Exporter))
+		s.OnShutdown(func(ctx context.Context) {
+			if err := sdkProvider.Shutdown(ctx); err != nil {
+				s.log(LogLevelError, "shutting down the tracer provider", "err", err)
+			}
+		})
+		provider = sdkProvider
+	}
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	propagator := o.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	s.tracing = &frameworkTracing{
+		tracer:     provider.Tracer(tracerName, trace.WithInstrumentationVersion(Version)),
+		propagator: propagator,
+	}
+}
+
+// startRequest starts the server span of a request, before the routing, and keeps it in the request's context
+func (t *frameworkTracing) startRequest(ctx *Context) {
+	r := ctx.Request
+	parent := t.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	spanCtx, span := t.tracer.Start(parent, r.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+			attribute.String("server.address", r.Host),
+			attribute.String("client.address", ctx.RemoteAddr()),
+		))
+	ctx.Request = r.WithContext(spanCtx)
+	ctx.span = span
+}
+
+// endRequest names the server span of a request after its route and ends it
+func (t *frameworkTracing) endRequest(ctx *Context) {
+	span := ctx.span
+	ctx.span = nil
+	if span == nil {
+		return
+	}
+	if route := ctx.framework.mux.routeOf(ctx); route != metricsUnmatchedRoute {
+		span.SetName(ctx.Request.Method + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+	}
+	status := ctx.ResponseWriter.StatusCode()
+	if status == 0 {
+		status = StatusOK
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= StatusInternalServerError {
+		span.SetStatus(codes.Error, statusText[status])
+	}
+	span.End()
+}
+
+// startSpan starts a child span of the request's span, it's a no-op span if the tracing is disabled
+func (ctx *Context) startSpan(name string, attrs ...attribute.KeyValue) trace.Span {
+	t := ctx.framework.tracing
+	if t == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+	_, span := t.tracer.Start(ctx.Request.Context(), name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan ends the 'span', with an error status if the 'err' is not nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// HTTPClient returns a http client of the default iris instance, see the Framework's HTTPClient
+func HTTPClient() *http.Client {
+	return Default.HTTPClient()
+}
+
+// HTTPClient returns a http client which, when the tracing is enabled, starts a client span for each request
+// and injects the trace context of the request's context to its headers, so the called services continue the trace.
+//
+// Usage:
+// req, _ := http.NewRequest("GET", "http://users-service/users/42", nil)
+// resp, err := app.HTTPClient().Do(req.WithContext(ctx.Request.Context()))
+func (s *Framework) HTTPClient() *http.Client {
+	return &http.Client{Transport: s.tracingTransport(http.DefaultTransport)}
+}
+
+// tracingTransport wraps the 'base' with the tracing of the outgoing requests, if it's enabled
+func (s *Framework) tracingTransport(base http.RoundTripper) http.RoundTripper {
+	if s.tracing == nil {
+		return base
+	}
+	return &tracingTransport{base: base, tracing: s.tracing}
+}
+
+type tracingTransport struct {
+	base    http.RoundTripper
+	tracing *frameworkTracing
+}
+
+func (t *tracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	spanCtx, span := t.tracing.tracer.Start(r.Context(), "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.full", r.URL.String()),
+		))
+
+	r = r.Clone(spanCtx)
+	t.tracing.propagator.Inject(spanCtx, propagation.HeaderCarrier(r.Header))
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= StatusBadRequest {
+		span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode))
+	}
+	span.End()
+	return resp, nil
+}
@@ -1,5 +1,7 @@
 package iris
 
+import "runtime/debug"
+
 // TransactionErrResult could be named also something like 'MaybeError',
 // it is useful to send it on transaction.Complete in order to execute a custom error mesasge to the user.
 //
@@ -40,7 +42,10 @@ func NewTransactionErrResult() TransactionErrResult {
 //
 // For more information please view the tests
 type Transaction struct {
-	Context  *Context
+	Context *Context
+	// Response renders the partial responses of this transaction,
+	// their results are kept on the transaction's writer until the transaction completes.
+	Response *TransactionResponse
 	parent   *Context
 	hasError bool
 	scope    TransactionScope
@@ -55,10 +60,54 @@ func newTransaction(from *Context) *Transaction {
 		Context: &tempCtx,
 		scope:   TransientTransactionScope,
 	}
+	t.Response = &TransactionResponse{ctx: t.Context}
 
 	return t
 }
 
+// TransactionResponse renders templates and serialized contents to the transaction's cloned writer,
+// the results are merged to the parent context's response when the transaction succeeds
+// and they are rolled back, by its scope, when it fails.
+type TransactionResponse struct {
+	ctx *Context
+}
+
+// Render same as context.Render but the result is buffered to the transaction's writer,
+// a failed render leaves nothing of its partial result behind.
+//
+// The "gzip" option is ignored, the transaction's response is a fragment of the parent's response and it can't be compressed alone.
+func (r *TransactionResponse) Render(name string, binding interface{}, options ...map[string]interface{}) error {
+	opts := map[string]interface{}{"gzip": false}
+	if len(options) > 0 {
+		for k, v := range options[0] {
+			if k != "gzip" {
+				opts[k] = v
+			}
+		}
+	}
+
+	w := r.ctx.ResponseWriter
+	written := len(w.Body())
+	if err := r.ctx.Render(name, binding, opts); err != nil {
+		w.SetBody(w.Body()[0:written])
+		return err
+	}
+	return nil
+}
+
+// RenderWithLayout same as Render but renders the template inside the 'layout' template
+func (r *TransactionResponse) RenderWithLayout(name string, layout string, binding interface{}, options ...map[string]interface{}) error {
+	opts := map[string]interface{}{"layout": layout}
+	if len(options) > 0 {
+		for k, v := range options[0] {
+			if k != "layout" {
+				opts[k] = v
+			}
+		}
+	}
+	return r.Render(name, binding, opts)
+}
+
 // SetScope sets the current transaction's scope
 // iris.RequestTransactionScope || iris.TransientTransactionScope (default)
 func (t *Transaction) SetScope(scope TransactionScope) {
@@ -97,6 +146,10 @@ func (t *Transaction) Complete(err error) {
 		maybeErr.StatusCode = statusCode
 		maybeErr.Reason = reason
 		maybeErr.ContentType = cType
+		// the reported error keeps the status code of the failed transaction
+		if s := t.parent.framework; s.hasErrorReports() {
+			s.reportError(t.parent, maybeErr, debug.Stack())
+		}
 	}
 	// the transaction ends with error or not error, it decides what to do next with its Response
 	// the Response is appended to the parent context an all cases but it checks for empty body,headers and all that,
@@ -0,0 +1,296 @@
+package iris
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validator validates the values which are read by the ctx.ReadJSON, ReadXML, ReadForm, ReadMsgPack, ReadCBOR and UnmarshalBody,
+// see the Framework's Validator field and the DefaultValidator
+type Validator interface {
+	// Validate returns the ValidationErrors of the 'v', or any other error, nil if it's valid
+	Validate(v interface{}) error
+}
+
+// ValidatorFunc the func which implements the Validator
+type ValidatorFunc func(v interface{}) error
+
+// Validate returns the error of the func
+func (f ValidatorFunc) Validate(v interface{}) error {
+	return f(v)
+}
+
+// FieldError the error of a field of a value which is not valid
+type FieldError struct {
+	// Field the path of the field, by its json name, i.e "address.city" or "items[0].quantity"
+	Field string `json:"field"`
+	// Rule the failed rule, i.e "required" or "min"
+	Rule string `json:"rule"`
+	// Code the code of the error, the key of its "validation.<code>" message, see the ctx.Bind
+	// Defaults to the Rule
+	Code string `json:"code"`
+	// Param the parameter of the rule, i.e "3" of the "min=3"
+	Param string `json:"param,omitempty"`
+	// Message the description of the error, i.e "must be at least 3 characters",
+	// translated to the request's language when the I18n has the "validation.<code>" message, see the ctx.Bind
+	Message string `json:"message"`
+}
+
+// Error returns the field and the message of the error
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationErrors the errors of the fields of a value which is not valid,
+// they're rendered with the 422 status code by the ctx.ReadJSON and the rest of the readers
+type ValidationErrors []FieldError
+
+// Error returns the errors of the fields, separated by "; "
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// DefaultValidator the Validator of the `validate` struct tags, which is used by default.
+// The rules of a tag are separated by commas:
+// required, the field is not its zero value
+// min=n and max=n, the length of the strings, slices and maps or the value of the numbers
+// len=n, the exact length of the strings, slices and maps
+// email, the string is an email address
+// oneof=a b c, the value is one of the space-separated values
+// The nested structs, the pointers to structs and the slices of structs are validated too.
+//
+// Usage:
+// type Signup struct {
+//	Email string `json:"email" validate:"required,email"`
+//	Name  string `json:"name" validate:"required,min=2,max=64"`
+//	Plan  string `json:"plan" validate:"oneof=free pro"`
+// }
+var DefaultValidator Validator = tagValidator{}
+
+// tagValidator the validator of the `validate` struct tags
+type tagValidator struct{}
+
+// Validate returns the ValidationErrors of the 'v', nil if it's valid or not a struct
+func (tagValidator) Validate(v interface{}) error {
+	var errs ValidationErrors
+	validateStruct(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct appends the errors of the fields of the 'val', if it's a struct or a pointer to a struct, with the 'prefix' to their paths
+func validateStruct(val reflect.Value, prefix string, errs *ValidationErrors) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		fv := val.Field(i)
+		if f.Anonymous {
+			validateStruct(fv, prefix, errs)
+			continue
+		}
+		name := fieldName(f)
+		if name == "-" {
+			continue
+		}
+		path := prefix + name
+		if tag := f.Tag.Get("validate"); tag != "" && tag != "-" {
+			validateField(fv, path, tag, errs)
+		}
+		validateNested(fv, path, errs)
+	}
+}
+
+// validateNested validates the structs of a field, its struct or the structs of its slice
+func validateNested(fv reflect.Value, path string, errs *ValidationErrors) {
+	elem := fv
+	for elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return
+		}
+		elem = elem.Elem()
+	}
+	switch elem.Kind() {
+	case reflect.Struct:
+		validateStruct(elem, path+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < elem.Len(); i++ {
+			validateStruct(elem.Index(i), path+"["+strconv.Itoa(i)+"].", errs)
+		}
+	}
+}
+
+// fieldName returns the name of the field in the errors, the one of its json or form tag, if any
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name := strings.Split(f.Tag.Get(key), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// validateField appends the errors of the rules of the 'tag' which the 'fv' fails
+func validateField(fv reflect.Value, path string, tag string, errs *ValidationErrors) {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		param := ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			rule, param = rule[:i], rule[i+1:]
+		}
+		if rule == "" {
+			continue
+		}
+		if message := checkRule(fv, rule, param); message != "" {
+			*errs = append(*errs, FieldError{Field: path, Rule: rule, Code: rule, Param: param, Message: message})
+		}
+	}
+}
+
+// checkRule returns the message of the error if the 'fv' fails the 'rule', empty if it passes
+func checkRule(fv reflect.Value, rule, param string) string {
+	if rule == "required" {
+		if isZeroValue(fv) {
+			return "is required"
+		}
+		return ""
+	}
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		// the optional fields are validated only if they're set
+		if fv.IsNil() {
+			return ""
+		}
+		fv = fv.Elem()
+	}
+
+	switch rule {
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "has the invalid rule " + rule + "=" + param
+		}
+		size, unit, ok := measure(fv)
+		if !ok {
+			return ""
+		}
+		switch {
+		case rule == "min" && size < n:
+			return "must be at least " + param + unit
+		case rule == "max" && size > n:
+			return "must be at most " + param + unit
+		case rule == "len" && size != n:
+			return "must be exactly " + param + unit
+		}
+	case "email":
+		if fv.Kind() == reflect.String && fv.Len() > 0 {
+			if addr, err := mail.ParseAddress(fv.String()); err != nil || addr.Address != fv.String() {
+				return "must be an email address"
+			}
+		}
+	case "oneof":
+		value := fmt.Sprint(fv.Interface())
+		for _, allowed := range strings.Fields(param) {
+			if value == allowed {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	}
+	return ""
+}
+
+// measure returns the length of the strings, slices and maps, or the value of the numbers, and the unit of the messages
+func measure(fv reflect.Value) (float64, string, bool) {
+	switch fv.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(fv.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(fv.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// isZeroValue returns true if the 'fv' is its type's zero value, the empty slices and maps are zero too
+func isZeroValue(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return fv.Len() == 0
+	}
+	return fv.IsZero()
+}
+
+// validate validates the 'v' with the Framework's Validator, if any,
+// it renders the errors with the 422 status code and returns them if it's not valid
+func (ctx *Context) validate(v interface{}) error {
+	validator := ctx.framework.Validator
+	if validator == nil {
+		return nil
+	}
+	err := validator.Validate(v)
+	if err != nil {
+		ctx.renderValidationError(err)
+	}
+	return err
+}
+
+// renderValidationError renders the 'err' of a validation with the 422 status code,
+// the ValidationErrors as {"errors": [{"field": "...", "rule": "...", "code": "...", "message": "..."}]}, with their messages translated,
+// and any other error as {"error": "..."}
+func (ctx *Context) renderValidationError(err error) {
+	if errs, ok := err.(ValidationErrors); ok {
+		ctx.JSON(StatusUnprocessableEntity, map[string]interface{}{"errors": ctx.translateValidationErrors(errs)})
+		return
+	}
+	ctx.JSON(StatusUnprocessableEntity, map[string]interface{}{"error": err.Error()})
+}
+
+// translateValidationErrors returns the 'errs' with their messages translated to the request's language,
+// by the "validation.<code>" messages of the I18n, i.e "validation.required": "{field} is required",
+// the {field} and the {param} of a message are replaced by the field and the parameter of the rule.
+// The messages of the codes which are not translated are kept.
+func (ctx *Context) translateValidationErrors(errs ValidationErrors) ValidationErrors {
+	i := ctx.framework.I18n
+	if i == nil || len(i.Languages()) == 0 {
+		return errs
+	}
+	lang := i.Language(ctx)
+	translated := make(ValidationErrors, len(errs))
+	for idx, e := range errs {
+		if e.Code == "" {
+			e.Code = e.Rule
+		}
+		key := "validation." + e.Code
+		if msg := i.Tr(lang, key); msg != key {
+			e.Message = strings.NewReplacer("{field}", e.Field, "{param}", e.Param).Replace(msg)
+		}
+		translated[idx] = e
+	}
+	return translated
+}
@@ -0,0 +1,293 @@
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/go-fs"
+)
+
+// ViewEngine is the interface which all view engines should implement in order to be registered by app.AdaptView.
+//
+// A view engine is responsible for the files of one extension, ctx.Render("page.html", binding)
+// is executed by the view engine with the ".html" extension, if any,
+// otherwise it's passed to the template engines registered with UseTemplate.
+type ViewEngine interface {
+	// Load parses all the templates of the engine, it's called once at the framework's Build
+	Load() error
+	// Reload when enabled the engine should re-parse its templates on each execution,
+	// it's enabled by the framework on development mode(Config.IsDevelopment)
+	Reload(enable bool)
+	// ExecuteWriter executes the template 'name' with the 'binding' and writes the result to 'w',
+	// the template is rendered inside the 'layout' template,
+	// an empty 'layout' means the engine's default layout, if any, and the NoLayout means no layout at all
+	ExecuteWriter(w io.Writer, name string, layout string, binding interface{}) error
+	// Ext returns the file extension which this view engine is responsible for, i.e ".html"
+	Ext() string
+	// AddFunc registers a template func which should be available to all the engine's templates,
+	// it's called before the Load
+	AddFunc(funcName string, funcBody interface{})
+}
+
+// templateFuncs returns the built'n template funcs, which are available to all the template and view engines:
+//
+// {{ url "routeName" args... }} and {{ urlpath "routeName" args... }} the reverse-routing of a named route,
+// {{ asset "/static/app.js" }} the path of a static file with its content's hash, for cache-busting, look Asset,
+// {{ t "el-GR" "hello" args... }} the translated message, look I18n,
+// {{ json .Value }} the value encoded as json, safe for the script tags,
+// {{ safe "<b>content</b>" }} the content as it's, not escaped html.
+//
+// More can be registered by each engine's AddFunc.
+func (s *Framework) templateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"url":     s.URL,
+		"urlpath": s.Path,
+		"asset":   s.Asset,
+		"t": func(lang string, key string, args ...interface{}) string {
+			return s.I18n.Tr(lang, key, args...)
+		},
+		"json": func(v interface{}) (template.JS, error) {
+			b, err := json.Marshal(v)
+			return template.JS(b), err
+		},
+		"safe": func(contents string) template.HTML {
+			return template.HTML(contents)
+		},
+	}
+}
+
+// viewEnginesContextKey the context's value of the party's view engines, set by the party's RegisterView
+const viewEnginesContextKey = "iris.views"
+
+// viewEngines keeps the registered view engines, one per file extension
+type viewEngines struct {
+	engines     []ViewEngine
+	sharedFuncs map[string]interface{}
+	// adapt is called for each new engine, before its registration
+	adapt   func(ViewEngine)
+	metrics *viewMetrics
+	// parties the view engines of the parties, they are loaded with these engines
+	parties []*viewEngines
+	mu      sync.RWMutex
+}
+
+func newViewEngines(sharedFuncs map[string]interface{}) *viewEngines {
+	return &viewEngines{sharedFuncs: sharedFuncs, metrics: &viewMetrics{}}
+}
+
+// party returns a new, empty, set of view engines, which shares the funcs and the metrics of these,
+// it's loaded with these engines, used by the party's RegisterView.
+func (v *viewEngines) party() *viewEngines {
+	p := &viewEngines{sharedFuncs: v.sharedFuncs, adapt: v.adapt, metrics: v.metrics}
+	v.mu.Lock()
+	v.parties = append(v.parties, p)
+	v.mu.Unlock()
+	return p
+}
+
+func (v *viewEngines) add(e ViewEngine) {
+	if v.adapt != nil {
+		v.adapt(e)
+	}
+	for funcName, funcBody := range v.sharedFuncs {
+		e.AddFunc(funcName, funcBody)
+	}
+	v.mu.Lock()
+	v.engines = append(v.engines, e)
+	v.mu.Unlock()
+}
+
+// handler returns the middleware which sets these engines to the context, it's the first handler of the routes of a party's RegisterView
+func (v *viewEngines) handler() HandlerFunc {
+	return func(ctx *Context) {
+		ctx.Set(viewEnginesContextKey, v)
+		ctx.Next()
+	}
+}
+
+func (v *viewEngines) len() int {
+	v.mu.RLock()
+	n := len(v.engines)
+	v.mu.RUnlock()
+	return n
+}
+
+// find returns the view engine which is responsible for the 'filename', nil if not any
+func (v *viewEngines) find(filename string) ViewEngine {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for i := range v.engines {
+		if strings.HasSuffix(filename, v.engines[i].Ext()) {
+			return v.engines[i]
+		}
+	}
+	return nil
+}
+
+// load loads all the view engines, reload is passed to each engine
+func (v *viewEngines) load(reload bool) error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for i := range v.engines {
+		v.engines[i].Reload(reload)
+		if err := v.engines[i].Load(); err != nil {
+			return err
+		}
+	}
+	for i := range v.parties {
+		if err := v.parties[i].load(reload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getLayoutOption returns the "layout" option, if any
+func getLayoutOption(defaultValue string, options []map[string]interface{}) string {
+	if len(options) > 0 {
+		if s, isString := options[0]["layout"].(string); isString {
+			return s
+		}
+	}
+	return defaultValue
+}
+
+// viewBuffers the pool of the buffers which the views are executed into,
+// a view is written to the response only when its execution succeed.
+var viewBuffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func acquireViewBuffer() *bytes.Buffer {
+	return viewBuffers.Get().(*bytes.Buffer)
+}
+
+func releaseViewBuffer(buf *bytes.Buffer) {
+	buf.Reset()
+	viewBuffers.Put(buf)
+}
+
+// ViewMetric keeps the render statistics of a template, look app.ViewMetrics
+type ViewMetric struct {
+	// Renders the number of the executions, including the failed ones
+	Renders uint64
+	// Errors the number of the failed executions
+	Errors uint64
+	// Total the total duration of the executions
+	Total time.Duration
+	// Max the duration of the slowest execution
+	Max time.Duration
+}
+
+// Average returns the average duration of the template's executions
+func (m ViewMetric) Average() time.Duration {
+	if m.Renders == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Renders)
+}
+
+// viewMetrics the render statistics by template name
+type viewMetrics struct {
+	metrics map[string]ViewMetric
+	mu      sync.Mutex
+}
+
+func (m *viewMetrics) observe(name string, took time.Duration, err error) {
+	m.mu.Lock()
+	if m.metrics == nil {
+		m.metrics = make(map[string]ViewMetric)
+	}
+	metric := m.metrics[name]
+	metric.Renders++
+	if err != nil {
+		metric.Errors++
+	}
+	metric.Total += took
+	if took > metric.Max {
+		metric.Max = took
+	}
+	m.metrics[name] = metric
+	m.mu.Unlock()
+}
+
+// snapshot returns a copy of the statistics
+func (m *viewMetrics) snapshot() map[string]ViewMetric {
+	m.mu.Lock()
+	metrics := make(map[string]ViewMetric, len(m.metrics))
+	for name, metric := range m.metrics {
+		metrics[name] = metric
+	}
+	m.mu.Unlock()
+	return metrics
+}
+
+// execute executes a view into the 'buf' and records its duration
+func (v *viewEngines) execute(buf *bytes.Buffer, e ViewEngine, name string, layout string, binding interface{}) error {
+	started := time.Now()
+	err := e.ExecuteWriter(buf, name, layout, binding)
+	v.metrics.observe(name, time.Since(started), err)
+	return err
+}
+
+// executeString executes a view and returns its result as string
+func (v *viewEngines) executeString(e ViewEngine, name string, binding interface{}, options []map[string]interface{}) (string, error) {
+	buf := acquireViewBuffer()
+	defer releaseViewBuffer(buf)
+	if err := v.execute(buf, e, name, getLayoutOption("", options), binding); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// render executes a view with the engine 'e' and writes its result to the context's body,
+// the PreRenders, the gzip, charset and layout options are working as they do with the template engines.
+//
+// The view is executed into a pooled buffer first, on failure nothing is written to the response.
+func (v *viewEngines) render(e ViewEngine, ctx *Context, name string, binding interface{}, options []map[string]interface{}) error {
+	if ctx.framework.Config.DisableTemplateEngines {
+		return errTemplateExecute.Format("Templates are disabled '.Config.DisableTemplatesEngines = true' please turn that to false, as defaulted.")
+	}
+
+	prerenders := ctx.framework.templates.prerenders
+	for i := range prerenders {
+		if shouldContinue := prerenders[i](ctx, name, binding, options...); !shouldContinue {
+			break
+		}
+	}
+
+	ctx.exposeCSPNonce(binding)
+
+	gzipEnabled := ctx.framework.Config.Gzip
+	charset := ctx.framework.Config.Charset
+	if len(options) > 0 {
+		gzipEnabled = getGzipOption(gzipEnabled, options[0])
+		charset = getCharsetOption(charset, options[0])
+	}
+
+	buf := acquireViewBuffer()
+	defer releaseViewBuffer(buf)
+	// the options' layout overrides the context's(party's) layout
+	layout := getLayoutOption(ctx.GetString(TemplateLayoutContextKey), options)
+	if err := v.execute(buf, e, name, layout, binding); err != nil {
+		return errTemplateExecute.Format(err)
+	}
+
+	ctx.SetContentType(contentHTML + "; charset=" + charset)
+
+	if gzipEnabled && ctx.clientAllowsGzip() {
+		ctx.ResponseWriter.Header().Add(varyHeader, acceptEncodingHeader)
+		ctx.SetHeader(contentEncodingHeader, "gzip")
+
+		gzipWriter := fs.AcquireGzipWriter(ctx.ResponseWriter)
+		defer fs.ReleaseGzipWriter(gzipWriter)
+		_, err := gzipWriter.Write(buf.Bytes())
+		return err
+	}
+
+	_, err := ctx.ResponseWriter.Write(buf.Bytes())
+	return err
+}
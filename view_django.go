@@ -0,0 +1,205 @@
+package iris
+
+import (
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+// DjangoEngine is the django-syntax view engine, an adapter of the pongo2 template engine.
+//
+// The layout inheritance is supported by the pongo2's {% extends "layouts/main.html" %} and {% block %} tags,
+// when a 'layout' is passed to the render, the page is rendered first and it's available to the layout as {{ yield }}.
+//
+// The render's binding is mapped to the template's context variables,
+// a map's keys and a struct's exported fields are the variables' names.
+//
+// Usage: app.AdaptView(iris.Django("./templates", ".html"))
+type DjangoEngine struct {
+	fs        fs.FS
+	fsErr     error
+	name      string
+	extension string
+	layout    string
+	globals   pongo2.Context
+	filters   map[string]pongo2.FilterFunction
+	reload    bool
+
+	set *pongo2.TemplateSet
+	mu  sync.RWMutex
+}
+
+var _ ViewEngine = &DjangoEngine{}
+
+// Django creates and returns a new django-syntax view engine,
+// which loads the files with the 'extension' from the 'fileSystem',
+// a directory (string), an fs.FS or an http.FileSystem
+func Django(fileSystem interface{}, extension string) *DjangoEngine {
+	fsys, err := toFS(fileSystem)
+	return &DjangoEngine{
+		fs:        fsys,
+		fsErr:     err,
+		name:      fileSystemName(fileSystem),
+		extension: extension,
+		globals:   make(pongo2.Context),
+		filters:   make(map[string]pongo2.FilterFunction),
+	}
+}
+
+// Ext returns the file extension which this view engine is responsible for
+func (s *DjangoEngine) Ext() string {
+	return s.extension
+}
+
+// Reload if enabled the templates are not cached, they are re-parsed on each execution, development mode
+func (s *DjangoEngine) Reload(enable bool) {
+	s.reload = enable
+}
+
+// Layout sets the default layout template, which can be overridden by the "layout" render option or by a party's Layout
+func (s *DjangoEngine) Layout(layoutFile string) *DjangoEngine {
+	s.layout = layoutFile
+	return s
+}
+
+// AddFunc adds a global function which is callable from the templates, i.e {{ greet("kataras") }}
+func (s *DjangoEngine) AddFunc(funcName string, funcBody interface{}) {
+	s.mu.Lock()
+	s.globals[funcName] = funcBody
+	s.mu.Unlock()
+}
+
+// RegisterFilter registers a filter, i.e {{ name|upper }}, it should be called before the Load.
+//
+// Note that the pongo2's filters are global, the filter replaces any previous filter with the same name.
+func (s *DjangoEngine) RegisterFilter(filterName string, filterBody pongo2.FilterFunction) *DjangoEngine {
+	s.mu.Lock()
+	s.filters[filterName] = filterBody
+	s.mu.Unlock()
+	return s
+}
+
+// Load registers the filters and precompiles all the files with the engine's extension, it fails on the first syntax error
+func (s *DjangoEngine) Load() error {
+	if s.fsErr != nil {
+		return errViewLoad.Format(s.name, s.fsErr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for filterName, filterBody := range s.filters {
+		var err error
+		if pongo2.FilterExists(filterName) {
+			err = pongo2.ReplaceFilter(filterName, filterBody)
+		} else {
+			err = pongo2.RegisterFilter(filterName, filterBody)
+		}
+		if err != nil {
+			return errViewLoad.Format(s.name, err)
+		}
+	}
+
+	set := pongo2.NewSet(s.name, pongo2.NewFSLoader(s.fs))
+	set.Debug = s.reload
+	set.Globals = s.globals
+
+	err := fs.WalkDir(s.fs, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(name, s.extension) {
+			return nil
+		}
+		_, err = set.FromCache(name)
+		return err
+	})
+	if err != nil {
+		return errViewLoad.Format(s.name, err)
+	}
+
+	s.set = set
+	return nil
+}
+
+// toDjangoContext maps the render's binding to the template's context variables
+func toDjangoContext(binding interface{}) pongo2.Context {
+	switch b := binding.(type) {
+	case nil:
+		return pongo2.Context{}
+	case pongo2.Context:
+		return b
+	case map[string]interface{}:
+		return pongo2.Context(b)
+	case Map:
+		return pongo2.Context(b)
+	}
+
+	ctx := pongo2.Context{}
+	v := reflect.Indirect(reflect.ValueOf(binding))
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			for _, k := range v.MapKeys() {
+				ctx[k.String()] = v.MapIndex(k).Interface()
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" { // exported
+				ctx[f.Name] = v.Field(i).Interface()
+			}
+		}
+	}
+	return ctx
+}
+
+// ExecuteWriter executes a template and writes its result to the w writer,
+// if the layout is not empty, or a default layout exists, then the rendered page is available to the layout as {{ yield }}.
+func (s *DjangoEngine) ExecuteWriter(w io.Writer, name string, layout string, binding interface{}) error {
+	s.mu.RLock()
+	set := s.set
+	s.mu.RUnlock()
+	if set == nil {
+		return errViewNotLoaded.Format(s.name)
+	}
+
+	if layout == "" {
+		layout = s.layout
+	} else if layout == NoLayout {
+		layout = ""
+	}
+
+	tmpl, err := set.FromCache(name)
+	if err != nil {
+		return err
+	}
+
+	ctx := toDjangoContext(binding)
+	if layout == "" {
+		return tmpl.ExecuteWriter(ctx, w)
+	}
+
+	layoutTmpl, err := set.FromCache(layout)
+	if err != nil {
+		return err
+	}
+
+	buf := acquireViewBuffer()
+	defer releaseViewBuffer(buf)
+	if err = tmpl.ExecuteWriter(ctx, buf); err != nil {
+		return err
+	}
+	// don't touch the user's map
+	layoutCtx := make(pongo2.Context, len(ctx)+1)
+	for k, v := range ctx {
+		layoutCtx[k] = v
+	}
+	layoutCtx["yield"] = pongo2.AsSafeValue(buf.String())
+	return layoutTmpl.ExecuteWriter(layoutCtx, w)
+}
@@ -0,0 +1,351 @@
+package iris
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+
+	"github.com/kataras/go-errors"
+)
+
+var (
+	errViewNotFound   = errors.New("View: template '%s' couldn't be found")
+	errViewLoad       = errors.New("View: unable to load the templates from '%s'. Trace: %s")
+	errViewNotLoaded  = errors.New("View: the templates of '%s' are not loaded yet")
+	errViewYieldUsage = errors.New("View: yield is available only inside a layout")
+)
+
+// HTMLEngine is the built'n view engine, based on the standard html/template package.
+//
+// Layouts are templates which call {{ yield }} where the rendered page should be placed,
+// they can declare sections with {{ block "name" . }}default{{ end }}
+// which a page fills with its own {{ define "name" }}...{{ end }}, the page's defines are isolated from the other pages.
+//
+// Partials are rendered with {{ partial "partials/nav.html" data }}, they receive only the data they are given.
+//
+// Usage: app.AdaptView(iris.HTML("./templates", ".html").Layout("layouts/main.html"))
+type HTMLEngine struct {
+	fs        fs.FS
+	fsErr     error
+	name      string
+	extension string
+	layout    string
+	left      string
+	right     string
+	funcs     template.FuncMap
+	reload    bool
+
+	// files the parsed trees of each file, by the file's name
+	files map[string]map[string]*parse.Tree
+	// pristine is the parsed, never executed, templates,
+	// html/template can't be cloned after its first execution, so each execution happens on a clone of it.
+	pristine *template.Template
+	// sets a pool of the cloned sets for each page and layout
+	sets map[string]*sync.Pool
+	// generation is increased on each load, the sets of an older generation are dropped
+	generation int
+	// modTimes the modification time of each file at the last load, used to detect the changes on reload mode
+	modTimes map[string]time.Time
+	mu       sync.RWMutex
+}
+
+var _ ViewEngine = &HTMLEngine{}
+
+// htmlSet is a cloned template set with its own execution state,
+// it's used by one execution at a time.
+type htmlSet struct {
+	tmpl       *template.Template
+	generation int
+	// yield is the rendered page, available to the layout
+	yield    template.HTML
+	inLayout bool
+}
+
+// HTML creates and returns a new html/template view engine,
+// which loads the files with the 'extension' from the 'fileSystem'.
+//
+// The 'fileSystem' can be a system directory (string), an fs.FS (i.e a go:embed embed.FS) or an http.FileSystem,
+// i.e iris.HTML("./templates", ".html") or iris.HTML(embeddedTemplates, ".html") for single-binary deployments,
+// use the fs.Sub when the templates are embedded with their parent directory.
+func HTML(fileSystem interface{}, extension string) *HTMLEngine {
+	fsys, err := toFS(fileSystem)
+	return &HTMLEngine{
+		fs:        fsys,
+		fsErr:     err,
+		name:      fileSystemName(fileSystem),
+		extension: extension,
+		funcs:     make(template.FuncMap),
+	}
+}
+
+// Ext returns the file extension which this view engine is responsible for
+func (s *HTMLEngine) Ext() string {
+	return s.extension
+}
+
+// Reload if enabled the templates file system is watched and the changed templates are re-parsed on the next execution,
+// development mode. Otherwise the templates are parsed once, at the Load.
+func (s *HTMLEngine) Reload(enable bool) {
+	s.reload = enable
+}
+
+// Layout sets the default layout template, which can be overridden by the "layout" render option or by a party's Layout
+func (s *HTMLEngine) Layout(layoutFile string) *HTMLEngine {
+	s.layout = layoutFile
+	return s
+}
+
+// Delims sets the action delimiters, i.e "{%", "%}"
+func (s *HTMLEngine) Delims(left, right string) *HTMLEngine {
+	s.left, s.right = left, right
+	return s
+}
+
+// Funcs adds the elements of the argument map to the template's function map
+func (s *HTMLEngine) Funcs(funcMap template.FuncMap) *HTMLEngine {
+	for funcName, funcBody := range funcMap {
+		s.AddFunc(funcName, funcBody)
+	}
+	return s
+}
+
+// AddFunc adds a template func, it should be called before the Load
+func (s *HTMLEngine) AddFunc(funcName string, funcBody interface{}) {
+	s.mu.Lock()
+	s.funcs[funcName] = funcBody
+	s.mu.Unlock()
+}
+
+func (s *HTMLEngine) newTemplate(name string) *template.Template {
+	t := template.New(name)
+	if s.left != "" || s.right != "" {
+		t.Delims(s.left, s.right)
+	}
+	// the placeholders of the execution-specific funcs, they are replaced on each set
+	t.Funcs(template.FuncMap{
+		"yield":   func() (template.HTML, error) { return "", nil },
+		"partial": func(string, ...interface{}) (template.HTML, error) { return "", nil },
+	})
+	return t.Funcs(s.funcs)
+}
+
+// Load parses all the files with the engine's extension inside the engine's file system,
+// each template is named after its path relative to the file system's root, with forward slashes, i.e "layouts/main.html".
+func (s *HTMLEngine) Load() error {
+	if s.fsErr != nil {
+		return errViewLoad.Format(s.name, s.fsErr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := make(map[string]map[string]*parse.Tree)
+	modTimes := make(map[string]time.Time)
+	root := s.newTemplate("")
+
+	// fs.WalkDir walks in lexical order, so the shared defines are always resolved the same way
+	err := s.walk(func(name string, info fs.FileInfo) error {
+		contents, err := fs.ReadFile(s.fs, name)
+		if err != nil {
+			return err
+		}
+		modTimes[name] = info.ModTime()
+		// each file is parsed alone, in order to keep its own defines
+		t, err := s.newTemplate(name).Parse(string(contents))
+		if err != nil {
+			return err
+		}
+		trees := make(map[string]*parse.Tree)
+		for _, tt := range t.Templates() {
+			if tt.Tree == nil {
+				continue
+			}
+			trees[tt.Name()] = tt.Tree
+			if _, err = root.AddParseTree(tt.Name(), tt.Tree.Copy()); err != nil {
+				return err
+			}
+		}
+		files[name] = trees
+		return nil
+	})
+	if err != nil {
+		return errViewLoad.Format(s.name, err)
+	}
+
+	s.files = files
+	s.modTimes = modTimes
+	s.pristine = root
+	s.sets = make(map[string]*sync.Pool)
+	s.generation++
+	return nil
+}
+
+// walk calls the 'visitor' for each file with the engine's extension
+func (s *HTMLEngine) walk(visitor func(name string, info fs.FileInfo) error) error {
+	return fs.WalkDir(s.fs, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(name, s.extension) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return visitor(name, info)
+	})
+}
+
+// changed reports whether a template file has been added, removed or modified since the last load
+func (s *HTMLEngine) changed() bool {
+	if s.fsErr != nil {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	found := 0
+	errChanged := errors.New("changed")
+	err := s.walk(func(name string, info fs.FileInfo) error {
+		found++
+		if modTime, ok := s.modTimes[name]; !ok || !modTime.Equal(info.ModTime()) {
+			return errChanged
+		}
+		return nil
+	})
+	return err != nil || found != len(s.modTimes)
+}
+
+// overlay puts the defines of a file on top of the set's templates
+func (s *HTMLEngine) overlay(tmpl *template.Template, filename string) error {
+	for name, tree := range s.files[filename] {
+		if name == filename {
+			continue
+		}
+		if _, err := tmpl.AddParseTree(name, tree.Copy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acquireSet returns a set to execute the template 'name' inside the 'layout', from the pool or a new clone
+func (s *HTMLEngine) acquireSet(name, layout string) (*htmlSet, error) {
+	if s.reload && s.changed() {
+		if err := s.Load(); err != nil {
+			return nil, err
+		}
+	}
+
+	key := name + "|" + layout
+	s.mu.RLock()
+	if s.pristine == nil {
+		s.mu.RUnlock()
+		return nil, errViewNotLoaded.Format(s.name)
+	}
+	pool := s.sets[key]
+	generation := s.generation
+	s.mu.RUnlock()
+
+	if pool != nil {
+		if v := pool.Get(); v != nil {
+			if set := v.(*htmlSet); set.generation == generation {
+				return set, nil
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[name]; !ok {
+		return nil, errViewNotFound.Format(name)
+	}
+	if _, ok := s.files[layout]; layout != "" && !ok {
+		return nil, errViewNotFound.Format(layout)
+	}
+	if s.sets[key] == nil {
+		s.sets[key] = &sync.Pool{}
+	}
+
+	tmpl, err := s.pristine.Clone()
+	if err != nil {
+		return nil, err
+	}
+	// the layout's blocks are the defaults, the page's defines override them
+	if layout != "" {
+		if err = s.overlay(tmpl, layout); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.overlay(tmpl, name); err != nil {
+		return nil, err
+	}
+
+	set := &htmlSet{tmpl: tmpl, generation: s.generation}
+	tmpl.Funcs(template.FuncMap{
+		"yield": func() (template.HTML, error) {
+			if !set.inLayout {
+				return "", errViewYieldUsage
+			}
+			return set.yield, nil
+		},
+		"partial": func(partialName string, data ...interface{}) (template.HTML, error) {
+			var binding interface{}
+			if len(data) > 0 {
+				binding = data[0]
+			}
+			buf := new(bytes.Buffer)
+			if err := set.tmpl.ExecuteTemplate(buf, partialName, binding); err != nil {
+				return "", err
+			}
+			return template.HTML(buf.String()), nil
+		},
+	})
+	return set, nil
+}
+
+func (s *HTMLEngine) releaseSet(name, layout string, set *htmlSet) {
+	set.yield = ""
+	set.inLayout = false
+	s.mu.RLock()
+	pool := s.sets[name+"|"+layout]
+	s.mu.RUnlock()
+	if pool != nil {
+		pool.Put(set)
+	}
+}
+
+// ExecuteWriter executes a template and writes its result to the w writer,
+// if the layout is not empty, or a default layout exists, then the template is rendered inside the layout's {{ yield }}.
+func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, binding interface{}) error {
+	if layout == "" {
+		layout = s.layout
+	} else if layout == NoLayout {
+		layout = ""
+	}
+
+	set, err := s.acquireSet(name, layout)
+	if err != nil {
+		return err
+	}
+	defer s.releaseSet(name, layout, set)
+
+	if layout == "" {
+		return set.tmpl.ExecuteTemplate(w, name, binding)
+	}
+
+	buf := acquireViewBuffer()
+	defer releaseViewBuffer(buf)
+	if err = set.tmpl.ExecuteTemplate(buf, name, binding); err != nil {
+		return err
+	}
+	set.yield = template.HTML(buf.String())
+	set.inLayout = true
+	return set.tmpl.ExecuteTemplate(w, layout, binding)
+}
@@ -0,0 +1,223 @@
+package iris
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/kataras/go-errors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+)
+
+var errMarkdownNonce = errors.New("Markdown: the random source of the code blocks' placeholders failed. Trace: %s")
+
+// MarkdownRenderer converts markdown to html, the result is sanitized by its Sanitizer
+// and the fenced code blocks are passed to its Highlighter, if any.
+//
+// The app.Markdown is used by the ctx.MarkdownBytes and the markdown view engine.
+type MarkdownRenderer struct {
+	// Sanitizer receives the produced html and returns the safe html,
+	// set it to nil in order to disable the sanitization, when the markdown is trusted
+	// Defaults to the bluemonday's user generated content policy
+	Sanitizer func(html []byte) []byte
+	// Highlighter receives the contents and the language(the fence's info, can be empty) of a fenced code block
+	// and returns its html, the result is not passed to the Sanitizer
+	// Defaults to nil, the code blocks are rendered as <pre><code>
+	Highlighter func(code []byte, lang string) []byte
+}
+
+// NewMarkdownRenderer returns a new markdown renderer which sanitizes its result with the bluemonday's user generated content policy
+func NewMarkdownRenderer() *MarkdownRenderer {
+	policy := bluemonday.UGCPolicy()
+	return &MarkdownRenderer{Sanitizer: policy.SanitizeBytes}
+}
+
+var (
+	defaultMarkdownRenderer     *MarkdownRenderer
+	defaultMarkdownRendererOnce sync.Once
+)
+
+// getDefaultMarkdownRenderer returns the renderer of the MarkdownEngines without a Renderer, outside of an app,
+// it's built once, a bluemonday policy is expensive to build
+func getDefaultMarkdownRenderer() *MarkdownRenderer {
+	defaultMarkdownRendererOnce.Do(func() {
+		defaultMarkdownRenderer = NewMarkdownRenderer()
+	})
+	return defaultMarkdownRenderer
+}
+
+// markdownHTMLRenderer renders the fenced code blocks as placeholders,
+// which are replaced by the highlighter's result after the sanitization.
+type markdownHTMLRenderer struct {
+	*blackfriday.HTMLRenderer
+	highlighter func(code []byte, lang string) []byte
+	nonce       string
+	blocks      [][]byte
+}
+
+func (r *markdownHTMLRenderer) placeholder(i int) string {
+	return "IRISCODEBLOCK" + r.nonce + strconv.Itoa(i) + "END"
+}
+
+func (r *markdownHTMLRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	if r.highlighter != nil && node.Type == blackfriday.CodeBlock && node.IsFenced {
+		lang := strings.TrimSpace(string(node.Info))
+		if idx := strings.IndexByte(lang, ' '); idx > -1 {
+			lang = lang[:idx]
+		}
+		r.blocks = append(r.blocks, r.highlighter(node.Literal, lang))
+		io.WriteString(w, r.placeholder(len(r.blocks)-1)+"\n")
+		return blackfriday.GoToNext
+	}
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+// Render converts the 'markdown' to html, it panics if the random source of the code blocks' placeholders fails,
+// a predictable placeholder could be written by the markdown to inject the html of a code block after the sanitization
+func (m *MarkdownRenderer) Render(markdown []byte) []byte {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		panic(errMarkdownNonce.Format(err))
+	}
+	r := &markdownHTMLRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{Flags: blackfriday.CommonHTMLFlags}),
+		highlighter:  m.Highlighter,
+		nonce:        hex.EncodeToString(nonce),
+	}
+
+	result := blackfriday.Run(markdown, blackfriday.WithExtensions(blackfriday.CommonExtensions), blackfriday.WithRenderer(r))
+	if m.Sanitizer != nil {
+		result = m.Sanitizer(result)
+	}
+
+	for i := range r.blocks {
+		result = bytes.Replace(result, []byte(r.placeholder(i)), r.blocks[i], 1)
+	}
+	return result
+}
+
+// MarkdownEngine is the markdown view engine, it renders the .md files to html.
+//
+// Each file is executed as a text/template first, with the render's binding, so it can contain {{ .Title }} actions,
+// then the result is converted to html by the app.Markdown renderer, which sanitizes it.
+//
+// The layouts are not supported by this engine, the rendered html is written as it's.
+//
+// Usage: app.AdaptView(iris.Markdown("./docs", ".md"))
+type MarkdownEngine struct {
+	fs        fs.FS
+	fsErr     error
+	name      string
+	extension string
+	funcs     template.FuncMap
+	reload    bool
+	// Renderer converts the markdown to html, if nil then the app.Markdown is used, or a shared NewMarkdownRenderer outside of an app
+	Renderer *MarkdownRenderer
+
+	templates *template.Template
+	mu        sync.RWMutex
+}
+
+var _ ViewEngine = &MarkdownEngine{}
+
+// Markdown creates and returns a new markdown view engine,
+// which loads the files with the 'extension' from the 'fileSystem',
+// a directory (string), an fs.FS or an http.FileSystem
+func Markdown(fileSystem interface{}, extension string) *MarkdownEngine {
+	fsys, err := toFS(fileSystem)
+	return &MarkdownEngine{
+		fs:        fsys,
+		fsErr:     err,
+		name:      fileSystemName(fileSystem),
+		extension: extension,
+		funcs:     make(template.FuncMap),
+	}
+}
+
+// Ext returns the file extension which this view engine is responsible for
+func (s *MarkdownEngine) Ext() string {
+	return s.extension
+}
+
+// Reload if enabled the templates are re-parsed on each execution, development mode
+func (s *MarkdownEngine) Reload(enable bool) {
+	s.reload = enable
+}
+
+// AddFunc adds a template func, it should be called before the Load
+func (s *MarkdownEngine) AddFunc(funcName string, funcBody interface{}) {
+	s.mu.Lock()
+	s.funcs[funcName] = funcBody
+	s.mu.Unlock()
+}
+
+// Load parses all the files with the engine's extension
+func (s *MarkdownEngine) Load() error {
+	if s.fsErr != nil {
+		return errViewLoad.Format(s.name, s.fsErr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root := template.New("").Funcs(s.funcs)
+	err := fs.WalkDir(s.fs, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(name, s.extension) {
+			return nil
+		}
+		contents, err := fs.ReadFile(s.fs, name)
+		if err != nil {
+			return err
+		}
+		_, err = root.New(name).Parse(string(contents))
+		return err
+	})
+	if err != nil {
+		return errViewLoad.Format(s.name, err)
+	}
+	s.templates = root
+	return nil
+}
+
+// ExecuteWriter executes the markdown template and writes its html result to the w writer, the 'layout' is ignored
+func (s *MarkdownEngine) ExecuteWriter(w io.Writer, name string, layout string, binding interface{}) error {
+	if s.reload {
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.RLock()
+	templates := s.templates
+	s.mu.RUnlock()
+	if templates == nil {
+		return errViewNotLoaded.Format(s.name)
+	}
+	tmpl := templates.Lookup(name)
+	if tmpl == nil {
+		return errViewNotFound.Format(name)
+	}
+
+	buf := acquireViewBuffer()
+	defer releaseViewBuffer(buf)
+	if err := tmpl.Execute(buf, binding); err != nil {
+		return err
+	}
+
+	renderer := s.Renderer
+	if renderer == nil {
+		renderer = getDefaultMarkdownRenderer()
+	}
+	_, err := w.Write(renderer.Render(buf.Bytes()))
+	return err
+}
@@ -1,6 +1,7 @@
 package iris
 
 import (
+	"io/fs"
 	"net/http"
 	"os"
 	"strings"
@@ -18,8 +19,8 @@ type StaticHandlerBuilder interface {
 }
 
 type webfs struct {
-	// user options, only directory is required.
-	directory       http.Dir
+	// user options, only the source file system is required.
+	source          http.FileSystem
 	requestPath     string
 	stripPath       bool
 	gzip            bool
@@ -50,7 +51,7 @@ func toWebPath(systemPath string) string {
 // structure and want a fluent api to work on.
 func NewStaticHandlerBuilder(dir string) StaticHandlerBuilder {
 	return &webfs{
-		directory: http.Dir(dir),
+		source: http.Dir(dir),
 		// default route path is the same as the directory
 		requestPath: toWebPath(dir),
 		// enable strip path by-default
@@ -62,6 +63,20 @@ func NewStaticHandlerBuilder(dir string) StaticHandlerBuilder {
 	}
 }
 
+// NewStaticHandlerBuilderFS same as NewStaticHandlerBuilder but
+// it serves the files of an fs.FS, i.e a go:embed embed.FS, instead of a system directory.
+//
+// The default request path is the root "/".
+func NewStaticHandlerBuilderFS(fileSystem fs.FS) StaticHandlerBuilder {
+	return &webfs{
+		source:          http.FS(fileSystem),
+		requestPath:     slash,
+		stripPath:       true,
+		gzip:            false,
+		listDirectories: false,
+	}
+}
+
 // Path sets the request path.
 // Defaults to same as system path
 func (w *webfs) Path(requestRoutePath string) StaticHandlerBuilder {
@@ -120,7 +135,7 @@ func (w *webfs) Build() HandlerFunc {
 	// we have to ensure that Build is called ONLY one time,
 	// one instance per one static directory.
 	w.once.Do(func() {
-		w.filesystem = http.Dir(w.directory)
+		w.filesystem = w.source
 
 		// set the filesystem to itself in order to be recognised of listing property (can be change at runtime too)
 		fileserver := http.FileServer(w)
@@ -0,0 +1,158 @@
+package iris
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// FaviconCacheControl the Cache-Control of the favicons which are served by the Favicon
+	FaviconCacheControl = "public, max-age=604800"
+	// WellKnownCacheControl the Cache-Control of the files which are served by the WellKnown
+	WellKnownCacheControl = "public, max-age=86400"
+	// wellKnownDir the directory of the well-known uris, RFC 8615
+	wellKnownDir = ".well-known"
+)
+
+// wellKnownRootFiles the files which are served at the root path by the WellKnown, if they exist
+var wellKnownRootFiles = []string{
+	"robots.txt",
+	"humans.txt",
+	"ads.txt",
+	"app-ads.txt",
+	"sitemap.xml",
+	"favicon.ico",
+	"apple-touch-icon.png",
+}
+
+// wellKnownContentTypes the content types of the well-known files which have no extension
+var wellKnownContentTypes = map[string]string{
+	"apple-app-site-association": contentJSON,
+}
+
+// memoryFileHandler returns a handler which serves the 'content' of the file 'name' from memory,
+// with its ETag, the 'cacheControl' and the conditional requests' support
+func memoryFileHandler(name string, content []byte, modTime time.Time, cacheControl string) HandlerFunc {
+	cType, ok := wellKnownContentTypes[path.Base(name)]
+	if !ok {
+		if cType = mime.TypeByExtension(path.Ext(name)); cType == "" {
+			cType = contentText + "; charset=utf-8"
+		}
+	}
+	sum := sha1.Sum(content)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	return func(ctx *Context) {
+		ctx.SetContentType(cType)
+		ctx.SetHeader("ETag", etag)
+		if cacheControl != "" {
+			ctx.SetHeader("Cache-Control", cacheControl)
+		}
+		http.ServeContent(ctx.ResponseWriter, ctx.Request, name, modTime, bytes.NewReader(content))
+	}
+}
+
+// readFavicon reads the favicon of the 'favicon', a favicon's file or a directory (string), an fs.FS or an http.FileSystem,
+// the directories are searched for a favicon.ico or a favicon.png
+func readFavicon(favicon interface{}) (name string, content []byte, modTime time.Time, err error) {
+	fsys := fs.FS(nil)
+	if favPath, ok := favicon.(string); ok {
+		info, err := os.Stat(favPath)
+		if err != nil {
+			return "", nil, time.Time{}, errDirectoryFileNotFound.Format(favPath, err.Error())
+		}
+		if !info.IsDir() {
+			content, err = os.ReadFile(favPath)
+			if err != nil {
+				return "", nil, time.Time{}, errDirectoryFileNotFound.Format(favPath, "Couldn't read the data bytes for Favicon: "+err.Error())
+			}
+			return info.Name(), content, info.ModTime(), nil
+		}
+		fsys = os.DirFS(favPath)
+	} else if fsys, err = toFS(favicon); err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	for _, name = range []string{"favicon.ico", "favicon.png"} {
+		info, statErr := fs.Stat(fsys, name)
+		if statErr != nil || info.IsDir() {
+			continue
+		}
+		if content, err = fs.ReadFile(fsys, name); err != nil {
+			return "", nil, time.Time{}, errDirectoryFileNotFound.Format(name, "Couldn't read the data bytes for Favicon: "+err.Error())
+		}
+		return name, content, info.ModTime(), nil
+	}
+	return "", nil, time.Time{}, errDirectoryFileNotFound.Format(fileSystemName(favicon)+"/favicon.ico", "favicon.ico or favicon.png not found")
+}
+
+// WellKnown serves, from memory, the well-known files of the 'fileSystem',
+// a directory (string or iris.Dir), an fs.FS (i.e a go:embed embed.FS) or an http.FileSystem:
+// the robots.txt, humans.txt, ads.txt, app-ads.txt, sitemap.xml, favicon.ico and apple-touch-icon.png at the root path, if they exist,
+// and each file of its .well-known directory under the /.well-known/, i.e /.well-known/security.txt.
+//
+// The files are read once, with the correct content types, ETags and the WellKnownCacheControl.
+//
+// panics on error
+func WellKnown(fileSystem interface{}) {
+	Default.WellKnown(fileSystem)
+}
+
+// WellKnown serves, from memory, the well-known files of the 'fileSystem',
+// a directory (string or iris.Dir), an fs.FS (i.e a go:embed embed.FS) or an http.FileSystem:
+// the robots.txt, humans.txt, ads.txt, app-ads.txt, sitemap.xml, favicon.ico and apple-touch-icon.png at the root path, if they exist,
+// and each file of its .well-known directory under the /.well-known/, i.e /.well-known/security.txt.
+//
+// The files are read once, with the correct content types, ETags and the WellKnownCacheControl.
+//
+// panics on error
+func (api *muxAPI) WellKnown(fileSystem interface{}) {
+	fsys, err := toFS(fileSystem)
+	if err != nil {
+		panic(err)
+	}
+
+	serve := func(name string, info fs.FileInfo) {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			panic(errDirectoryFileNotFound.Format(name, err.Error()))
+		}
+		cacheControl := WellKnownCacheControl
+		if strings.HasPrefix(name, "favicon.") {
+			cacheControl = FaviconCacheControl
+		}
+		api.registerResourceRoute(slash+name, memoryFileHandler(name, content, info.ModTime(), cacheControl))
+	}
+
+	for _, name := range wellKnownRootFiles {
+		if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+			serve(name, info)
+		}
+	}
+
+	if info, err := fs.Stat(fsys, wellKnownDir); err != nil || !info.IsDir() {
+		return
+	}
+	err = fs.WalkDir(fsys, wellKnownDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		serve(name, info)
+		return nil
+	})
+	if err != nil {
+		panic(errDirectoryFileNotFound.Format(wellKnownDir, err.Error()))
+	}
+}
@@ -0,0 +1,184 @@
+package iris
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kataras/go-fs"
+)
+
+// XMLOptions the options of the ctx.XML, of the Config.XML and each ctx.XML call,
+// the non-zero fields of the call override the Config.XML ones
+type XMLOptions struct {
+	// Root the name of the root element, which overrides the XMLName of the value, i.e "soap:Envelope"
+	// Defaults to "", the name of the XMLName field or of the value's type
+	Root string
+	// Namespaces the namespaces which are declared at the root element, by prefix, the empty prefix is the default namespace,
+	// i.e {"soap": "http://schemas.xmlsoap.org/soap/envelope/"} declares the xmlns:soap
+	// Defaults to nil
+	Namespaces map[string]string
+	// Header the declaration which is written before the root element, i.e the xml.Header
+	// Defaults to "", no declaration
+	Header string
+	// Indent the indentation of the elements, i.e "  "
+	// Defaults to "", compact, or to two spaces if the Config.IsDevelopment is true
+	Indent string
+	// Transform returns the value which is encoded instead of the 'v' of the ctx.XML,
+	// i.e to wrap the legacy structs to an envelope or to convert them to the types of the integration
+	// Defaults to nil
+	Transform func(v interface{}) interface{}
+}
+
+// isZero returns true if none of the options is set
+func (opts XMLOptions) isZero() bool {
+	return opts.Root == "" && len(opts.Namespaces) == 0 && opts.Header == "" && opts.Indent == "" && opts.Transform == nil
+}
+
+// override returns the options overridden by the non-zero fields of the 'o', the namespaces are merged
+func (opts XMLOptions) override(o XMLOptions) XMLOptions {
+	if o.Root != "" {
+		opts.Root = o.Root
+	}
+	if len(o.Namespaces) > 0 {
+		namespaces := make(map[string]string, len(opts.Namespaces)+len(o.Namespaces))
+		for prefix, uri := range opts.Namespaces {
+			namespaces[prefix] = uri
+		}
+		for prefix, uri := range o.Namespaces {
+			namespaces[prefix] = uri
+		}
+		opts.Namespaces = namespaces
+	}
+	if o.Header != "" {
+		opts.Header = o.Header
+	}
+	if o.Indent != "" {
+		opts.Indent = o.Indent
+	}
+	if o.Transform != nil {
+		opts.Transform = o.Transform
+	}
+	return opts
+}
+
+// XML marshals the given interface object and writes the XML response.
+// The Config.XML and the 'options' control its root element, namespaces, declaration and indentation,
+// without any of them it's rendered by the xml serializer, see the UseSerializer.
+//
+// Usage:
+// ctx.XML(iris.StatusOK, body, iris.XMLOptions{
+//	Root:       "soap:Envelope",
+//	Namespaces: map[string]string{"soap": "http://schemas.xmlsoap.org/soap/envelope/"},
+//	Header:     xml.Header,
+// })
+func (ctx *Context) XML(status int, v interface{}, options ...XMLOptions) error {
+	opts := ctx.framework.Config.XML
+	for _, o := range options {
+		opts = opts.override(o)
+	}
+	if opts.isZero() {
+		return ctx.RenderWithStatus(status, contentXML, v)
+	}
+	if opts.Indent == "" && ctx.framework.Config.IsDevelopment {
+		opts.Indent = "  "
+	}
+	return ctx.writeXML(status, v, opts)
+}
+
+// writeXML encodes the 'v' with the 'opts' to the response, gzipped if the Config.Gzip is true and the client accepts it
+func (ctx *Context) writeXML(status int, v interface{}, opts XMLOptions) error {
+	if opts.Transform != nil {
+		v = opts.Transform(v)
+	}
+	ctx.SetContentType(contentXML + "; charset=" + ctx.framework.Config.Charset)
+
+	var w io.Writer = ctx.ResponseWriter
+	if ctx.framework.Config.Gzip && ctx.clientAllowsGzip() {
+		ctx.ResponseWriter.Header().Add(varyHeader, acceptEncodingHeader)
+		ctx.SetHeader(contentEncodingHeader, "gzip")
+		gzipWriter := fs.AcquireGzipWriter(ctx.ResponseWriter)
+		defer fs.ReleaseGzipWriter(gzipWriter)
+		w = gzipWriter
+	}
+
+	fail := func(err error) error {
+		ctx.ResponseWriter.ResetBody()
+		ctx.ResponseWriter.Header().Del(contentEncodingHeader)
+		return err
+	}
+	if opts.Header != "" {
+		if _, err := io.WriteString(w, opts.Header); err != nil {
+			return fail(err)
+		}
+	}
+	enc := xml.NewEncoder(w)
+	if opts.Indent != "" {
+		enc.Indent("", opts.Indent)
+	}
+	var err error
+	if opts.Root != "" || len(opts.Namespaces) > 0 {
+		err = enc.EncodeElement(v, xmlRoot(v, opts))
+	} else {
+		err = enc.Encode(v)
+	}
+	if err == nil {
+		err = enc.Flush()
+	}
+	if err != nil {
+		return fail(err)
+	}
+	ctx.SetStatusCode(status)
+	return nil
+}
+
+// xmlRoot returns the root element of the 'v', named by the 'opts' or by the 'v', with the namespace declarations of the 'opts'
+func xmlRoot(v interface{}, opts XMLOptions) xml.StartElement {
+	root := xml.StartElement{Name: xml.Name{Local: opts.Root}}
+	if root.Name.Local == "" {
+		root.Name = xmlName(v)
+	}
+	prefixes := make([]string, 0, len(opts.Namespaces))
+	for prefix := range opts.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		attr := "xmlns"
+		if prefix != "" {
+			attr += ":" + prefix
+		}
+		root.Attr = append(root.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: opts.Namespaces[prefix]})
+	}
+	return root
+}
+
+// xmlName returns the name of the element of the 'v', the one of its XMLName field or of its type, like the encoding/xml
+func xmlName(v interface{}) xml.Name {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Struct {
+		if f, ok := val.Type().FieldByName("XMLName"); ok {
+			if name, isName := val.FieldByIndex(f.Index).Interface().(xml.Name); isName && name.Local != "" {
+				return name
+			}
+			if tag := strings.Split(f.Tag.Get("xml"), ",")[0]; tag != "" {
+				if i := strings.LastIndex(tag, " "); i >= 0 {
+					return xml.Name{Space: tag[:i], Local: tag[i+1:]}
+				}
+				return xml.Name{Local: tag}
+			}
+		}
+	}
+	if val.IsValid() && val.Type().Name() != "" {
+		return xml.Name{Local: val.Type().Name()}
+	}
+	return xml.Name{Local: "???"}
+}